@@ -26,6 +26,7 @@ import (
 	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -70,6 +71,78 @@ type ApplicationSpec struct {
 	// HTTPRouteTemplate is the desired HTTPRoute spec. Nil means no HTTPRoute.
 	// +optional
 	HTTPRouteTemplate *HTTPRouteTemplateSpec `json:"httpRouteTemplate,omitempty"`
+
+	// RolloutStrategy is the canary strategy used when Kind is "Rollout".
+	// Ignored for other kinds.
+	// +optional
+	RolloutStrategy *RolloutStrategySpec `json:"rolloutStrategy,omitempty"`
+}
+
+// RolloutStrategySpec renders a workload as an Argo Rollout with a canary
+// strategy. Traffic follows the replica ratio behind the application's
+// Service; no traffic router is configured.
+type RolloutStrategySpec struct {
+	// Steps run in order on every update. Without steps the canary is
+	// promoted as soon as it is available.
+	// +optional
+	Steps []CanaryStepSpec `json:"steps,omitempty"`
+
+	// Analysis runs in the background while the steps execute; a failed
+	// AnalysisRun aborts the rollout.
+	// +optional
+	Analysis *BackgroundAnalysisSpec `json:"analysis,omitempty"`
+
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// CanaryStepSpec is a single canary step. Exactly one field must be set.
+// +kubebuilder:validation:XValidation:rule="[has(self.setWeight), has(self.pause), has(self.analysis)].filter(x, x).size() == 1",message="exactly one of setWeight, pause or analysis must be set"
+type CanaryStepSpec struct {
+	// SetWeight is the percentage of replicas running the new version.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	SetWeight *int32 `json:"setWeight,omitempty"`
+	// Pause halts the rollout, for Duration or until it is promoted.
+	// +optional
+	Pause *CanaryPauseSpec `json:"pause,omitempty"`
+	// Analysis runs the templates and waits for the result before the next step.
+	// +optional
+	Analysis *RolloutAnalysisSpec `json:"analysis,omitempty"`
+}
+
+type CanaryPauseSpec struct {
+	// Duration is a number of seconds or a value like "30s", "10m" or "1h".
+	// Empty waits for a manual `kubectl argo rollouts promote`.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[0-9]+(s|m|h)?$`
+	Duration string `json:"duration,omitempty"`
+}
+
+// RolloutAnalysisSpec references AnalysisTemplates that already exist in the
+// application namespace (or ClusterAnalysisTemplates).
+type RolloutAnalysisSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	Templates []v1alpha1.AnalysisTemplateRef `json:"templates"`
+	// Args are passed to the AnalysisRun.
+	// +optional
+	Args []RolloutAnalysisArg `json:"args,omitempty"`
+}
+
+type BackgroundAnalysisSpec struct {
+	RolloutAnalysisSpec `json:",inline"`
+	// StartingStep is the index of the step at which the analysis starts.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	StartingStep *int32 `json:"startingStep,omitempty"`
+}
+
+type RolloutAnalysisArg struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 // HTTPRouteTemplateSpec contains the fields needed to build a Gateway API HTTPRoute.
@@ -89,12 +162,18 @@ type HTTPRouteTemplateSpec struct {
 	// Nil means no port is specified in the backend ref.
 	// +optional
 	ServicePort *gatewayv1.PortNumber `json:"servicePort,omitempty"`
+
+	// Routing is copied from the WeightsAndBiases spec.networking.gatewayAPI.routing
+	// entry for this application.
+	// +optional
+	Routing *GatewayRouting `json:"routing,omitempty"`
 }
 
 // ApplicationStatus defines the observed state of Application.
 type ApplicationStatus struct {
 	Ready             bool                                         `json:"ready"`
 	CronJobStatuses   map[string]batchv1.CronJobStatus             `json:"cronJobStatuses,omitempty"`
+	DaemonSetStatus   *v1.DaemonSetStatus                          `json:"daemonSetStatus,omitempty"`
 	DeploymentStatus  *v1.DeploymentStatus                         `json:"deploymentStatus,omitempty"`
 	IngressStatus     *networkingv1.IngressStatus                  `json:"ingressStatus,omitempty"`
 	JobStatuses       map[string]batchv1.JobStatus                 `json:"jobStatuses,omitempty"`
@@ -105,12 +184,57 @@ type ApplicationStatus struct {
 
 	// +optional
 	HTTPRouteStatus *HTTPRouteStatusSummary `json:"httpRouteStatus,omitempty"`
+
+	// ScaledObjectStatus summarizes the KEDA ScaledObject scaling the workload,
+	// when one is rendered.
+	// +optional
+	ScaledObjectStatus *ScaledObjectStatusSummary `json:"scaledObjectStatus,omitempty"`
+
+	// Rollout summarizes canary progress when Kind is "Rollout".
+	// +optional
+	Rollout *RolloutStatusSummary `json:"rollout,omitempty"`
 }
 
 type HTTPRouteStatusSummary struct {
 	Accepted bool `json:"accepted,omitempty"`
 }
 
+// ScaledObjectStatusSummary mirrors the KEDA ScaledObject conditions.
+type ScaledObjectStatusSummary struct {
+	// Ready is true once KEDA has accepted the ScaledObject and created its HPA.
+	Ready bool `json:"ready,omitempty"`
+	// Active is true while a trigger is above its activation threshold.
+	Active bool `json:"active,omitempty"`
+	// HpaName is the HPA KEDA manages on behalf of the ScaledObject.
+	// +optional
+	HpaName string `json:"hpaName,omitempty"`
+	// Message carries the Ready condition message when not ready.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RolloutStatusSummary is the canary state of an Argo Rollout.
+type RolloutStatusSummary struct {
+	// Phase is the Rollout phase: Healthy, Progressing, Paused or Degraded.
+	// +optional
+	Phase string `json:"phase,omitempty"`
+	// CurrentStep is the index of the step being executed; it equals
+	// TotalSteps once the canary is fully promoted.
+	// +optional
+	CurrentStep *int32 `json:"currentStep,omitempty"`
+	// +optional
+	TotalSteps int32 `json:"totalSteps,omitempty"`
+	// Paused is true while a pause step or a manual pause holds the rollout.
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+	// Aborted is true once the rollout was aborted, by hand or by a failed
+	// analysis, and has scaled back to the stable version.
+	// +optional
+	Aborted bool `json:"aborted,omitempty"`
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 
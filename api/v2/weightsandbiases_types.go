@@ -17,7 +17,12 @@ limitations under the License.
 package v2
 
 import (
+	"strings"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -100,6 +105,10 @@ const (
 	DetachOnDelete OnDeletePolicy = "detach"
 	// PurgeOnDelete will delete all associated resources upon deletion
 	PurgeOnDelete OnDeletePolicy = "purge"
+	// SnapshotOnDelete takes a CSI VolumeSnapshot of each infrastructure PVC and
+	// purges once they are all ready to use. Clusters without the VolumeSnapshot
+	// API are detached instead.
+	SnapshotOnDelete OnDeletePolicy = "snapshot"
 )
 
 type RetentionPolicy struct {
@@ -242,6 +251,84 @@ type NetworkingSpec struct {
 	// Annotations applied to all generated Ingress or Gateway resources.
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Hosts are extra hostnames served alongside spec.wandb.hostname, each
+	// with its own certificate, annotations and, optionally, a subset of the
+	// applications. In ingress mode each host gets its own Ingress; in gateway
+	// mode each host gets its own listener on a managed Gateway.
+	// +optional
+	Hosts []IngressHost `json:"hosts,omitempty"`
+
+	// NetworkPolicies opts in to default-deny NetworkPolicies for the W&B
+	// applications and managed infrastructure.
+	// +optional
+	NetworkPolicies *NetworkPoliciesConfig `json:"networkPolicies,omitempty"`
+}
+
+// NetworkPoliciesConfig controls the NetworkPolicies rendered from the
+// manifest. Applications may only talk to each other, DNS, telemetry and the
+// managed infrastructure their env sources reference; managed infrastructure
+// only admits its consumers, its own peers and the operators that run it.
+type NetworkPoliciesConfig struct {
+	// +kubebuilder:default=false
+	Enabled bool `json:"enabled,omitempty"`
+
+	// OperatorNamespace is where the W&B operator and the database operators
+	// installed with it run. Their pods may reach managed infrastructure on
+	// any port. Required when enabled.
+	// +optional
+	OperatorNamespace string `json:"operatorNamespace,omitempty"`
+
+	// IngressFrom selects the ingress controller or Gateway pods allowed to
+	// reach applications and infrastructure exposed by spec.networking. When
+	// empty, pods in any namespace may reach the exposed ports.
+	// +optional
+	IngressFrom []networkingv1.NetworkPolicyPeer `json:"ingressFrom,omitempty"`
+
+	// EgressCIDRs are destinations outside the cluster the applications may
+	// reach, such as external databases, object storage or identity providers.
+	// +optional
+	EgressCIDRs []string `json:"egressCIDRs,omitempty"`
+}
+
+// IngressHost is one extra hostname. Unlike spec.wandb.additionalHostnames,
+// which share the primary Ingress and certificate, a host is rendered on its
+// own so it can use a different certificate, issuer or ingress class.
+type IngressHost struct {
+	// Hostname, e.g. "wandb.partner.com".
+	Hostname string `json:"hostname"`
+
+	// Name distinguishes the host's resources: the Ingress is named
+	// "<ingress name>-<name>" and the Gateway listener "host-<name>".
+	// Defaults to the hostname with dots replaced by dashes.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// TLS for this host. A cert-manager issuer without a secretName issues
+	// into "<ingress name>-<name>-tls". Unset serves the host without TLS.
+	// +optional
+	TLS *TLSConfig `json:"tls,omitempty"`
+
+	// Annotations for this host's Ingress, applied over
+	// spec.networking.annotations. Ingress mode only.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// IngressClassName overrides spec.networking.ingress.ingressClassName for
+	// this host. Ingress mode only.
+	// +optional
+	IngressClassName *string `json:"ingressClassName,omitempty"`
+
+	// Applications limits the host to these applications, or infrastructure
+	// routes named as in spec.networking.gatewayAPI.routing (e.g.
+	// "bucket-default"). Empty serves everything.
+	// +optional
+	Applications []string `json:"applications,omitempty"`
+
+	// Paths limits the host to application paths under these prefixes.
+	// Ingress mode only; empty serves every path.
+	// +optional
+	Paths []string `json:"paths,omitempty"`
 }
 
 type IngressConfig struct {
@@ -260,6 +347,133 @@ type GatewayAPIConfig struct {
 	// ListenerName selects which listener on the Gateway to attach HTTPRoutes to.
 	// +optional
 	ListenerName *string `json:"listenerName,omitempty"`
+
+	// Routing customises the HTTPRoutes the operator renders, keyed by
+	// application name (e.g. "api") or, for infrastructure routes, by the
+	// route name without the CR name prefix (e.g. "bucket-default").
+	// +optional
+	Routing map[string]GatewayRouting `json:"routing,omitempty"`
+}
+
+// GatewayRouting customises one HTTPRoute. The actions apply to the route's
+// default rule, built from the manifest paths. Rules are rendered ahead of
+// the default rule and take precedence for the requests they match, e.g. to
+// send part of /graphql to a canary.
+type GatewayRouting struct {
+	GatewayRouteActions `json:",inline"`
+
+	// +optional
+	Rules []GatewayRouteRule `json:"rules,omitempty"`
+}
+
+// GatewayRouteRule is an extra HTTPRoute rule. It must match on paths,
+// headers or both; requests it does not match fall through to the default rule.
+type GatewayRouteRule struct {
+	// Paths to match. Defaults to the paths of the default rule.
+	// +optional
+	Paths []string `json:"paths,omitempty"`
+
+	// PathType is "Exact" or "PathPrefix". Defaults to the default rule's.
+	// +kubebuilder:validation:Enum=Exact;PathPrefix
+	// +optional
+	PathType string `json:"pathType,omitempty"`
+
+	// Headers that must all match.
+	// +optional
+	Headers []GatewayHeaderMatch `json:"headers,omitempty"`
+
+	GatewayRouteActions `json:",inline"`
+}
+
+type GatewayHeaderMatch struct {
+	Name string `json:"name"`
+
+	// Type is "Exact" (default) or "RegularExpression".
+	// +kubebuilder:validation:Enum=Exact;RegularExpression
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	Value string `json:"value"`
+}
+
+// GatewayRouteActions controls where a rule sends traffic and how.
+type GatewayRouteActions struct {
+	// ServiceWeight is the weight of the route's own Service relative to
+	// Backends. Unset means the Gateway API default of 1; 0 sends it nothing.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	ServiceWeight *int32 `json:"serviceWeight,omitempty"`
+
+	// Backends are additional Services that share the rule's traffic by
+	// weight, such as a canary.
+	// +optional
+	Backends []GatewayBackend `json:"backends,omitempty"`
+
+	// Mirror copies requests to a Service, such as a shadow deployment, and
+	// discards its responses.
+	// +optional
+	Mirror *GatewayMirror `json:"mirror,omitempty"`
+
+	// +optional
+	Timeouts *GatewayRouteTimeouts `json:"timeouts,omitempty"`
+
+	// +optional
+	Retry *GatewayRouteRetry `json:"retry,omitempty"`
+}
+
+type GatewayBackend struct {
+	// Name of a Service in the route's namespace.
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Weight relative to the other backends of the rule. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+type GatewayMirror struct {
+	// Name of a Service in the route's namespace.
+	Name string `json:"name"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Percent of requests to mirror. Defaults to all of them.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Percent *int32 `json:"percent,omitempty"`
+}
+
+// GatewayRouteTimeouts take Gateway API durations such as "30s" or "1h30m".
+type GatewayRouteTimeouts struct {
+	// Request bounds the whole request, including retries.
+	// +optional
+	Request string `json:"request,omitempty"`
+
+	// BackendRequest bounds each request to a backend. It must not exceed
+	// Request.
+	// +optional
+	BackendRequest string `json:"backendRequest,omitempty"`
+}
+
+type GatewayRouteRetry struct {
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Attempts *int32 `json:"attempts,omitempty"`
+
+	// Backoff is the minimum wait between attempts, e.g. "100ms".
+	// +optional
+	Backoff string `json:"backoff,omitempty"`
+
+	// Codes are the HTTP status codes (400-599) that are retried.
+	// +optional
+	Codes []int32 `json:"codes,omitempty"`
 }
 
 type GatewayConfig struct {
@@ -355,6 +569,58 @@ func ValidMysqlReplicaCount(r int32) bool {
 	return r > 0 && r%2 == 1
 }
 
+// mysqldProtectedSettings are the mysqld options users may not set: the ones
+// MOCO forces for GTID replication and its own plumbing (MOCO's ConstMycnf and
+// the binlog options it strips), and the TLS paths the operator manages.
+var mysqldProtectedSettings = map[string]bool{
+	"_include":                 true,
+	"admin_port":               true,
+	"binlog_format":            true,
+	"datadir":                  true,
+	"disable_log_bin":          true,
+	"enforce_gtid_consistency": true,
+	"gtid_mode":                true,
+	"log_bin":                  true,
+	"log_error":                true,
+	"log_replica_updates":      true,
+	"log_slave_updates":        true,
+	"mysqlx_port":              true,
+	"pid_file":                 true,
+	"port":                     true,
+	"read_only":                true,
+	"relay_log_recovery":       true,
+	"replication_optimize_for_static_plugin_config": true,
+	"replication_sender_observe_commit_only":        true,
+	"secure_file_priv":                              true,
+	"server_id":                                     true,
+	"skip_log_bin":                                  true,
+	"skip_name_resolve":                             true,
+	"skip_replica_start":                            true,
+	"skip_slave_start":                              true,
+	"slow_query_log_file":                           true,
+	"socket":                                        true,
+	"ssl_ca":                                        true,
+	"ssl_cert":                                      true,
+	"ssl_key":                                       true,
+	"super_read_only":                               true,
+}
+
+// NormalizeMysqldSetting returns the canonical name of a mysqld option: mysqld
+// treats dashes and underscores alike, and a "loose_" prefix only softens
+// unknown-option errors.
+func NormalizeMysqldSetting(name string) string {
+	name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_")
+	return strings.TrimPrefix(name, "loose_")
+}
+
+// MysqldSettingProtected reports whether a mysqld option is managed by MOCO or
+// the operator and so cannot be set through MysqldConfig.Settings. Semi-sync
+// replication options are protected as a family.
+func MysqldSettingProtected(name string) bool {
+	name = NormalizeMysqldSetting(name)
+	return mysqldProtectedSettings[name] || strings.HasPrefix(name, "rpl_semi_sync_")
+}
+
 // WandbAppSpec defines the configuration for the Wandb application deployment.
 type WandbAppSpec struct {
 	Hostname            string              `json:"hostname"`
@@ -401,11 +667,79 @@ type WandbAppSpec struct {
 	// manifest application name. Unknown keys are logged and ignored.
 	// +optional
 	Applications map[string]WandbApplicationOverride `json:"applications,omitempty"`
+
+	// ManifestVerification, when set, requires the server manifest to carry a
+	// cosign signature by one of the listed public keys. An unsigned or
+	// mis-signed manifest blocks reconcile with Ready=False/ManifestUntrusted.
+	// +optional
+	ManifestVerification *ManifestVerificationSpec `json:"manifestVerification,omitempty"`
+
+	// RolloutStrategy renders every W&B application as an Argo Rollout with
+	// this canary strategy instead of a Deployment. It needs the Argo Rollouts
+	// controller and an operator started with --enable-rollouts; otherwise
+	// applications stay Deployments. Canary progress is reported in
+	// status.wandb.applications[*].rollout and holds Ready=False until the
+	// canary is promoted.
+	// +optional
+	RolloutStrategy *RolloutStrategySpec `json:"rolloutStrategy,omitempty"`
+
+	// GeneratedSecrets sets rotation policies for the secrets generated from
+	// the server manifest's generatedSecrets, keyed by their manifest name.
+	// Secrets without a policy are never rotated automatically, but can still
+	// be rotated by annotating them with RotateGeneratedSecretAnnotation.
+	// +optional
+	GeneratedSecrets map[string]GeneratedSecretPolicy `json:"generatedSecrets,omitempty"`
+}
+
+// RotateGeneratedSecretAnnotation, set to any value on a generated Secret,
+// rotates it on the next reconcile. The operator removes it afterwards.
+const RotateGeneratedSecretAnnotation = "weightsandbiases.apps.wandb.com/rotate"
+
+// DefaultGeneratedSecretGracePeriod is how long a rotated-out value is kept
+// when a policy does not set gracePeriod.
+const DefaultGeneratedSecretGracePeriod = 24 * time.Hour
+
+// GeneratedSecretPolicy controls rotation of one generated secret. A rotation
+// moves the current value to the "previous" key, writes a new value to "key"
+// and rolls the applications that consume it.
+type GeneratedSecretPolicy struct {
+	// RotationInterval rotates the secret once its value is older than this,
+	// e.g. "2160h" for 90 days. Unset disables scheduled rotation.
+	// +optional
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+
+	// GracePeriod is how long the replaced value stays in the "previous" key,
+	// so tokens signed with it are still accepted while pods roll. Defaults
+	// to 24h; "0s" drops it immediately.
+	// +optional
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+}
+
+// ManifestVerificationSpec lists the public keys trusted to sign the server
+// manifest. Signatures are cosign key-based signatures attached to the
+// manifest as OCI referrers.
+type ManifestVerificationSpec struct {
+	// +kubebuilder:validation:MinItems=1
+	PublicKeys []ManifestPublicKeySource `json:"publicKeys"`
+}
+
+// ManifestPublicKeySource references PEM encoded public keys in a ConfigMap
+// or Secret in the CR namespace. A key may hold several PEM blocks.
+// +kubebuilder:validation:XValidation:rule="has(self.configMapKeyRef) != has(self.secretKeyRef)",message="exactly one of configMapKeyRef or secretKeyRef must be set"
+type ManifestPublicKeySource struct {
+	// +optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// +optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
 }
 
 type WandbApplicationOverride struct {
 	// +optional
 	Autoscaling *ApplicationAutoscalingOverride `json:"autoscaling,omitempty"`
+
+	// RolloutStrategy replaces spec.wandb.rolloutStrategy for this application.
+	// +optional
+	RolloutStrategy *RolloutStrategySpec `json:"rolloutStrategy,omitempty"`
 }
 
 // +kubebuilder:validation:XValidation:rule="!has(self.minReplicas) || !has(self.maxReplicas) || self.minReplicas <= self.maxReplicas",message="minReplicas must be <= maxReplicas"
@@ -559,6 +893,22 @@ type ManagedInfraSpec struct {
 	Tolerations *[]corev1.Toleration `json:"tolerations,omitempty"`
 }
 
+// InfraTLSSpec serves a managed instance over TLS. The server certificate is
+// issued through cert-manager when certManager is set, and otherwise by a CA
+// the operator creates and keeps in the "<cr-name>-infra-ca" Secret. The CA is
+// published as the connection's sslCa, so applications trust it through the
+// same mounts as an external instance's CA.
+type InfraTLSSpec struct {
+	Enabled bool `json:"enabled"`
+
+	// CertManager issues the server certificate through a cert-manager Issuer
+	// or ClusterIssuer instead of the operator CA. The issuer must populate
+	// ca.crt in the certificate Secret (e.g. a CA or Vault issuer); public
+	// ACME issuers cannot sign in-cluster service names.
+	// +optional
+	CertManager *CertManagerConfig `json:"certManager,omitempty"`
+}
+
 // MySQLSpec fields have many default values that, if unspecified,
 // will be applied by a defaulting webook
 type MySQLSpec struct {
@@ -575,6 +925,62 @@ type ManagedMysqlSpec struct {
 	Namespace   string      `json:"namespace,omitempty"`
 	Name        string      `json:"name,omitempty"`
 	Telemetry   Telemetry   `json:"telemetry,omitempty"`
+
+	// StorageClassName selects the StorageClass for the instance's volumes.
+	// Empty uses the cluster default. It cannot be changed once volumes exist.
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// TLS serves MySQL over TLS with an operator- or cert-manager-issued
+	// server certificate.
+	// +optional
+	TLS *InfraTLSSpec `json:"tls,omitempty"`
+
+	// Backup schedules MOCO backups of the instance to an object store.
+	// +optional
+	Backup *MySQLBackupSpec `json:"backup,omitempty"`
+
+	// Restore bootstraps a new instance from MOCO backups in an object
+	// store. It only takes effect when the cluster is created.
+	// +optional
+	Restore *MySQLRestoreSpec `json:"restore,omitempty"`
+}
+
+// MySQLBackupSpec schedules MOCO backups: a full dump plus the binary logs
+// written since the previous backup, stored under
+// moco/<namespace>/<name>/ in the object store's bucket.
+type MySQLBackupSpec struct {
+	// Schedule is a standard cron expression, e.g. "0 3 * * *".
+	Schedule string `json:"schedule"`
+
+	// Retention is how many of the newest backups to keep in the bucket;
+	// older ones are deleted on the same schedule. Zero keeps every backup.
+	// +optional
+	Retention int32 `json:"retention,omitempty"`
+
+	// ObjectStore is the spec.objectStore instance to back up to. Defaults
+	// to the default instance. Only S3-compatible object stores are
+	// supported.
+	// +optional
+	ObjectStore string `json:"objectStore,omitempty"`
+}
+
+// MySQLRestoreSpec points a new instance at the backups of a source cluster.
+// MOCO loads the newest full dump taken before PointInTime and replays the
+// binary logs up to it.
+type MySQLRestoreSpec struct {
+	// ObjectStore is the spec.objectStore instance holding the backups.
+	// Defaults to the default instance.
+	// +optional
+	ObjectStore string `json:"objectStore,omitempty"`
+
+	// Prefix is the source cluster's backup location in the bucket, in the
+	// moco/<namespace>/<name>/ layout MOCO writes.
+	Prefix string `json:"prefix"`
+
+	// PointInTime is the time to restore to. Defaults to the time the
+	// cluster is created, i.e. the latest recoverable state.
+	// +optional
+	PointInTime *metav1.Time `json:"pointInTime,omitempty"`
 }
 
 type MysqlConnection struct {
@@ -597,6 +1003,44 @@ type MysqlConnection struct {
 
 type MySQLConfig struct {
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Mysqld tunes the mysqld server through the MOCO my.cnf ConfigMap.
+	// Settings left unset fall back to the manifest's sizing defaults, then to
+	// MOCO's own defaults.
+	// +optional
+	Mysqld *MysqldConfig `json:"mysqld,omitempty"`
+}
+
+// MysqldConfig holds mysqld settings. The typed fields cover the common
+// tuning knobs; Settings passes any other option through by its my.cnf name
+// and loses to a typed field naming the same option. Options MOCO manages
+// for replication (see MysqldSettingProtected) are rejected.
+type MysqldConfig struct {
+	// InnodbBufferPoolSize sets innodb_buffer_pool_size. MOCO defaults it to
+	// 70% of the memory request.
+	// +optional
+	InnodbBufferPoolSize *resource.Quantity `json:"innodbBufferPoolSize,omitempty"`
+
+	// MaxConnections sets max_connections.
+	// +optional
+	MaxConnections *int32 `json:"maxConnections,omitempty"`
+
+	// SlowQueryLog sets slow_query_log. MOCO enables it by default.
+	// +optional
+	SlowQueryLog *bool `json:"slowQueryLog,omitempty"`
+
+	// LongQueryTime sets long_query_time, in seconds; fractions are allowed.
+	// +optional
+	LongQueryTime string `json:"longQueryTime,omitempty"`
+
+	// BinlogExpireLogsSeconds sets binlog_expire_logs_seconds, how long
+	// binary logs are kept.
+	// +optional
+	BinlogExpireLogsSeconds *int64 `json:"binlogExpireLogsSeconds,omitempty"`
+
+	// Settings are further mysqld options, keyed by option name.
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
 }
 
 // Telemetry defines telemetry configuration for infrastructure components
@@ -620,6 +1064,15 @@ type ManagedRedisSpec struct {
 	Namespace   string            `json:"namespace,omitempty"`
 	Name        string            `json:"name,omitempty"`
 	Telemetry   Telemetry         `json:"telemetry,omitempty"`
+
+	// StorageClassName selects the StorageClass for the instance's volumes.
+	// Empty uses the cluster default. It cannot be changed once volumes exist.
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// TLS serves Redis, and Sentinel when enabled, over TLS with an operator-
+	// or cert-manager-issued server certificate.
+	// +optional
+	TLS *InfraTLSSpec `json:"tls,omitempty"`
 }
 
 type RedisConnection struct {
@@ -647,9 +1100,11 @@ type RedisSentinelConfig struct {
 }
 
 // KafkaSpec defines the desired state of the Kafka infrastructure component.
-// Kafka is managed-only (backed by Bufstream); there is no external Kafka option.
+// Managed Kafka is backed by Bufstream; externalKafka points the apps at an
+// existing Kafka-compatible cluster (e.g. MSK or Confluent) instead.
 type KafkaSpec struct {
-	ManagedKafka *ManagedKafkaSpec `json:"managedKafka,omitempty"`
+	ManagedKafka  *ManagedKafkaSpec `json:"managedKafka,omitempty"`
+	ExternalKafka *KafkaConnection  `json:"externalKafka,omitempty"`
 }
 
 type ManagedKafkaSpec struct {
@@ -664,14 +1119,29 @@ type ManagedKafkaSpec struct {
 	// ServiceAccount configures the identity used by the Bufstream broker.
 	ServiceAccount   ManagedServiceAccountSpec `json:"serviceAccount,omitempty"`
 	SkipDataRecovery bool                      `json:"skipDataRecovery,omitempty"`
+
+	// StorageClassName selects the StorageClass for the instance's volumes.
+	// Empty uses the cluster default. It cannot be changed once volumes exist.
+	StorageClassName string `json:"storageClassName,omitempty"`
 }
 
 type KafkaConnection struct {
-	Host           corev1.SecretKeySelector `json:"host,omitempty"`
-	Port           corev1.SecretKeySelector `json:"port,omitempty"`
+	Host corev1.SecretKeySelector `json:"host,omitempty"`
+	Port corev1.SecretKeySelector `json:"port,omitempty"`
+	// BrokerEndpoint is a comma-separated list of bootstrap host:port pairs.
+	// For externalKafka it may be given instead of host and port.
 	BrokerEndpoint corev1.SecretKeySelector `json:"brokerEndpoint,omitempty"`
 	ClusterID      corev1.SecretKeySelector `json:"clusterID,omitempty"`
 
+	// optional, externalKafka only
+	// SaslMechanism is one of PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512. Defaults
+	// to SCRAM-SHA-512 when a username is set.
+	SaslMechanism corev1.SecretKeySelector `json:"saslMechanism,omitempty"`
+	Username      corev1.SecretKeySelector `json:"username,omitempty"`
+	Password      corev1.SecretKeySelector `json:"password,omitempty"`
+	Tls           corev1.SecretKeySelector `json:"tls,omitempty"`
+	SslCa         corev1.SecretKeySelector `json:"sslCa,omitempty"`
+
 	URL corev1.SecretKeySelector `json:"url,omitempty"`
 }
 
@@ -706,6 +1176,11 @@ type ManagedObjectStoreSpec struct {
 	Namespace              string                 `json:"namespace,omitempty"`
 	Name                   string                 `json:"name,omitempty"`
 	Telemetry              Telemetry              `json:"telemetry,omitempty"`
+
+	// StorageClassName selects the StorageClass for the volume servers' data
+	// disks, and for the filer unless SeaweedObjectStoreSpec overrides it. Empty
+	// uses the cluster default. It cannot be changed once volumes exist.
+	StorageClassName string `json:"storageClassName,omitempty"`
 }
 
 type SeaweedObjectStoreSpec struct {
@@ -714,6 +1189,9 @@ type SeaweedObjectStoreSpec struct {
 	// number of objects, not their total size, so bump it for large object counts.
 	// Defaults to 20Gi when unset.
 	FilerStorageSize string `json:"filerStorageSize,omitempty"`
+	// FilerStorageClassName selects the StorageClass for the filer's metadata
+	// disk. Defaults to the instance's storageClassName.
+	FilerStorageClassName string `json:"filerStorageClassName,omitempty"`
 }
 
 // ObjectStoreProvider selects the object store backend for an external object store.
@@ -781,6 +1259,64 @@ type ManagedClickHouseSpec struct {
 	// Keeper configures the ClickHouse Keeper ensemble that coordinates
 	// ReplicatedMergeTree replication across ClickHouse replicas.
 	Keeper ClickHouseKeeperSpec `json:"keeper,omitempty"`
+
+	// StorageClassName selects the StorageClass for the server volumes, and for
+	// Keeper unless Keeper sets its own. Empty uses the cluster default. It
+	// cannot be changed once volumes exist.
+	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// TLS serves the native and HTTP interfaces on their secure ports with an
+	// operator- or cert-manager-issued server certificate and publishes those
+	// ports to applications. The plaintext ports stay open in-cluster for the
+	// Altinity operator.
+	// +optional
+	TLS *InfraTLSSpec `json:"tls,omitempty"`
+
+	// Backup schedules backups of the W&B database to an object store.
+	// +optional
+	Backup *ClickHouseBackupSpec `json:"backup,omitempty"`
+
+	// RestoreFrom loads the W&B database from a backup before the W&B
+	// migrations first run. It has no effect once they have.
+	// +optional
+	RestoreFrom *ClickHouseRestoreSpec `json:"restoreFrom,omitempty"`
+}
+
+// ClickHouseBackupSpec schedules BACKUP DATABASE runs, each stored in its own
+// directory under clickhouse-backup/<namespace>/<name>/ in the object store's
+// bucket.
+type ClickHouseBackupSpec struct {
+	// Schedule is a standard cron expression, e.g. "0 3 * * *".
+	Schedule string `json:"schedule"`
+
+	// Retention is how many of the newest backups to keep in the bucket;
+	// older ones are deleted after each backup. Zero keeps every backup.
+	// +optional
+	Retention int32 `json:"retention,omitempty"`
+
+	// ObjectStore is the spec.objectStore instance to back up to. Defaults
+	// to the default instance. Only S3-compatible object stores are
+	// supported.
+	// +optional
+	ObjectStore string `json:"objectStore,omitempty"`
+}
+
+// ClickHouseRestoreSpec points a new instance at the backups of a source
+// instance.
+type ClickHouseRestoreSpec struct {
+	// ObjectStore is the spec.objectStore instance holding the backups.
+	// Defaults to the default instance.
+	// +optional
+	ObjectStore string `json:"objectStore,omitempty"`
+
+	// Prefix is the source instance's backup location in the bucket, in the
+	// clickhouse-backup/<namespace>/<name>/ layout the backup job writes.
+	Prefix string `json:"prefix"`
+
+	// Backup is the directory of the backup to restore, e.g.
+	// "20261001T030000Z". Defaults to the newest backup under Prefix.
+	// +optional
+	Backup string `json:"backup,omitempty"`
 }
 
 // ClickHouseObjectStorageSpec configures object-store-backed storage for managed
@@ -808,6 +1344,10 @@ type ClickHouseKeeperSpec struct {
 	// and snapshots. Keeper state is small; defaults to a modest value.
 	StorageSize string `json:"storageSize,omitempty"`
 
+	// StorageClassName selects the StorageClass for the Keeper volumes.
+	// Defaults to the ClickHouse instance's storageClassName.
+	StorageClassName string `json:"storageClassName,omitempty"`
+
 	// Config holds resource requirements for the Keeper pods.
 	Config ClickHouseConfig `json:"config,omitempty"`
 }
@@ -820,6 +1360,10 @@ type ClickHouseConnection struct {
 	Username corev1.SecretKeySelector `json:"username,omitempty"`
 	Password corev1.SecretKeySelector `json:"password,omitempty"`
 
+	// optional
+	Tls   corev1.SecretKeySelector `json:"tls,omitempty"`
+	SslCa corev1.SecretKeySelector `json:"sslCa,omitempty"`
+
 	URL corev1.SecretKeySelector `json:"url,omitempty"`
 
 	// Replicated tells applications whether to create ReplicatedMergeTree tables.
@@ -854,8 +1398,8 @@ type WeightsAndBiasesStatus struct {
 	// from the server manifest's generatedSecrets section. The key is the
 	// logical secret name from the manifest, and the value is a SecretKeySelector
 	// referencing the concrete Secret and key that holds the generated value.
-	GeneratedSecrets   map[string]corev1.SecretKeySelector `json:"generatedSecrets,omitempty"`
-	ObservedGeneration int64                               `json:"observedGeneration"`
+	GeneratedSecrets   map[string]GeneratedSecretStatus `json:"generatedSecrets,omitempty"`
+	ObservedGeneration int64                            `json:"observedGeneration"`
 
 	// +optional
 	GatewayStatus *GatewayStatusSummary `json:"gatewayStatus,omitempty"`
@@ -863,6 +1407,15 @@ type WeightsAndBiasesStatus struct {
 	IngressStatus *IngressStatusSummary `json:"ingressStatus,omitempty"`
 }
 
+// GeneratedSecretStatus references a generated secret's current value.
+type GeneratedSecretStatus struct {
+	corev1.SecretKeySelector `json:",inline"`
+
+	// LastRotated is when the current value was generated.
+	// +optional
+	LastRotated *metav1.Time `json:"lastRotated,omitempty"`
+}
+
 type GatewayStatusSummary struct {
 	Name       string            `json:"name,omitempty"`
 	Ready      bool              `json:"ready,omitempty"`
@@ -873,6 +1426,28 @@ type GatewayStatusSummary struct {
 type IngressStatusSummary struct {
 	Name                string                       `json:"name,omitempty"`
 	LoadBalancerIngress []corev1.LoadBalancerIngress `json:"loadBalancerIngress,omitempty"`
+
+	// Hosts breaks the status down per hostname, covering the primary
+	// Ingress's hostnames and each spec.networking.hosts entry.
+	// +optional
+	Hosts []IngressHostStatus `json:"hosts,omitempty"`
+}
+
+type IngressHostStatus struct {
+	Hostname string `json:"hostname"`
+
+	// Ingress is the name of the Ingress serving the host.
+	Ingress string `json:"ingress,omitempty"`
+
+	// TLSSecretName is the certificate secret the host is served with, if any.
+	// +optional
+	TLSSecretName string `json:"tlsSecretName,omitempty"`
+
+	// Paths is the number of paths routed for the host.
+	Paths int32 `json:"paths,omitempty"`
+
+	// +optional
+	LoadBalancerIngress []corev1.LoadBalancerIngress `json:"loadBalancerIngress,omitempty"`
 }
 
 type WandbStatus struct {
@@ -919,6 +1494,10 @@ type WBInfraStatus struct {
 type MysqlInfraStatus struct {
 	WBInfraStatus `json:",inline"`
 	Connection    MysqlConnection `json:"connection,omitempty"`
+
+	// LastBackupTime is when the last successful backup was taken.
+	// +optional
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
 }
 
 type RedisInfraStatus struct {
@@ -939,6 +1518,10 @@ type ObjectStoreInfraStatus struct {
 type ClickHouseInfraStatus struct {
 	WBInfraStatus `json:",inline"`
 	Connection    ClickHouseConnection `json:"connection,omitempty"`
+
+	// LastBackupTime is when the last successful backup was taken.
+	// +optional
+	LastBackupTime *metav1.Time `json:"lastBackupTime,omitempty"`
 }
 
 type TelemetryInfraStatus struct {
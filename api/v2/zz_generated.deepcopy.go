@@ -31,6 +31,7 @@ import (
 	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	apisv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
@@ -179,6 +180,11 @@ func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
 		*out = new(HTTPRouteTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(RolloutStrategySpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSpec.
@@ -201,6 +207,11 @@ func (in *ApplicationStatus) DeepCopyInto(out *ApplicationStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.DaemonSetStatus != nil {
+		in, out := &in.DaemonSetStatus, &out.DaemonSetStatus
+		*out = new(appsv1.DaemonSetStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.DeploymentStatus != nil {
 		in, out := &in.DeploymentStatus, &out.DeploymentStatus
 		*out = new(appsv1.DeploymentStatus)
@@ -243,6 +254,16 @@ func (in *ApplicationStatus) DeepCopyInto(out *ApplicationStatus) {
 		*out = new(HTTPRouteStatusSummary)
 		**out = **in
 	}
+	if in.ScaledObjectStatus != nil {
+		in, out := &in.ScaledObjectStatus, &out.ScaledObjectStatus
+		*out = new(ScaledObjectStatusSummary)
+		**out = **in
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatusSummary)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationStatus.
@@ -255,6 +276,72 @@ func (in *ApplicationStatus) DeepCopy() *ApplicationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackgroundAnalysisSpec) DeepCopyInto(out *BackgroundAnalysisSpec) {
+	*out = *in
+	in.RolloutAnalysisSpec.DeepCopyInto(&out.RolloutAnalysisSpec)
+	if in.StartingStep != nil {
+		in, out := &in.StartingStep, &out.StartingStep
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackgroundAnalysisSpec.
+func (in *BackgroundAnalysisSpec) DeepCopy() *BackgroundAnalysisSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BackgroundAnalysisSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryPauseSpec) DeepCopyInto(out *CanaryPauseSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryPauseSpec.
+func (in *CanaryPauseSpec) DeepCopy() *CanaryPauseSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryPauseSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryStepSpec) DeepCopyInto(out *CanaryStepSpec) {
+	*out = *in
+	if in.SetWeight != nil {
+		in, out := &in.SetWeight, &out.SetWeight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = new(CanaryPauseSpec)
+		**out = **in
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(RolloutAnalysisSpec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryStepSpec.
+func (in *CanaryStepSpec) DeepCopy() *CanaryStepSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryStepSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertManagerConfig) DeepCopyInto(out *CertManagerConfig) {
 	*out = *in
@@ -270,6 +357,21 @@ func (in *CertManagerConfig) DeepCopy() *CertManagerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseBackupSpec) DeepCopyInto(out *ClickHouseBackupSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseBackupSpec.
+func (in *ClickHouseBackupSpec) DeepCopy() *ClickHouseBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClickHouseConfig) DeepCopyInto(out *ClickHouseConfig) {
 	*out = *in
@@ -295,6 +397,8 @@ func (in *ClickHouseConnection) DeepCopyInto(out *ClickHouseConnection) {
 	in.Database.DeepCopyInto(&out.Database)
 	in.Username.DeepCopyInto(&out.Username)
 	in.Password.DeepCopyInto(&out.Password)
+	in.Tls.DeepCopyInto(&out.Tls)
+	in.SslCa.DeepCopyInto(&out.SslCa)
 	in.URL.DeepCopyInto(&out.URL)
 	in.Replicated.DeepCopyInto(&out.Replicated)
 	in.ClusterName.DeepCopyInto(&out.ClusterName)
@@ -315,6 +419,10 @@ func (in *ClickHouseInfraStatus) DeepCopyInto(out *ClickHouseInfraStatus) {
 	*out = *in
 	in.WBInfraStatus.DeepCopyInto(&out.WBInfraStatus)
 	in.Connection.DeepCopyInto(&out.Connection)
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseInfraStatus.
@@ -358,6 +466,21 @@ func (in *ClickHouseObjectStorageSpec) DeepCopy() *ClickHouseObjectStorageSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClickHouseRestoreSpec) DeepCopyInto(out *ClickHouseRestoreSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClickHouseRestoreSpec.
+func (in *ClickHouseRestoreSpec) DeepCopy() *ClickHouseRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClickHouseRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClickHouseSpec) DeepCopyInto(out *ClickHouseSpec) {
 	*out = *in
@@ -436,6 +559,13 @@ func (in *GatewayAPIConfig) DeepCopyInto(out *GatewayAPIConfig) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Routing != nil {
+		in, out := &in.Routing, &out.Routing
+		*out = make(map[string]GatewayRouting, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayAPIConfig.
@@ -448,6 +578,26 @@ func (in *GatewayAPIConfig) DeepCopy() *GatewayAPIConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayBackend) DeepCopyInto(out *GatewayBackend) {
+	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayBackend.
+func (in *GatewayBackend) DeepCopy() *GatewayBackend {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayBackend)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayConfig) DeepCopyInto(out *GatewayConfig) {
 	*out = *in
@@ -487,6 +637,21 @@ func (in *GatewayConfig) DeepCopy() *GatewayConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayHeaderMatch) DeepCopyInto(out *GatewayHeaderMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayHeaderMatch.
+func (in *GatewayHeaderMatch) DeepCopy() *GatewayHeaderMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayHeaderMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayListener) DeepCopyInto(out *GatewayListener) {
 	*out = *in
@@ -512,6 +677,26 @@ func (in *GatewayListener) DeepCopy() *GatewayListener {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayMirror) DeepCopyInto(out *GatewayMirror) {
+	*out = *in
+	if in.Percent != nil {
+		in, out := &in.Percent, &out.Percent
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayMirror.
+func (in *GatewayMirror) DeepCopy() *GatewayMirror {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayMirror)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayReference) DeepCopyInto(out *GatewayReference) {
 	*out = *in
@@ -527,6 +712,137 @@ func (in *GatewayReference) DeepCopy() *GatewayReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayRouteActions) DeepCopyInto(out *GatewayRouteActions) {
+	*out = *in
+	if in.ServiceWeight != nil {
+		in, out := &in.ServiceWeight, &out.ServiceWeight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Backends != nil {
+		in, out := &in.Backends, &out.Backends
+		*out = make([]GatewayBackend, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(GatewayMirror)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeouts != nil {
+		in, out := &in.Timeouts, &out.Timeouts
+		*out = new(GatewayRouteTimeouts)
+		**out = **in
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(GatewayRouteRetry)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRouteActions.
+func (in *GatewayRouteActions) DeepCopy() *GatewayRouteActions {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayRouteActions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayRouteRetry) DeepCopyInto(out *GatewayRouteRetry) {
+	*out = *in
+	if in.Attempts != nil {
+		in, out := &in.Attempts, &out.Attempts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Codes != nil {
+		in, out := &in.Codes, &out.Codes
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRouteRetry.
+func (in *GatewayRouteRetry) DeepCopy() *GatewayRouteRetry {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayRouteRetry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayRouteRule) DeepCopyInto(out *GatewayRouteRule) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]GatewayHeaderMatch, len(*in))
+		copy(*out, *in)
+	}
+	in.GatewayRouteActions.DeepCopyInto(&out.GatewayRouteActions)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRouteRule.
+func (in *GatewayRouteRule) DeepCopy() *GatewayRouteRule {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayRouteRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayRouteTimeouts) DeepCopyInto(out *GatewayRouteTimeouts) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRouteTimeouts.
+func (in *GatewayRouteTimeouts) DeepCopy() *GatewayRouteTimeouts {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayRouteTimeouts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GatewayRouting) DeepCopyInto(out *GatewayRouting) {
+	*out = *in
+	in.GatewayRouteActions.DeepCopyInto(&out.GatewayRouteActions)
+	if in.Rules != nil {
+		in, out := &in.Rules, &out.Rules
+		*out = make([]GatewayRouteRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GatewayRouting.
+func (in *GatewayRouting) DeepCopy() *GatewayRouting {
+	if in == nil {
+		return nil
+	}
+	out := new(GatewayRouting)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GatewayStatusSummary) DeepCopyInto(out *GatewayStatusSummary) {
 	*out = *in
@@ -552,6 +868,51 @@ func (in *GatewayStatusSummary) DeepCopy() *GatewayStatusSummary {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratedSecretPolicy) DeepCopyInto(out *GeneratedSecretPolicy) {
+	*out = *in
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratedSecretPolicy.
+func (in *GeneratedSecretPolicy) DeepCopy() *GeneratedSecretPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratedSecretPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratedSecretStatus) DeepCopyInto(out *GeneratedSecretStatus) {
+	*out = *in
+	in.SecretKeySelector.DeepCopyInto(&out.SecretKeySelector)
+	if in.LastRotated != nil {
+		in, out := &in.LastRotated, &out.LastRotated
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratedSecretStatus.
+func (in *GeneratedSecretStatus) DeepCopy() *GeneratedSecretStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratedSecretStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GlobalSpec) DeepCopyInto(out *GlobalSpec) {
 	*out = *in
@@ -607,9 +968,101 @@ func (in *HTTPRouteTemplateSpec) DeepCopyInto(out *HTTPRouteTemplateSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Hostnames != nil {
-		in, out := &in.Hostnames, &out.Hostnames
-		*out = make([]apisv1.Hostname, len(*in))
+	if in.Hostnames != nil {
+		in, out := &in.Hostnames, &out.Hostnames
+		*out = make([]apisv1.Hostname, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServicePort != nil {
+		in, out := &in.ServicePort, &out.ServicePort
+		*out = new(apisv1.PortNumber)
+		**out = **in
+	}
+	if in.Routing != nil {
+		in, out := &in.Routing, &out.Routing
+		*out = new(GatewayRouting)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteTemplateSpec.
+func (in *HTTPRouteTemplateSpec) DeepCopy() *HTTPRouteTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRouteTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InfraTLSSpec) DeepCopyInto(out *InfraTLSSpec) {
+	*out = *in
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(CertManagerConfig)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InfraTLSSpec.
+func (in *InfraTLSSpec) DeepCopy() *InfraTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InfraTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressConfig) DeepCopyInto(out *IngressConfig) {
+	*out = *in
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressConfig.
+func (in *IngressConfig) DeepCopy() *IngressConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressHost) DeepCopyInto(out *IngressHost) {
+	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.IngressClassName != nil {
+		in, out := &in.IngressClassName, &out.IngressClassName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Applications != nil {
+		in, out := &in.Applications, &out.Applications
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 	if in.Paths != nil {
@@ -617,39 +1070,36 @@ func (in *HTTPRouteTemplateSpec) DeepCopyInto(out *HTTPRouteTemplateSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.ServicePort != nil {
-		in, out := &in.ServicePort, &out.ServicePort
-		*out = new(apisv1.PortNumber)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRouteTemplateSpec.
-func (in *HTTPRouteTemplateSpec) DeepCopy() *HTTPRouteTemplateSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressHost.
+func (in *IngressHost) DeepCopy() *IngressHost {
 	if in == nil {
 		return nil
 	}
-	out := new(HTTPRouteTemplateSpec)
+	out := new(IngressHost)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IngressConfig) DeepCopyInto(out *IngressConfig) {
+func (in *IngressHostStatus) DeepCopyInto(out *IngressHostStatus) {
 	*out = *in
-	if in.IngressClassName != nil {
-		in, out := &in.IngressClassName, &out.IngressClassName
-		*out = new(string)
-		**out = **in
+	if in.LoadBalancerIngress != nil {
+		in, out := &in.LoadBalancerIngress, &out.LoadBalancerIngress
+		*out = make([]v1.LoadBalancerIngress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressConfig.
-func (in *IngressConfig) DeepCopy() *IngressConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressHostStatus.
+func (in *IngressHostStatus) DeepCopy() *IngressHostStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(IngressConfig)
+	out := new(IngressHostStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -664,6 +1114,13 @@ func (in *IngressStatusSummary) DeepCopyInto(out *IngressStatusSummary) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]IngressHostStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressStatusSummary.
@@ -720,6 +1177,11 @@ func (in *KafkaConnection) DeepCopyInto(out *KafkaConnection) {
 	in.Port.DeepCopyInto(&out.Port)
 	in.BrokerEndpoint.DeepCopyInto(&out.BrokerEndpoint)
 	in.ClusterID.DeepCopyInto(&out.ClusterID)
+	in.SaslMechanism.DeepCopyInto(&out.SaslMechanism)
+	in.Username.DeepCopyInto(&out.Username)
+	in.Password.DeepCopyInto(&out.Password)
+	in.Tls.DeepCopyInto(&out.Tls)
+	in.SslCa.DeepCopyInto(&out.SslCa)
 	in.URL.DeepCopyInto(&out.URL)
 }
 
@@ -773,6 +1235,11 @@ func (in *KafkaSpec) DeepCopyInto(out *KafkaSpec) {
 		*out = new(ManagedKafkaSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExternalKafka != nil {
+		in, out := &in.ExternalKafka, &out.ExternalKafka
+		*out = new(KafkaConnection)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSpec.
@@ -846,6 +1313,21 @@ func (in *ManagedClickHouseSpec) DeepCopyInto(out *ManagedClickHouseSpec) {
 	in.ServiceAccount.DeepCopyInto(&out.ServiceAccount)
 	out.ObjectStorage = in.ObjectStorage
 	in.Keeper.DeepCopyInto(&out.Keeper)
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(InfraTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(ClickHouseBackupSpec)
+		**out = **in
+	}
+	if in.RestoreFrom != nil {
+		in, out := &in.RestoreFrom, &out.RestoreFrom
+		*out = new(ClickHouseRestoreSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClickHouseSpec.
@@ -919,6 +1401,21 @@ func (in *ManagedMysqlSpec) DeepCopyInto(out *ManagedMysqlSpec) {
 	in.ManagedInfraSpec.DeepCopyInto(&out.ManagedInfraSpec)
 	in.Config.DeepCopyInto(&out.Config)
 	out.Telemetry = in.Telemetry
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(InfraTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
+		in, out := &in.Backup, &out.Backup
+		*out = new(MySQLBackupSpec)
+		**out = **in
+	}
+	if in.Restore != nil {
+		in, out := &in.Restore, &out.Restore
+		*out = new(MySQLRestoreSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedMysqlSpec.
@@ -957,6 +1454,11 @@ func (in *ManagedRedisSpec) DeepCopyInto(out *ManagedRedisSpec) {
 	in.Config.DeepCopyInto(&out.Config)
 	in.Sentinel.DeepCopyInto(&out.Sentinel)
 	out.Telemetry = in.Telemetry
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(InfraTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRedisSpec.
@@ -996,6 +1498,53 @@ func (in *ManagedServiceAccountSpec) DeepCopy() *ManagedServiceAccountSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestPublicKeySource) DeepCopyInto(out *ManifestPublicKeySource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(v1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestPublicKeySource.
+func (in *ManifestPublicKeySource) DeepCopy() *ManifestPublicKeySource {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestPublicKeySource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManifestVerificationSpec) DeepCopyInto(out *ManifestVerificationSpec) {
+	*out = *in
+	if in.PublicKeys != nil {
+		in, out := &in.PublicKeys, &out.PublicKeys
+		*out = make([]ManifestPublicKeySource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManifestVerificationSpec.
+func (in *ManifestVerificationSpec) DeepCopy() *ManifestVerificationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ManifestVerificationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MigrationJobStatus) DeepCopyInto(out *MigrationJobStatus) {
 	*out = *in
@@ -1011,10 +1560,30 @@ func (in *MigrationJobStatus) DeepCopy() *MigrationJobStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MySQLBackupSpec) DeepCopyInto(out *MySQLBackupSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQLBackupSpec.
+func (in *MySQLBackupSpec) DeepCopy() *MySQLBackupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MySQLBackupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MySQLConfig) DeepCopyInto(out *MySQLConfig) {
 	*out = *in
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Mysqld != nil {
+		in, out := &in.Mysqld, &out.Mysqld
+		*out = new(MysqldConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQLConfig.
@@ -1027,6 +1596,25 @@ func (in *MySQLConfig) DeepCopy() *MySQLConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MySQLRestoreSpec) DeepCopyInto(out *MySQLRestoreSpec) {
+	*out = *in
+	if in.PointInTime != nil {
+		in, out := &in.PointInTime, &out.PointInTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MySQLRestoreSpec.
+func (in *MySQLRestoreSpec) DeepCopy() *MySQLRestoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MySQLRestoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MySQLSpec) DeepCopyInto(out *MySQLSpec) {
 	*out = *in
@@ -1082,6 +1670,10 @@ func (in *MysqlInfraStatus) DeepCopyInto(out *MysqlInfraStatus) {
 	*out = *in
 	in.WBInfraStatus.DeepCopyInto(&out.WBInfraStatus)
 	in.Connection.DeepCopyInto(&out.Connection)
+	if in.LastBackupTime != nil {
+		in, out := &in.LastBackupTime, &out.LastBackupTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqlInfraStatus.
@@ -1094,6 +1686,75 @@ func (in *MysqlInfraStatus) DeepCopy() *MysqlInfraStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MysqldConfig) DeepCopyInto(out *MysqldConfig) {
+	*out = *in
+	if in.InnodbBufferPoolSize != nil {
+		in, out := &in.InnodbBufferPoolSize, &out.InnodbBufferPoolSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MaxConnections != nil {
+		in, out := &in.MaxConnections, &out.MaxConnections
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SlowQueryLog != nil {
+		in, out := &in.SlowQueryLog, &out.SlowQueryLog
+		*out = new(bool)
+		**out = **in
+	}
+	if in.BinlogExpireLogsSeconds != nil {
+		in, out := &in.BinlogExpireLogsSeconds, &out.BinlogExpireLogsSeconds
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MysqldConfig.
+func (in *MysqldConfig) DeepCopy() *MysqldConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MysqldConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPoliciesConfig) DeepCopyInto(out *NetworkPoliciesConfig) {
+	*out = *in
+	if in.IngressFrom != nil {
+		in, out := &in.IngressFrom, &out.IngressFrom
+		*out = make([]networkingv1.NetworkPolicyPeer, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EgressCIDRs != nil {
+		in, out := &in.EgressCIDRs, &out.EgressCIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPoliciesConfig.
+func (in *NetworkPoliciesConfig) DeepCopy() *NetworkPoliciesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPoliciesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkingSpec) DeepCopyInto(out *NetworkingSpec) {
 	*out = *in
@@ -1119,6 +1780,18 @@ func (in *NetworkingSpec) DeepCopyInto(out *NetworkingSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]IngressHost, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NetworkPolicies != nil {
+		in, out := &in.NetworkPolicies, &out.NetworkPolicies
+		*out = new(NetworkPoliciesConfig)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkingSpec.
@@ -1455,6 +2128,118 @@ func (in *RetentionPolicy) DeepCopy() *RetentionPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutAnalysisArg) DeepCopyInto(out *RolloutAnalysisArg) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutAnalysisArg.
+func (in *RolloutAnalysisArg) DeepCopy() *RolloutAnalysisArg {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutAnalysisArg)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutAnalysisSpec) DeepCopyInto(out *RolloutAnalysisSpec) {
+	*out = *in
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]argoproj_io_rolloutsv1alpha1.AnalysisTemplateRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]RolloutAnalysisArg, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutAnalysisSpec.
+func (in *RolloutAnalysisSpec) DeepCopy() *RolloutAnalysisSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutAnalysisSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatusSummary) DeepCopyInto(out *RolloutStatusSummary) {
+	*out = *in
+	if in.CurrentStep != nil {
+		in, out := &in.CurrentStep, &out.CurrentStep
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatusSummary.
+func (in *RolloutStatusSummary) DeepCopy() *RolloutStatusSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatusSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategySpec) DeepCopyInto(out *RolloutStrategySpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]CanaryStepSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(BackgroundAnalysisSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxSurge != nil {
+		in, out := &in.MaxSurge, &out.MaxSurge
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategySpec.
+func (in *RolloutStrategySpec) DeepCopy() *RolloutStrategySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaledObjectStatusSummary) DeepCopyInto(out *ScaledObjectStatusSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaledObjectStatusSummary.
+func (in *ScaledObjectStatusSummary) DeepCopy() *ScaledObjectStatusSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaledObjectStatusSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SeaweedObjectStoreSpec) DeepCopyInto(out *SeaweedObjectStoreSpec) {
 	*out = *in
@@ -1672,6 +2457,23 @@ func (in *WandbAppSpec) DeepCopyInto(out *WandbAppSpec) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.ManifestVerification != nil {
+		in, out := &in.ManifestVerification, &out.ManifestVerification
+		*out = new(ManifestVerificationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(RolloutStrategySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GeneratedSecrets != nil {
+		in, out := &in.GeneratedSecrets, &out.GeneratedSecrets
+		*out = make(map[string]GeneratedSecretPolicy, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WandbAppSpec.
@@ -1692,6 +2494,11 @@ func (in *WandbApplicationOverride) DeepCopyInto(out *WandbApplicationOverride)
 		*out = new(ApplicationAutoscalingOverride)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RolloutStrategy != nil {
+		in, out := &in.RolloutStrategy, &out.RolloutStrategy
+		*out = new(RolloutStrategySpec)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WandbApplicationOverride.
@@ -1957,7 +2764,7 @@ func (in *WeightsAndBiasesStatus) DeepCopyInto(out *WeightsAndBiasesStatus) {
 	}
 	if in.GeneratedSecrets != nil {
 		in, out := &in.GeneratedSecrets, &out.GeneratedSecrets
-		*out = make(map[string]v1.SecretKeySelector, len(*in))
+		*out = make(map[string]GeneratedSecretStatus, len(*in))
 		for key, val := range *in {
 			(*out)[key] = *val.DeepCopy()
 		}
@@ -27,6 +27,7 @@ import (
 	"syscall"
 
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	"k8s.io/client-go/dynamic"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	"github.com/wandb/operator/internal/crdinstaller"
@@ -39,8 +40,11 @@ import (
 //
 // Subcommands:
 //
-//	render   compose the CRDs and emit YAML to stdout (debugging / dry-run)
-//	apply    compose the CRDs and server-side apply them to the cluster
+//	render          compose the CRDs and emit YAML to stdout (debugging / dry-run)
+//	apply           compose the CRDs and server-side apply them to the cluster
+//	diff            compare the composed CRDs against the cluster (pre-upgrade check)
+//	migrate-storage rewrite operator objects to the storage version and prune
+//	                status.storedVersions
 //
 // Every flag also accepts the matching UPPER_SNAKE_CASE env var. Required
 // flags differ per subcommand — see usage.
@@ -57,6 +61,10 @@ func main() {
 		os.Exit(runRender(args))
 	case "apply":
 		os.Exit(runApply(args))
+	case "diff":
+		os.Exit(runDiff(args))
+	case "migrate-storage":
+		os.Exit(runMigrateStorage(args))
 	case "-h", "--help", "help":
 		usage()
 		os.Exit(0)
@@ -68,11 +76,17 @@ func main() {
 }
 
 func usage() {
-	fmt.Fprint(os.Stderr, `crd-installer — install operator CRDs (server-side apply) or render them to stdout.
+	fmt.Fprint(os.Stderr, `crd-installer — install operator CRDs (server-side apply), render or diff them, or migrate stored objects.
 
 usage:
   crd-installer render [flags]
   crd-installer apply  [flags]
+  crd-installer diff   [flags]
+  crd-installer migrate-storage [--log-level, --log-format]
+
+diff exits 0 when nothing changes, 1 on error, 3 when the cluster differs and
+4 when a change can invalidate existing objects (new required fields, narrower
+enums or bounds, versions still listed in status.storedVersions).
 
 flags (each also accepts the matching UPPER_SNAKE_CASE env var, e.g. CERT_INJECT_REFERENCE):
   --cert-inject-reference     value for cert-manager.io/inject-ca-from on operator CRDs (required)
@@ -202,6 +216,78 @@ func runApply(args []string) int {
 	return 0
 }
 
+func runDiff(args []string) int {
+	c, err := parseSubcommand("diff", args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	logger := setupLogger(c)
+	ctx, stop := signalContext()
+	defer stop()
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		logger.Error("loading kubeconfig", "err", err)
+		return 1
+	}
+	client, err := apiextensionsclient.NewForConfig(cfg)
+	if err != nil {
+		logger.Error("constructing apiextensions client", "err", err)
+		return 1
+	}
+	diffs, err := crdinstaller.Diff(ctx, c.opts, client)
+	if err != nil {
+		logger.Error("diff failed", "err", err)
+		return 1
+	}
+	if err := crdinstaller.WriteDiff(os.Stdout, diffs); err != nil {
+		logger.Error("writing diff", "err", err)
+		return 1
+	}
+	for _, d := range diffs {
+		if d.Tightened() {
+			return 4
+		}
+	}
+	if len(diffs) > 0 {
+		return 3
+	}
+	return 0
+}
+
+func runMigrateStorage(args []string) int {
+	c, err := parseSubcommand("migrate-storage", args)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	logger := setupLogger(c)
+	ctx, stop := signalContext()
+	defer stop()
+
+	cfg, err := config.GetConfig()
+	if err != nil {
+		logger.Error("loading kubeconfig", "err", err)
+		return 1
+	}
+	client, err := apiextensionsclient.NewForConfig(cfg)
+	if err != nil {
+		logger.Error("constructing apiextensions client", "err", err)
+		return 1
+	}
+	dyn, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		logger.Error("constructing dynamic client", "err", err)
+		return 1
+	}
+	if err := crdinstaller.MigrateStorage(ctx, client, dyn, logger); err != nil {
+		logger.Error("migrate-storage failed", "err", err)
+		return 1
+	}
+	return 0
+}
+
 func signalContext() (context.Context, context.CancelFunc) {
 	return signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 }
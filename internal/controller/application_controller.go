@@ -20,22 +20,28 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strconv"
 
 	gkeGatewayApiNetworkingv1 "github.com/GoogleCloudPlatform/gke-gateway-api/apis/networking/v1"
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	wandbv2 "github.com/wandb/operator/api/v2"
 	"github.com/wandb/operator/internal/controller/common"
 	"github.com/wandb/operator/internal/logx"
+	"github.com/wandb/operator/internal/observability/tracing"
 	"github.com/wandb/operator/pkg/utils"
 	v1alpha1 "github.com/wandb/operator/pkg/vendored/argo-rollouts/argoproj.io.rollouts/v1alpha1"
+	"go.opentelemetry.io/otel/attribute"
 	appsv1 "k8s.io/api/apps/v1"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"knative.dev/pkg/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -56,7 +62,10 @@ type ApplicationReconciler struct {
 // +kubebuilder:rbac:groups=apps.wandb.com,resources=applications,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=apps.wandb.com,resources=applications/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=apps.wandb.com,resources=applications/finalizers,verbs=update
+// +kubebuilder:rbac:groups=argoproj.io,resources=rollouts,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=keda.sh,resources=scaledobjects,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes/status,verbs=get
@@ -72,6 +81,16 @@ type ApplicationReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.20.0/pkg/reconcile
 func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.Start(ctx, "Application.Reconcile",
+		attribute.String("k8s.namespace.name", req.Namespace),
+		attribute.String("application.name", req.Name),
+	)
+	result, err := r.reconcile(ctx, req)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (r *ApplicationReconciler) reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	ctx, logger := logx.WithSlog(ctx, logx.ReconcileAppV2)
 
 	var app wandbv2.Application
@@ -122,6 +141,11 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 					logger.Error("Failed to delete Rollout during finalization", logx.ErrAttr(err))
 					return ctrl.Result{}, err
 				}
+			case "DaemonSet":
+				if err := r.deleteDaemonSet(ctx, &app); err != nil {
+					logger.Error("Failed to delete DaemonSet during finalization", logx.ErrAttr(err))
+					return ctrl.Result{}, err
+				}
 			}
 
 			// Delete Service if present
@@ -136,6 +160,18 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 				return ctrl.Result{}, err
 			}
 
+			// Delete PDB if present
+			if err := r.deletePDB(ctx, &app); err != nil {
+				logger.Error("Failed to delete PDB during finalization", logx.ErrAttr(err))
+				return ctrl.Result{}, err
+			}
+
+			// Delete ScaledObject if present
+			if err := r.deleteScaledObject(ctx, &app); err != nil {
+				logger.Error("Failed to delete ScaledObject during finalization", logx.ErrAttr(err))
+				return ctrl.Result{}, err
+			}
+
 			// Delete Jobs
 			if err := r.deleteJobs(ctx, &app); err != nil {
 				logger.Error("Failed to delete Jobs during finalization", logx.ErrAttr(err))
@@ -206,12 +242,24 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
+	// Reconcile ScaledObject if specified; it supersedes the HPA below
+	if err := r.reconcileScaledObject(ctx, &app); err != nil {
+		logger.Error("Failed to reconcile ScaledObject", logx.ErrAttr(err))
+		return ctrl.Result{}, err
+	}
+
 	// Reconcile HPA if specified
 	if err := r.reconcileHPA(ctx, &app); err != nil {
 		logger.Error("Failed to reconcile HPA", logx.ErrAttr(err))
 		return ctrl.Result{}, err
 	}
 
+	// Reconcile PDB if specified
+	if err := r.reconcilePDB(ctx, &app); err != nil {
+		logger.Error("Failed to reconcile PDB", logx.ErrAttr(err))
+		return ctrl.Result{}, err
+	}
+
 	if err := r.reconcileHTTPRoute(ctx, &app); err != nil {
 		logger.Error("Failed to reconcile HTTPRoute", logx.ErrAttr(err))
 		return ctrl.Result{}, err
@@ -230,7 +278,13 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		}
 	} else if app.Status.RolloutStatus != nil {
 		if app.Status.RolloutStatus.ReadyReplicas == app.Status.RolloutStatus.Replicas &&
-			app.Status.RolloutStatus.Replicas > 0 {
+			app.Status.RolloutStatus.Replicas > 0 &&
+			(app.Status.Rollout == nil || app.Status.Rollout.Phase == string(v1alpha1.RolloutPhaseHealthy)) {
+			app.Status.Ready = true
+		}
+	} else if app.Status.DaemonSetStatus != nil {
+		if app.Status.DaemonSetStatus.NumberReady == app.Status.DaemonSetStatus.DesiredNumberScheduled &&
+			app.Status.DaemonSetStatus.DesiredNumberScheduled > 0 {
 			app.Status.Ready = true
 		}
 	}
@@ -287,11 +341,11 @@ func (r *ApplicationReconciler) reconcileDeployment(ctx context.Context, app *wa
 		MatchLabels: selectorLabels,
 	}
 
-	if app.Spec.HpaTemplate != nil {
+	if minReplicas, autoscaled := r.autoscaledReplicas(app); autoscaled {
 		if deployment.CreationTimestamp.IsZero() {
-			deployment.Spec.Replicas = app.Spec.HpaTemplate.MinReplicas
+			deployment.Spec.Replicas = minReplicas
 		}
-		// Do not update replicas if HPA is managing them
+		// Do not update replicas if an autoscaler is managing them
 	} else {
 		deployment.Spec.Replicas = app.Spec.Replicas
 	}
@@ -321,6 +375,17 @@ func (r *ApplicationReconciler) reconcileDeployment(ctx context.Context, app *wa
 
 	app.Status.DeploymentStatus = &deployment.Status
 
+	// The reverse of reconcileRollout: once the Deployment is ready, drop the
+	// Rollout left behind by a removed rolloutStrategy.
+	if app.Status.RolloutStatus != nil && utils.IsRegistered(r.Scheme, &v1alpha1.Rollout{}) &&
+		deployment.Status.Replicas > 0 && deployment.Status.ReadyReplicas == deployment.Status.Replicas {
+		if err := r.deleteRollout(ctx, app); err != nil {
+			return ctrl.Result{}, err
+		}
+		app.Status.RolloutStatus = nil
+		app.Status.Rollout = nil
+	}
+
 	logger.Info("Successfully reconciled Deployment", "Deployment", deployment.Name)
 	return ctrl.Result{}, nil
 }
@@ -360,6 +425,11 @@ func statefulSetManagedFieldsEqual(before, after *appsv1.StatefulSet) bool {
 		apiequality.Semantic.DeepEqual(before.Spec, after.Spec)
 }
 
+func daemonSetManagedFieldsEqual(before, after *appsv1.DaemonSet) bool {
+	return managedObjectMetadataEqual(before, after) &&
+		apiequality.Semantic.DeepEqual(before.Spec, after.Spec)
+}
+
 // deleteDeployment deletes the Deployment associated with the Application
 func (r *ApplicationReconciler) deleteDeployment(ctx context.Context, app *wandbv2.Application) error {
 	logger := logx.GetSlog(ctx)
@@ -426,12 +496,13 @@ func (r *ApplicationReconciler) reconcileRollout(ctx context.Context, app *wandb
 	rollout.Spec.Selector = &metav1.LabelSelector{
 		MatchLabels: selectorLabels,
 	}
+	rollout.Spec.Strategy = buildRolloutStrategy(app.Spec.RolloutStrategy)
 
-	if app.Spec.HpaTemplate != nil {
+	if minReplicas, autoscaled := r.autoscaledReplicas(app); autoscaled {
 		if rollout.CreationTimestamp.IsZero() {
-			rollout.Spec.Replicas = app.Spec.HpaTemplate.MinReplicas
+			rollout.Spec.Replicas = minReplicas
 		}
-		// Do not update replicas if HPA is managing them
+		// Do not update replicas if an autoscaler is managing them
 	} else {
 		rollout.Spec.Replicas = app.Spec.Replicas
 	}
@@ -460,6 +531,16 @@ func (r *ApplicationReconciler) reconcileRollout(ctx context.Context, app *wandb
 	}
 
 	app.Status.RolloutStatus = &rollout.Status
+	app.Status.Rollout = summarizeRolloutStatus(rollout)
+
+	// An application switched over from a Deployment keeps serving from it
+	// until the Rollout is healthy; only then is the Deployment removed.
+	if rolloutHealthy(rollout) && app.Status.DeploymentStatus != nil {
+		if err := r.deleteDeployment(ctx, app); err != nil {
+			return ctrl.Result{}, err
+		}
+		app.Status.DeploymentStatus = nil
+	}
 
 	logger.Info("Successfully reconciled Rollout", "Rollout", rollout.Name)
 	return ctrl.Result{}, nil
@@ -491,6 +572,87 @@ func (r *ApplicationReconciler) deleteRollout(ctx context.Context, app *wandbv2.
 	return nil
 }
 
+// buildRolloutStrategy converts the Application's canary spec into the Argo
+// strategy. A nil spec yields a canary without steps, which promotes as soon
+// as the new ReplicaSet is available, like a Deployment rolling update.
+func buildRolloutStrategy(spec *wandbv2.RolloutStrategySpec) v1alpha1.RolloutStrategy {
+	canary := &v1alpha1.CanaryStrategy{}
+	if spec == nil {
+		return v1alpha1.RolloutStrategy{Canary: canary}
+	}
+
+	canary.MaxSurge = spec.MaxSurge
+	canary.MaxUnavailable = spec.MaxUnavailable
+	for _, step := range spec.Steps {
+		canaryStep := v1alpha1.CanaryStep{SetWeight: step.SetWeight}
+		if step.Pause != nil {
+			canaryStep.Pause = &v1alpha1.RolloutPause{Duration: rolloutPauseDuration(step.Pause.Duration)}
+		}
+		if step.Analysis != nil {
+			canaryStep.Analysis = buildRolloutAnalysis(*step.Analysis)
+		}
+		canary.Steps = append(canary.Steps, canaryStep)
+	}
+	if spec.Analysis != nil {
+		canary.Analysis = &v1alpha1.RolloutAnalysisBackground{
+			RolloutAnalysis: *buildRolloutAnalysis(spec.Analysis.RolloutAnalysisSpec),
+			StartingStep:    spec.Analysis.StartingStep,
+		}
+	}
+	return v1alpha1.RolloutStrategy{Canary: canary}
+}
+
+func buildRolloutAnalysis(spec wandbv2.RolloutAnalysisSpec) *v1alpha1.RolloutAnalysis {
+	analysis := &v1alpha1.RolloutAnalysis{
+		Templates: append([]v1alpha1.AnalysisTemplateRef(nil), spec.Templates...),
+	}
+	for _, arg := range spec.Args {
+		analysis.Args = append(analysis.Args, v1alpha1.AnalysisRunArgument{Name: arg.Name, Value: arg.Value})
+	}
+	return analysis
+}
+
+// rolloutPauseDuration keeps bare seconds as an integer, which is how Argo
+// itself serializes them, so the spec round-trips without churn.
+func rolloutPauseDuration(duration string) *intstr.IntOrString {
+	if duration == "" {
+		return nil
+	}
+	if seconds, err := strconv.Atoi(duration); err == nil {
+		d := intstr.FromInt32(int32(seconds))
+		return &d
+	}
+	d := intstr.FromString(duration)
+	return &d
+}
+
+// rolloutHealthy reports whether the Argo controller has fully promoted the
+// current spec of rollout.
+func rolloutHealthy(rollout *v1alpha1.Rollout) bool {
+	return rollout.Status.Phase == v1alpha1.RolloutPhaseHealthy &&
+		rollout.Status.ObservedGeneration == strconv.FormatInt(rollout.Generation, 10)
+}
+
+func summarizeRolloutStatus(rollout *v1alpha1.Rollout) *wandbv2.RolloutStatusSummary {
+	status := rollout.Status
+	summary := &wandbv2.RolloutStatusSummary{
+		Phase:       string(status.Phase),
+		CurrentStep: status.CurrentStepIndex,
+		Paused:      status.ControllerPause || len(status.PauseConditions) > 0,
+		Aborted:     status.Abort,
+		Message:     status.Message,
+	}
+	if rollout.Spec.Strategy.Canary != nil {
+		summary.TotalSteps = int32(len(rollout.Spec.Strategy.Canary.Steps))
+	}
+	// A Healthy phase reported for an older generation says nothing about
+	// the spec just written.
+	if status.Phase == v1alpha1.RolloutPhaseHealthy && !rolloutHealthy(rollout) {
+		summary.Phase = string(v1alpha1.RolloutPhaseProgressing)
+	}
+	return summary
+}
+
 // reconcileStatefulSet handles StatefulSet type applications
 func (r *ApplicationReconciler) reconcileStatefulSet(ctx context.Context, app *wandbv2.Application) (ctrl.Result, error) {
 	logger := logx.GetSlog(ctx)
@@ -548,11 +710,11 @@ func (r *ApplicationReconciler) reconcileStatefulSet(ctx context.Context, app *w
 		statefulSet.Spec.VolumeClaimTemplates = templates
 	}
 
-	if app.Spec.HpaTemplate != nil {
+	if minReplicas, autoscaled := r.autoscaledReplicas(app); autoscaled {
 		if statefulSet.CreationTimestamp.IsZero() {
-			statefulSet.Spec.Replicas = app.Spec.HpaTemplate.MinReplicas
+			statefulSet.Spec.Replicas = minReplicas
 		}
-		// Do not update replicas if HPA is managing them
+		// Do not update replicas if an autoscaler is managing them
 	} else {
 		statefulSet.Spec.Replicas = app.Spec.Replicas
 	}
@@ -616,9 +778,98 @@ func (r *ApplicationReconciler) deleteStatefulSet(ctx context.Context, app *wand
 func (r *ApplicationReconciler) reconcileDaemonSet(ctx context.Context, app *wandbv2.Application) (ctrl.Result, error) {
 	logger := logx.GetSlog(ctx)
 	logger.Info("Reconciling DaemonSet", "Application", app.Name)
+
+	daemonSet := &appsv1.DaemonSet{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: app.Name}, daemonSet)
+	before := daemonSet.DeepCopy()
+	if err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			logger.Error("Failed to get DaemonSet", logx.ErrAttr(err))
+			return ctrl.Result{}, err
+		}
+		logger.Info("DaemonSet not found", "DaemonSet", app.Name)
+	}
+
+	selectorLabels := getSelectorLabels(app)
+
+	daemonSet.Name = app.Name
+	daemonSet.Namespace = app.Namespace
+
+	daemonSet.Spec.Template.Spec = r.defaultPodSpec(app.Spec.PodTemplate.Spec)
+	daemonSet.Spec.Template.SetLabels(
+		utils.MergeMapsStringString(
+			daemonSet.Spec.Template.GetLabels(),
+			app.Spec.MetaTemplate.Labels,
+			app.Spec.PodTemplate.GetLabels(),
+			selectorLabels,
+		))
+
+	daemonSet.Spec.Template.SetAnnotations(
+		utils.MergeMapsStringString(
+			daemonSet.Spec.Template.GetAnnotations(),
+			app.Spec.MetaTemplate.Annotations,
+			app.Spec.PodTemplate.GetAnnotations(),
+		))
+
+	daemonSet.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: selectorLabels,
+	}
+
+	if err = controllerutil.SetControllerReference(app, daemonSet, r.Scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	logger.Debug(
+		"Desired DaemonSet",
+		"DaemonSet", daemonSet.Name,
+		"containers", len(daemonSet.Spec.Template.Spec.Containers),
+		"initContainers", len(daemonSet.Spec.Template.Spec.InitContainers),
+	)
+
+	if daemonSet.CreationTimestamp.IsZero() {
+		if err := r.Create(ctx, daemonSet); err != nil {
+			logger.Error("Failed to create DaemonSet", logx.ErrAttr(err))
+			return ctrl.Result{}, err
+		}
+	} else if !daemonSetManagedFieldsEqual(before, daemonSet) {
+		if err := r.Update(ctx, daemonSet); err != nil {
+			logger.Error("Failed to update DaemonSet", logx.ErrAttr(err))
+			return ctrl.Result{}, err
+		}
+	}
+
+	app.Status.DaemonSetStatus = &daemonSet.Status
+
+	logger.Info("Successfully reconciled DaemonSet", "DaemonSet", daemonSet.Name)
 	return ctrl.Result{}, nil
 }
 
+// deleteDaemonSet deletes the DaemonSet associated with the Application
+func (r *ApplicationReconciler) deleteDaemonSet(ctx context.Context, app *wandbv2.Application) error {
+	logger := logx.GetSlog(ctx)
+	logger.Info("Deleting DaemonSet", "Application", app.Name)
+
+	daemonSet := &appsv1.DaemonSet{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: app.Name}, daemonSet)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.Info("DaemonSet not found, nothing to delete", "DaemonSet", app.Name)
+			return nil
+		}
+		logger.Error("Failed to get DaemonSet", logx.ErrAttr(err))
+		return err
+	}
+
+	deletePolicy := client.PropagationPolicy(metav1.DeletePropagationBackground)
+	if err := r.Delete(ctx, daemonSet, deletePolicy); err != nil {
+		logger.Error("Failed to delete DaemonSet", logx.ErrAttr(err), "DaemonSet", app.Name)
+		return err
+	}
+	logger.Info("Successfully deleted DaemonSet", "DaemonSet", app.Name)
+
+	return nil
+}
+
 // reconcileJobs handles multiple Job resources defined in the Application spec
 func (r *ApplicationReconciler) reconcileJobs(ctx context.Context, app *wandbv2.Application) error {
 	logger := logx.GetSlog(ctx)
@@ -980,12 +1231,167 @@ func (r *ApplicationReconciler) deleteService(ctx context.Context, app *wandbv2.
 	return nil
 }
 
+// scaleTargetForKind returns the apiVersion and kind an autoscaler targets for
+// an application workload kind.
+func scaleTargetForKind(appKind string) (string, string, error) {
+	switch appKind {
+	case "Deployment", "StatefulSet":
+		return appsv1.SchemeGroupVersion.String(), appKind, nil
+	case "Rollout":
+		return v1alpha1.SchemeGroupVersion.String(), appKind, nil
+	default:
+		return "", "", fmt.Errorf("%q is not scalable", appKind)
+	}
+}
+
+// autoscaledReplicas reports whether an autoscaler owns the workload's replica
+// count and, if so, the replicas a newly created workload starts with.
+func (r *ApplicationReconciler) autoscaledReplicas(app *wandbv2.Application) (*int32, bool) {
+	if r.scaledObjectManaged(app) {
+		return app.Spec.ScaledObjectTemplate.MinReplicaCount, true
+	}
+	if app.Spec.HpaTemplate != nil {
+		return app.Spec.HpaTemplate.MinReplicas, true
+	}
+	return nil, false
+}
+
+// kedaAvailable reports whether the KEDA ScaledObject API is served by the
+// cluster.
+func (r *ApplicationReconciler) kedaAvailable() bool {
+	return r.Scheme != nil && utils.IsRegistered(r.Scheme, &kedav1alpha1.ScaledObject{})
+}
+
+// scaledObjectManaged reports whether a KEDA ScaledObject scales the
+// application. Without KEDA installed the template is ignored and any HPA
+// template keeps scaling the workload.
+func (r *ApplicationReconciler) scaledObjectManaged(app *wandbv2.Application) bool {
+	return app.Spec.ScaledObjectTemplate != nil && r.kedaAvailable()
+}
+
+// reconcileScaledObject handles the KEDA ScaledObject defined in the Application spec
+func (r *ApplicationReconciler) reconcileScaledObject(ctx context.Context, app *wandbv2.Application) error {
+	logger := logx.GetSlog(ctx)
+
+	if !r.scaledObjectManaged(app) {
+		if app.Spec.ScaledObjectTemplate != nil {
+			logger.Info("KEDA is not installed; skipping ScaledObject", "Application", app.Name)
+		}
+		// If ScaledObject template is not specified, ensure any existing ScaledObject owned by the Application is deleted
+		return r.deleteScaledObject(ctx, app)
+	}
+
+	groupVersion, kind, err := scaleTargetForKind(app.Spec.Kind)
+	if err != nil {
+		return fmt.Errorf("unsupported application kind for ScaledObject: %w", err)
+	}
+
+	desired := &kedav1alpha1.ScaledObject{}
+	desired.Name = app.Name
+	desired.Namespace = app.Namespace
+
+	// Merge labels/annotations from meta template
+	desired.Labels = utils.MergeMapsStringString(
+		desired.Labels,
+		app.Spec.MetaTemplate.Labels,
+	)
+	desired.Annotations = utils.MergeMapsStringString(
+		desired.Annotations,
+		app.Spec.MetaTemplate.Annotations,
+	)
+
+	// Copy spec from template and point it at our application workload
+	desired.Spec = *app.Spec.ScaledObjectTemplate.DeepCopy()
+	desired.Spec.ScaleTargetRef = &kedav1alpha1.ScaleTarget{
+		APIVersion: groupVersion,
+		Kind:       kind,
+		Name:       app.Name,
+	}
+
+	if err := controllerutil.SetControllerReference(app, desired, r.Scheme); err != nil {
+		return err
+	}
+
+	current := &kedav1alpha1.ScaledObject{}
+	err = r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: app.Name}, current)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error("Failed to get ScaledObject", logx.ErrAttr(err))
+			return err
+		}
+		// Create path
+		logger.Info("Creating ScaledObject", "ScaledObject", desired.Name)
+		if err := r.Create(ctx, desired); err != nil {
+			logger.Error("Failed to create ScaledObject", logx.ErrAttr(err))
+			return err
+		}
+		app.Status.ScaledObjectStatus = summarizeScaledObjectStatus(desired)
+		logger.Info("Successfully created ScaledObject", "ScaledObject", desired.Name)
+		return nil
+	}
+
+	// Update path
+	desired.ResourceVersion = current.ResourceVersion
+	if managedObjectMetadataEqual(current, desired) && apiequality.Semantic.DeepEqual(current.Spec, desired.Spec) {
+		app.Status.ScaledObjectStatus = summarizeScaledObjectStatus(current)
+		return nil
+	}
+	logger.Info("Updating ScaledObject", "ScaledObject", desired.Name)
+	if err := r.Update(ctx, desired); err != nil {
+		logger.Error("Failed to update ScaledObject", logx.ErrAttr(err))
+		return err
+	}
+
+	app.Status.ScaledObjectStatus = summarizeScaledObjectStatus(current)
+	logger.Info("Successfully updated ScaledObject", "ScaledObject", desired.Name)
+	return nil
+}
+
+// deleteScaledObject deletes the ScaledObject associated with the Application
+func (r *ApplicationReconciler) deleteScaledObject(ctx context.Context, app *wandbv2.Application) error {
+	app.Status.ScaledObjectStatus = nil
+	if !r.kedaAvailable() {
+		return nil
+	}
+
+	logger := logx.GetSlog(ctx)
+	scaledObject := &kedav1alpha1.ScaledObject{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: app.Name}, scaledObject); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	deletePolicy := client.PropagationPolicy(metav1.DeletePropagationBackground)
+	if err := r.Delete(ctx, scaledObject, deletePolicy); err != nil {
+		logger.Error("Failed to delete ScaledObject", logx.ErrAttr(err), "ScaledObject", app.Name)
+		return err
+	}
+	logger.Info("Successfully deleted ScaledObject", "ScaledObject", app.Name)
+	return nil
+}
+
+func summarizeScaledObjectStatus(scaledObject *kedav1alpha1.ScaledObject) *wandbv2.ScaledObjectStatusSummary {
+	ready := scaledObject.Status.Conditions.GetReadyCondition()
+	active := scaledObject.Status.Conditions.GetActiveCondition()
+	summary := &wandbv2.ScaledObjectStatusSummary{
+		Ready:   ready.IsTrue(),
+		Active:  active.IsTrue(),
+		HpaName: scaledObject.Status.HpaName,
+	}
+	if !summary.Ready {
+		summary.Message = ready.Message
+	}
+	return summary
+}
+
 // reconcileHPA handles HorizontalPodAutoscaler resources defined in the Application spec
 func (r *ApplicationReconciler) reconcileHPA(ctx context.Context, app *wandbv2.Application) error {
 	logger := logx.GetSlog(ctx)
 
-	if app.Spec.HpaTemplate == nil {
-		// If HPA template is not specified, ensure any existing HPA owned by the Application is deleted
+	if app.Spec.HpaTemplate == nil || r.scaledObjectManaged(app) {
+		// If HPA template is not specified, or KEDA scales the workload through
+		// its own HPA, ensure any existing HPA owned by the Application is deleted
 		return r.deleteHPA(ctx, app)
 	}
 
@@ -1012,20 +1418,9 @@ func (r *ApplicationReconciler) reconcileHPA(ctx context.Context, app *wandbv2.A
 	desired.Spec = *app.Spec.HpaTemplate.DeepCopy()
 
 	// Ensure scaleTargetRef points to our application workload
-	var groupVersion string
-	var kind string
-	switch app.Spec.Kind {
-	case "Deployment":
-		groupVersion = appsv1.SchemeGroupVersion.String()
-		kind = "Deployment"
-	case "StatefulSet":
-		groupVersion = appsv1.SchemeGroupVersion.String()
-		kind = "StatefulSet"
-	case "Rollout":
-		groupVersion = v1alpha1.SchemeGroupVersion.String()
-		kind = "Rollout"
-	default:
-		return fmt.Errorf("unsupported application kind for HPA: %s", app.Spec.Kind)
+	groupVersion, kind, err := scaleTargetForKind(app.Spec.Kind)
+	if err != nil {
+		return fmt.Errorf("unsupported application kind for HPA: %w", err)
 	}
 
 	desired.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
@@ -1039,7 +1434,7 @@ func (r *ApplicationReconciler) reconcileHPA(ctx context.Context, app *wandbv2.A
 	}
 
 	current := &autoscalingv2.HorizontalPodAutoscaler{}
-	err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: app.Name}, current)
+	err = r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: app.Name}, current)
 	if err != nil {
 		if !errors.IsNotFound(err) {
 			logger.Error("Failed to get HPA", logx.ErrAttr(err))
@@ -1093,6 +1488,91 @@ func (r *ApplicationReconciler) deleteHPA(ctx context.Context, app *wandbv2.Appl
 	return nil
 }
 
+// reconcilePDB handles the PodDisruptionBudget defined in the Application spec.
+// The selector always targets the application's pods via getSelectorLabels, so
+// templates only need to carry minAvailable or maxUnavailable.
+func (r *ApplicationReconciler) reconcilePDB(ctx context.Context, app *wandbv2.Application) error {
+	logger := logx.GetSlog(ctx)
+
+	if app.Spec.PdbTemplate == nil {
+		// If PDB template is not specified, ensure any existing PDB owned by the Application is deleted
+		return r.deletePDB(ctx, app)
+	}
+
+	desired := &policyv1.PodDisruptionBudget{}
+	desired.Name = app.Name
+	desired.Namespace = app.Namespace
+
+	// Merge labels/annotations from meta template
+	desired.Labels = utils.MergeMapsStringString(
+		desired.Labels,
+		app.Spec.MetaTemplate.Labels,
+	)
+	desired.Annotations = utils.MergeMapsStringString(
+		desired.Annotations,
+		app.Spec.MetaTemplate.Annotations,
+	)
+
+	// Copy spec from template and pin the selector to our application pods
+	desired.Spec = *app.Spec.PdbTemplate.DeepCopy()
+	desired.Spec.Selector = &metav1.LabelSelector{
+		MatchLabels: getSelectorLabels(app),
+	}
+
+	if err := controllerutil.SetControllerReference(app, desired, r.Scheme); err != nil {
+		return err
+	}
+
+	current := &policyv1.PodDisruptionBudget{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: app.Name}, current)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			logger.Error("Failed to get PDB", logx.ErrAttr(err))
+			return err
+		}
+		// Create path
+		logger.Info("Creating PDB", "PDB", desired.Name)
+		if err := r.Create(ctx, desired); err != nil {
+			logger.Error("Failed to create PDB", logx.ErrAttr(err))
+			return err
+		}
+		logger.Info("Successfully created PDB", "PDB", desired.Name)
+		return nil
+	}
+
+	// Update path
+	desired.ResourceVersion = current.ResourceVersion
+	if managedObjectMetadataEqual(current, desired) && apiequality.Semantic.DeepEqual(current.Spec, desired.Spec) {
+		return nil
+	}
+	logger.Info("Updating PDB", "PDB", desired.Name)
+	if err := r.Update(ctx, desired); err != nil {
+		logger.Error("Failed to update PDB", logx.ErrAttr(err))
+		return err
+	}
+	logger.Info("Successfully updated PDB", "PDB", desired.Name)
+	return nil
+}
+
+// deletePDB deletes the PDB associated with the Application
+func (r *ApplicationReconciler) deletePDB(ctx context.Context, app *wandbv2.Application) error {
+	logger := logx.GetSlog(ctx)
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: app.Namespace, Name: app.Name}, pdb); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	deletePolicy := client.PropagationPolicy(metav1.DeletePropagationBackground)
+	if err := r.Delete(ctx, pdb, deletePolicy); err != nil {
+		logger.Error("Failed to delete PDB", logx.ErrAttr(err), "PDB", app.Name)
+		return err
+	}
+	logger.Info("Successfully deleted PDB", "PDB", app.Name)
+	return nil
+}
+
 func (r *ApplicationReconciler) reconcileHTTPRoute(ctx context.Context, app *wandbv2.Application) error {
 	if app.Spec.HTTPRouteTemplate == nil {
 		if err := r.deleteHTTPRoute(ctx, app); err != nil {
@@ -1177,39 +1657,11 @@ func (r *ApplicationReconciler) reconcileHTTPRoute(ctx context.Context, app *wan
 
 func buildHTTPRouteRules(app *wandbv2.Application) []gatewayv1.HTTPRouteRule {
 	tmpl := app.Spec.HTTPRouteTemplate
-
-	paths := tmpl.Paths
-	if len(paths) == 0 {
-		paths = []string{"/"}
-	}
-
-	var matches []gatewayv1.HTTPRouteMatch
-	for _, p := range paths {
-		p := p
-		matchType := gatewayv1.PathMatchPathPrefix
-		if tmpl.PathType == "Exact" {
-			matchType = gatewayv1.PathMatchExact
-		}
-		matches = append(matches, gatewayv1.HTTPRouteMatch{
-			Path: &gatewayv1.HTTPPathMatch{
-				Type:  &matchType,
-				Value: &p,
-			},
-		})
-	}
-
-	backendRef := gatewayv1.HTTPBackendRef{
-		BackendRef: gatewayv1.BackendRef{
-			BackendObjectReference: gatewayv1.BackendObjectReference{
-				Name: gatewayv1.ObjectName(app.Name),
-				Port: tmpl.ServicePort,
-			},
-		},
-	}
-
-	return []gatewayv1.HTTPRouteRule{{
-		Matches:     matches,
-		BackendRefs: []gatewayv1.HTTPBackendRef{backendRef},
+	return common.BuildHTTPRouteRules(common.HTTPRouteBackend{
+		ServiceName: app.Name,
+		ServicePort: tmpl.ServicePort,
+		Paths:       tmpl.Paths,
+		PathType:    tmpl.PathType,
 		Filters: []gatewayv1.HTTPRouteFilter{
 			{
 				Type: gatewayv1.HTTPRouteFilterRequestHeaderModifier,
@@ -1218,7 +1670,7 @@ func buildHTTPRouteRules(app *wandbv2.Application) []gatewayv1.HTTPRouteRule {
 				},
 			},
 		},
-	}}
+	}, tmpl.Routing)
 }
 
 func (r *ApplicationReconciler) deleteHTTPRoute(ctx context.Context, app *wandbv2.Application) error {
@@ -1257,8 +1709,10 @@ func (r *ApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		For(&wandbv2.Application{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&appsv1.StatefulSet{}).
+		Owns(&appsv1.DaemonSet{}).
 		Owns(&corev1.Service{}).
 		Owns(&autoscalingv2.HorizontalPodAutoscaler{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Named("application")
 
 	if utils.IsRegistered(r.Scheme, &v1alpha1.Rollout{}) {
@@ -1269,5 +1723,9 @@ func (r *ApplicationReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		controller = controller.Owns(&gatewayv1.HTTPRoute{})
 	}
 
+	if r.kedaAvailable() {
+		controller = controller.Owns(&kedav1alpha1.ScaledObject{})
+	}
+
 	return controller.Complete(r)
 }
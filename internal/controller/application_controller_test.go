@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	apiv2 "github.com/wandb/operator/api/v2"
@@ -28,11 +29,13 @@ import (
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -266,6 +269,141 @@ var _ = Describe("Application Controller", func() {
 			Expect(*foundDeployment.Spec.Replicas).To(Equal(replicas))
 		})
 
+		It("should successfully create and manage a PDB", func() {
+			resourceName := "test-pdb"
+			typeNamespacedName := types.NamespacedName{
+				Name:      resourceName,
+				Namespace: "default",
+			}
+			minAvailable := intstr.FromInt32(1)
+
+			resource := &apiv2.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: apiv2.ApplicationSpec{
+					Kind: "Deployment",
+					PdbTemplate: &policyv1.PodDisruptionBudgetSpec{
+						MinAvailable: &minAvailable,
+					},
+					PodTemplate: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name:  "test-container",
+									Image: "nginx",
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &ApplicationReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			// Reconcile to add finalizer
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			// Reconcile to create Deployment and PDB
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			// PDB selects the application's pods
+			foundPDB := &policyv1.PodDisruptionBudget{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, foundPDB)).To(Succeed())
+			Expect(foundPDB.Spec.MinAvailable).To(Equal(&minAvailable))
+			Expect(foundPDB.Spec.Selector.MatchLabels).To(Equal(getSelectorLabels(resource)))
+
+			// Switch to maxUnavailable
+			maxUnavailable := intstr.FromString("50%")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.PdbTemplate = &policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable}
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, foundPDB)).To(Succeed())
+			Expect(foundPDB.Spec.MinAvailable).To(BeNil())
+			Expect(foundPDB.Spec.MaxUnavailable).To(Equal(&maxUnavailable))
+
+			// Remove PDB template
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			resource.Spec.PdbTemplate = nil
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			// PDB should be deleted
+			err = k8sClient.Get(ctx, typeNamespacedName, foundPDB)
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should keep the HPA when KEDA is not installed", func() {
+			resourceName := "test-scaledobject-fallback"
+			typeNamespacedName := types.NamespacedName{
+				Name:      resourceName,
+				Namespace: "default",
+			}
+			var minReplicas int32 = 2
+
+			resource := &apiv2.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: apiv2.ApplicationSpec{
+					Kind: "Deployment",
+					HpaTemplate: &autoscalingv2.HorizontalPodAutoscalerSpec{
+						MinReplicas: &minReplicas,
+						MaxReplicas: 5,
+					},
+					ScaledObjectTemplate: &kedav1alpha1.ScaledObjectSpec{
+						Triggers: []kedav1alpha1.ScaleTriggers{{
+							Type:     "kafka",
+							Metadata: map[string]string{"topic": "events"},
+						}},
+					},
+					PodTemplate: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{
+								{
+									Name:  "test-container",
+									Image: "nginx",
+								},
+							},
+						},
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+			controllerReconciler := &ApplicationReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			// Reconcile to add finalizer, then to create workload and autoscaler
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			// The KEDA CRDs are not served by envtest, so the HPA keeps scaling
+			foundHPA := &autoscalingv2.HorizontalPodAutoscaler{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, foundHPA)).To(Succeed())
+			Expect(*foundHPA.Spec.MinReplicas).To(Equal(minReplicas))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.ScaledObjectStatus).To(BeNil())
+		})
+
 		It("should successfully reconcile a StatefulSet", func() {
 			resourceName := "test-statefulset"
 			typeNamespacedName := types.NamespacedName{
@@ -456,11 +594,33 @@ var _ = Describe("Application Controller", func() {
 			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
 			Expect(err).NotTo(HaveOccurred())
 
-			// 2. Reconcile (DaemonSet is currently a no-op in the controller)
+			// 2. Create DaemonSet
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			found := &appsv1.DaemonSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, found)).To(Succeed())
+			Expect(found.Spec.Template.Spec.Containers[0].Image).To(Equal("fluentd"))
+			Expect(found.Spec.Selector.MatchLabels).To(HaveKeyWithValue("app.kubernetes.io/name", resourceName))
+			Expect(found.OwnerReferences).To(HaveLen(1))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+			Expect(resource.Status.DaemonSetStatus).NotTo(BeNil())
+			Expect(resource.Status.Ready).To(BeFalse())
+
+			By("Updating the pod template")
+			resource.Spec.PodTemplate.Spec.Containers[0].Image = "fluentd:v2"
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
 			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
 			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, found)).To(Succeed())
+			Expect(found.Spec.Template.Spec.Containers[0].Image).To(Equal("fluentd:v2"))
 
-			By("DaemonSet is currently not implemented, so we just verify reconciliation succeeds")
+			By("Deleting the Application")
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apierrors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &appsv1.DaemonSet{}))).To(BeTrue())
 		})
 
 		It("should successfully reconcile a Service", func() {
@@ -36,6 +36,37 @@ var _ = Describe("Application HTTPRoute Helpers", func() {
 		Expect(*rules[0].BackendRefs[0].Port).To(Equal(gatewayv1.PortNumber(8080)))
 	})
 
+	It("renders routing overrides copied onto the HTTPRoute template", func() {
+		servicePort := gatewayv1.PortNumber(8080)
+		weight := int32(10)
+		app := &wandbv2.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "wandb-api"},
+			Spec: wandbv2.ApplicationSpec{
+				HTTPRouteTemplate: &wandbv2.HTTPRouteTemplateSpec{
+					Paths:       []string{"/"},
+					ServicePort: &servicePort,
+					Routing: &wandbv2.GatewayRouting{
+						Rules: []wandbv2.GatewayRouteRule{{
+							Paths: []string{"/graphql"},
+							GatewayRouteActions: wandbv2.GatewayRouteActions{
+								Backends: []wandbv2.GatewayBackend{{Name: "wandb-api-canary", Port: 8080, Weight: &weight}},
+							},
+						}},
+					},
+				},
+			},
+		}
+
+		rules := buildHTTPRouteRules(app)
+
+		Expect(rules).To(HaveLen(2))
+		Expect(*rules[0].Matches[0].Path.Value).To(Equal("/graphql"))
+		Expect(rules[0].BackendRefs).To(HaveLen(2))
+		Expect(rules[0].BackendRefs[1].Name).To(Equal(gatewayv1.ObjectName("wandb-api-canary")))
+		Expect(rules[0].Filters).To(HaveLen(1), "the header-stripping filter applies to every rule")
+		Expect(rules[1].BackendRefs).To(HaveLen(1))
+	})
+
 	It("marks an HTTPRoute accepted when any parent reports Accepted=True", func() {
 		route := &gatewayv1.HTTPRoute{
 			Status: gatewayv1.HTTPRouteStatus{
@@ -0,0 +1,84 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	wandbv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/pkg/vendored/argo-rollouts/argoproj.io.rollouts/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestBuildRolloutStrategy(t *testing.T) {
+	templates := []v1alpha1.AnalysisTemplateRef{{TemplateName: "success-rate"}}
+	surge := intstr.FromString("25%")
+	strategy := buildRolloutStrategy(&wandbv2.RolloutStrategySpec{
+		Steps: []wandbv2.CanaryStepSpec{
+			{SetWeight: ptr.To[int32](20)},
+			{Pause: &wandbv2.CanaryPauseSpec{Duration: "300"}},
+			{Pause: &wandbv2.CanaryPauseSpec{Duration: "10m"}},
+			{Pause: &wandbv2.CanaryPauseSpec{}},
+			{Analysis: &wandbv2.RolloutAnalysisSpec{
+				Templates: templates,
+				Args:      []wandbv2.RolloutAnalysisArg{{Name: "service", Value: "api"}},
+			}},
+		},
+		Analysis: &wandbv2.BackgroundAnalysisSpec{
+			RolloutAnalysisSpec: wandbv2.RolloutAnalysisSpec{Templates: templates},
+			StartingStep:        ptr.To[int32](1),
+		},
+		MaxSurge: &surge,
+	})
+
+	canary := strategy.Canary
+	require.NotNil(t, canary)
+	require.Len(t, canary.Steps, 5)
+	require.Equal(t, int32(20), *canary.Steps[0].SetWeight)
+	require.Equal(t, intstr.FromInt32(300), *canary.Steps[1].Pause.Duration, "bare seconds stay an integer")
+	require.Equal(t, intstr.FromString("10m"), *canary.Steps[2].Pause.Duration)
+	require.Nil(t, canary.Steps[3].Pause.Duration, "an empty duration pauses until promoted")
+	require.Equal(t, []v1alpha1.AnalysisRunArgument{{Name: "service", Value: "api"}}, canary.Steps[4].Analysis.Args)
+	require.Equal(t, templates, canary.Analysis.Templates)
+	require.Equal(t, int32(1), *canary.Analysis.StartingStep)
+	require.Equal(t, &surge, canary.MaxSurge)
+
+	require.NotNil(t, buildRolloutStrategy(nil).Canary, "a Rollout without a strategy still needs a canary")
+}
+
+func TestSummarizeRolloutStatus(t *testing.T) {
+	rollout := func(status v1alpha1.RolloutStatus) *v1alpha1.Rollout {
+		r := &v1alpha1.Rollout{Status: status}
+		r.Generation = 3
+		r.Spec.Strategy = buildRolloutStrategy(&wandbv2.RolloutStrategySpec{
+			Steps: []wandbv2.CanaryStepSpec{{SetWeight: ptr.To[int32](50)}, {Pause: &wandbv2.CanaryPauseSpec{}}},
+		})
+		return r
+	}
+
+	paused := summarizeRolloutStatus(rollout(v1alpha1.RolloutStatus{
+		Phase:              v1alpha1.RolloutPhasePaused,
+		CurrentStepIndex:   ptr.To[int32](1),
+		PauseConditions:    []v1alpha1.PauseCondition{{Reason: "CanaryPauseStep"}},
+		ObservedGeneration: "3",
+	}))
+	require.Equal(t, &wandbv2.RolloutStatusSummary{
+		Phase: "Paused", CurrentStep: ptr.To[int32](1), TotalSteps: 2, Paused: true,
+	}, paused)
+
+	aborted := summarizeRolloutStatus(rollout(v1alpha1.RolloutStatus{
+		Phase:              v1alpha1.RolloutPhaseDegraded,
+		Abort:              true,
+		Message:            "RolloutAborted: metric success-rate assessed Failed",
+		ObservedGeneration: "3",
+	}))
+	require.True(t, aborted.Aborted)
+	require.Equal(t, "RolloutAborted: metric success-rate assessed Failed", aborted.Message)
+
+	require.Equal(t, "Healthy", summarizeRolloutStatus(rollout(v1alpha1.RolloutStatus{
+		Phase: v1alpha1.RolloutPhaseHealthy, ObservedGeneration: "3",
+	})).Phase)
+	require.Equal(t, "Progressing", summarizeRolloutStatus(rollout(v1alpha1.RolloutStatus{
+		Phase: v1alpha1.RolloutPhaseHealthy, ObservedGeneration: "2",
+	})).Phase, "a Healthy phase for an older generation is not promoted yet")
+}
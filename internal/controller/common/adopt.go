@@ -0,0 +1,230 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"maps"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/logx"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// DeploymentUIDAnnotation identifies the W&B deployment that managed
+	// infrastructure belongs to. The operator sets it on the WeightsAndBiases
+	// CR to the CR's UID and copies it onto the infrastructure CRs, so a CR
+	// restored with its annotations keeps its identity.
+	DeploymentUIDAnnotation = "weightsandbiases.apps.wandb.com/deployment-uid"
+	// AdoptDeploymentAnnotation on the WeightsAndBiases CR names another
+	// deployment whose detached infrastructure the CR may adopt.
+	AdoptDeploymentAnnotation = "weightsandbiases.apps.wandb.com/adopt-deployment"
+
+	// InfraOwnedType reports whether the CR owns its infrastructure CRs.
+	InfraOwnedType = "InfraOwned"
+
+	OwnedReason   = "Owned"
+	AdoptedReason = "Adopted"
+	// ForeignResourceReason: the infrastructure CR at the instance's name
+	// belongs to a different deployment, so it was left alone.
+	ForeignResourceReason = "ForeignResource"
+)
+
+// DeploymentUID is the deployment identity of wandb: its
+// DeploymentUIDAnnotation, or its UID before the annotation is set.
+func DeploymentUID(wandb *apiv2.WeightsAndBiases) string {
+	if uid := wandb.GetAnnotations()[DeploymentUIDAnnotation]; uid != "" {
+		return uid
+	}
+	return string(wandb.GetUID())
+}
+
+// EnsureDeploymentUID sets the DeploymentUIDAnnotation on wandb when it is
+// missing and reports whether it did.
+func EnsureDeploymentUID(wandb *apiv2.WeightsAndBiases) bool {
+	if wandb.GetAnnotations()[DeploymentUIDAnnotation] != "" || wandb.GetUID() == "" {
+		return false
+	}
+	annotations := maps.Clone(wandb.GetAnnotations())
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[DeploymentUIDAnnotation] = string(wandb.GetUID())
+	wandb.SetAnnotations(annotations)
+	return true
+}
+
+// AdoptDetached reads the infrastructure CR at nsName into actual and takes
+// ownership of it when a previous CR of the same deployment detached it. It
+// returns the InfraOwnedType condition for the CR, or nothing when there is
+// no CR yet.
+//
+// actual is foreign, and left alone, when another controller owns it, when
+// one of wandbLabels is set to a different value, or when it records a
+// different deployment. A foreign CR of a recorded deployment is adopted
+// anyway once wandb names that deployment in its AdoptDeploymentAnnotation.
+// CRs that predate the deployment annotation are matched by labels alone.
+func AdoptDetached(
+	ctx context.Context,
+	cl client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	nsName types.NamespacedName,
+	resourceType string,
+	actual client.Object,
+	wandbLabels map[string]string,
+) []metav1.Condition {
+	found, err := GetResource(ctx, cl, nsName, resourceType, actual)
+	if err != nil {
+		return []metav1.Condition{adoptionApiError()}
+	}
+	if !found {
+		return nil
+	}
+	return []metav1.Condition{adoptDetached(ctx, cl, wandb, resourceType, actual, wandbLabels)}
+}
+
+func adoptDetached(
+	ctx context.Context,
+	cl client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	resourceType string,
+	actual client.Object,
+	wandbLabels map[string]string,
+) metav1.Condition {
+	log := logx.GetSlog(ctx)
+	deploymentUID := DeploymentUID(wandb)
+	recorded := actual.GetAnnotations()[DeploymentUIDAnnotation]
+	objectName := fmt.Sprintf("%s %s/%s", resourceType, actual.GetNamespace(), actual.GetName())
+
+	if !IsDetached(actual, wandb.GetUID()) {
+		if recorded != deploymentUID {
+			before := actual.DeepCopyObject().(client.Object)
+			setAnnotation(actual, DeploymentUIDAnnotation, deploymentUID)
+			if err := cl.Patch(ctx, actual, client.MergeFrom(before)); err != nil {
+				log.Error("failed to record deployment on infrastructure", logx.ErrAttr(err), "type", resourceType)
+				return adoptionApiError()
+			}
+		}
+		return metav1.Condition{Type: InfraOwnedType, Status: metav1.ConditionTrue, Reason: OwnedReason}
+	}
+
+	if owner := metav1.GetControllerOf(actual); owner != nil {
+		return foreignResource(fmt.Sprintf("%s is controlled by %s %s", objectName, owner.Kind, owner.Name))
+	}
+	override := recorded != "" && wandb.GetAnnotations()[AdoptDeploymentAnnotation] == recorded
+	if !override {
+		for key, value := range wandbLabels {
+			if actualValue, ok := actual.GetLabels()[key]; ok && actualValue != value {
+				return foreignResource(fmt.Sprintf("%s is labelled %s=%s, not %s", objectName, key, actualValue, value))
+			}
+		}
+		if recorded != "" && recorded != deploymentUID {
+			return foreignResource(fmt.Sprintf(
+				"%s belongs to deployment %s; set the %s annotation to %s on this WeightsAndBiases to adopt it",
+				objectName, recorded, AdoptDeploymentAnnotation, recorded,
+			))
+		}
+	}
+
+	before := actual.DeepCopyObject().(client.Object)
+	if err := controllerutil.SetControllerReference(wandb, actual, cl.Scheme()); err != nil {
+		log.Error("failed to set owner reference on detached infrastructure", logx.ErrAttr(err), "type", resourceType)
+		return adoptionApiError()
+	}
+	setAnnotation(actual, DeploymentUIDAnnotation, deploymentUID)
+	labels := maps.Clone(actual.GetLabels())
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	maps.Copy(labels, wandbLabels)
+	actual.SetLabels(labels)
+	if err := cl.Patch(ctx, actual, client.MergeFromWithOptions(before, client.MergeFromWithOptimisticLock{})); err != nil {
+		log.Error("failed to adopt detached infrastructure", logx.ErrAttr(err), "type", resourceType)
+		return adoptionApiError()
+	}
+	log.Info("adopted detached infrastructure", "type", resourceType, "namespace", actual.GetNamespace(), "name", actual.GetName())
+
+	message := "adopted detached " + objectName
+	if recorded != "" && recorded != deploymentUID {
+		message += " of deployment " + recorded
+	}
+	return metav1.Condition{Type: InfraOwnedType, Status: metav1.ConditionTrue, Reason: AdoptedReason, Message: message}
+}
+
+// AdoptionConditions folds the InfraOwnedType conditions of an instance's
+// CRs into the conditions WriteState reports. A foreign CR or an API error
+// blocks the instance, and is reported with a Reconciled condition; it
+// returns proceed=false then.
+func AdoptionConditions(results []metav1.Condition) ([]metav1.Condition, bool) {
+	var adopted []metav1.Condition
+	for _, result := range results {
+		switch {
+		case result.Reason == ApiErrorReason || result.Reason == ForeignResourceReason:
+			return []metav1.Condition{
+				{Type: ReconciledType, Status: metav1.ConditionFalse, Reason: result.Reason, Message: result.Message},
+				result,
+			}, false
+		case result.Reason == AdoptedReason:
+			adopted = append(adopted, result)
+		}
+	}
+	if len(adopted) > 0 {
+		return adopted[len(adopted)-1:], true
+	}
+	if len(results) > 0 {
+		return results[len(results)-1:], true
+	}
+	return nil, true
+}
+
+// AdoptionEvents reports an adoption, or a refusal to adopt, the first time
+// it shows up in the InfraOwnedType condition.
+func AdoptionEvents(oldConditions, currentConditions []metav1.Condition) []corev1.Event {
+	var events []corev1.Event
+	for _, current := range currentConditions {
+		if current.Type != InfraOwnedType || current.Reason == OwnedReason || current.Reason == ApiErrorReason {
+			continue
+		}
+		old := apimeta.FindStatusCondition(oldConditions, InfraOwnedType)
+		if old != nil && !hasConditionChanged(*old, current) {
+			continue
+		}
+		eventType := corev1.EventTypeNormal
+		if current.Status != metav1.ConditionTrue {
+			eventType = corev1.EventTypeWarning
+		}
+		events = append(events, corev1.Event{Type: eventType, Reason: current.Reason, Message: current.Message})
+	}
+	return events
+}
+
+// InferInfraOwnedState fails an instance whose infrastructure CRs belong to
+// another deployment. Owning them says nothing about their health.
+func InferInfraOwnedState(condition metav1.Condition) string {
+	if condition.Status == metav1.ConditionFalse {
+		return ErrorState
+	}
+	return UnknownState
+}
+
+func foreignResource(message string) metav1.Condition {
+	return metav1.Condition{Type: InfraOwnedType, Status: metav1.ConditionFalse, Reason: ForeignResourceReason, Message: message}
+}
+
+func adoptionApiError() metav1.Condition {
+	return metav1.Condition{Type: InfraOwnedType, Status: metav1.ConditionUnknown, Reason: ApiErrorReason}
+}
+
+func setAnnotation(obj client.Object, key, value string) {
+	annotations := maps.Clone(obj.GetAnnotations())
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = value
+	obj.SetAnnotations(annotations)
+}
@@ -0,0 +1,201 @@
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAdoptDetachedAdoptsSameDeployment(t *testing.T) {
+	t.Parallel()
+
+	wandb := testAdoptWandb("uid-new", "deployment-1")
+	c := newAdoptClient(t, testInfraConfigMap(wandb, "deployment-1"))
+
+	conditions := AdoptDetached(context.Background(), c, wandb, testInfraNsName, "ConfigMap", &corev1.ConfigMap{}, BuildWandbLabels(wandb, "mysql"))
+	if len(conditions) != 1 || conditions[0].Status != metav1.ConditionTrue || conditions[0].Reason != AdoptedReason {
+		t.Fatalf("conditions = %+v, want one Adopted condition", conditions)
+	}
+	actual := getInfraConfigMap(t, c)
+	if owner := metav1.GetControllerOf(actual); owner == nil || owner.UID != "uid-new" {
+		t.Fatalf("controller = %+v, want the new CR", owner)
+	}
+}
+
+func TestAdoptDetachedAdoptsUnrecordedResourceByLabels(t *testing.T) {
+	t.Parallel()
+
+	wandb := testAdoptWandb("uid-new", "")
+	c := newAdoptClient(t, testInfraConfigMap(wandb, ""))
+
+	conditions := AdoptDetached(context.Background(), c, wandb, testInfraNsName, "ConfigMap", &corev1.ConfigMap{}, BuildWandbLabels(wandb, "mysql"))
+	if len(conditions) != 1 || conditions[0].Reason != AdoptedReason {
+		t.Fatalf("conditions = %+v, want one Adopted condition", conditions)
+	}
+	if got := getInfraConfigMap(t, c).Annotations[DeploymentUIDAnnotation]; got != "uid-new" {
+		t.Fatalf("deployment annotation = %q, want the adopting deployment", got)
+	}
+}
+
+func TestAdoptDetachedRefusesOtherDeploymentUntilOverridden(t *testing.T) {
+	t.Parallel()
+
+	wandb := testAdoptWandb("uid-new", "deployment-2")
+	c := newAdoptClient(t, testInfraConfigMap(wandb, "deployment-1"))
+	labels := BuildWandbLabels(wandb, "mysql")
+
+	conditions := AdoptDetached(context.Background(), c, wandb, testInfraNsName, "ConfigMap", &corev1.ConfigMap{}, labels)
+	if len(conditions) != 1 || conditions[0].Status != metav1.ConditionFalse || conditions[0].Reason != ForeignResourceReason {
+		t.Fatalf("conditions = %+v, want a ForeignResource condition", conditions)
+	}
+	if !strings.Contains(conditions[0].Message, AdoptDeploymentAnnotation) {
+		t.Fatalf("message %q should name the override annotation", conditions[0].Message)
+	}
+	if len(getInfraConfigMap(t, c).OwnerReferences) != 0 {
+		t.Fatal("a foreign resource must not be adopted")
+	}
+
+	wandb.Annotations[AdoptDeploymentAnnotation] = "deployment-1"
+	conditions = AdoptDetached(context.Background(), c, wandb, testInfraNsName, "ConfigMap", &corev1.ConfigMap{}, labels)
+	if len(conditions) != 1 || conditions[0].Reason != AdoptedReason {
+		t.Fatalf("conditions = %+v, want the override to adopt", conditions)
+	}
+	if got := getInfraConfigMap(t, c).Annotations[DeploymentUIDAnnotation]; got != "deployment-2" {
+		t.Fatalf("deployment annotation = %q, want deployment-2", got)
+	}
+}
+
+func TestAdoptDetachedRefusesForeignLabelsAndControllers(t *testing.T) {
+	t.Parallel()
+
+	wandb := testAdoptWandb("uid-new", "")
+	otherLabels := testInfraConfigMap(wandb, "")
+	otherLabels.Labels[WandbNameLabel] = "other"
+	controlled := testInfraConfigMap(wandb, "")
+	controlled.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: "apps/v1", Kind: "Deployment", Name: "someone-else", UID: "uid-other", Controller: ptr.To(true),
+	}}
+
+	for name, obj := range map[string]*corev1.ConfigMap{"labels": otherLabels, "controller": controlled} {
+		c := newAdoptClient(t, obj)
+		conditions := AdoptDetached(context.Background(), c, wandb, testInfraNsName, "ConfigMap", &corev1.ConfigMap{}, BuildWandbLabels(wandb, "mysql"))
+		if len(conditions) != 1 || conditions[0].Reason != ForeignResourceReason {
+			t.Fatalf("%s: conditions = %+v, want a ForeignResource condition", name, conditions)
+		}
+	}
+}
+
+func TestAdoptDetachedRecordsDeploymentOnOwnedResource(t *testing.T) {
+	t.Parallel()
+
+	wandb := testAdoptWandb("uid-new", "deployment-1")
+	owned := testInfraConfigMap(wandb, "")
+	owned.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: apiv2.GroupVersion.String(), Kind: "WeightsAndBiases", Name: wandb.Name, UID: wandb.UID, Controller: ptr.To(true),
+	}}
+	c := newAdoptClient(t, owned)
+
+	conditions := AdoptDetached(context.Background(), c, wandb, testInfraNsName, "ConfigMap", &corev1.ConfigMap{}, BuildWandbLabels(wandb, "mysql"))
+	if len(conditions) != 1 || conditions[0].Reason != OwnedReason {
+		t.Fatalf("conditions = %+v, want one Owned condition", conditions)
+	}
+	if got := getInfraConfigMap(t, c).Annotations[DeploymentUIDAnnotation]; got != "deployment-1" {
+		t.Fatalf("deployment annotation = %q, want deployment-1", got)
+	}
+}
+
+func TestAdoptDetachedIgnoresMissingResource(t *testing.T) {
+	t.Parallel()
+
+	wandb := testAdoptWandb("uid-new", "")
+	conditions := AdoptDetached(context.Background(), newAdoptClient(t), wandb, testInfraNsName, "ConfigMap", &corev1.ConfigMap{}, nil)
+	if conditions != nil {
+		t.Fatalf("conditions = %+v, want none", conditions)
+	}
+}
+
+func TestAdoptionConditionsBlockOnForeignResource(t *testing.T) {
+	t.Parallel()
+
+	owned := metav1.Condition{Type: InfraOwnedType, Status: metav1.ConditionTrue, Reason: OwnedReason}
+	adopted := metav1.Condition{Type: InfraOwnedType, Status: metav1.ConditionTrue, Reason: AdoptedReason, Message: "adopted"}
+	foreign := foreignResource("not ours")
+
+	conditions, proceed := AdoptionConditions([]metav1.Condition{owned, adopted})
+	if !proceed || len(conditions) != 1 || conditions[0].Reason != AdoptedReason {
+		t.Fatalf("conditions = %+v, proceed = %v; want the adoption reported", conditions, proceed)
+	}
+	conditions, proceed = AdoptionConditions([]metav1.Condition{adopted, foreign})
+	if proceed || len(conditions) != 2 || conditions[0].Type != ReconciledType || conditions[1].Reason != ForeignResourceReason {
+		t.Fatalf("conditions = %+v, proceed = %v; want the instance blocked", conditions, proceed)
+	}
+}
+
+func TestAdoptionEventsReportChangesOnce(t *testing.T) {
+	t.Parallel()
+
+	foreign := foreignResource("not ours")
+	events := AdoptionEvents(nil, []metav1.Condition{foreign})
+	if len(events) != 1 || events[0].Type != corev1.EventTypeWarning || events[0].Reason != ForeignResourceReason {
+		t.Fatalf("events = %+v, want one warning", events)
+	}
+	if events := AdoptionEvents([]metav1.Condition{foreign}, []metav1.Condition{foreign}); len(events) != 0 {
+		t.Fatalf("events = %+v, want none for an unchanged condition", events)
+	}
+	owned := metav1.Condition{Type: InfraOwnedType, Status: metav1.ConditionTrue, Reason: OwnedReason}
+	if events := AdoptionEvents([]metav1.Condition{foreign}, []metav1.Condition{owned}); len(events) != 0 {
+		t.Fatalf("events = %+v, want none for an owned resource", events)
+	}
+}
+
+var testInfraNsName = types.NamespacedName{Namespace: "default", Name: "wandb-mysql"}
+
+func testAdoptWandb(uid types.UID, deploymentUID string) *apiv2.WeightsAndBiases {
+	wandb := &apiv2.WeightsAndBiases{ObjectMeta: metav1.ObjectMeta{
+		Name: "wandb", Namespace: "default", UID: uid, Annotations: map[string]string{},
+	}}
+	if deploymentUID != "" {
+		wandb.Annotations[DeploymentUIDAnnotation] = deploymentUID
+	}
+	return wandb
+}
+
+func testInfraConfigMap(wandb *apiv2.WeightsAndBiases, deploymentUID string) *corev1.ConfigMap {
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name: testInfraNsName.Name, Namespace: testInfraNsName.Namespace, Labels: BuildWandbLabels(wandb, "mysql"),
+	}}
+	if deploymentUID != "" {
+		obj.Annotations = map[string]string{DeploymentUIDAnnotation: deploymentUID}
+	}
+	return obj
+}
+
+func getInfraConfigMap(t *testing.T, c client.Client) *corev1.ConfigMap {
+	t.Helper()
+	obj := &corev1.ConfigMap{}
+	if err := c.Get(context.Background(), testInfraNsName, obj); err != nil {
+		t.Fatalf("get ConfigMap: %v", err)
+	}
+	return obj
+}
+
+func newAdoptClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := apiv2.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
@@ -0,0 +1,164 @@
+package common
+
+import (
+	apiv2 "github.com/wandb/operator/api/v2"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// HTTPRouteBackend is the Service an operator-rendered HTTPRoute sends its
+// traffic to, along with the paths it serves and the filters every rule gets.
+type HTTPRouteBackend struct {
+	ServiceName string
+	ServicePort *gatewayv1.PortNumber
+	Paths       []string
+	// PathType is "Exact" for exact matching, anything else for prefix.
+	PathType string
+	Filters  []gatewayv1.HTTPRouteFilter
+}
+
+// BuildHTTPRouteRules renders the rules of an application or infrastructure
+// HTTPRoute. The default rule sends the backend's paths to its Service, with
+// the routing actions applied; routing rules come first so the Gateway
+// prefers them for the requests they match.
+func BuildHTTPRouteRules(backend HTTPRouteBackend, routing *apiv2.GatewayRouting) []gatewayv1.HTTPRouteRule {
+	paths := backend.Paths
+	if len(paths) == 0 {
+		paths = []string{"/"}
+	}
+	matchType := gatewayv1.PathMatchPathPrefix
+	if backend.PathType == "Exact" {
+		matchType = gatewayv1.PathMatchExact
+	}
+
+	defaultRule := gatewayv1.HTTPRouteRule{
+		Matches: pathMatches(paths, matchType, nil),
+	}
+	if routing == nil {
+		routing = &apiv2.GatewayRouting{}
+	}
+
+	var rules []gatewayv1.HTTPRouteRule
+	for _, r := range routing.Rules {
+		rulePaths := paths
+		if len(r.Paths) > 0 {
+			rulePaths = r.Paths
+		}
+		ruleMatchType := matchType
+		switch r.PathType {
+		case "Exact":
+			ruleMatchType = gatewayv1.PathMatchExact
+		case "PathPrefix":
+			ruleMatchType = gatewayv1.PathMatchPathPrefix
+		}
+		rule := gatewayv1.HTTPRouteRule{
+			Matches: pathMatches(rulePaths, ruleMatchType, headerMatches(r.Headers)),
+		}
+		applyRouteActions(&rule, backend, r.GatewayRouteActions)
+		rules = append(rules, rule)
+	}
+
+	applyRouteActions(&defaultRule, backend, routing.GatewayRouteActions)
+	return append(rules, defaultRule)
+}
+
+func pathMatches(paths []string, matchType gatewayv1.PathMatchType, headers []gatewayv1.HTTPHeaderMatch) []gatewayv1.HTTPRouteMatch {
+	var matches []gatewayv1.HTTPRouteMatch
+	for _, p := range paths {
+		p := p
+		matchType := matchType
+		matches = append(matches, gatewayv1.HTTPRouteMatch{
+			Path: &gatewayv1.HTTPPathMatch{
+				Type:  &matchType,
+				Value: &p,
+			},
+			Headers: headers,
+		})
+	}
+	return matches
+}
+
+func headerMatches(headers []apiv2.GatewayHeaderMatch) []gatewayv1.HTTPHeaderMatch {
+	var out []gatewayv1.HTTPHeaderMatch
+	for _, h := range headers {
+		matchType := gatewayv1.HeaderMatchExact
+		if h.Type == "RegularExpression" {
+			matchType = gatewayv1.HeaderMatchRegularExpression
+		}
+		out = append(out, gatewayv1.HTTPHeaderMatch{
+			Type:  &matchType,
+			Name:  gatewayv1.HTTPHeaderName(h.Name),
+			Value: h.Value,
+		})
+	}
+	return out
+}
+
+func applyRouteActions(rule *gatewayv1.HTTPRouteRule, backend HTTPRouteBackend, actions apiv2.GatewayRouteActions) {
+	rule.BackendRefs = routeBackendRefs(backend, actions)
+	rule.Filters = append([]gatewayv1.HTTPRouteFilter(nil), backend.Filters...)
+	if actions.Mirror != nil {
+		port := gatewayv1.PortNumber(actions.Mirror.Port)
+		rule.Filters = append(rule.Filters, gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterRequestMirror,
+			RequestMirror: &gatewayv1.HTTPRequestMirrorFilter{
+				BackendRef: gatewayv1.BackendObjectReference{
+					Name: gatewayv1.ObjectName(actions.Mirror.Name),
+					Port: &port,
+				},
+				Percent: actions.Mirror.Percent,
+			},
+		})
+	}
+	if t := actions.Timeouts; t != nil {
+		rule.Timeouts = &gatewayv1.HTTPRouteTimeouts{
+			Request:        durationPtr(t.Request),
+			BackendRequest: durationPtr(t.BackendRequest),
+		}
+	}
+	if r := actions.Retry; r != nil {
+		retry := &gatewayv1.HTTPRouteRetry{Backoff: durationPtr(r.Backoff)}
+		if r.Attempts != nil {
+			attempts := int(*r.Attempts)
+			retry.Attempts = &attempts
+		}
+		for _, code := range r.Codes {
+			retry.Codes = append(retry.Codes, gatewayv1.HTTPRouteRetryStatusCode(code))
+		}
+		rule.Retry = retry
+	}
+}
+
+// routeBackendRefs lists the backend's own Service followed by any weighted
+// backends. A weight left unset falls back to the Gateway API default of 1.
+func routeBackendRefs(backend HTTPRouteBackend, actions apiv2.GatewayRouteActions) []gatewayv1.HTTPBackendRef {
+	refs := []gatewayv1.HTTPBackendRef{{
+		BackendRef: gatewayv1.BackendRef{
+			BackendObjectReference: gatewayv1.BackendObjectReference{
+				Name: gatewayv1.ObjectName(backend.ServiceName),
+				Port: backend.ServicePort,
+			},
+			Weight: actions.ServiceWeight,
+		},
+	}}
+	for _, b := range actions.Backends {
+		port := gatewayv1.PortNumber(b.Port)
+		refs = append(refs, gatewayv1.HTTPBackendRef{
+			BackendRef: gatewayv1.BackendRef{
+				BackendObjectReference: gatewayv1.BackendObjectReference{
+					Name: gatewayv1.ObjectName(b.Name),
+					Port: &port,
+				},
+				Weight: b.Weight,
+			},
+		})
+	}
+	return refs
+}
+
+func durationPtr(d string) *gatewayv1.Duration {
+	if d == "" {
+		return nil
+	}
+	duration := gatewayv1.Duration(d)
+	return &duration
+}
@@ -0,0 +1,89 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"k8s.io/utils/ptr"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestBuildHTTPRouteRulesWithoutRouting(t *testing.T) {
+	port := gatewayv1.PortNumber(8080)
+	rules := BuildHTTPRouteRules(HTTPRouteBackend{ServiceName: "wandb-api", ServicePort: &port}, nil)
+
+	require.Len(t, rules, 1)
+	require.Len(t, rules[0].Matches, 1)
+	require.Equal(t, "/", *rules[0].Matches[0].Path.Value)
+	require.Equal(t, gatewayv1.PathMatchPathPrefix, *rules[0].Matches[0].Path.Type)
+	require.Len(t, rules[0].BackendRefs, 1)
+	require.Nil(t, rules[0].BackendRefs[0].Weight)
+	require.Nil(t, rules[0].Timeouts)
+	require.Nil(t, rules[0].Retry)
+	require.Empty(t, rules[0].Filters)
+}
+
+func TestBuildHTTPRouteRulesAppliesRouting(t *testing.T) {
+	port := gatewayv1.PortNumber(8080)
+	stripHeaders := gatewayv1.HTTPRouteFilter{
+		Type:                  gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+		RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{Remove: []string{"X-Forwarded-Host"}},
+	}
+	backend := HTTPRouteBackend{
+		ServiceName: "wandb-api",
+		ServicePort: &port,
+		Paths:       []string{"/api", "/graphql"},
+		Filters:     []gatewayv1.HTTPRouteFilter{stripHeaders},
+	}
+	routing := &apiv2.GatewayRouting{
+		GatewayRouteActions: apiv2.GatewayRouteActions{
+			Mirror:   &apiv2.GatewayMirror{Name: "wandb-api-shadow", Port: 8080, Percent: ptr.To[int32](5)},
+			Timeouts: &apiv2.GatewayRouteTimeouts{Request: "10m"},
+			Retry:    &apiv2.GatewayRouteRetry{Attempts: ptr.To[int32](2), Backoff: "100ms", Codes: []int32{503}},
+		},
+		Rules: []apiv2.GatewayRouteRule{{
+			Paths:    []string{"/graphql"},
+			PathType: "Exact",
+			Headers:  []apiv2.GatewayHeaderMatch{{Name: "X-Canary", Value: "true"}},
+			GatewayRouteActions: apiv2.GatewayRouteActions{
+				ServiceWeight: ptr.To[int32](90),
+				Backends:      []apiv2.GatewayBackend{{Name: "wandb-api-canary", Port: 8081, Weight: ptr.To[int32](10)}},
+			},
+		}},
+	}
+
+	rules := BuildHTTPRouteRules(backend, routing)
+	require.Len(t, rules, 2, "routing rules come before the default rule")
+
+	canary := rules[0]
+	require.Len(t, canary.Matches, 1)
+	require.Equal(t, "/graphql", *canary.Matches[0].Path.Value)
+	require.Equal(t, gatewayv1.PathMatchExact, *canary.Matches[0].Path.Type)
+	require.Equal(t, gatewayv1.HTTPHeaderName("X-Canary"), canary.Matches[0].Headers[0].Name)
+	require.Equal(t, gatewayv1.HeaderMatchExact, *canary.Matches[0].Headers[0].Type)
+	require.Len(t, canary.BackendRefs, 2)
+	require.Equal(t, gatewayv1.ObjectName("wandb-api"), canary.BackendRefs[0].Name)
+	require.Equal(t, int32(90), *canary.BackendRefs[0].Weight)
+	require.Equal(t, gatewayv1.ObjectName("wandb-api-canary"), canary.BackendRefs[1].Name)
+	require.Equal(t, gatewayv1.PortNumber(8081), *canary.BackendRefs[1].Port)
+	require.Equal(t, int32(10), *canary.BackendRefs[1].Weight)
+	require.Equal(t, []gatewayv1.HTTPRouteFilter{stripHeaders}, canary.Filters, "the default rule's actions do not leak into rules")
+	require.Nil(t, canary.Timeouts)
+
+	def := rules[1]
+	require.Len(t, def.Matches, 2)
+	require.Nil(t, def.Matches[0].Headers)
+	require.Len(t, def.BackendRefs, 1)
+	require.Len(t, def.Filters, 2)
+	require.Equal(t, stripHeaders, def.Filters[0])
+	require.Equal(t, gatewayv1.HTTPRouteFilterRequestMirror, def.Filters[1].Type)
+	require.Equal(t, gatewayv1.ObjectName("wandb-api-shadow"), def.Filters[1].RequestMirror.BackendRef.Name)
+	require.Equal(t, int32(5), *def.Filters[1].RequestMirror.Percent)
+	require.Equal(t, gatewayv1.Duration("10m"), *def.Timeouts.Request)
+	require.Nil(t, def.Timeouts.BackendRequest)
+	require.Equal(t, 2, *def.Retry.Attempts)
+	require.Equal(t, gatewayv1.Duration("100ms"), *def.Retry.Backoff)
+	require.Equal(t, []gatewayv1.HTTPRouteRetryStatusCode{503}, def.Retry.Codes)
+	require.Len(t, backend.Filters, 1, "the backend's filters are not modified")
+}
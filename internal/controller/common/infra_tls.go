@@ -0,0 +1,395 @@
+package common
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// InfraTLSCAKey is the key holding the issuing CA in a managed instance's
+	// server certificate Secret; cert-manager uses the same key.
+	InfraTLSCAKey = "ca.crt"
+
+	// InfraTLSChecksumAnnotation carries a hash of the server certificate on
+	// the vendor pod template, so a reissued certificate rolls the pods.
+	InfraTLSChecksumAnnotation = "weightsandbiases.apps.wandb.com/tls-checksum"
+
+	infraTLSComponent = "infra-tls"
+	infraTLSTypeName  = "InfraTLSSecret"
+
+	infraTLSCAValidity     = 10 * 365 * 24 * time.Hour
+	infraTLSServerValidity = 365 * 24 * time.Hour
+	// infraTLSRenewBefore reissues operator-CA server certificates this long
+	// before they expire.
+	infraTLSRenewBefore = 30 * 24 * time.Hour
+)
+
+var certManagerCertificateGVK = schema.GroupVersionKind{
+	Group:   "cert-manager.io",
+	Version: "v1",
+	Kind:    "Certificate",
+}
+
+// InfraTLSEnabled reports whether a managed instance serves TLS.
+func InfraTLSEnabled(tls *apiv2.InfraTLSSpec) bool {
+	return tls != nil && tls.Enabled
+}
+
+// InfraTLSSecretName names a managed instance's server certificate Secret.
+func InfraTLSSecretName(instanceName string) string {
+	return instanceName + "-tls"
+}
+
+// InfraTLSCASecretName names the Secret holding the operator CA that signs
+// server certificates when no cert-manager issuer is configured.
+func InfraTLSCASecretName(wandb *apiv2.WeightsAndBiases) string {
+	return wandb.Name + "-infra-ca"
+}
+
+// InfraServiceDNSNames lists the names a server certificate must cover for
+// each Service: the short, namespaced and fully qualified forms, plus a
+// wildcard for the per-pod records of a headless Service.
+func InfraServiceDNSNames(namespace string, services ...string) []string {
+	var names []string
+	for _, svc := range services {
+		names = append(names,
+			svc,
+			fmt.Sprintf("%s.%s", svc, namespace),
+			fmt.Sprintf("%s.%s.svc", svc, namespace),
+			fmt.Sprintf("%s.%s.svc.cluster.local", svc, namespace),
+			fmt.Sprintf("*.%s.%s.svc", svc, namespace),
+			fmt.Sprintf("*.%s.%s.svc.cluster.local", svc, namespace),
+		)
+	}
+	return names
+}
+
+// InfraTLSChecksum hashes the certificate material of a server certificate
+// Secret; see InfraTLSChecksumAnnotation.
+func InfraTLSChecksum(secret *corev1.Secret) string {
+	hash := sha256.New()
+	for _, key := range []string{InfraTLSCAKey, corev1.TLSCertKey} {
+		_, _ = hash.Write(secret.Data[key])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// EnsureInfraTLSSecret makes sure the server certificate Secret nn exists and
+// covers dnsNames. With a cert-manager issuer it applies a Certificate and
+// waits for cert-manager to fill the Secret; otherwise it signs the
+// certificate with the operator CA, reissuing it ahead of expiry or when the
+// names or CA change. It returns nil while the certificate is not issued yet.
+func EnsureInfraTLSSecret(
+	ctx context.Context,
+	c client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	tls *apiv2.InfraTLSSpec,
+	nn types.NamespacedName,
+	dnsNames []string,
+	labels map[string]string,
+) (*corev1.Secret, error) {
+	if tls.CertManager != nil {
+		return ensureCertManagerCertificate(ctx, c, wandb, tls.CertManager, nn, dnsNames, labels)
+	}
+
+	caCert, caKey, caPEM, err := ensureInfraCA(ctx, c, wandb)
+	if err != nil {
+		return nil, err
+	}
+
+	actual := &corev1.Secret{}
+	found, err := GetResource(ctx, c, nn, infraTLSTypeName, actual)
+	if err != nil {
+		return nil, err
+	}
+	if found && !serverCertNeedsReissue(actual, caPEM, dnsNames, time.Now()) {
+		return actual, nil
+	}
+
+	certPEM, keyPEM, err := issueServerCert(caCert, caKey, nn.Name, dnsNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue server certificate %s: %w", nn.Name, err)
+	}
+	desired := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nn.Name,
+			Namespace: nn.Namespace,
+			Labels:    labels,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			InfraTLSCAKey:           caPEM,
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}
+	if wandb.Namespace == nn.Namespace {
+		if err := controllerutil.SetOwnerReference(wandb, desired, c.Scheme()); err != nil {
+			return nil, err
+		}
+	}
+	if !found {
+		actual = nil
+	}
+	if _, err := CrudResource(ctx, c, desired, actual); err != nil {
+		return nil, err
+	}
+	return desired, nil
+}
+
+// ReadInfraTLSCA returns the CA that signed the server certificate in Secret
+// nn, or "" when the Secret or its CA is missing.
+func ReadInfraTLSCA(ctx context.Context, c client.Client, nn types.NamespacedName) (string, error) {
+	secret := &corev1.Secret{}
+	found, err := GetResource(ctx, c, nn, infraTLSTypeName, secret)
+	if err != nil || !found {
+		return "", err
+	}
+	return string(secret.Data[InfraTLSCAKey]), nil
+}
+
+// ensureInfraCA loads the operator CA, creating it on first use. The CA is
+// kept for the life of the CR so applications keep trusting reissued server
+// certificates.
+func ensureInfraCA(ctx context.Context, c client.Client, wandb *apiv2.WeightsAndBiases) (*x509.Certificate, *ecdsa.PrivateKey, []byte, error) {
+	nn := types.NamespacedName{Namespace: wandb.Namespace, Name: InfraTLSCASecretName(wandb)}
+	secret := &corev1.Secret{}
+	found, err := GetResource(ctx, c, nn, infraTLSTypeName, secret)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if found {
+		cert, key, err := parseCertAndKey(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("infra CA Secret %s is invalid: %w", nn.Name, err)
+		}
+		return cert, key, secret.Data[corev1.TLSCertKey], nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber:          randomSerial(),
+		Subject:               pkix.Name{CommonName: fmt.Sprintf("%s infra CA", wandb.Name), Organization: []string{"wandb-operator"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(infraTLSCAValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	certPEM, keyPEM, err := encodeCertAndKey(der, key)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nn.Name,
+			Namespace: nn.Namespace,
+			Labels:    BuildWandbLabels(wandb, infraTLSComponent),
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       certPEM,
+			corev1.TLSPrivateKeyKey: keyPEM,
+		},
+	}
+	if err := controllerutil.SetOwnerReference(wandb, secret, c.Scheme()); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := c.Create(ctx, secret); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create infra CA Secret %s: %w", nn.Name, err)
+	}
+	return cert, key, certPEM, nil
+}
+
+// serverCertNeedsReissue reports whether an operator-issued server
+// certificate is unusable, close to expiry, signed by another CA, or missing
+// one of dnsNames.
+func serverCertNeedsReissue(secret *corev1.Secret, caPEM []byte, dnsNames []string, now time.Time) bool {
+	if !bytes.Equal(secret.Data[InfraTLSCAKey], caPEM) {
+		return true
+	}
+	cert, _, err := parseCertAndKey(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return true
+	}
+	if now.Add(infraTLSRenewBefore).After(cert.NotAfter) {
+		return true
+	}
+	return !slices.Equal(cert.DNSNames, dnsNames)
+}
+
+func issueServerCert(caCert *x509.Certificate, caKey *ecdsa.PrivateKey, commonName string, dnsNames []string) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: randomSerial(),
+		Subject:      pkix.Name{CommonName: commonName, Organization: []string{"wandb-operator"}},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(infraTLSServerValidity),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		// Replicas also dial each other with the same certificate.
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return encodeCertAndKey(der, key)
+}
+
+func encodeCertAndKey(der []byte, key *ecdsa.PrivateKey) ([]byte, []byte, error) {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+func parseCertAndKey(certPEM, keyPEM []byte) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	certBlock, _ := pem.Decode(certPEM)
+	if certBlock == nil {
+		return nil, nil, errors.New("no certificate PEM block")
+	}
+	cert, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return nil, nil, errors.New("no private key PEM block")
+	}
+	key, err := x509.ParseECPrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cert, key, nil
+}
+
+func randomSerial() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 127))
+	if err != nil {
+		return big.NewInt(time.Now().UnixNano())
+	}
+	return serial
+}
+
+// ensureCertManagerCertificate applies the cert-manager Certificate for nn and
+// returns its Secret once cert-manager has issued it with a CA.
+func ensureCertManagerCertificate(
+	ctx context.Context,
+	c client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	issuer *apiv2.CertManagerConfig,
+	nn types.NamespacedName,
+	dnsNames []string,
+	labels map[string]string,
+) (*corev1.Secret, error) {
+	if !utils.IsServerResource(fmt.Sprintf("%s.%s/%s", certManagerCertificateGVK.Kind, certManagerCertificateGVK.Group, certManagerCertificateGVK.Version)) {
+		return nil, errors.New("tls.certManager is set but the cert-manager Certificate API is not installed")
+	}
+
+	issuerRef := map[string]any{"name": issuer.Issuer, "kind": "Issuer", "group": "cert-manager.io"}
+	if issuer.ClusterIssuer != "" {
+		issuerRef = map[string]any{"name": issuer.ClusterIssuer, "kind": "ClusterIssuer", "group": "cert-manager.io"}
+	}
+	names := make([]any, 0, len(dnsNames))
+	for _, name := range dnsNames {
+		names = append(names, name)
+	}
+
+	desired := &unstructured.Unstructured{}
+	desired.SetGroupVersionKind(certManagerCertificateGVK)
+	desired.SetName(nn.Name)
+	desired.SetNamespace(nn.Namespace)
+	desired.SetLabels(labels)
+	desired.Object["spec"] = map[string]any{
+		"secretName": nn.Name,
+		"commonName": nn.Name,
+		"dnsNames":   names,
+		"issuerRef":  issuerRef,
+		"usages":     []any{"server auth", "client auth"},
+		"privateKey": map[string]any{"algorithm": "ECDSA", "rotationPolicy": "Always"},
+		"secretTemplate": map[string]any{
+			"labels": toAnyMap(labels),
+		},
+	}
+	if wandb.Namespace == nn.Namespace {
+		if err := controllerutil.SetOwnerReference(wandb, desired, c.Scheme()); err != nil {
+			return nil, err
+		}
+	}
+
+	actual := &unstructured.Unstructured{}
+	actual.SetGroupVersionKind(certManagerCertificateGVK)
+	found, err := GetResource(ctx, c, nn, certManagerCertificateGVK.Kind, actual)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		actual = nil
+	}
+	if _, err := CrudResource(ctx, c, desired, actual); err != nil {
+		return nil, err
+	}
+
+	secret := &corev1.Secret{}
+	found, err = GetResource(ctx, c, nn, infraTLSTypeName, secret)
+	if err != nil || !found {
+		return nil, err
+	}
+	if len(secret.Data[corev1.TLSCertKey]) == 0 || len(secret.Data[corev1.TLSPrivateKeyKey]) == 0 {
+		return nil, nil
+	}
+	if len(secret.Data[InfraTLSCAKey]) == 0 {
+		return nil, fmt.Errorf("cert-manager issued %s without %s; use an issuer that publishes its CA", nn.Name, InfraTLSCAKey)
+	}
+	return secret, nil
+}
+
+func toAnyMap(in map[string]string) map[string]any {
+	out := make(map[string]any, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
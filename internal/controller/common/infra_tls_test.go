@@ -0,0 +1,135 @@
+package common
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"slices"
+	"testing"
+	"time"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestEnsureInfraTLSSecretIssuesFromOperatorCA(t *testing.T) {
+	t.Parallel()
+
+	c, wandb := newInfraTLSClient(t)
+	nn := types.NamespacedName{Name: InfraTLSSecretName("wandb-mysql"), Namespace: "default"}
+	dnsNames := InfraServiceDNSNames("default", "moco-wandb-mysql-primary")
+
+	secret, err := EnsureInfraTLSSecret(context.Background(), c, wandb, &apiv2.InfraTLSSpec{Enabled: true}, nn, dnsNames, nil)
+	if err != nil {
+		t.Fatalf("ensure server certificate: %v", err)
+	}
+	cert := parseTestCert(t, secret.Data[corev1.TLSCertKey])
+	if !slices.Equal(cert.DNSNames, dnsNames) {
+		t.Fatalf("DNSNames = %v, want %v", cert.DNSNames, dnsNames)
+	}
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(secret.Data[InfraTLSCAKey])
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: roots, DNSName: "moco-wandb-mysql-primary.default.svc"}); err != nil {
+		t.Fatalf("server certificate does not chain to the published CA: %v", err)
+	}
+
+	ca, err := ReadInfraTLSCA(context.Background(), c, nn)
+	if err != nil {
+		t.Fatalf("read CA: %v", err)
+	}
+	if ca != string(secret.Data[InfraTLSCAKey]) {
+		t.Fatalf("ReadInfraTLSCA returned a different CA than the server Secret")
+	}
+}
+
+func TestEnsureInfraTLSSecretReissuesOnlyWhenNamesChange(t *testing.T) {
+	t.Parallel()
+
+	c, wandb := newInfraTLSClient(t)
+	tls := &apiv2.InfraTLSSpec{Enabled: true}
+	nn := types.NamespacedName{Name: InfraTLSSecretName("wandb-clickhouse"), Namespace: "default"}
+	names := InfraServiceDNSNames("default", "chi-wandb-clickhouse-default-0-0")
+
+	first, err := EnsureInfraTLSSecret(context.Background(), c, wandb, tls, nn, names, nil)
+	if err != nil {
+		t.Fatalf("ensure server certificate: %v", err)
+	}
+	again, err := EnsureInfraTLSSecret(context.Background(), c, wandb, tls, nn, names, nil)
+	if err != nil {
+		t.Fatalf("ensure server certificate: %v", err)
+	}
+	if string(again.Data[corev1.TLSCertKey]) != string(first.Data[corev1.TLSCertKey]) {
+		t.Fatalf("unchanged names reissued the certificate")
+	}
+
+	scaled := append(names, InfraServiceDNSNames("default", "chi-wandb-clickhouse-default-0-1")...)
+	reissued, err := EnsureInfraTLSSecret(context.Background(), c, wandb, tls, nn, scaled, nil)
+	if err != nil {
+		t.Fatalf("ensure server certificate: %v", err)
+	}
+	if string(reissued.Data[corev1.TLSCertKey]) == string(first.Data[corev1.TLSCertKey]) {
+		t.Fatalf("added names did not reissue the certificate")
+	}
+	if string(reissued.Data[InfraTLSCAKey]) != string(first.Data[InfraTLSCAKey]) {
+		t.Fatalf("reissue rotated the CA; applications would stop trusting the server")
+	}
+	if InfraTLSChecksum(reissued) == InfraTLSChecksum(first) {
+		t.Fatalf("checksum did not change with the certificate")
+	}
+}
+
+func TestServerCertNeedsReissueAheadOfExpiry(t *testing.T) {
+	t.Parallel()
+
+	c, wandb := newInfraTLSClient(t)
+	nn := types.NamespacedName{Name: InfraTLSSecretName("wandb-redis"), Namespace: "default"}
+	names := InfraServiceDNSNames("default", "wandb-redis")
+	secret, err := EnsureInfraTLSSecret(context.Background(), c, wandb, &apiv2.InfraTLSSpec{Enabled: true}, nn, names, nil)
+	if err != nil {
+		t.Fatalf("ensure server certificate: %v", err)
+	}
+	caPEM := secret.Data[InfraTLSCAKey]
+
+	if serverCertNeedsReissue(secret, caPEM, names, time.Now()) {
+		t.Fatalf("fresh certificate flagged for reissue")
+	}
+	if !serverCertNeedsReissue(secret, caPEM, names, time.Now().Add(infraTLSServerValidity-infraTLSRenewBefore+time.Hour)) {
+		t.Fatalf("certificate inside the renewal window not flagged for reissue")
+	}
+	if !serverCertNeedsReissue(secret, []byte("other-ca"), names, time.Now()) {
+		t.Fatalf("certificate from another CA not flagged for reissue")
+	}
+}
+
+func newInfraTLSClient(t *testing.T) (client.Client, *apiv2.WeightsAndBiases) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add corev1 scheme: %v", err)
+	}
+	if err := apiv2.AddToScheme(scheme); err != nil {
+		t.Fatalf("add apiv2 scheme: %v", err)
+	}
+	wandb := &apiv2.WeightsAndBiases{
+		ObjectMeta: metav1.ObjectMeta{Name: "wandb", Namespace: "default", UID: "wandb-uid"},
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(wandb).Build(), wandb
+}
+
+func parseTestCert(t *testing.T, data []byte) *x509.Certificate {
+	t.Helper()
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatalf("no PEM block in certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	return cert
+}
@@ -58,6 +58,28 @@ const (
 	DeleteAction    = "Delete"
 )
 
+// CrudResourceByName applies desired over whatever lives at nsName, read
+// into actual; a nil desired deletes it.
+func CrudResourceByName[T client.Object](
+	ctx context.Context,
+	c client.Client,
+	nsName types.NamespacedName,
+	resourceTypeName string,
+	desired T,
+	actual T,
+) error {
+	found, err := GetResource(ctx, c, nsName, resourceTypeName, actual)
+	if err != nil {
+		return err
+	}
+	if !found {
+		var zero T
+		actual = zero
+	}
+	_, err = CrudResource(ctx, c, desired, actual)
+	return err
+}
+
 // CrudResource is a generic function that gets a resource, and creates it if not found, or updates it if it exists.
 // The getter function should return (nil, nil) if the resource is not found.
 func CrudResource[T client.Object](ctx context.Context, c client.Client, desired T, actual T) (CrudAction, error) {
@@ -220,3 +242,13 @@ func IsNil[T any](v T) bool {
 	// Since we've already checked that the Kind is Pointer, we can safely call IsNil().
 	return val.IsNil()
 }
+
+// StorageClassName converts an optional storageClassName from the CR into the
+// PVC field. An empty name stays nil so the claim uses the cluster default
+// class; a pointer to "" would instead disable dynamic provisioning.
+func StorageClassName(name string) *string {
+	if name == "" {
+		return nil
+	}
+	return &name
+}
@@ -10,6 +10,9 @@ type OnDeletePolicy string
 const (
 	Purge  OnDeletePolicy = "purge"
 	Detach OnDeletePolicy = "detach"
+	// Snapshot purges like Purge, but only from the retention finalizer once
+	// the PVCs have been snapshotted; nothing is deleted outside it.
+	Snapshot OnDeletePolicy = "snapshot"
 )
 
 type OnDeleteRule struct {
@@ -25,11 +28,20 @@ func ToOnDeleteRule(
 	componentName string,
 ) OnDeleteRule {
 	policy := Detach
-	if retentionPolicy.OnDelete == apiv2.PurgeOnDelete {
+	switch retentionPolicy.OnDelete {
+	case apiv2.PurgeOnDelete:
 		policy = Purge
+	case apiv2.SnapshotOnDelete:
+		policy = Snapshot
 	}
 	return OnDeleteRule{
 		Policy:   policy,
 		Selector: labels.SelectorFromSet(BuildWandbLabels(wandb, componentName)),
 	}
 }
+
+// Purges reports whether the retention finalizer deletes the selected
+// resources.
+func (r OnDeleteRule) Purges() bool {
+	return r.Policy == Purge || r.Policy == Snapshot
+}
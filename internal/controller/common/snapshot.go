@@ -0,0 +1,127 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/wandb/operator/internal/logx"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// VolumeSnapshotGVK is the CSI snapshot API. It is a CRD installed with the
+// external snapshotter, so the operator talks to it without a typed client.
+var VolumeSnapshotGVK = schema.GroupVersionKind{
+	Group:   "snapshot.storage.k8s.io",
+	Version: "v1",
+	Kind:    "VolumeSnapshot",
+}
+
+// SourcePVCAnnotation records which PVC a VolumeSnapshot was taken of.
+const SourcePVCAnnotation = "weightsandbiases.apps.wandb.com/source-pvc"
+
+// ErrVolumeSnapshotsPending is returned while snapshots exist but are not all
+// ready to use yet.
+var ErrVolumeSnapshotsPending = errors.New("waiting for volume snapshots to become ready to use")
+
+// IsVolumeSnapshotAPIMissing reports whether err means the cluster does not
+// serve the VolumeSnapshot API.
+func IsVolumeSnapshotAPIMissing(err error) bool {
+	return meta.IsNoMatchError(err)
+}
+
+// VolumeSnapshot is a snapshot taken of a PVC before it is purged.
+type VolumeSnapshot struct {
+	Namespace  string
+	Name       string
+	PVC        string
+	ReadyToUse bool
+}
+
+// VolumeSnapshotName names the snapshot of pvc. The PVC's UID keeps a PVC
+// recreated under the same name from reusing an older snapshot.
+func VolumeSnapshotName(pvc *corev1.PersistentVolumeClaim) string {
+	suffix := "-" + string(pvc.UID)
+	if len(suffix) > 9 {
+		suffix = suffix[:9]
+	}
+	return sanitizeLabelPrefix(pvc.Name, 253-len(suffix)) + suffix
+}
+
+// SnapshotVolumeClaims ensures a VolumeSnapshot of every bound, live PVC in
+// namespace that selector matches, using the cluster's default
+// VolumeSnapshotClass. The snapshots carry the PVC's labels but no owner, so
+// they outlive the purge. A snapshot the snapshotter failed to take is an
+// error, since purging would lose the data.
+func SnapshotVolumeClaims(
+	ctx context.Context,
+	cl client.Client,
+	namespace string,
+	selector labels.Selector,
+) ([]VolumeSnapshot, error) {
+	log := logx.GetSlog(ctx)
+
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := cl.List(ctx, pvcList, &client.ListOptions{Namespace: namespace, LabelSelector: selector}); err != nil {
+		return nil, err
+	}
+
+	var snapshots []VolumeSnapshot
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		if pvc.Status.Phase != corev1.ClaimBound {
+			log.Debug("Skipping snapshot of unbound PVC", "name", pvc.Name, "phase", pvc.Status.Phase)
+			continue
+		}
+		// A PVC already being deleted was snapshotted before its purge; the
+		// finalizers run again for every instance while another one waits.
+		if pvc.DeletionTimestamp != nil {
+			continue
+		}
+		nsName := types.NamespacedName{Namespace: namespace, Name: VolumeSnapshotName(pvc)}
+
+		actual := &unstructured.Unstructured{}
+		actual.SetGroupVersionKind(VolumeSnapshotGVK)
+		err := cl.Get(ctx, nsName, actual)
+		switch {
+		case apierrors.IsNotFound(err):
+			actual = desiredVolumeSnapshot(nsName, pvc)
+			if err := cl.Create(ctx, actual); err != nil {
+				return nil, err
+			}
+			log.Info("Created VolumeSnapshot", "namespace", namespace, "name", nsName.Name, "pvc", pvc.Name)
+		case err != nil:
+			return nil, err
+		}
+
+		if message, _, _ := unstructured.NestedString(actual.Object, "status", "error", "message"); message != "" {
+			return nil, fmt.Errorf("volume snapshot %s/%s of PVC %s failed: %s", namespace, nsName.Name, pvc.Name, message)
+		}
+		ready, _, _ := unstructured.NestedBool(actual.Object, "status", "readyToUse")
+		snapshots = append(snapshots, VolumeSnapshot{
+			Namespace:  namespace,
+			Name:       nsName.Name,
+			PVC:        pvc.Name,
+			ReadyToUse: ready,
+		})
+	}
+	return snapshots, nil
+}
+
+func desiredVolumeSnapshot(nsName types.NamespacedName, pvc *corev1.PersistentVolumeClaim) *unstructured.Unstructured {
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+	snapshot.SetNamespace(nsName.Namespace)
+	snapshot.SetName(nsName.Name)
+	snapshot.SetLabels(pvc.Labels)
+	snapshot.SetAnnotations(map[string]string{SourcePVCAnnotation: pvc.Name})
+	_ = unstructured.SetNestedField(snapshot.Object, pvc.Name, "spec", "source", "persistentVolumeClaimName")
+	return snapshot
+}
@@ -0,0 +1,146 @@
+package common
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestSnapshotVolumeClaimsSnapshotsSelectedBoundClaims(t *testing.T) {
+	t.Parallel()
+
+	selected := map[string]string{WandbComponentLabel: "mysql"}
+	terminating := testSnapshotPVC("data-db-2", "uid-3333-dddd", selected, corev1.ClaimBound)
+	terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	terminating.Finalizers = []string{"kubernetes.io/pvc-protection"}
+	c := newSnapshotClient(t, true,
+		testSnapshotPVC("data-db-0", "uid-0000-aaaa", selected, corev1.ClaimBound),
+		testSnapshotPVC("data-db-1", "uid-1111-bbbb", selected, corev1.ClaimPending),
+		terminating,
+		testSnapshotPVC("data-cache-0", "uid-2222-cccc", map[string]string{WandbComponentLabel: "redis"}, corev1.ClaimBound),
+	)
+
+	snapshots, err := SnapshotVolumeClaims(context.Background(), c, "default", labels.SelectorFromSet(selected))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 || snapshots[0].Name != "data-db-0-uid-0000" || snapshots[0].PVC != "data-db-0" || snapshots[0].ReadyToUse {
+		t.Fatalf("snapshots = %+v, want one pending snapshot of data-db-0", snapshots)
+	}
+
+	snapshot := getVolumeSnapshot(t, c, "data-db-0-uid-0000")
+	source, _, _ := unstructured.NestedString(snapshot.Object, "spec", "source", "persistentVolumeClaimName")
+	if source != "data-db-0" {
+		t.Fatalf("snapshot source = %q, want data-db-0", source)
+	}
+	if snapshot.GetLabels()[WandbComponentLabel] != "mysql" || len(snapshot.GetOwnerReferences()) != 0 {
+		t.Fatalf("snapshot should carry the PVC labels and no owner: %v", snapshot.Object["metadata"])
+	}
+}
+
+func TestSnapshotVolumeClaimsReportsReadiness(t *testing.T) {
+	t.Parallel()
+
+	pvc := testSnapshotPVC("data-db-0", "uid-0000-aaaa", nil, corev1.ClaimBound)
+	c := newSnapshotClient(t, true, pvc)
+	if _, err := SnapshotVolumeClaims(context.Background(), c, "default", labels.Everything()); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := getVolumeSnapshot(t, c, "data-db-0-uid-0000")
+	_ = unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse")
+	if err := c.Update(context.Background(), snapshot); err != nil {
+		t.Fatal(err)
+	}
+	snapshots, err := SnapshotVolumeClaims(context.Background(), c, "default", labels.Everything())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshots) != 1 || !snapshots[0].ReadyToUse {
+		t.Fatalf("snapshots = %+v, want one ready snapshot", snapshots)
+	}
+
+	_ = unstructured.SetNestedField(snapshot.Object, false, "status", "readyToUse")
+	_ = unstructured.SetNestedField(snapshot.Object, "no snapshot class", "status", "error", "message")
+	if err := c.Update(context.Background(), snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SnapshotVolumeClaims(context.Background(), c, "default", labels.Everything()); err == nil ||
+		!strings.Contains(err.Error(), "no snapshot class") {
+		t.Fatalf("err = %v, want the snapshot error", err)
+	}
+}
+
+func TestSnapshotVolumeClaimsDetectsMissingAPI(t *testing.T) {
+	t.Parallel()
+
+	c := newSnapshotClient(t, false, testSnapshotPVC("data-db-0", "uid-0000-aaaa", nil, corev1.ClaimBound))
+	_, err := SnapshotVolumeClaims(context.Background(), c, "default", labels.Everything())
+	if !IsVolumeSnapshotAPIMissing(err) {
+		t.Fatalf("err = %v, want a missing API error", err)
+	}
+}
+
+func testSnapshotPVC(name, uid string, pvcLabels map[string]string, phase corev1.PersistentVolumeClaimPhase) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID(uid), Labels: pvcLabels},
+		Status:     corev1.PersistentVolumeClaimStatus{Phase: phase},
+	}
+}
+
+func getVolumeSnapshot(t *testing.T, c client.Client, name string) *unstructured.Unstructured {
+	t.Helper()
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(VolumeSnapshotGVK)
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, snapshot); err != nil {
+		t.Fatalf("get VolumeSnapshot %s: %v", name, err)
+	}
+	return snapshot
+}
+
+// newSnapshotClient builds a fake client that serves the VolumeSnapshot API
+// only when withSnapshotAPI is set; otherwise snapshot requests fail the way
+// a REST mapper without the kind fails them.
+func newSnapshotClient(t *testing.T, withSnapshotAPI bool, objects ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add core API to scheme: %v", err)
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...)
+	if !withSnapshotAPI {
+		noMatch := func(obj client.Object) error {
+			if u, ok := obj.(*unstructured.Unstructured); ok && u.GroupVersionKind() == VolumeSnapshotGVK {
+				return &meta.NoKindMatchError{GroupKind: VolumeSnapshotGVK.GroupKind(), SearchedVersions: []string{VolumeSnapshotGVK.Version}}
+			}
+			return nil
+		}
+		builder = builder.WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if err := noMatch(obj); err != nil {
+					return err
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if err := noMatch(obj); err != nil {
+					return err
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		})
+	}
+	return builder.Build()
+}
@@ -0,0 +1,183 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/wandb/operator/internal/logx"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// VolumeExpansionType tracks growing an instance's PVCs to its storageSize.
+	VolumeExpansionType = "VolumeExpansion"
+
+	VolumesAtSizeReason        = "VolumesAtSize"
+	ExpansionInProgressReason  = "ExpansionInProgress"
+	ExpansionUnsupportedReason = "ExpansionUnsupported"
+)
+
+// VolumeClaimSize is the size wanted for every PVC named one of NamePrefixes
+// followed by Ordinals dash-separated numbers. StatefulSet claims are
+// "<template>-<statefulset>-<ordinal>", so a prefix selects one template of
+// one instance. The ordinals must match exactly: the prefix of instance
+// "wandb" is also a prefix of every claim of instance "wandb-2".
+type VolumeClaimSize struct {
+	NamePrefixes []string
+	// Ordinals is how many numbers follow the prefix. Zero means one, the
+	// StatefulSet ordinal; Altinity claims end in "<shard>-<replica>-<ordinal>".
+	Ordinals int
+	Size     resource.Quantity
+}
+
+// ExpandVolumeClaims grows bound PVCs matched by claims to their desired size.
+// volumeClaimTemplates are immutable on a StatefulSet, so none of the vendor
+// operators resize existing claims on their own; patching the claim's request
+// lets the CSI driver expand the volume online. Claims are never shrunk, and a
+// claim whose StorageClass does not set allowVolumeExpansion is reported
+// rather than patched, since the apiserver would reject the patch anyway.
+func ExpandVolumeClaims(
+	ctx context.Context,
+	cl client.Client,
+	namespace string,
+	claims ...VolumeClaimSize,
+) metav1.Condition {
+	log := logx.GetSlog(ctx)
+
+	pvcList := &corev1.PersistentVolumeClaimList{}
+	if err := cl.List(ctx, pvcList, &client.ListOptions{Namespace: namespace}); err != nil {
+		log.Error("failed to list PVCs for expansion", logx.ErrAttr(err))
+		return metav1.Condition{
+			Type:   VolumeExpansionType,
+			Status: metav1.ConditionUnknown,
+			Reason: ApiErrorReason,
+		}
+	}
+
+	expandable := map[string]bool{}
+	var unsupported, pending []string
+	for i := range pvcList.Items {
+		pvc := &pvcList.Items[i]
+		size, ok := desiredClaimSize(pvc.Name, claims)
+		if !ok || size.IsZero() || pvc.Status.Phase != corev1.ClaimBound {
+			continue
+		}
+
+		requested := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		if requested.Cmp(size) < 0 {
+			className := ""
+			if pvc.Spec.StorageClassName != nil {
+				className = *pvc.Spec.StorageClassName
+			}
+			allowed, seen := expandable[className]
+			if !seen {
+				var err error
+				if allowed, err = storageClassAllowsExpansion(ctx, cl, className); err != nil {
+					return metav1.Condition{
+						Type:   VolumeExpansionType,
+						Status: metav1.ConditionUnknown,
+						Reason: ApiErrorReason,
+					}
+				}
+				expandable[className] = allowed
+			}
+			if !allowed {
+				unsupported = append(unsupported, pvc.Name)
+				continue
+			}
+
+			patch := client.MergeFrom(pvc.DeepCopy())
+			if pvc.Spec.Resources.Requests == nil {
+				pvc.Spec.Resources.Requests = corev1.ResourceList{}
+			}
+			pvc.Spec.Resources.Requests[corev1.ResourceStorage] = size
+			if err := cl.Patch(ctx, pvc, patch); err != nil {
+				log.Error("failed to patch PVC storage request", logx.ErrAttr(err), "pvc", pvc.Name)
+				return metav1.Condition{
+					Type:   VolumeExpansionType,
+					Status: metav1.ConditionFalse,
+					Reason: ApiErrorReason,
+				}
+			}
+			log.Info("requested PVC expansion", "pvc", pvc.Name, "from", requested.String(), "to", size.String())
+		}
+
+		capacity := pvc.Status.Capacity[corev1.ResourceStorage]
+		if capacity.Cmp(size) < 0 {
+			pending = append(pending, pvc.Name)
+		}
+	}
+
+	switch {
+	case len(unsupported) > 0:
+		return metav1.Condition{
+			Type:   VolumeExpansionType,
+			Status: metav1.ConditionFalse,
+			Reason: ExpansionUnsupportedReason,
+			Message: fmt.Sprintf(
+				"cannot grow %s: the StorageClass does not allow volume expansion",
+				strings.Join(unsupported, ", "),
+			),
+		}
+	case len(pending) > 0:
+		return metav1.Condition{
+			Type:    VolumeExpansionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  ExpansionInProgressReason,
+			Message: fmt.Sprintf("waiting for %s to be resized", strings.Join(pending, ", ")),
+		}
+	}
+	return metav1.Condition{
+		Type:   VolumeExpansionType,
+		Status: metav1.ConditionTrue,
+		Reason: VolumesAtSizeReason,
+	}
+}
+
+func desiredClaimSize(pvcName string, claims []VolumeClaimSize) (resource.Quantity, bool) {
+	for _, claim := range claims {
+		for _, prefix := range claim.NamePrefixes {
+			if claimNameMatches(pvcName, prefix, max(claim.Ordinals, 1)) {
+				return claim.Size, true
+			}
+		}
+	}
+	return resource.Quantity{}, false
+}
+
+func claimNameMatches(pvcName, prefix string, ordinals int) bool {
+	rest, ok := strings.CutPrefix(pvcName, prefix)
+	if !ok {
+		return false
+	}
+	segments := strings.Split(rest, "-")
+	if len(segments) != ordinals {
+		return false
+	}
+	for _, segment := range segments {
+		if segment == "" || strings.Trim(segment, "0123456789") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// storageClassAllowsExpansion is false for claims without a class (statically
+// bound volumes) and for classes that are gone, as neither can be resized.
+func storageClassAllowsExpansion(ctx context.Context, cl client.Client, className string) (bool, error) {
+	if className == "" {
+		return false, nil
+	}
+	storageClass := &storagev1.StorageClass{}
+	found, err := GetResource(ctx, cl, types.NamespacedName{Name: className}, "StorageClass", storageClass)
+	if err != nil || !found {
+		return false, err
+	}
+	return storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion, nil
+}
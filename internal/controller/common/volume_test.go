@@ -0,0 +1,149 @@
+package common
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestExpandVolumeClaimsPatchesExpandableClaims(t *testing.T) {
+	t.Parallel()
+
+	c := newVolumeClient(t,
+		testStorageClass("expandable", true),
+		testBoundPVC("data-db-0", "expandable", "10Gi"),
+		testBoundPVC("data-db-1", "expandable", "10Gi"),
+		testBoundPVC("data-other-0", "expandable", "10Gi"),
+	)
+
+	cond := ExpandVolumeClaims(context.Background(), c, "default",
+		VolumeClaimSize{NamePrefixes: []string{"data-db-"}, Size: resource.MustParse("20Gi")})
+
+	if cond.Status != metav1.ConditionFalse || cond.Reason != ExpansionInProgressReason {
+		t.Fatalf("condition = %s/%s, want False/%s", cond.Status, cond.Reason, ExpansionInProgressReason)
+	}
+	for name, want := range map[string]string{"data-db-0": "20Gi", "data-db-1": "20Gi", "data-other-0": "10Gi"} {
+		if got := pvcRequest(t, c, name); got.Cmp(resource.MustParse(want)) != 0 {
+			t.Errorf("%s request = %s, want %s", name, got.String(), want)
+		}
+	}
+}
+
+func TestExpandVolumeClaimsReportsClassWithoutExpansion(t *testing.T) {
+	t.Parallel()
+
+	c := newVolumeClient(t,
+		testStorageClass("fixed", false),
+		testBoundPVC("data-db-0", "fixed", "10Gi"),
+	)
+
+	cond := ExpandVolumeClaims(context.Background(), c, "default",
+		VolumeClaimSize{NamePrefixes: []string{"data-db-"}, Size: resource.MustParse("20Gi")})
+
+	if cond.Status != metav1.ConditionFalse || cond.Reason != ExpansionUnsupportedReason {
+		t.Fatalf("condition = %s/%s, want False/%s", cond.Status, cond.Reason, ExpansionUnsupportedReason)
+	}
+	if got := pvcRequest(t, c, "data-db-0"); got.Cmp(resource.MustParse("10Gi")) != 0 {
+		t.Fatalf("request = %s, want it left at 10Gi", got.String())
+	}
+}
+
+func TestExpandVolumeClaimsNeverShrinks(t *testing.T) {
+	t.Parallel()
+
+	c := newVolumeClient(t,
+		testStorageClass("expandable", true),
+		testBoundPVC("data-db-0", "expandable", "50Gi"),
+	)
+
+	cond := ExpandVolumeClaims(context.Background(), c, "default",
+		VolumeClaimSize{NamePrefixes: []string{"data-db-"}, Size: resource.MustParse("20Gi")})
+
+	if cond.Status != metav1.ConditionTrue || cond.Reason != VolumesAtSizeReason {
+		t.Fatalf("condition = %s/%s, want True/%s", cond.Status, cond.Reason, VolumesAtSizeReason)
+	}
+	if got := pvcRequest(t, c, "data-db-0"); got.Cmp(resource.MustParse("50Gi")) != 0 {
+		t.Fatalf("request = %s, want it left at 50Gi", got.String())
+	}
+}
+
+func TestExpandVolumeClaimsLeavesInstanceWithLongerNameAlone(t *testing.T) {
+	t.Parallel()
+
+	// "data-wandb-" is a prefix of every claim of instance "wandb-2" too.
+	c := newVolumeClient(t,
+		testStorageClass("expandable", true),
+		testBoundPVC("data-wandb-0", "expandable", "10Gi"),
+		testBoundPVC("data-wandb-2-0", "expandable", "10Gi"),
+		testBoundPVC("chi-wandb-c-0-1-0", "expandable", "10Gi"),
+		testBoundPVC("chi-wandb-2-c-0-0-0", "expandable", "10Gi"),
+	)
+
+	ExpandVolumeClaims(context.Background(), c, "default",
+		VolumeClaimSize{NamePrefixes: []string{"data-wandb-"}, Size: resource.MustParse("20Gi")},
+		VolumeClaimSize{NamePrefixes: []string{"chi-wandb-c-"}, Ordinals: 3, Size: resource.MustParse("20Gi")})
+
+	for name, want := range map[string]string{
+		"data-wandb-0":        "20Gi",
+		"data-wandb-2-0":      "10Gi",
+		"chi-wandb-c-0-1-0":   "20Gi",
+		"chi-wandb-2-c-0-0-0": "10Gi",
+	} {
+		if got := pvcRequest(t, c, name); got.Cmp(resource.MustParse(want)) != 0 {
+			t.Errorf("%s request = %s, want %s", name, got.String(), want)
+		}
+	}
+}
+
+func testStorageClass(name string, allowExpansion bool) *storagev1.StorageClass {
+	return &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: name},
+		Provisioner:          "csi.example.com",
+		AllowVolumeExpansion: ptr.To(allowExpansion),
+	}
+}
+
+func testBoundPVC(name, className, size string) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			StorageClassName: ptr.To(className),
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+			},
+		},
+		Status: corev1.PersistentVolumeClaimStatus{
+			Phase:    corev1.ClaimBound,
+			Capacity: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(size)},
+		},
+	}
+}
+
+func pvcRequest(t *testing.T, c client.Client, name string) resource.Quantity {
+	t.Helper()
+	pvc := &corev1.PersistentVolumeClaim{}
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "default", Name: name}, pvc); err != nil {
+		t.Fatalf("get PVC %s: %v", name, err)
+	}
+	return pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+}
+
+func newVolumeClient(t *testing.T, objects ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add core API to scheme: %v", err)
+	}
+	if err := storagev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("add storage API to scheme: %v", err)
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
@@ -44,6 +44,8 @@ func WriteState(
 		"Database":    spec.Database,
 		"Replicated":  spec.Replicated,
 		"ClusterName": spec.ClusterName,
+		"Tls":         spec.Tls,
+		"SslCa":       spec.SslCa,
 	}
 
 	data, err := external.ResolveFields(ctx, c, wandb.Namespace, fields)
@@ -70,8 +72,10 @@ func ReadState(
 	nsName := types.NamespacedName{Namespace: wandb.Namespace, Name: connectionSecretName(key)}
 	secret, conditions, found := external.ReadConnectionSecret(ctx, c, nsName, newConditions)
 	if !found {
+		external.ForgetConnection(nsName)
 		return conditions, nil
 	}
+	conditions = append(conditions, external.VerifyConnection(ctx, nsName, secret.Data, probeConnection))
 
 	localRef := corev1.LocalObjectReference{Name: nsName.Name}
 	conn := &apiv2.ClickHouseConnection{
@@ -93,6 +97,12 @@ func ReadState(
 	if _, ok := secret.Data["ClusterName"]; ok {
 		conn.ClusterName = corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "ClusterName", Optional: ptr.To(false)}
 	}
+	if _, ok := secret.Data["Tls"]; ok {
+		conn.Tls = corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Tls", Optional: ptr.To(true)}
+	}
+	if _, ok := secret.Data["SslCa"]; ok {
+		conn.SslCa = corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "SslCa", Optional: ptr.To(true)}
+	}
 
 	return conditions, conn
 }
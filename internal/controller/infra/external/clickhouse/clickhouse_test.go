@@ -2,10 +2,12 @@ package clickhouse
 
 import (
 	"context"
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/infra/external"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -82,6 +84,19 @@ func fullyDeclaredConnection() *apiv2.ClickHouseConnection {
 	}
 }
 
+// stubProbe replaces the connectivity probe for a test and clears any cached
+// result so ReadState runs it.
+func stubProbe(t *testing.T, err error) {
+	t.Helper()
+	nsName := types.NamespacedName{Namespace: "default", Name: ConnectionSecretName}
+	external.ForgetConnection(nsName)
+	probeConnection = func(context.Context, map[string][]byte) error { return err }
+	t.Cleanup(func() {
+		probeConnection = Probe
+		external.ForgetConnection(nsName)
+	})
+}
+
 func baseSourceData() map[string][]byte {
 	return map[string][]byte{
 		"Host":     []byte("clickhouse.example.com"),
@@ -150,6 +165,7 @@ func TestReadStatePublishesTopologySelectorsWhenPresent(t *testing.T) {
 	data["Replicated"] = []byte("true")
 	data["ClusterName"] = []byte("weavecluster")
 	c := externalTestClient(t, wandb, data, withConnectionSecret(data))
+	stubProbe(t, nil)
 
 	_, conn := ReadState(context.Background(), c, wandb, apiv2.DefaultInstanceName, nil)
 
@@ -169,6 +185,7 @@ func TestReadStateLeavesTopologySelectorsUnsetWhenAbsent(t *testing.T) {
 
 	wandb := externalTestWandb(declared)
 	c := externalTestClient(t, wandb, baseSourceData(), withConnectionSecret(baseSourceData()))
+	stubProbe(t, nil)
 
 	_, conn := ReadState(context.Background(), c, wandb, apiv2.DefaultInstanceName, nil)
 
@@ -177,3 +194,17 @@ func TestReadStateLeavesTopologySelectorsUnsetWhenAbsent(t *testing.T) {
 	require.Empty(t, conn.ClusterName.Name)
 	require.Equal(t, "Host", conn.Host.Key, "the rest of the connection must still resolve")
 }
+
+func TestReadStateReportsConnectionVerification(t *testing.T) {
+	wandb := externalTestWandb(fullyDeclaredConnection())
+	c := externalTestClient(t, wandb, baseSourceData(), withConnectionSecret(baseSourceData()))
+	stubProbe(t, &net.DNSError{Err: "no such host", Name: "clickhouse.example.com", IsNotFound: true})
+
+	conditions, conn := ReadState(context.Background(), c, wandb, apiv2.DefaultInstanceName, nil)
+
+	require.NotNil(t, conn)
+	require.Len(t, conditions, 1)
+	require.Equal(t, external.ConnectionVerifiedType, conditions[0].Type)
+	require.Equal(t, metav1.ConditionFalse, conditions[0].Status)
+	require.Equal(t, external.DNSErrorReason, conditions[0].Reason)
+}
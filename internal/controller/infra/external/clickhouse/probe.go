@@ -0,0 +1,133 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/wandb/operator/internal/controller/infra/external"
+)
+
+const (
+	defaultHTTPPort  = "8123"
+	defaultHTTPSPort = "8443"
+)
+
+// ClickHouse exception codes that mean the credentials were rejected.
+var authExceptionCodes = map[string]bool{
+	"192": true, // UNKNOWN_USER
+	"193": true, // WRONG_PASSWORD
+	"516": true, // AUTHENTICATION_FAILED
+}
+
+// probeConnection is swapped out in tests that exercise ReadState without a
+// ClickHouse to talk to.
+var probeConnection external.Prober = Probe
+
+// Probe runs SELECT 1 against the HTTP interface of the external ClickHouse
+// described by the connection secret data.
+func Probe(ctx context.Context, data map[string][]byte) error {
+	endpoint, user, password, err := probeEndpoint(data)
+	if err != nil {
+		return err
+	}
+	query := endpoint.Query()
+	query.Set("query", "SELECT 1")
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+	if user != "" {
+		req.Header.Set("X-ClickHouse-User", user)
+		req.Header.Set("X-ClickHouse-Key", password)
+	}
+	if database := string(data["Database"]); database != "" {
+		req.Header.Set("X-ClickHouse-Database", database)
+	}
+
+	httpClient := http.DefaultClient
+	if endpoint.Scheme == "https" {
+		config, err := external.TLSConfig(endpoint.Hostname(), data["SslCa"])
+		if err != nil {
+			return err
+		}
+		httpClient = &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+	}
+
+	response, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = response.Body.Close() }()
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+
+	if authExceptionCodes[response.Header.Get("X-ClickHouse-Exception-Code")] ||
+		response.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("%w: %s", external.ErrAuthFailed, strings.TrimSpace(string(body)))
+	}
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("clickhouse returned %s: %s", response.Status, strings.TrimSpace(string(body)))
+	}
+	if strings.TrimSpace(string(body)) != "1" {
+		return fmt.Errorf("unexpected reply to SELECT 1: %q", strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// probeEndpoint derives the HTTP interface address. Discrete Host/HTTPPort keys
+// win; otherwise the host of the connection url is used, keeping its port only
+// when the url already speaks HTTP. A Tls or SslCa key selects HTTPS.
+func probeEndpoint(data map[string][]byte) (*url.URL, string, string, error) {
+	user, password := string(data["User"]), string(data["Password"])
+
+	if host := string(data["Host"]); host != "" {
+		port := string(data["HTTPPort"])
+		if port == "" {
+			port = defaultHTTPPort
+		}
+		scheme := "http"
+		if port == "8443" || port == "443" || probeWantsTLS(data) {
+			scheme = "https"
+		}
+		return &url.URL{Scheme: scheme, Host: net.JoinHostPort(host, port), Path: "/"}, user, password, nil
+	}
+
+	raw := string(data["url"])
+	if raw == "" {
+		return nil, "", "", fmt.Errorf("clickhouse connection has neither host nor url")
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("clickhouse url: %w", err)
+	}
+	if user == "" && parsed.User != nil {
+		user = parsed.User.Username()
+		password, _ = parsed.User.Password()
+	}
+
+	endpoint := &url.URL{Scheme: "http", Host: parsed.Host, Path: "/"}
+	switch parsed.Scheme {
+	case "http", "https":
+		endpoint.Scheme = parsed.Scheme
+	default:
+		endpoint.Host = net.JoinHostPort(parsed.Hostname(), defaultHTTPPort)
+		if probeWantsTLS(data) {
+			endpoint.Scheme = "https"
+			endpoint.Host = net.JoinHostPort(parsed.Hostname(), defaultHTTPSPort)
+		}
+	}
+	return endpoint, user, password, nil
+}
+
+func probeWantsTLS(data map[string][]byte) bool {
+	useTLS, _ := strconv.ParseBool(string(data["Tls"]))
+	_, hasCA := data["SslCa"]
+	return useTLS || hasCA
+}
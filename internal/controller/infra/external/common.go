@@ -157,9 +157,12 @@ func InferExternalStatus(
 		common.DefaultConditionExpiry,
 	)
 
+	// A connection that has been probed must also have answered; instances
+	// that were never probed (no connection yet) stay not ready anyway.
 	ready := hasConnection
 	for _, condition := range newConditions {
-		if condition.Type == common.ReconciledType && condition.Status != metav1.ConditionTrue {
+		if (condition.Type == common.ReconciledType || condition.Type == ConnectionVerifiedType) &&
+			condition.Status != metav1.ConditionTrue {
 			ready = false
 			break
 		}
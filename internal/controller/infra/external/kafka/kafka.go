@@ -0,0 +1,197 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/internal/controller/infra/external"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const ConnectionSecretName = "wandb-kafka-connection"
+const caCertPath = "/etc/ssl/certs/kafka_ca.pem"
+
+// Supported SASL mechanisms. SCRAM-SHA-512 is the default because it is what
+// MSK and Confluent Platform use for username/password auth.
+const (
+	SaslPlain       = "PLAIN"
+	SaslScramSha256 = "SCRAM-SHA-256"
+	SaslScramSha512 = "SCRAM-SHA-512"
+)
+
+func WriteState(
+	ctx context.Context,
+	c client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	spec *apiv2.KafkaConnection,
+) []metav1.Condition {
+	logger := ctrl.LoggerFrom(ctx)
+
+	fields := map[string]corev1.SecretKeySelector{
+		"Host":           spec.Host,
+		"Port":           spec.Port,
+		"BrokerEndpoint": spec.BrokerEndpoint,
+		"ClusterID":      spec.ClusterID,
+		"SaslMechanism":  spec.SaslMechanism,
+		"Username":       spec.Username,
+		"Password":       spec.Password,
+		"Tls":            spec.Tls,
+		"SslCa":          spec.SslCa,
+	}
+
+	data, err := external.ResolveFields(ctx, c, wandb.Namespace, fields)
+	if err != nil {
+		logger.Error(err, "failed to resolve external kafka fields")
+		return []metav1.Condition{{
+			Type:    common.ReconciledType,
+			Status:  metav1.ConditionFalse,
+			Reason:  common.ApiErrorReason,
+			Message: err.Error(),
+		}}
+	}
+
+	if err := validateConnectionData(data); err != nil {
+		logger.Error(err, "invalid external kafka connection")
+		return []metav1.Condition{{
+			Type:    common.ReconciledType,
+			Status:  metav1.ConditionFalse,
+			Reason:  common.ResourceErrorReason,
+			Message: err.Error(),
+		}}
+	}
+
+	kafkaUrl := url.URL{
+		Scheme: "kafka",
+		Host:   data["BrokerEndpoint"],
+	}
+	values := kafkaUrl.Query()
+	if mechanism, ok := data["SaslMechanism"]; ok {
+		kafkaUrl.User = url.UserPassword(data["Username"], data["Password"])
+		values.Set("sasl", mechanism)
+	}
+	if tls, ok := data["Tls"]; ok {
+		values.Set("tls", tls)
+	}
+	if _, ok := data["SslCa"]; ok {
+		values.Set("tls", "true")
+		values.Set("caCertPath", caCertPath)
+	}
+	kafkaUrl.RawQuery = values.Encode()
+
+	data["url"] = kafkaUrl.String()
+
+	nsName := types.NamespacedName{Namespace: wandb.Namespace, Name: ConnectionSecretName}
+	return external.WriteConnectionSecret(ctx, c, wandb, nsName, data)
+}
+
+// validateConnectionData checks the resolved connection and normalizes it in
+// place: BrokerEndpoint always holds the bootstrap list, Host/Port always hold
+// the first broker, and SaslMechanism is set whenever credentials are.
+func validateConnectionData(data map[string]string) error {
+	var brokers []string
+	for _, broker := range strings.Split(data["BrokerEndpoint"], ",") {
+		if broker = strings.TrimSpace(broker); broker != "" {
+			brokers = append(brokers, broker)
+		}
+	}
+
+	host := strings.TrimSpace(data["Host"])
+	portValue := strings.TrimSpace(data["Port"])
+	if len(brokers) == 0 {
+		if host == "" {
+			return fmt.Errorf("external Kafka requires either brokerEndpoint or host and port")
+		}
+		brokers = []string{net.JoinHostPort(host, portValue)}
+	}
+
+	for _, broker := range brokers {
+		brokerHost, brokerPort, err := net.SplitHostPort(broker)
+		if err != nil || brokerHost == "" {
+			return fmt.Errorf("external Kafka broker %q must be host:port", broker)
+		}
+		port, err := strconv.Atoi(brokerPort)
+		if err != nil || port < 1 || port > 65535 {
+			return fmt.Errorf("external Kafka broker %q port must be an integer between 1 and 65535", broker)
+		}
+	}
+	if host == "" {
+		host, portValue, _ = net.SplitHostPort(brokers[0])
+	}
+
+	data["BrokerEndpoint"] = strings.Join(brokers, ",")
+	data["Host"] = host
+	data["Port"] = portValue
+
+	mechanism := strings.ToUpper(strings.TrimSpace(data["SaslMechanism"]))
+	_, hasUsername := data["Username"]
+	_, hasPassword := data["Password"]
+	if mechanism == "" && (hasUsername || hasPassword) {
+		mechanism = SaslScramSha512
+	}
+	switch mechanism {
+	case "":
+		delete(data, "SaslMechanism")
+	case SaslPlain, SaslScramSha256, SaslScramSha512:
+		if !hasUsername || !hasPassword {
+			return fmt.Errorf("external Kafka SASL mechanism %s requires both username and password", mechanism)
+		}
+		data["SaslMechanism"] = mechanism
+	default:
+		return fmt.Errorf("external Kafka SASL mechanism %q must be one of %s, %s or %s",
+			data["SaslMechanism"], SaslPlain, SaslScramSha256, SaslScramSha512)
+	}
+
+	if tls, ok := data["Tls"]; ok {
+		enabled, err := strconv.ParseBool(strings.TrimSpace(tls))
+		if err != nil {
+			return fmt.Errorf("external Kafka tls %q must be a boolean", tls)
+		}
+		data["Tls"] = strconv.FormatBool(enabled)
+	}
+	return nil
+}
+
+func ReadState(
+	ctx context.Context,
+	c client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	newConditions []metav1.Condition,
+) ([]metav1.Condition, *apiv2.KafkaConnection) {
+	nsName := types.NamespacedName{Namespace: wandb.Namespace, Name: ConnectionSecretName}
+	_, conditions, found := external.ReadConnectionSecret(ctx, c, nsName, newConditions)
+	if !found {
+		return conditions, nil
+	}
+
+	localRef := corev1.LocalObjectReference{Name: nsName.Name}
+	return conditions, &apiv2.KafkaConnection{
+		URL:            corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "url", Optional: ptr.To(false)},
+		Host:           corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Host", Optional: ptr.To(false)},
+		Port:           corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Port", Optional: ptr.To(false)},
+		BrokerEndpoint: corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "BrokerEndpoint", Optional: ptr.To(false)},
+		ClusterID:      corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "ClusterID", Optional: ptr.To(true)},
+		SaslMechanism:  corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "SaslMechanism", Optional: ptr.To(true)},
+		Username:       corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Username", Optional: ptr.To(true)},
+		Password:       corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Password", Optional: ptr.To(true)},
+		Tls:            corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Tls", Optional: ptr.To(true)},
+		SslCa:          corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "SslCa", Optional: ptr.To(true)},
+	}
+}
+
+func DeleteConnectionSecret(ctx context.Context, c client.Client, wandb *apiv2.WeightsAndBiases) error {
+	return external.DeleteConnectionSecret(ctx, c, types.NamespacedName{
+		Namespace: wandb.Namespace,
+		Name:      ConnectionSecretName,
+	})
+}
@@ -0,0 +1,181 @@
+package kafka
+
+import (
+	"context"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const kafkaSourceSecretName = "external-kafka"
+
+func kafkaSel(key string) corev1.SecretKeySelector {
+	return corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: kafkaSourceSecretName},
+		Key:                  key,
+	}
+}
+
+// kafkaWriteStateFixture points every externalKafka selector whose key is
+// present in sourceData at the source secret.
+func kafkaWriteStateFixture(t *testing.T, sourceData map[string][]byte) (ctrlclient.Client, *apiv2.WeightsAndBiases) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, apiv2.AddToScheme(scheme))
+
+	source := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: kafkaSourceSecretName, Namespace: "default"},
+		Data:       sourceData,
+	}
+	connection := &apiv2.KafkaConnection{}
+	selectors := map[string]*corev1.SecretKeySelector{
+		"Host":           &connection.Host,
+		"Port":           &connection.Port,
+		"BrokerEndpoint": &connection.BrokerEndpoint,
+		"SaslMechanism":  &connection.SaslMechanism,
+		"Username":       &connection.Username,
+		"Password":       &connection.Password,
+		"Tls":            &connection.Tls,
+		"SslCa":          &connection.SslCa,
+	}
+	for key, sel := range selectors {
+		if _, ok := sourceData[key]; ok {
+			*sel = kafkaSel(key)
+		}
+	}
+	wandb := &apiv2.WeightsAndBiases{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apps.wandb.com/v2", Kind: "WeightsAndBiases"},
+		ObjectMeta: metav1.ObjectMeta{Name: "wandb", Namespace: "default"},
+		Spec: apiv2.WeightsAndBiasesSpec{
+			Kafka: apiv2.KafkaSpec{ExternalKafka: connection},
+		},
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(wandb, source).Build(), wandb
+}
+
+func readConnectionData(t *testing.T, client ctrlclient.Client) map[string]string {
+	t.Helper()
+	written := &corev1.Secret{}
+	require.NoError(t, client.Get(context.Background(), types.NamespacedName{Name: ConnectionSecretName, Namespace: "default"}, written))
+	out := map[string]string{}
+	for k, v := range written.Data {
+		out[k] = string(v)
+	}
+	for k, v := range written.StringData {
+		out[k] = v
+	}
+	return out
+}
+
+func TestWriteStateHostAndPort(t *testing.T) {
+	client, wandb := kafkaWriteStateFixture(t, map[string][]byte{
+		"Host": []byte("kafka.example.com"),
+		"Port": []byte("9092"),
+	})
+
+	require.Nil(t, WriteState(context.Background(), client, wandb, wandb.Spec.Kafka.ExternalKafka))
+
+	data := readConnectionData(t, client)
+	require.Equal(t, "kafka://kafka.example.com:9092", data["url"])
+	require.Equal(t, "kafka.example.com:9092", data["BrokerEndpoint"])
+	require.NotContains(t, data, "SaslMechanism")
+}
+
+func TestWriteStateSaslScramWithCA(t *testing.T) {
+	client, wandb := kafkaWriteStateFixture(t, map[string][]byte{
+		"BrokerEndpoint": []byte("b-1.msk.example.com:9096, b-2.msk.example.com:9096"),
+		"Username":       []byte("wandb"),
+		"Password":       []byte("s3cret"),
+		"SslCa":          []byte("---ca---"),
+	})
+
+	require.Nil(t, WriteState(context.Background(), client, wandb, wandb.Spec.Kafka.ExternalKafka))
+
+	data := readConnectionData(t, client)
+	require.Equal(t, "b-1.msk.example.com:9096,b-2.msk.example.com:9096", data["BrokerEndpoint"])
+	require.Equal(t, "b-1.msk.example.com", data["Host"])
+	require.Equal(t, "9096", data["Port"])
+	require.Equal(t, SaslScramSha512, data["SaslMechanism"])
+
+	parsed, err := url.Parse(data["url"])
+	require.NoError(t, err)
+	require.Equal(t, "kafka", parsed.Scheme)
+	require.Equal(t, "wandb", parsed.User.Username())
+	password, _ := parsed.User.Password()
+	require.Equal(t, "s3cret", password)
+	require.Equal(t, SaslScramSha512, parsed.Query().Get("sasl"))
+	require.Equal(t, "true", parsed.Query().Get("tls"))
+	require.Equal(t, caCertPath, parsed.Query().Get("caCertPath"))
+}
+
+func TestWriteStateRejectsInvalidConnection(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string][]byte
+	}{
+		{name: "no brokers", data: map[string][]byte{"Port": []byte("9092")}},
+		{name: "missing port", data: map[string][]byte{"Host": []byte("kafka.example.com")}},
+		{name: "broker without port", data: map[string][]byte{"BrokerEndpoint": []byte("kafka.example.com")}},
+		{name: "port above range", data: map[string][]byte{"BrokerEndpoint": []byte("kafka.example.com:65536")}},
+		{name: "unknown mechanism", data: map[string][]byte{
+			"BrokerEndpoint": []byte("kafka.example.com:9096"),
+			"SaslMechanism":  []byte("GSSAPI"),
+			"Username":       []byte("wandb"),
+			"Password":       []byte("s3cret"),
+		}},
+		{name: "mechanism without password", data: map[string][]byte{
+			"BrokerEndpoint": []byte("kafka.example.com:9096"),
+			"SaslMechanism":  []byte("scram-sha-256"),
+			"Username":       []byte("wandb"),
+		}},
+		{name: "non-boolean tls", data: map[string][]byte{
+			"BrokerEndpoint": []byte("kafka.example.com:9096"),
+			"Tls":            []byte("maybe"),
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, wandb := kafkaWriteStateFixture(t, test.data)
+
+			conditions := WriteState(context.Background(), client, wandb, wandb.Spec.Kafka.ExternalKafka)
+
+			require.Len(t, conditions, 1)
+			require.Equal(t, common.ReconciledType, conditions[0].Type)
+			require.Equal(t, metav1.ConditionFalse, conditions[0].Status)
+			require.Equal(t, common.ResourceErrorReason, conditions[0].Reason)
+			require.NotEmpty(t, conditions[0].Message)
+
+			err := client.Get(context.Background(), types.NamespacedName{Name: ConnectionSecretName, Namespace: "default"}, &corev1.Secret{})
+			require.True(t, apierrors.IsNotFound(err))
+		})
+	}
+}
+
+func TestReadStateReferencesConnectionSecret(t *testing.T) {
+	client, wandb := kafkaWriteStateFixture(t, map[string][]byte{
+		"BrokerEndpoint": []byte("kafka.example.com:9092"),
+	})
+
+	conditions, conn := ReadState(context.Background(), client, wandb, nil)
+	require.Empty(t, conditions)
+	require.Nil(t, conn)
+
+	require.Nil(t, WriteState(context.Background(), client, wandb, wandb.Spec.Kafka.ExternalKafka))
+	_, conn = ReadState(context.Background(), client, wandb, nil)
+	require.NotNil(t, conn)
+	require.Equal(t, ConnectionSecretName, conn.URL.Name)
+	require.Equal(t, "BrokerEndpoint", conn.BrokerEndpoint.Key)
+	require.Equal(t, "SaslMechanism", conn.SaslMechanism.Key)
+}
@@ -0,0 +1,92 @@
+package mysql
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/wandb/operator/internal/controller/infra/external"
+)
+
+// MySQL server errors that mean the credentials, not the network, are wrong.
+var authErrorNumbers = map[uint16]bool{
+	1044: true, // ER_DBACCESS_DENIED_ERROR
+	1045: true, // ER_ACCESS_DENIED_ERROR
+	1698: true, // ER_ACCESS_DENIED_NO_PASSWORD_ERROR
+}
+
+// probeConnection is swapped out in tests that exercise ReadState without a
+// MySQL server to talk to.
+var probeConnection external.Prober = Probe
+
+// Probe connects to the external MySQL described by the connection secret data
+// with the same TLS settings the applications use and pings it.
+func Probe(ctx context.Context, data map[string][]byte) error {
+	host := string(data["Host"])
+
+	cfg := driver.NewConfig()
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(host, string(data["Port"]))
+	cfg.User = string(data["Username"])
+	cfg.Passwd = string(data["Password"])
+	cfg.DBName = string(data["Database"])
+
+	tlsConfig, err := probeTLSConfig(host, data)
+	if err != nil {
+		return err
+	}
+	if tlsConfig != nil {
+		cfg.TLS = tlsConfig
+		cfg.AllowFallbackToPlaintext = strings.EqualFold(string(data["Tls"]), "preferred")
+	}
+
+	connector, err := driver.NewConnector(cfg)
+	if err != nil {
+		return err
+	}
+	db := sql.OpenDB(connector)
+	defer func() { _ = db.Close() }()
+
+	if err := db.PingContext(ctx); err != nil {
+		var mysqlErr *driver.MySQLError
+		if errors.As(err, &mysqlErr) && authErrorNumbers[mysqlErr.Number] {
+			return fmt.Errorf("%w: %s", external.ErrAuthFailed, mysqlErr.Message)
+		}
+		return err
+	}
+	return nil
+}
+
+// probeTLSConfig mirrors the tls/ssl-* parameters WriteState puts on the URL.
+// It returns nil when the connection is plaintext.
+func probeTLSConfig(host string, data map[string][]byte) (*tls.Config, error) {
+	mode := strings.ToLower(string(data["Tls"]))
+	_, hasCA := data["SslCa"]
+	_, hasCert := data["SslCert"]
+
+	switch {
+	case hasCA || hasCert || mode == "true" || mode == "custom":
+	case mode == "skip-verify" || mode == "preferred":
+		return &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12, InsecureSkipVerify: true}, nil // #nosec G402
+	default:
+		return nil, nil
+	}
+
+	config, err := external.TLSConfig(host, data["SslCa"])
+	if err != nil {
+		return nil, err
+	}
+	if hasCert {
+		cert, err := tls.X509KeyPair(data["SslCert"], data["SslKey"])
+		if err != nil {
+			return nil, fmt.Errorf("%w: client certificate: %v", external.ErrInvalidCA, err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
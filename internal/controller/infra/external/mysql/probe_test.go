@@ -0,0 +1,45 @@
+package mysql
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wandb/operator/internal/controller/infra/external"
+)
+
+func TestProbeTLSConfigFollowsConnectionSettings(t *testing.T) {
+	config, err := probeTLSConfig("db.example.com", map[string][]byte{})
+	require.NoError(t, err)
+	require.Nil(t, config, "no tls settings means plaintext")
+
+	config, err = probeTLSConfig("db.example.com", map[string][]byte{"Tls": []byte("true")})
+	require.NoError(t, err)
+	require.Equal(t, "db.example.com", config.ServerName)
+	require.False(t, config.InsecureSkipVerify)
+
+	config, err = probeTLSConfig("db.example.com", map[string][]byte{"Tls": []byte("skip-verify")})
+	require.NoError(t, err)
+	require.True(t, config.InsecureSkipVerify)
+
+	_, err = probeTLSConfig("db.example.com", map[string][]byte{"SslCa": []byte("not a certificate")})
+	require.ErrorIs(t, err, external.ErrInvalidCA)
+}
+
+func TestProbeReportsUnreachableServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+
+	err = Probe(context.Background(), map[string][]byte{
+		"Host":     []byte(host),
+		"Port":     []byte(port),
+		"Username": []byte("wandb"),
+		"Password": []byte("s3cret"),
+	})
+	require.Error(t, err)
+	require.Equal(t, external.ConnectionFailedReason, external.ClassifyProbeError(err))
+}
@@ -177,16 +177,22 @@ func joinBucketPrefix(base, prefix string) string {
 	}
 }
 
-// ReadState is a no-op for external object stores; it passes through the
-// conditions produced by WriteState since there is no additional state to read.
+// ReadState verifies that the bucket in the connection secret is reachable;
+// the connection itself is published by WriteState.
 func ReadState(
-	_ context.Context,
-	_ client.Client,
-	_ *apiv2.WeightsAndBiases,
-	_ string,
+	ctx context.Context,
+	c client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	key string,
 	newConditions []metav1.Condition,
 ) []metav1.Condition {
-	return newConditions
+	nsName := types.NamespacedName{Namespace: wandb.Namespace, Name: connectionSecretName(key)}
+	secret, conditions, found := external.ReadConnectionSecret(ctx, c, nsName, newConditions)
+	if !found {
+		external.ForgetConnection(nsName)
+		return conditions
+	}
+	return append(conditions, external.VerifyConnection(ctx, nsName, secret.Data, probeConnection))
 }
 
 // DeleteConnectionSecret removes the connection secret written for the given
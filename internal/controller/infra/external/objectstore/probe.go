@@ -0,0 +1,131 @@
+package objectstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/infra/external"
+	osconn "github.com/wandb/operator/internal/controller/infra/objectstore"
+)
+
+// emptyPayloadHash is the SHA-256 of an empty request body, which SigV4 signs
+// for a HEAD request.
+var emptyPayloadHash = func() string {
+	sum := sha256.Sum256(nil)
+	return hex.EncodeToString(sum[:])
+}()
+
+// probeConnection is swapped out in tests that exercise ReadState without an
+// object store to talk to.
+var probeConnection external.Prober = Probe
+
+// Probe checks the bucket described by the connection secret data. S3 and
+// S3-compatible stores get a HeadBucket, signed with SigV4 when static keys are
+// configured. GCS and Azure credentials are not in a form the operator can sign
+// with, and ambient identities belong to the application pods, so those cases
+// only prove the bucket endpoint resolves and answers over TLS.
+func Probe(ctx context.Context, data map[string][]byte) error {
+	info := osconn.ConnInfo{
+		Provider:  apiv2.ObjectStoreProvider(data["Provider"]),
+		Endpoint:  string(data["Host"]),
+		Port:      string(data["Port"]),
+		Region:    string(data["Region"]),
+		AccessKey: string(data["AccessKey"]),
+		SecretKey: string(data["SecretKey"]),
+	}
+	info.Bucket, _ = osconn.SplitBucketPath(string(data["Bucket"]))
+	info.TlsEnabled, _ = strconv.ParseBool(string(data["TlsEnabled"]))
+	if fps, err := strconv.ParseBool(string(data["ForcePathStyle"])); err == nil {
+		info.ForcePathStyle = fps
+	} else {
+		info.ForcePathStyle = osconn.RequiresPathStyle(info.Endpoint)
+	}
+	if info.Provider == "" {
+		info.Provider = apiv2.ObjectStoreProviderS3
+	}
+	if info.Bucket == "" {
+		return fmt.Errorf("object store connection has no bucket")
+	}
+
+	bucketURL, err := headBucketURL(info)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, bucketURL, nil)
+	if err != nil {
+		return err
+	}
+
+	signed := osconn.S3Compatible(string(info.Provider)) && info.HasStaticCredentials()
+	if signed {
+		region := info.Region
+		if region == "" {
+			region = osconn.DefaultRegion
+		}
+		req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+		creds := aws.Credentials{AccessKeyID: info.AccessKey, SecretAccessKey: info.SecretKey}
+		if err := v4.NewSigner().SignHTTP(ctx, creds, req, emptyPayloadHash, "s3", region, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	response, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = response.Body.Close()
+
+	switch {
+	case response.StatusCode < http.StatusMultipleChoices:
+		return nil
+	case !signed && response.StatusCode < http.StatusInternalServerError:
+		// Unsigned requests are expected to be refused; the endpoint answered.
+		return nil
+	case response.StatusCode == http.StatusUnauthorized, response.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%w: HeadBucket %s returned %s", external.ErrAuthFailed, info.Bucket, response.Status)
+	case response.StatusCode == http.StatusNotFound:
+		return fmt.Errorf("bucket %s does not exist", info.Bucket)
+	case response.StatusCode == http.StatusMovedPermanently:
+		return fmt.Errorf("bucket %s is in region %s, not %s", info.Bucket, response.Header.Get("X-Amz-Bucket-Region"), info.Region)
+	}
+	return fmt.Errorf("HeadBucket %s returned %s", info.Bucket, response.Status)
+}
+
+// headBucketURL addresses the bucket root the way the provider expects.
+func headBucketURL(info osconn.ConnInfo) (string, error) {
+	switch info.Provider {
+	case apiv2.ObjectStoreProviderGCS:
+		return "https://storage.googleapis.com/" + url.PathEscape(info.Bucket), nil
+	case apiv2.ObjectStoreProviderAzure:
+		return osconn.AzureBlobURI(info.AccessKey, info.Bucket, "") + "?restype=container", nil
+	}
+
+	endpoint := info.EndpointURL()
+	if endpoint == "" {
+		region := info.Region
+		if region == "" {
+			region = osconn.DefaultRegion
+		}
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("object store endpoint: %w", err)
+	}
+	if info.ForcePathStyle {
+		parsed.Path = "/" + info.Bucket
+	} else {
+		parsed.Host = info.Bucket + "." + parsed.Host
+		parsed.Path = "/"
+	}
+	return parsed.String(), nil
+}
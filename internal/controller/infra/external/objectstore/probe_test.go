@@ -0,0 +1,95 @@
+package objectstore
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/infra/external"
+	osconn "github.com/wandb/operator/internal/controller/infra/objectstore"
+)
+
+// s3Server answers HeadBucket for bucket "wandb", accepting only requests
+// signed with access key "AKID".
+func s3Server(t *testing.T) map[string][]byte {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method != http.MethodHead || r.URL.Path != "/wandb":
+			w.WriteHeader(http.StatusNotFound)
+		case !strings.Contains(r.Header.Get("Authorization"), "Credential=AKID/"):
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return map[string][]byte{
+		"Provider":       []byte("s3"),
+		"Host":           []byte(server.URL),
+		"Bucket":         []byte("wandb"),
+		"Region":         []byte("us-east-1"),
+		"ForcePathStyle": []byte("true"),
+	}
+}
+
+func TestProbeSignsHeadBucket(t *testing.T) {
+	data := s3Server(t)
+	data["AccessKey"] = []byte("AKID")
+	data["SecretKey"] = []byte("secret")
+
+	require.NoError(t, Probe(context.Background(), data))
+}
+
+func TestProbeReportsRejectedKeys(t *testing.T) {
+	data := s3Server(t)
+	data["AccessKey"] = []byte("OTHER")
+	data["SecretKey"] = []byte("secret")
+
+	require.ErrorIs(t, Probe(context.Background(), data), external.ErrAuthFailed)
+}
+
+func TestProbeReportsMissingBucket(t *testing.T) {
+	data := s3Server(t)
+	data["Bucket"] = []byte("missing")
+	data["AccessKey"] = []byte("AKID")
+	data["SecretKey"] = []byte("secret")
+
+	err := Probe(context.Background(), data)
+	require.ErrorContains(t, err, "does not exist")
+}
+
+// Without static keys the pods authenticate with an ambient identity the
+// operator doesn't hold, so a refusal still proves the bucket endpoint is up.
+func TestProbeAcceptsRefusalWithoutStaticKeys(t *testing.T) {
+	require.NoError(t, Probe(context.Background(), s3Server(t)))
+}
+
+func TestHeadBucketURL(t *testing.T) {
+	tests := []struct {
+		name string
+		info osconn.ConnInfo
+		want string
+	}{
+		{"aws", osconn.ConnInfo{Provider: apiv2.ObjectStoreProviderS3, Bucket: "wandb", Region: "eu-west-1"},
+			"https://wandb.s3.eu-west-1.amazonaws.com/"},
+		{"minio", osconn.ConnInfo{Provider: apiv2.ObjectStoreProviderS3, Bucket: "wandb", Endpoint: "minio.local", Port: "9000", ForcePathStyle: true},
+			"http://minio.local:9000/wandb"},
+		{"gcs", osconn.ConnInfo{Provider: apiv2.ObjectStoreProviderGCS, Bucket: "wandb"},
+			"https://storage.googleapis.com/wandb"},
+		{"azure", osconn.ConnInfo{Provider: apiv2.ObjectStoreProviderAzure, Bucket: "wandb", AccessKey: "acct"},
+			"https://acct.blob.core.windows.net/wandb?restype=container"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := headBucketURL(test.info)
+			require.NoError(t, err)
+			require.Equal(t, test.want, got)
+		})
+	}
+}
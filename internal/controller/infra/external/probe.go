@@ -0,0 +1,164 @@
+package external
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const ConnectionVerifiedType = "ConnectionVerified"
+
+// Reasons reported on the ConnectionVerified condition. Failures are
+// classified so an operator can tell a typo'd hostname from a bad password
+// without reading controller logs.
+const (
+	ConnectionVerifiedReason = "Verified"
+	DNSErrorReason           = "DNSError"
+	TLSErrorReason           = "TLSError"
+	AuthFailedReason         = "AuthFailed"
+	TimeoutReason            = "Timeout"
+	ConnectionFailedReason   = "ConnectionFailed"
+)
+
+const (
+	// ProbeTimeout bounds a single connectivity probe.
+	ProbeTimeout = 10 * time.Second
+	// ProbeInterval is how long a probe result is reused before the backend
+	// is contacted again, unless the connection details change in between.
+	ProbeInterval = 2 * time.Minute
+)
+
+// ErrAuthFailed is wrapped by probes when the backend answered but rejected
+// the supplied credentials.
+var ErrAuthFailed = errors.New("authentication failed")
+
+// ErrInvalidCA is returned when the configured CA bundle cannot be used.
+var ErrInvalidCA = errors.New("CA bundle contains no PEM certificates")
+
+// Prober checks that the resolved connection data reaches a live backend.
+type Prober func(ctx context.Context, data map[string][]byte) error
+
+type probeResult struct {
+	fingerprint string
+	checkedAt   time.Time
+	condition   metav1.Condition
+}
+
+var (
+	probeResultsMu sync.Mutex
+	probeResults   = map[types.NamespacedName]probeResult{}
+	probeNow       = time.Now
+)
+
+// VerifyConnection runs probe against the connection data stored in the
+// connection secret nsName and returns the resulting ConnectionVerified
+// condition. Results are cached per secret for ProbeInterval so a busy
+// reconcile loop does not hammer the backend.
+func VerifyConnection(
+	ctx context.Context,
+	nsName types.NamespacedName,
+	data map[string][]byte,
+	probe Prober,
+) metav1.Condition {
+	fingerprint := fingerprintConnection(data)
+	now := probeNow()
+
+	probeResultsMu.Lock()
+	cached, ok := probeResults[nsName]
+	probeResultsMu.Unlock()
+	if ok && cached.fingerprint == fingerprint && now.Sub(cached.checkedAt) < ProbeInterval {
+		return cached.condition
+	}
+
+	probeCtx, cancel := context.WithTimeout(ctx, ProbeTimeout)
+	defer cancel()
+
+	condition := metav1.Condition{
+		Type:   ConnectionVerifiedType,
+		Status: metav1.ConditionTrue,
+		Reason: ConnectionVerifiedReason,
+	}
+	if err := probe(probeCtx, data); err != nil {
+		ctrl.LoggerFrom(ctx).Info("external connection probe failed", "secret", nsName.String(), "error", err.Error())
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ClassifyProbeError(err)
+		condition.Message = err.Error()
+	}
+
+	probeResultsMu.Lock()
+	probeResults[nsName] = probeResult{fingerprint: fingerprint, checkedAt: now, condition: condition}
+	probeResultsMu.Unlock()
+	return condition
+}
+
+// ForgetConnection drops the cached probe result for a connection secret.
+func ForgetConnection(nsName types.NamespacedName) {
+	probeResultsMu.Lock()
+	delete(probeResults, nsName)
+	probeResultsMu.Unlock()
+}
+
+// ClassifyProbeError maps a probe error to a ConnectionVerified reason.
+func ClassifyProbeError(err error) string {
+	var dnsErr *net.DNSError
+	var netErr net.Error
+	var recordErr tls.RecordHeaderError
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCert x509.CertificateInvalidError
+	switch {
+	case errors.Is(err, ErrAuthFailed):
+		return AuthFailedReason
+	case errors.As(err, &dnsErr) && !dnsErr.IsTimeout:
+		return DNSErrorReason
+	case errors.Is(err, ErrInvalidCA), errors.As(err, &recordErr), errors.As(err, &certErr), errors.As(err, &unknownAuthority),
+		errors.As(err, &hostnameErr), errors.As(err, &invalidCert):
+		return TLSErrorReason
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return TimeoutReason
+	}
+	return ConnectionFailedReason
+}
+
+// TLSConfig builds the client TLS configuration for a probe. A CA bundle, when
+// given, replaces the system roots so a private CA behaves the way it does in
+// the application pods.
+func TLSConfig(serverName string, caPEM []byte) (*tls.Config, error) {
+	config := &tls.Config{ServerName: serverName, MinVersion: tls.VersionTLS12}
+	if len(caPEM) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, ErrInvalidCA
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+func fingerprintConnection(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write(data[k])
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
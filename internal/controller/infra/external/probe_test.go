@@ -0,0 +1,89 @@
+package external
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wandb/operator/internal/controller/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestClassifyProbeError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"dns", &net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", Name: "db.invalid", IsNotFound: true}}, DNSErrorReason},
+		{"dns timeout", &net.DNSError{Err: "i/o timeout", Name: "db.example.com", IsTimeout: true}, TimeoutReason},
+		{"unknown authority", fmt.Errorf("handshake: %w", x509.UnknownAuthorityError{}), TLSErrorReason},
+		{"invalid ca", ErrInvalidCA, TLSErrorReason},
+		{"auth", fmt.Errorf("%w: WRONGPASS", ErrAuthFailed), AuthFailedReason},
+		{"deadline", fmt.Errorf("ping: %w", context.DeadlineExceeded), TimeoutReason},
+		{"refused", &net.OpError{Op: "dial", Err: errors.New("connection refused")}, ConnectionFailedReason},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.want, ClassifyProbeError(test.err))
+		})
+	}
+}
+
+func TestVerifyConnectionCachesUntilIntervalOrDataChange(t *testing.T) {
+	now := time.Now()
+	probeNow = func() time.Time { return now }
+	t.Cleanup(func() { probeNow = time.Now })
+
+	nsName := types.NamespacedName{Namespace: "default", Name: "wandb-redis-connection"}
+	t.Cleanup(func() { ForgetConnection(nsName) })
+
+	calls := 0
+	failing := func(context.Context, map[string][]byte) error {
+		calls++
+		return fmt.Errorf("%w: WRONGPASS", ErrAuthFailed)
+	}
+	data := map[string][]byte{"Host": []byte("redis.example.com")}
+
+	condition := VerifyConnection(context.Background(), nsName, data, failing)
+	require.Equal(t, ConnectionVerifiedType, condition.Type)
+	require.Equal(t, metav1.ConditionFalse, condition.Status)
+	require.Equal(t, AuthFailedReason, condition.Reason)
+
+	VerifyConnection(context.Background(), nsName, data, failing)
+	require.Equal(t, 1, calls, "a fresh result must be reused")
+
+	data["Password"] = []byte("fixed")
+	VerifyConnection(context.Background(), nsName, data, failing)
+	require.Equal(t, 2, calls, "changed connection data must be probed again")
+
+	now = now.Add(ProbeInterval)
+	condition = VerifyConnection(context.Background(), nsName, data, func(context.Context, map[string][]byte) error {
+		calls++
+		return nil
+	})
+	require.Equal(t, 3, calls, "an expired result must be probed again")
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+	require.Equal(t, ConnectionVerifiedReason, condition.Reason)
+}
+
+func TestInferExternalStatusRequiresVerifiedConnection(t *testing.T) {
+	unverified := metav1.Condition{
+		Type:    ConnectionVerifiedType,
+		Status:  metav1.ConditionFalse,
+		Reason:  DNSErrorReason,
+		Message: "lookup db.invalid: no such host",
+	}
+
+	state, ready, conditions := InferExternalStatus(nil, []metav1.Condition{unverified}, 1, true)
+
+	require.Equal(t, common.ErrorState, state)
+	require.False(t, ready)
+	require.Len(t, conditions, 2)
+}
@@ -0,0 +1,91 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/wandb/operator/internal/controller/infra/external"
+)
+
+// probeConnection is swapped out in tests that exercise ReadState without a
+// Redis to talk to.
+var probeConnection external.Prober = Probe
+
+// Probe dials the external Redis described by the connection secret data,
+// authenticates when a password is configured and expects PONG back from a PING.
+func Probe(ctx context.Context, data map[string][]byte) error {
+	host := string(data["Host"])
+	address := net.JoinHostPort(host, string(data["Port"]))
+
+	useTLS, _ := strconv.ParseBool(string(data["Tls"]))
+	_, hasCA := data["SslCa"]
+
+	var conn net.Conn
+	var err error
+	if useTLS || hasCA {
+		config, configErr := external.TLSConfig(host, data["SslCa"])
+		if configErr != nil {
+			return configErr
+		}
+		dialer := &tls.Dialer{Config: config}
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	reader := bufio.NewReader(conn)
+	if password, ok := data["Password"]; ok && len(password) > 0 {
+		if _, err := sendCommand(conn, reader, "AUTH", string(password)); err != nil {
+			return err
+		}
+	}
+	reply, err := sendCommand(conn, reader, "PING")
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("unexpected reply to PING: %q", reply)
+	}
+	return nil
+}
+
+// sendCommand writes a RESP array command and returns the simple-string reply.
+func sendCommand(conn net.Conn, reader *bufio.Reader, args ...string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := conn.Write([]byte(b.String())); err != nil {
+		return "", err
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	switch {
+	case strings.HasPrefix(line, "+"):
+		return line[1:], nil
+	case strings.HasPrefix(line, "-NOAUTH"), strings.HasPrefix(line, "-WRONGPASS"),
+		strings.HasPrefix(line, "-ERR invalid password"), strings.HasPrefix(line, "-ERR AUTH"):
+		return "", fmt.Errorf("%w: %s", external.ErrAuthFailed, line[1:])
+	case strings.HasPrefix(line, "-"):
+		return "", fmt.Errorf("redis %s: %s", args[0], line[1:])
+	}
+	return "", fmt.Errorf("unexpected reply to %s: %q", args[0], line)
+}
@@ -0,0 +1,114 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wandb/operator/internal/controller/infra/external"
+)
+
+// serveRESP answers AUTH with password and PING with PONG, refusing PING
+// until a successful AUTH when a password is required.
+func serveRESP(t *testing.T, password string) map[string][]byte {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func(conn net.Conn) {
+				defer func() { _ = conn.Close() }()
+				reader := bufio.NewReader(conn)
+				authed := password == ""
+				for {
+					args, err := readCommand(reader)
+					if err != nil {
+						return
+					}
+					switch strings.ToUpper(args[0]) {
+					case "AUTH":
+						if len(args) == 2 && args[1] == password {
+							authed = true
+							_, _ = conn.Write([]byte("+OK\r\n"))
+						} else {
+							_, _ = conn.Write([]byte("-WRONGPASS invalid username-password pair\r\n"))
+						}
+					case "PING":
+						if !authed {
+							_, _ = conn.Write([]byte("-NOAUTH Authentication required.\r\n"))
+						} else {
+							_, _ = conn.Write([]byte("+PONG\r\n"))
+						}
+					}
+				}
+			}(conn)
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return map[string][]byte{"Host": []byte(host), "Port": []byte(port)}
+}
+
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, 0, count)
+	for range count {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, strings.TrimRight(arg, "\r\n"))
+	}
+	return args, nil
+}
+
+func TestProbeAuthenticatesAndPings(t *testing.T) {
+	data := serveRESP(t, "s3cret")
+	data["Password"] = []byte("s3cret")
+
+	require.NoError(t, Probe(context.Background(), data))
+}
+
+func TestProbeReportsRejectedPassword(t *testing.T) {
+	data := serveRESP(t, "s3cret")
+	data["Password"] = []byte("wrong")
+
+	err := Probe(context.Background(), data)
+	require.ErrorIs(t, err, external.ErrAuthFailed)
+	require.Equal(t, external.AuthFailedReason, external.ClassifyProbeError(err))
+}
+
+func TestProbeReportsMissingPassword(t *testing.T) {
+	data := serveRESP(t, "s3cret")
+
+	require.ErrorIs(t, Probe(context.Background(), data), external.ErrAuthFailed)
+}
+
+func TestProbeRejectsUnusableCA(t *testing.T) {
+	data := serveRESP(t, "")
+	data["SslCa"] = []byte("not a certificate")
+
+	err := Probe(context.Background(), data)
+	require.Equal(t, external.TLSErrorReason, external.ClassifyProbeError(err))
+}
@@ -120,10 +120,12 @@ func ReadState(
 	newConditions []metav1.Condition,
 ) ([]metav1.Condition, *apiv2.RedisConnection) {
 	nsName := types.NamespacedName{Namespace: wandb.Namespace, Name: connectionSecretName(key)}
-	_, conditions, found := external.ReadConnectionSecret(ctx, c, nsName, newConditions)
+	secret, conditions, found := external.ReadConnectionSecret(ctx, c, nsName, newConditions)
 	if !found {
+		external.ForgetConnection(nsName)
 		return conditions, nil
 	}
+	conditions = append(conditions, external.VerifyConnection(ctx, nsName, secret.Data, probeConnection))
 
 	localRef := corev1.LocalObjectReference{Name: nsName.Name}
 	return conditions, &apiv2.RedisConnection{
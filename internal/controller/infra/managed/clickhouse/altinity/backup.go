@@ -0,0 +1,406 @@
+package altinity
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/internal/controller/infra/objectstore"
+	"github.com/wandb/operator/internal/logx"
+	chiv1 "github.com/wandb/operator/pkg/vendored/altinity-clickhouse/clickhouse.altinity.com/v1"
+	"github.com/wandb/operator/pkg/wandb/manifest"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// ClickHouseBackupType reports the outcome of the most recent scheduled
+	// backup. It is informational and does not affect the state.
+	ClickHouseBackupType = "ClickHouseBackup"
+
+	BackupSucceededReason = "Succeeded"
+	BackupPendingReason   = "Pending"
+	BackupFailedReason    = "Failed"
+	// ObjectStoreUnavailableReason: the backup or restore object store is not
+	// ready or its connection could not be read.
+	ObjectStoreUnavailableReason = "ObjectStoreUnavailable"
+	// UnsupportedObjectStoreReason: the backup or restore object store is not
+	// S3-compatible, the only backend BACKUP ... TO S3 and the aws-cli jobs
+	// share.
+	UnsupportedObjectStoreReason = "UnsupportedObjectStore"
+
+	// TODO: remove this hardcoded default once all supported manifest versions
+	// supply clickhouse.<instance>.images.backupTools.
+	defaultBackupToolsImage = "amazon/aws-cli:2.35.10"
+	imageKeyBackupTools     = "backupTools"
+
+	// backupEndpointName and restoreEndpointName key the server-side S3
+	// credentials for the backup and restore buckets, so the statements the
+	// jobs send never carry keys.
+	backupEndpointName  = "wandb_backup"
+	restoreEndpointName = "wandb_restore"
+
+	backupJobName    = "clickhouse-backup"
+	backupWorkVolume = "work"
+	backupWorkPath   = "/work"
+	backupCreatedBy  = "wandb-operator"
+)
+
+// BackupToolsImage resolves the aws-cli image the backup and restore jobs use
+// to list and prune backups.
+func BackupToolsImage(img manifest.ImageRef, globalImageRegistry string) string {
+	if out := img.GetImage(globalImageRegistry); out != "" {
+		return out
+	}
+	// Fallback for older manifests that don't supply the image.
+	return defaultBackupToolsImage
+}
+
+// BackupPrefix is the object key prefix backups of an instance are written
+// under, one directory per run.
+func BackupPrefix(namespace, name string) string {
+	return fmt.Sprintf("clickhouse-backup/%s/%s/", namespace, name)
+}
+
+func (n *NsNameBuilder) BackupCronJobName() string {
+	return n.SpecName() + "-backup"
+}
+
+func (n *NsNameBuilder) RestoreJobName() string {
+	return n.SpecName() + "-restore"
+}
+
+// BackupPodSelector matches the pods of the backup and restore jobs.
+func (n *NsNameBuilder) BackupPodSelector() metav1.LabelSelector {
+	return metav1.LabelSelector{MatchLabels: backupPodLabels(n.SpecName())}
+}
+
+func backupPodLabels(specName string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       backupJobName,
+		"app.kubernetes.io/instance":   specName,
+		"app.kubernetes.io/created-by": backupCreatedBy,
+	}
+}
+
+// BackupTarget is a resolved backup or restore object store: where the
+// backups live and the S3 URL ClickHouse addresses the bucket by.
+type BackupTarget struct {
+	Storage   objectstore.ConnInfo
+	BucketURL string
+}
+
+// ResolveBackupTarget checks the store is S3-compatible and derives the
+// bucket URL BACKUP and RESTORE address.
+func ResolveBackupTarget(storage objectstore.ConnInfo) (*BackupTarget, error) {
+	if storage.Provider != apiv2.ObjectStoreProviderS3 {
+		return nil, fmt.Errorf("managed ClickHouse backups need an S3-compatible object store, got %q", storage.Provider)
+	}
+	if storage.Bucket == "" {
+		return nil, fmt.Errorf("object store connection has no bucket reference")
+	}
+	bucketURL, err := buildEndpoint(storage, "")
+	if err != nil {
+		return nil, err
+	}
+	return &BackupTarget{Storage: storage, BucketURL: bucketURL}, nil
+}
+
+// ApplyBackupEndpoints gives the server credentials for the backup and
+// restore buckets. ClickHouse matches them to a statement's S3 URL by prefix.
+// Either target may be nil.
+func ApplyBackupEndpoints(chi *chiv1.ClickHouseInstallation, backup, restore *BackupTarget) {
+	settings := chi.Spec.Configuration.Settings
+	for name, target := range map[string]*BackupTarget{backupEndpointName: backup, restoreEndpointName: restore} {
+		if target == nil {
+			continue
+		}
+		key := func(field string) string { return "s3/" + name + "/" + field }
+		settings.Set(key("endpoint"), chiv1.NewSettingScalar(target.BucketURL))
+		if target.Storage.Region != "" {
+			settings.Set(key("region"), chiv1.NewSettingScalar(target.Storage.Region))
+		}
+		if target.Storage.HasStaticCredentials() {
+			settings.Set(key("access_key_id"), secretSetting(target.Storage.AccessKeyRef))
+			settings.Set(key("secret_access_key"), secretSetting(target.Storage.SecretKeyRef))
+		} else {
+			settings.Set(key("use_environment_credentials"), chiv1.NewSettingScalar("true"))
+		}
+	}
+}
+
+// RetainBackupEndpoints keeps the live installation's credentials for the
+// backup or restore bucket while that object store is temporarily
+// unavailable, so an outage neither restarts the servers nor breaks a job
+// already running.
+func RetainBackupEndpoints(
+	ctx context.Context,
+	cl client.Client,
+	specNamespacedName types.NamespacedName,
+	desired *chiv1.ClickHouseInstallation,
+	backup, restore bool,
+) {
+	if !backup && !restore {
+		return
+	}
+	actual := &chiv1.ClickHouseInstallation{}
+	found, err := common.GetResource(
+		ctx, cl, createNsNameBuilder(specNamespacedName).InstallationNsName(), ResourceTypeName, actual,
+	)
+	if err != nil || !found || actual.Spec.Configuration == nil || actual.Spec.Configuration.Settings == nil {
+		return
+	}
+	var prefixes []string
+	if backup {
+		prefixes = append(prefixes, "s3/"+backupEndpointName+"/")
+	}
+	if restore {
+		prefixes = append(prefixes, "s3/"+restoreEndpointName+"/")
+	}
+	actual.Spec.Configuration.Settings.Walk(func(name string, setting *chiv1.Setting) {
+		for _, prefix := range prefixes {
+			if strings.HasPrefix(name, prefix) {
+				desired.Spec.Configuration.Settings.Set(name, setting)
+			}
+		}
+	})
+}
+
+// backupScript runs BACKUP DATABASE into a new timestamped directory. $1 and
+// $2 are the server's host and native port, $3 the bucket URL, $4 the
+// backup prefix, $5 the database and $6 the cluster.
+const backupScript = `set -eu
+dir="$4$(date -u +%Y%m%dT%H%M%SZ)/"
+echo "backing up $5 to $3$dir"
+clickhouse-client --host "$1" --port "$2" --user "$CLICKHOUSE_USER" --password "$CLICKHOUSE_PASSWORD" \
+  --query "BACKUP DATABASE $5 ON CLUSTER '$6' TO S3('$3$dir')"`
+
+// ToBackupCronJob renders the CronJob that backs the instance up on the
+// backup schedule and, with a retention, prunes older backups once the new
+// one has been written.
+func ToBackupCronJob(
+	spec *apiv2.ManagedClickHouseSpec,
+	wandb *apiv2.WeightsAndBiases,
+	scheme *runtime.Scheme,
+	target *BackupTarget,
+	mfst manifest.Manifest,
+) (*batchv1.CronJob, error) {
+	nsnBuilder := CreateNsNameBuilder(types.NamespacedName{Namespace: spec.Namespace, Name: spec.Name})
+	prefix := BackupPrefix(spec.Namespace, spec.Name)
+
+	backup := clickHouseClientContainer(spec, wandb, mfst, "backup", backupScript,
+		target.BucketURL, prefix, ClickHouseDatabase, chiClusterName)
+	podSpec := backupJobPodSpec(spec, wandb, target)
+	if spec.Backup.Retention > 0 {
+		podSpec.InitContainers = []corev1.Container{backup}
+		podSpec.Containers = []corev1.Container{backupToolsContainer(wandb, target, mfst, "prune-backups",
+			objectstore.PruneBackupsScript, prefix, strconv.Itoa(int(spec.Backup.Retention)))}
+	} else {
+		podSpec.Containers = []corev1.Container{backup}
+	}
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nsnBuilder.BackupCronJobName(),
+			Namespace: spec.Namespace,
+			Labels:    BuildWandbClickhouseLabels(wandb),
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   spec.Backup.Schedule,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.To[int32](1),
+			FailedJobsHistoryLimit:     ptr.To[int32](1),
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					// A retry after a partial BACKUP writes a fresh directory.
+					BackoffLimit: ptr.To[int32](2),
+					Template: corev1.PodTemplateSpec{
+						ObjectMeta: metav1.ObjectMeta{Labels: backupPodLabels(spec.Name)},
+						Spec:       podSpec,
+					},
+				},
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(wandb, cronJob, scheme); err != nil {
+		return nil, fmt.Errorf("failed to set owner reference on ClickHouse backup CronJob: %w", err)
+	}
+	return cronJob, nil
+}
+
+// backupJobPodSpec is the pod shell shared by the backup and restore jobs.
+// They run as the server's ServiceAccount so ambient object store identity
+// covers the aws-cli containers too.
+func backupJobPodSpec(spec *apiv2.ManagedClickHouseSpec, wandb *apiv2.WeightsAndBiases, target *BackupTarget) corev1.PodSpec {
+	return corev1.PodSpec{
+		RestartPolicy:                corev1.RestartPolicyNever,
+		ServiceAccountName:           clickHouseServiceAccountName(spec),
+		AutomountServiceAccountToken: ptr.To(!target.Storage.HasStaticCredentials()),
+		ImagePullSecrets:             wandb.Spec.Global.ImagePullSecrets,
+		SecurityContext:              clickHousePodSecurityContext(),
+		Volumes:                      []corev1.Volume{writableEmptyDirVolume(backupWorkVolume)},
+	}
+}
+
+// clickHouseClientContainer runs script with clickhouse-client against the
+// instance's plaintext native port, which stays open in-cluster with TLS.
+func clickHouseClientContainer(
+	spec *apiv2.ManagedClickHouseSpec,
+	wandb *apiv2.WeightsAndBiases,
+	mfst manifest.Manifest,
+	name, script string,
+	args ...string,
+) corev1.Container {
+	nsnBuilder := CreateNsNameBuilder(types.NamespacedName{Namespace: spec.Namespace, Name: spec.Name})
+	connection := corev1.LocalObjectReference{Name: nsnBuilder.ConnectionName()}
+	return corev1.Container{
+		Name:    name,
+		Image:   ClickHouseImage(mfst.Clickhouse["default"].Images["server"], wandb.Spec.Global.ImageRegistry),
+		Command: []string{"/bin/sh", "-c"},
+		Args: append([]string{
+			script, name,
+			fmt.Sprintf("clickhouse-%s.%s.svc.cluster.local", spec.Name, spec.Namespace),
+			strconv.Itoa(ClickHouseNativePort),
+		}, args...),
+		Env: []corev1.EnvVar{
+			{Name: "HOME", Value: "/tmp"},
+			{Name: "CLICKHOUSE_USER", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: connection, Key: "User"},
+			}},
+			{Name: "CLICKHOUSE_PASSWORD", ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: connection, Key: "Password"},
+			}},
+		},
+		VolumeMounts:    []corev1.VolumeMount{{Name: backupWorkVolume, MountPath: backupWorkPath}},
+		SecurityContext: clickHouseContainerSecurityContext(),
+	}
+}
+
+// backupToolsContainer runs one of the objectstore backup scripts with the
+// bucket's endpoint, bucket and the given arguments.
+func backupToolsContainer(
+	wandb *apiv2.WeightsAndBiases,
+	target *BackupTarget,
+	mfst manifest.Manifest,
+	name, script string,
+	args ...string,
+) corev1.Container {
+	region := target.Storage.Region
+	if region == "" {
+		region = objectstore.DefaultRegion
+	}
+	env := []corev1.EnvVar{
+		{Name: "HOME", Value: "/tmp"},
+		{Name: "AWS_REGION", Value: region},
+	}
+	if target.Storage.HasStaticCredentials() {
+		accessKey, secretKey := target.Storage.AccessKeyRef, target.Storage.SecretKeyRef
+		env = append(env,
+			corev1.EnvVar{Name: "AWS_ACCESS_KEY_ID", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &accessKey}},
+			corev1.EnvVar{Name: "AWS_SECRET_ACCESS_KEY", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &secretKey}},
+		)
+	}
+	return corev1.Container{
+		Name:    name,
+		Image:   BackupToolsImage(mfst.Clickhouse["default"].Images[imageKeyBackupTools], wandb.Spec.Global.ImageRegistry),
+		Command: []string{"/bin/sh", "-c"},
+		Args: append([]string{
+			script, name, target.Storage.EndpointURL(), target.Storage.Bucket,
+		}, args...),
+		Env:             env,
+		VolumeMounts:    []corev1.VolumeMount{{Name: backupWorkVolume, MountPath: backupWorkPath}},
+		SecurityContext: clickHouseContainerSecurityContext(),
+	}
+}
+
+// WriteBackupState applies the backup CronJob, deleting it when desired is
+// nil, and returns the ClickHouseBackup condition.
+func WriteBackupState(
+	ctx context.Context,
+	cl client.Client,
+	specNamespacedName types.NamespacedName,
+	desired *batchv1.CronJob,
+) []metav1.Condition {
+	ctx, _ = logx.WithSlog(ctx, logx.ClickHouse)
+	log := logx.GetSlog(ctx)
+	nsName := types.NamespacedName{
+		Namespace: specNamespacedName.Namespace,
+		Name:      createNsNameBuilder(specNamespacedName).BackupCronJobName(),
+	}
+	if err := common.CrudResourceByName(ctx, cl, nsName, "CronJob", desired, &batchv1.CronJob{}); err != nil {
+		log.Error("failed to reconcile backup CronJob", logx.ErrAttr(err))
+		return []metav1.Condition{
+			{Type: common.ReconciledType, Status: metav1.ConditionFalse, Reason: common.ApiErrorReason},
+			{Type: ClickHouseBackupType, Status: metav1.ConditionUnknown, Reason: common.ApiErrorReason},
+		}
+	}
+	if desired == nil {
+		return nil
+	}
+	return computeClickHouseBackupCondition(ctx, cl, nsName)
+}
+
+// computeClickHouseBackupCondition reads the outcome of the latest backup from
+// the CronJob: a last schedule past the last success without an active job
+// means the newest run failed.
+func computeClickHouseBackupCondition(ctx context.Context, cl client.Client, nsName types.NamespacedName) []metav1.Condition {
+	cronJob := &batchv1.CronJob{}
+	found, err := common.GetResource(ctx, cl, nsName, "CronJob", cronJob)
+	if err != nil {
+		return []metav1.Condition{{Type: ClickHouseBackupType, Status: metav1.ConditionUnknown, Reason: common.ApiErrorReason}}
+	}
+	if !found {
+		cronJob = &batchv1.CronJob{}
+	}
+	status := cronJob.Status
+	last := status.LastSuccessfulTime
+	failed := len(status.Active) == 0 && status.LastScheduleTime != nil &&
+		(last == nil || last.Before(status.LastScheduleTime))
+
+	switch {
+	case failed && last == nil:
+		return []metav1.Condition{{
+			Type: ClickHouseBackupType, Status: metav1.ConditionFalse, Reason: BackupFailedReason,
+			Message: fmt.Sprintf("backup job from %s failed; no backup has succeeded yet", nsName.Name),
+		}}
+	case failed:
+		return []metav1.Condition{{
+			Type: ClickHouseBackupType, Status: metav1.ConditionFalse, Reason: BackupFailedReason,
+			Message: fmt.Sprintf("backup job from %s failed; last successful backup at %s", nsName.Name, last.UTC().Format(time.RFC3339)),
+		}}
+	case last == nil:
+		return []metav1.Condition{{
+			Type: ClickHouseBackupType, Status: metav1.ConditionFalse, Reason: BackupPendingReason,
+			Message: "waiting for the first scheduled backup",
+		}}
+	}
+	return []metav1.Condition{{
+		Type: ClickHouseBackupType, Status: metav1.ConditionTrue, Reason: BackupSucceededReason,
+		Message: fmt.Sprintf("last backup at %s", last.UTC().Format(time.RFC3339)),
+	}}
+}
+
+// ReadLastBackupTime returns when the backup CronJob last succeeded, or nil
+// if it has not yet.
+func ReadLastBackupTime(ctx context.Context, cl client.Client, specNamespacedName types.NamespacedName) *metav1.Time {
+	cronJob := &batchv1.CronJob{}
+	nsName := types.NamespacedName{
+		Namespace: specNamespacedName.Namespace,
+		Name:      createNsNameBuilder(specNamespacedName).BackupCronJobName(),
+	}
+	found, err := common.GetResource(ctx, cl, nsName, "CronJob", cronJob)
+	if err != nil || !found || cronJob.Status.LastSuccessfulTime == nil {
+		return nil
+	}
+	return cronJob.Status.LastSuccessfulTime.DeepCopy()
+}
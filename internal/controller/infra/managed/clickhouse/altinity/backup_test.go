@@ -0,0 +1,188 @@
+package altinity
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/infra/objectstore"
+	"github.com/wandb/operator/pkg/utils"
+	"github.com/wandb/operator/pkg/wandb/manifest"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func backupScheme() *runtime.Scheme {
+	scheme := clickHouseScheme()
+	Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}
+
+func seaweedBackupTarget() *BackupTarget {
+	ref := corev1.LocalObjectReference{Name: "objstore-conn"}
+	target, err := ResolveBackupTarget(objectstore.ConnInfo{
+		Provider:       apiv2.ObjectStoreProviderS3,
+		Bucket:         "wandb",
+		Endpoint:       "wandb-seaweedfs-s3.wandb.svc.cluster.local",
+		Port:           "8333",
+		AccessKey:      "access",
+		SecretKey:      "secret",
+		AccessKeyRef:   corev1.SecretKeySelector{LocalObjectReference: ref, Key: "AccessKey"},
+		SecretKeyRef:   corev1.SecretKeySelector{LocalObjectReference: ref, Key: "SecretKey"},
+		ForcePathStyle: true,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return target
+}
+
+var _ = Describe("ClickHouse backups", func() {
+	var wandb *apiv2.WeightsAndBiases
+	var spec *apiv2.ManagedClickHouseSpec
+	nn := types.NamespacedName{Name: "clickhouse", Namespace: "wandb"}
+
+	BeforeEach(func() {
+		utils.SetOpenShiftMode(false)
+		wandb = clickHouseWandb()
+		spec = wandb.Spec.ClickHouse[apiv2.DefaultInstanceName].ManagedClickHouse
+		spec.Backup = &apiv2.ClickHouseBackupSpec{Schedule: "0 3 * * *", Retention: 7}
+	})
+
+	It("backs up and then prunes on the schedule", func() {
+		cronJob, err := ToBackupCronJob(spec, wandb, backupScheme(), seaweedBackupTarget(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cronJob.Name).To(Equal("clickhouse-backup"))
+		Expect(cronJob.OwnerReferences).To(HaveLen(1))
+		Expect(cronJob.Spec.Schedule).To(Equal("0 3 * * *"))
+		Expect(cronJob.Spec.ConcurrencyPolicy).To(Equal(batchv1.ForbidConcurrent))
+		pod := cronJob.Spec.JobTemplate.Spec.Template
+		Expect(pod.Labels).To(HaveKeyWithValue("app.kubernetes.io/name", "clickhouse-backup"))
+		Expect(pod.Labels).To(HaveKeyWithValue("app.kubernetes.io/instance", "clickhouse"))
+		Expect(pod.Spec.ServiceAccountName).To(Equal(clickHouseServiceAccountName(spec)))
+		Expect(*pod.Spec.AutomountServiceAccountToken).To(BeFalse())
+
+		Expect(pod.Spec.InitContainers).To(HaveLen(1))
+		backup := pod.Spec.InitContainers[0]
+		Expect(backup.Args[1:]).To(Equal([]string{
+			"backup",
+			"clickhouse-clickhouse.wandb.svc.cluster.local",
+			"9000",
+			"http://wandb-seaweedfs-s3.wandb.svc.cluster.local:8333/wandb/",
+			"clickhouse-backup/wandb/clickhouse/",
+			ClickHouseDatabase,
+			chiClusterName,
+		}))
+		Expect(backup.Env).To(ContainElement(HaveField("Name", "CLICKHOUSE_PASSWORD")))
+
+		Expect(pod.Spec.Containers).To(HaveLen(1))
+		prune := pod.Spec.Containers[0]
+		Expect(prune.Image).To(Equal(defaultBackupToolsImage))
+		Expect(prune.Args[1:]).To(Equal([]string{
+			"prune-backups",
+			"http://wandb-seaweedfs-s3.wandb.svc.cluster.local:8333",
+			"wandb",
+			"clickhouse-backup/wandb/clickhouse/",
+			"7",
+		}))
+		Expect(prune.Env).To(ContainElement(HaveField("Name", "AWS_SECRET_ACCESS_KEY")))
+		Expect(*prune.SecurityContext.RunAsNonRoot).To(BeTrue())
+	})
+
+	It("keeps every backup without a retention", func() {
+		spec.Backup.Retention = 0
+		cronJob, err := ToBackupCronJob(spec, wandb, backupScheme(), seaweedBackupTarget(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		pod := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		Expect(pod.InitContainers).To(BeEmpty())
+		Expect(pod.Containers).To(HaveLen(1))
+		Expect(pod.Containers[0].Name).To(Equal("backup"))
+	})
+
+	It("rejects object stores BACKUP cannot write to", func() {
+		_, err := ResolveBackupTarget(objectstore.ConnInfo{Provider: apiv2.ObjectStoreProviderAzure, Bucket: "wandb"})
+		Expect(err).To(MatchError(ContainSubstring("S3-compatible")))
+	})
+
+	It("gives the servers credentials for the backup and restore buckets", func() {
+		chi, err := ToClickHouseVendorSpec(context.Background(), wandb, spec, clickHouseScheme(), testObjectStorageConn(), testObjectStorageEndpoint, true, manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		ambient, err := ResolveBackupTarget(objectstore.ConnInfo{Provider: apiv2.ObjectStoreProviderS3, Bucket: "archive", Region: "eu-west-1"})
+		Expect(err).NotTo(HaveOccurred())
+
+		ApplyBackupEndpoints(chi, seaweedBackupTarget(), ambient)
+		settings := chi.Spec.Configuration.Settings
+		Expect(settings.Get("s3/wandb_backup/endpoint").String()).To(Equal("http://wandb-seaweedfs-s3.wandb.svc.cluster.local:8333/wandb/"))
+		Expect(settings.Has("s3/wandb_backup/access_key_id")).To(BeTrue())
+		Expect(settings.Has("s3/wandb_backup/use_environment_credentials")).To(BeFalse())
+		Expect(settings.Get("s3/wandb_restore/region").String()).To(Equal("eu-west-1"))
+		Expect(settings.Get("s3/wandb_restore/use_environment_credentials").String()).To(Equal("true"))
+		Expect(settings.Has("s3/wandb_restore/access_key_id")).To(BeFalse())
+	})
+
+	It("keeps the live endpoint while its object store is unavailable", func() {
+		live, err := ToClickHouseVendorSpec(context.Background(), wandb, spec, clickHouseScheme(), testObjectStorageConn(), testObjectStorageEndpoint, true, manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		ApplyBackupEndpoints(live, seaweedBackupTarget(), nil)
+		c := fake.NewClientBuilder().WithScheme(backupScheme()).WithObjects(live).Build()
+
+		desired, err := ToClickHouseVendorSpec(context.Background(), wandb, spec, clickHouseScheme(), testObjectStorageConn(), testObjectStorageEndpoint, true, manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		RetainBackupEndpoints(context.Background(), c, nn, desired, true, false)
+		Expect(desired.Spec.Configuration.Settings.Get("s3/wandb_backup/endpoint").String()).
+			To(Equal("http://wandb-seaweedfs-s3.wandb.svc.cluster.local:8333/wandb/"))
+	})
+
+	It("creates the CronJob, waits for the first backup and removes it once switched off", func() {
+		cronJob, err := ToBackupCronJob(spec, wandb, backupScheme(), seaweedBackupTarget(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		c := fake.NewClientBuilder().WithScheme(backupScheme()).Build()
+
+		conditions := WriteBackupState(context.Background(), c, nn, cronJob)
+		Expect(conditions).To(HaveLen(1))
+		Expect(conditions[0].Reason).To(Equal(BackupPendingReason))
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(cronJob), &batchv1.CronJob{})).To(Succeed())
+
+		Expect(WriteBackupState(context.Background(), c, nn, nil)).To(BeEmpty())
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(cronJob), &batchv1.CronJob{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	Describe("ClickHouseBackup condition", func() {
+		lastBackup := metav1.NewTime(time.Date(2026, 10, 1, 3, 0, 5, 0, time.UTC))
+
+		condition := func(lastSchedule, lastSuccess *metav1.Time) metav1.Condition {
+			cronJob := &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "clickhouse-backup", Namespace: "wandb"},
+				Status:     batchv1.CronJobStatus{LastScheduleTime: lastSchedule, LastSuccessfulTime: lastSuccess},
+			}
+			c := fake.NewClientBuilder().WithScheme(backupScheme()).WithObjects(cronJob).Build()
+			conditions := computeClickHouseBackupCondition(context.Background(), c, client.ObjectKeyFromObject(cronJob))
+			Expect(conditions).To(HaveLen(1))
+			Expect(conditions[0].Type).To(Equal(ClickHouseBackupType))
+			return conditions[0]
+		}
+
+		It("reports the last successful backup", func() {
+			cond := condition(&lastBackup, &lastBackup)
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal(BackupSucceededReason))
+			Expect(cond.Message).To(ContainSubstring("2026-10-01T03:00:05Z"))
+		})
+
+		It("fails when the newest scheduled run did not succeed", func() {
+			nextDay := metav1.NewTime(lastBackup.Add(24 * time.Hour))
+			cond := condition(&nextDay, &lastBackup)
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(BackupFailedReason))
+			Expect(cond.Message).To(ContainSubstring("last successful backup at 2026-10-01T03:00:05Z"))
+		})
+	})
+})
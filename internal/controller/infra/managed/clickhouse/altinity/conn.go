@@ -26,6 +26,8 @@ type clickhouseConnInfo struct {
 	Tls         bool
 	Replicated  bool
 	ClusterName string
+	// SslCa is the CA of a TLS-served cluster; empty when it serves plaintext.
+	SslCa string
 }
 
 func (c *clickhouseConnInfo) toURL() string {
@@ -103,13 +105,17 @@ func writeClickHouseConnInfo(
 			"ClusterName": connInfo.ClusterName,
 		},
 	}
+	if connInfo.SslCa != "" {
+		desired.StringData["Tls"] = "true"
+		desired.StringData["SslCa"] = connInfo.SslCa
+	}
 
 	if _, err = common.CrudResource(ctx, client, desired, actual); err != nil {
 		return nil, err
 	}
 
 	localRef := corev1.LocalObjectReference{Name: nsName.Name}
-	return &apiv2.ClickHouseConnection{
+	connection := &apiv2.ClickHouseConnection{
 		URL:      corev1.SecretKeySelector{LocalObjectReference: localRef, Key: urlKey, Optional: ptr.To(false)},
 		Host:     corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Host", Optional: ptr.To(false)},
 		HTTPPort: corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "HTTPPort", Optional: ptr.To(false)},
@@ -120,5 +126,10 @@ func writeClickHouseConnInfo(
 		// The operator provisions the cluster, so these keys always exist.
 		Replicated:  corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Replicated", Optional: ptr.To(false)},
 		ClusterName: corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "ClusterName", Optional: ptr.To(false)},
-	}, nil
+	}
+	if connInfo.SslCa != "" {
+		connection.Tls = corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Tls", Optional: ptr.To(true)}
+		connection.SslCa = corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "SslCa", Optional: ptr.To(true)}
+	}
+	return connection, nil
 }
@@ -3,6 +3,7 @@ package altinity
 import (
 	"context"
 
+	apiv2 "github.com/wandb/operator/api/v2"
 	"github.com/wandb/operator/internal/controller/common"
 	"github.com/wandb/operator/internal/logx"
 	chiv1 "github.com/wandb/operator/pkg/vendored/altinity-clickhouse/clickhouse.altinity.com/v1"
@@ -31,6 +32,21 @@ func CheckDetached(
 	return nil
 }
 
+// AdoptDetached adopts the ClickHouseInstallation a previous CR of this
+// deployment detached. It returns proceed=false when the installation belongs
+// to another deployment.
+func AdoptDetached(
+	ctx context.Context,
+	cl client.Client,
+	specNamespacedName types.NamespacedName,
+	wandb *apiv2.WeightsAndBiases,
+) ([]metav1.Condition, bool) {
+	nsnBuilder := createNsNameBuilder(specNamespacedName)
+	return common.AdoptionConditions(common.AdoptDetached(
+		ctx, cl, wandb, nsnBuilder.InstallationNsName(), ResourceTypeName, &chiv1.ClickHouseInstallation{}, BuildWandbClickhouseLabels(wandb),
+	))
+}
+
 func DetachFinalizer(
 	ctx context.Context,
 	cl client.Client,
@@ -2,6 +2,8 @@ package keeper
 
 import (
 	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // chkNameSuffix is terse on purpose: it comes out of the DNS-1123 budget of
@@ -33,3 +35,17 @@ func PerHostConfigVolumeName(baseName string, shardOrdinal, replicaOrdinal int)
 	return fmt.Sprintf("chk-%s-deploy-confd-%s-%d-%d",
 		InstallationName(baseName), ClusterName, shardOrdinal, replicaOrdinal)
 }
+
+// PodSelector matches the Keeper pods, which Altinity labels with their CHK.
+func PodSelector(installationName string) metav1.LabelSelector {
+	return metav1.LabelSelector{MatchLabels: map[string]string{
+		"clickhouse-keeper.altinity.com/chk": installationName,
+	}}
+}
+
+// DataPVCPrefix is the name prefix of the Keeper data PVCs. Altinity names a
+// host's claim "<volumeClaimTemplate>-<pod>", with pods named
+// "chk-<chk-name>-<cluster>-<shard>-<replica>-0".
+func DataPVCPrefix(installationName string) string {
+	return fmt.Sprintf("%s-chk-%s-%s-", volumeTemplateName, installationName, ClusterName)
+}
@@ -120,7 +120,8 @@ func ToKeeperVendorSpec(
 						Name:       volumeTemplateName,
 						ObjectMeta: metav1.ObjectMeta{Labels: labels},
 						Spec: corev1.PersistentVolumeClaimSpec{
-							AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+							AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+							StorageClassName: common.StorageClassName(keeperStorageClassName(spec)),
 							Resources: corev1.VolumeResourceRequirements{
 								Requests: corev1.ResourceList{corev1.ResourceStorage: storageQuantity},
 							},
@@ -177,3 +178,12 @@ func keeperContainerSecurityContext() *corev1.SecurityContext {
 func runtimeDefaultSeccompProfile() *corev1.SeccompProfile {
 	return &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
 }
+
+// keeperStorageClassName picks the class for the Keeper raft disks, falling
+// back to the class of the ClickHouse server volumes.
+func keeperStorageClassName(spec *apiv2.ManagedClickHouseSpec) string {
+	if spec.Keeper.StorageClassName != "" {
+		return spec.Keeper.StorageClassName
+	}
+	return spec.StorageClassName
+}
@@ -90,6 +90,26 @@ var _ = Describe("Keeper vendor spec", func() {
 			To(Equal("myregistry.io/docker.io/altinity/clickhouse-keeper:25.8"))
 	})
 
+	It("puts Keeper on the server storage class unless it has its own", func() {
+		wandb := keeperWandb()
+		spec := wandb.Spec.ClickHouse[apiv2.DefaultInstanceName].ManagedClickHouse
+		spec.Keeper = apiv2.ClickHouseKeeperSpec{Replicas: 3, StorageSize: "5Gi"}
+
+		chk, err := ToKeeperVendorSpec(context.Background(), wandb, spec, keeperScheme(), keeperNsName(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(chk.Spec.Templates.VolumeClaimTemplates[0].Spec.StorageClassName).To(BeNil())
+
+		spec.StorageClassName = "fast-ssd"
+		chk, err = ToKeeperVendorSpec(context.Background(), wandb, spec, keeperScheme(), keeperNsName(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(chk.Spec.Templates.VolumeClaimTemplates[0].Spec.StorageClassName).To(HaveValue(Equal("fast-ssd")))
+
+		spec.Keeper.StorageClassName = "keeper-ssd"
+		chk, err = ToKeeperVendorSpec(context.Background(), wandb, spec, keeperScheme(), keeperNsName(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(chk.Spec.Templates.VolumeClaimTemplates[0].Spec.StorageClassName).To(HaveValue(Equal("keeper-ssd")))
+	})
+
 	It("errors when keeper storage size is unset (no operator defaults)", func() {
 		wandb := keeperWandb()
 		wandb.Spec.ClickHouse[apiv2.DefaultInstanceName].ManagedClickHouse.Keeper = apiv2.ClickHouseKeeperSpec{}
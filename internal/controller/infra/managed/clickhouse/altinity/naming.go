@@ -7,6 +7,7 @@ import (
 	apiv2 "github.com/wandb/operator/api/v2"
 	"github.com/wandb/operator/internal/controller/common"
 	"github.com/wandb/operator/internal/controller/infra/managed/clickhouse/altinity/keeper"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
 )
@@ -56,6 +57,20 @@ func (n *NsNameBuilder) VolumeTemplateName() string {
 	return fmt.Sprintf("%s-voltempl", n.SpecName())
 }
 
+// DataPVCPrefix is the name prefix of the server data PVCs. Altinity names a
+// host's claim "<volumeClaimTemplate>-<pod>", with pods named
+// "chi-<chi-name>-<cluster>-<shard>-<replica>-0".
+func (n *NsNameBuilder) DataPVCPrefix() string {
+	return fmt.Sprintf("%s-chi-%s-%s-", n.VolumeTemplateName(), n.InstallationName(), chiClusterName)
+}
+
+// PodSelector matches the server pods, which Altinity labels with their CHI.
+func (n *NsNameBuilder) PodSelector() metav1.LabelSelector {
+	return metav1.LabelSelector{MatchLabels: map[string]string{
+		"clickhouse.altinity.com/chi": n.InstallationName(),
+	}}
+}
+
 func (n *NsNameBuilder) PodTemplateName() string {
 	return fmt.Sprintf("%s-podtempl", n.SpecName())
 }
@@ -22,7 +22,7 @@ func PurgeFinalizer(
 	onDeleteRule common.OnDeleteRule,
 ) error {
 	ctx, _ = logx.WithSlog(ctx, logx.ClickHouse)
-	if onDeleteRule.Policy != common.Purge {
+	if !onDeleteRule.Purges() {
 		return nil
 	}
 	return purgeAssociatedResources(ctx, cl, specNamespacedName.Namespace, onDeleteRule.Selector)
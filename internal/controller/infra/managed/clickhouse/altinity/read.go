@@ -24,6 +24,12 @@ func readConnectionDetails(actual *chiv1.ClickHouseInstallation) *clickhouseConn
 	clickhouseHTTPPort := strconv.Itoa(ClickHouseHTTPPort)
 	clickhouseTCPPort := strconv.Itoa(ClickHouseNativePort)
 
+	secure, _ := readServerTLS(actual)
+	if secure {
+		clickhouseHTTPPort = strconv.Itoa(ClickHouseHTTPSPort)
+		clickhouseTCPPort = strconv.Itoa(ClickHouseNativeSecurePort)
+	}
+
 	replicated, clusterName := readClusterTopology(actual)
 
 	return &clickhouseConnInfo{
@@ -33,6 +39,7 @@ func readConnectionDetails(actual *chiv1.ClickHouseInstallation) *clickhouseConn
 		User:        ClickHouseUser,
 		Password:    ClickHousePassword,
 		Database:    ClickHouseDatabase,
+		Tls:         secure,
 		Replicated:  replicated,
 		ClusterName: clusterName,
 	}
@@ -121,6 +128,18 @@ func ReadState(
 		}
 
 		connInfo := readConnectionDetails(actual)
+		if connInfo != nil && connInfo.Tls {
+			if connInfo.SslCa, err = readServerCA(ctx, k8sClient, actual); err != nil {
+				log.Error("Failed to read ClickHouse server CA", logx.ErrAttr(err))
+				return []metav1.Condition{
+					{
+						Type:   ClickHouseConnectionInfoType,
+						Status: metav1.ConditionUnknown,
+						Reason: ctrlcommon.ApiErrorReason,
+					},
+				}, nil
+			}
+		}
 
 		connection, err = writeClickHouseConnInfo(
 			ctx, k8sClient, wandbOwner, nsnBuilder, connInfo,
@@ -0,0 +1,235 @@
+package altinity
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/internal/logx"
+	"github.com/wandb/operator/pkg/wandb/manifest"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// ClickHouseRestoreType reports a restore requested through restoreFrom.
+	// The instance stays pending until it is restored, which holds back the
+	// W&B migrations.
+	ClickHouseRestoreType = "ClickHouseRestore"
+
+	RestoreRestoringReason = "Restoring"
+	RestoreRestoredReason  = "Restored"
+	RestoreFailedReason    = "Failed"
+	// RestoreSkippedReason: the W&B migrations had already run, so the
+	// database was no longer empty.
+	RestoreSkippedReason = "Skipped"
+
+	latestBackupFile = backupWorkPath + "/backup"
+)
+
+var (
+	backupPrefixPattern = regexp.MustCompile(`^clickhouse-backup/[a-z0-9]([-a-z0-9]*[a-z0-9])?/[a-z0-9]([-a-z0-9]*[a-z0-9])?/?$`)
+	backupNamePattern   = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+)
+
+// ValidBackupPrefix reports whether prefix is a clickhouse-backup/<namespace>/<name>/
+// backup location.
+func ValidBackupPrefix(prefix string) bool {
+	return backupPrefixPattern.MatchString(prefix)
+}
+
+// ValidBackupName reports whether name can be a single backup directory.
+func ValidBackupName(name string) bool {
+	return backupNamePattern.MatchString(name)
+}
+
+// latestBackupScript writes the key prefix of the newest complete backup
+// under $3 to the file $4. ClickHouse writes a backup's .backup file last,
+// so directories without one are skipped.
+const latestBackupScript = `set -eu
+endpoint=""
+if [ -n "$1" ]; then endpoint="--endpoint-url $1"; fi
+for dir in $(aws $endpoint s3api list-objects-v2 --bucket "$2" --prefix "$3" --delimiter / \
+    --query 'CommonPrefixes[].Prefix' --output text | tr '\t' '\n' | grep -v '^None$' | sort -r); do
+  if aws $endpoint s3api head-object --bucket "$2" --key "${dir}.backup" >/dev/null 2>&1; then
+    echo "found backup s3://$2/$dir"
+    printf '%s' "$dir" > "$4"
+    exit 0
+  fi
+done
+echo "no complete backup under s3://$2/$3" >&2
+exit 1`
+
+// restoreScript waits for the server and runs RESTORE DATABASE. $1 and $2 are
+// the server's host and native port, $3 the bucket URL, $4 the backup's key
+// prefix (read from the find-backup container when empty), $5 the database
+// and $6 the cluster.
+const restoreScript = `set -eu
+dir="$4"
+if [ -z "$dir" ]; then dir="$(cat ` + latestBackupFile + `)"; fi
+until clickhouse-client --host "$1" --port "$2" --user "$CLICKHOUSE_USER" --password "$CLICKHOUSE_PASSWORD" \
+    --query 'SELECT 1' >/dev/null; do
+  echo "waiting for ClickHouse"
+  sleep 5
+done
+echo "restoring $5 from $3$dir"
+clickhouse-client --host "$1" --port "$2" --user "$CLICKHOUSE_USER" --password "$CLICKHOUSE_PASSWORD" \
+  --query "RESTORE DATABASE $5 ON CLUSTER '$6' FROM S3('$3$dir')"`
+
+// ToRestoreJob renders the Job that restores spec.RestoreFrom into the
+// instance. Without a named backup, a find-backup init container picks the
+// newest complete one.
+func ToRestoreJob(
+	spec *apiv2.ManagedClickHouseSpec,
+	wandb *apiv2.WeightsAndBiases,
+	scheme *runtime.Scheme,
+	target *BackupTarget,
+	mfst manifest.Manifest,
+) (*batchv1.Job, error) {
+	restoreFrom := spec.RestoreFrom
+	if !ValidBackupPrefix(restoreFrom.Prefix) {
+		return nil, fmt.Errorf("restore prefix %q is not of the form clickhouse-backup/<namespace>/<name>/", restoreFrom.Prefix)
+	}
+	prefix := restoreFrom.Prefix
+	if prefix[len(prefix)-1] != '/' {
+		prefix += "/"
+	}
+	nsnBuilder := CreateNsNameBuilder(types.NamespacedName{Namespace: spec.Namespace, Name: spec.Name})
+
+	podSpec := backupJobPodSpec(spec, wandb, target)
+	dir := ""
+	if restoreFrom.Backup != "" {
+		dir = prefix + restoreFrom.Backup + "/"
+	} else {
+		podSpec.InitContainers = []corev1.Container{backupToolsContainer(wandb, target, mfst, "find-backup",
+			latestBackupScript, prefix, latestBackupFile)}
+	}
+	podSpec.Containers = []corev1.Container{clickHouseClientContainer(spec, wandb, mfst, "restore", restoreScript,
+		target.BucketURL, dir, ClickHouseDatabase, chiClusterName)}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      nsnBuilder.RestoreJobName(),
+			Namespace: spec.Namespace,
+			Labels:    BuildWandbClickhouseLabels(wandb),
+		},
+		Spec: batchv1.JobSpec{
+			// A failed RESTORE can leave tables behind that make a retry fail
+			// too; retrying is left to the user.
+			BackoffLimit: ptr.To[int32](0),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: backupPodLabels(spec.Name)},
+				Spec:       podSpec,
+			},
+		},
+	}
+	if err := controllerutil.SetControllerReference(wandb, job, scheme); err != nil {
+		return nil, fmt.Errorf("failed to set owner reference on ClickHouse restore Job: %w", err)
+	}
+	return job, nil
+}
+
+// WriteRestoreState runs the restore Job once and reports on it. The Job is
+// not created again when previous records a finished restore, nor when
+// migrated reports that the W&B migrations have already filled the database.
+// A nil desired, for a source that cannot be read yet, only reports on a Job
+// that already exists.
+func WriteRestoreState(
+	ctx context.Context,
+	cl client.Client,
+	specNamespacedName types.NamespacedName,
+	desired *batchv1.Job,
+	previous *metav1.Condition,
+	migrated bool,
+) []metav1.Condition {
+	ctx, _ = logx.WithSlog(ctx, logx.ClickHouse)
+	log := logx.GetSlog(ctx)
+	apiError := []metav1.Condition{
+		{Type: common.ReconciledType, Status: metav1.ConditionFalse, Reason: common.ApiErrorReason},
+		{Type: ClickHouseRestoreType, Status: metav1.ConditionUnknown, Reason: common.ApiErrorReason},
+	}
+
+	actual := &batchv1.Job{}
+	found, err := common.GetResource(ctx, cl, restoreJobNsName(specNamespacedName), "Job", actual)
+	if err != nil {
+		return apiError
+	}
+	switch {
+	case found:
+		return []metav1.Condition{computeClickHouseRestoreCondition(actual)}
+	case previous != nil && previous.Status == metav1.ConditionTrue:
+		return []metav1.Condition{{
+			Type: ClickHouseRestoreType, Status: metav1.ConditionTrue, Reason: previous.Reason, Message: previous.Message,
+		}}
+	case migrated:
+		return []metav1.Condition{{
+			Type: ClickHouseRestoreType, Status: metav1.ConditionTrue, Reason: RestoreSkippedReason,
+			Message: "the W&B migrations have already run; restoreFrom only applies to a new instance",
+		}}
+	case desired == nil:
+		return nil
+	}
+
+	if err := cl.Create(ctx, desired); err != nil {
+		log.Error("failed to create restore Job", logx.ErrAttr(err))
+		return apiError
+	}
+	log.Info("Create", "namespace", desired.Namespace, "name", desired.Name)
+	return []metav1.Condition{computeClickHouseRestoreCondition(desired)}
+}
+
+// DeleteRestoreJob removes the restore Job and its pods once restoreFrom is
+// unset.
+func DeleteRestoreJob(ctx context.Context, cl client.Client, specNamespacedName types.NamespacedName) []metav1.Condition {
+	ctx, _ = logx.WithSlog(ctx, logx.ClickHouse)
+	log := logx.GetSlog(ctx)
+	job := &batchv1.Job{}
+	found, err := common.GetResource(ctx, cl, restoreJobNsName(specNamespacedName), "Job", job)
+	if err == nil && found {
+		err = client.IgnoreNotFound(cl.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground)))
+	}
+	if err != nil {
+		log.Error("failed to delete restore Job", logx.ErrAttr(err))
+		return []metav1.Condition{{Type: common.ReconciledType, Status: metav1.ConditionFalse, Reason: common.ApiErrorReason}}
+	}
+	return nil
+}
+
+func restoreJobNsName(specNamespacedName types.NamespacedName) types.NamespacedName {
+	return types.NamespacedName{
+		Namespace: specNamespacedName.Namespace,
+		Name:      createNsNameBuilder(specNamespacedName).RestoreJobName(),
+	}
+}
+
+func computeClickHouseRestoreCondition(job *batchv1.Job) metav1.Condition {
+	for _, cond := range job.Status.Conditions {
+		if cond.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch cond.Type {
+		case batchv1.JobComplete:
+			return metav1.Condition{
+				Type: ClickHouseRestoreType, Status: metav1.ConditionTrue, Reason: RestoreRestoredReason,
+				Message: fmt.Sprintf("restore job %s completed", job.Name),
+			}
+		case batchv1.JobFailed:
+			return metav1.Condition{
+				Type: ClickHouseRestoreType, Status: metav1.ConditionFalse, Reason: RestoreFailedReason,
+				Message: fmt.Sprintf("restore job %s failed: %s; delete it to retry", job.Name, cond.Message),
+			}
+		}
+	}
+	return metav1.Condition{
+		Type: ClickHouseRestoreType, Status: metav1.ConditionFalse, Reason: RestoreRestoringReason,
+		Message: fmt.Sprintf("restore job %s is running", job.Name),
+	}
+}
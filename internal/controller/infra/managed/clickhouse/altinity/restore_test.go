@@ -0,0 +1,133 @@
+package altinity
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/pkg/utils"
+	"github.com/wandb/operator/pkg/wandb/manifest"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("ClickHouse restores", func() {
+	var wandb *apiv2.WeightsAndBiases
+	var spec *apiv2.ManagedClickHouseSpec
+	nn := types.NamespacedName{Name: "clickhouse", Namespace: "wandb"}
+
+	BeforeEach(func() {
+		utils.SetOpenShiftMode(false)
+		wandb = clickHouseWandb()
+		spec = wandb.Spec.ClickHouse[apiv2.DefaultInstanceName].ManagedClickHouse
+		spec.RestoreFrom = &apiv2.ClickHouseRestoreSpec{Prefix: "clickhouse-backup/old/clickhouse"}
+	})
+
+	It("finds the newest backup before restoring it", func() {
+		job, err := ToRestoreJob(spec, wandb, backupScheme(), seaweedBackupTarget(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Name).To(Equal("clickhouse-restore"))
+		Expect(*job.Spec.BackoffLimit).To(BeZero())
+
+		pod := job.Spec.Template.Spec
+		Expect(pod.InitContainers).To(HaveLen(1))
+		Expect(pod.InitContainers[0].Args[1:]).To(Equal([]string{
+			"find-backup",
+			"http://wandb-seaweedfs-s3.wandb.svc.cluster.local:8333",
+			"wandb",
+			"clickhouse-backup/old/clickhouse/",
+			latestBackupFile,
+		}))
+		Expect(pod.Containers[0].Args[4:6]).To(Equal([]string{
+			"http://wandb-seaweedfs-s3.wandb.svc.cluster.local:8333/wandb/", "",
+		}))
+	})
+
+	It("restores a named backup directly", func() {
+		spec.RestoreFrom.Backup = "20261001T030000Z"
+		job, err := ToRestoreJob(spec, wandb, backupScheme(), seaweedBackupTarget(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(job.Spec.Template.Spec.InitContainers).To(BeEmpty())
+		Expect(job.Spec.Template.Spec.Containers[0].Args[5]).To(Equal("clickhouse-backup/old/clickhouse/20261001T030000Z/"))
+	})
+
+	It("rejects a prefix that is not a ClickHouse backup location", func() {
+		spec.RestoreFrom.Prefix = "moco/old/mysql/"
+		_, err := ToRestoreJob(spec, wandb, backupScheme(), seaweedBackupTarget(), manifest.Manifest{})
+		Expect(err).To(MatchError(ContainSubstring("clickhouse-backup/<namespace>/<name>/")))
+	})
+
+	Describe("WriteRestoreState", func() {
+		var job *batchv1.Job
+
+		BeforeEach(func() {
+			var err error
+			job, err = ToRestoreJob(spec, wandb, backupScheme(), seaweedBackupTarget(), manifest.Manifest{})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("starts the restore on a new instance", func() {
+			c := fake.NewClientBuilder().WithScheme(backupScheme()).Build()
+			conditions := WriteRestoreState(context.Background(), c, nn, job, nil, false)
+			Expect(conditions).To(HaveLen(1))
+			Expect(conditions[0].Status).To(Equal(metav1.ConditionFalse))
+			Expect(conditions[0].Reason).To(Equal(RestoreRestoringReason))
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(job), &batchv1.Job{})).To(Succeed())
+		})
+
+		It("follows the job to completion or failure", func() {
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}
+			c := fake.NewClientBuilder().WithScheme(backupScheme()).WithObjects(job).Build()
+			conditions := WriteRestoreState(context.Background(), c, nn, nil, nil, true)
+			Expect(conditions).To(ConsistOf(HaveField("Reason", RestoreRestoredReason)))
+
+			job.Status.Conditions = []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "exit 1"}}
+			Expect(computeClickHouseRestoreCondition(job).Message).To(ContainSubstring("delete it to retry"))
+		})
+
+		It("does not restore again once done", func() {
+			c := fake.NewClientBuilder().WithScheme(backupScheme()).Build()
+			previous := &metav1.Condition{Type: ClickHouseRestoreType, Status: metav1.ConditionTrue, Reason: RestoreRestoredReason}
+			conditions := WriteRestoreState(context.Background(), c, nn, job, previous, true)
+			Expect(conditions).To(ConsistOf(HaveField("Reason", RestoreRestoredReason)))
+			err := c.Get(context.Background(), client.ObjectKeyFromObject(job), &batchv1.Job{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("skips an instance the migrations have already filled", func() {
+			c := fake.NewClientBuilder().WithScheme(backupScheme()).Build()
+			conditions := WriteRestoreState(context.Background(), c, nn, job, nil, true)
+			Expect(conditions).To(HaveLen(1))
+			Expect(conditions[0].Status).To(Equal(metav1.ConditionTrue))
+			Expect(conditions[0].Reason).To(Equal(RestoreSkippedReason))
+			err := c.Get(context.Background(), client.ObjectKeyFromObject(job), &batchv1.Job{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("removes the job once restoreFrom is unset", func() {
+			c := fake.NewClientBuilder().WithScheme(backupScheme()).WithObjects(job).Build()
+			Expect(DeleteRestoreJob(context.Background(), c, nn)).To(BeEmpty())
+			err := c.Get(context.Background(), client.ObjectKeyFromObject(job), &batchv1.Job{})
+			Expect(apierrors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	It("keeps the instance pending until the restore is done", func() {
+		state := func(status metav1.ConditionStatus, reason string) string {
+			return inferState_ClickHouseRestoreType(context.Background(), metav1.Condition{
+				Type: ClickHouseRestoreType, Status: status, Reason: reason,
+			})
+		}
+		Expect(state(metav1.ConditionFalse, RestoreRestoringReason)).To(Equal(common.PendingState))
+		Expect(state(metav1.ConditionFalse, ObjectStoreUnavailableReason)).To(Equal(common.PendingState))
+		Expect(state(metav1.ConditionFalse, RestoreFailedReason)).To(Equal(common.ErrorState))
+		Expect(state(metav1.ConditionTrue, RestoreSkippedReason)).To(Equal(common.HealthyState))
+	})
+})
@@ -240,8 +240,15 @@ func ToClickHouseVendorSpec(
 	}
 
 	reclaimPolicy := v1.PVCReclaimPolicyUnspecified
-	if wandb.GetRetentionPolicy(spec.ManagedInfraSpec).OnDelete == apiv2.PurgeOnDelete {
+	switch wandb.GetRetentionPolicy(spec.ManagedInfraSpec).OnDelete {
+	case apiv2.PurgeOnDelete:
 		reclaimPolicy = v1.PVCReclaimPolicyDelete
+	case apiv2.SnapshotOnDelete:
+		// A foreground delete removes the installation before the retention
+		// finalizer runs; keep the PVCs for the finalizer to snapshot and purge.
+		// This also keeps PVCs left behind by a scale-down; see
+		// docs/retention-snapshots.md.
+		reclaimPolicy = v1.PVCReclaimPolicyRetain
 	}
 
 	clickHouseImage := ClickHouseImage(mfst.Clickhouse["default"].Images["server"], wandb.Spec.Global.ImageRegistry)
@@ -332,6 +339,7 @@ func ToClickHouseVendorSpec(
 							AccessModes: []corev1.PersistentVolumeAccessMode{
 								corev1.ReadWriteOnce,
 							},
+							StorageClassName: common.StorageClassName(spec.StorageClassName),
 							Resources: corev1.VolumeResourceRequirements{
 								Requests: corev1.ResourceList{
 									corev1.ResourceStorage: storageQuantity,
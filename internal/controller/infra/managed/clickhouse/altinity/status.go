@@ -44,6 +44,7 @@ func ComputeStatus(
 	)
 
 	state, events := inferInfraState(ctx, enabled, result.Conditions)
+	events = append(events, common.AdoptionEvents(oldConditions, currentConditions)...)
 	result.State = state
 
 	result.Ready = !lo.Contains(common.NotReadyStates, result.State)
@@ -97,10 +98,12 @@ func inferInfraState(
 		})
 	}
 
+	impliedStates = inferStateFromCondition(ctx, common.InfraOwnedType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, ClickHouseCustomResourceType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, ClickHouseConnectionInfoType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, ClickHouseReportedReadyType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, keeper.KeeperReportedReadyType, impliedStates, conditions)
+	impliedStates = inferStateFromCondition(ctx, ClickHouseRestoreType, impliedStates, conditions)
 
 	hasImpliedState := func(target string) bool {
 		return len(lo.FilterValues(
@@ -152,6 +155,8 @@ func inferStateFromCondition(ctx context.Context, conditionType string, impliedS
 		impliedStates[conditionType] = common.UnknownState
 	} else {
 		switch conditionType {
+		case common.InfraOwnedType:
+			impliedStates[conditionType] = common.InferInfraOwnedState(cond)
 		case ClickHouseCustomResourceType:
 			impliedStates[conditionType] = inferState_ClickHouseCustomResourceType(ctx, cond)
 		case ClickHouseConnectionInfoType:
@@ -160,6 +165,8 @@ func inferStateFromCondition(ctx context.Context, conditionType string, impliedS
 			impliedStates[conditionType] = inferState_ClickHouseReportedReadyType(ctx, cond)
 		case keeper.KeeperReportedReadyType:
 			impliedStates[conditionType] = inferState_KeeperReportedReadyType(ctx, cond)
+		case ClickHouseRestoreType:
+			impliedStates[conditionType] = inferState_ClickHouseRestoreType(ctx, cond)
 		default:
 			impliedStates[conditionType] = common.UnknownState
 		}
@@ -243,3 +250,23 @@ func inferState_ClickHouseReportedReadyType(ctx context.Context, condition metav
 	)
 	return result
 }
+
+// inferState_ClickHouseRestoreType holds the instance pending while a restore
+// runs, so the W&B migrations wait for it.
+func inferState_ClickHouseRestoreType(ctx context.Context, condition metav1.Condition) string {
+	log := logx.GetSlog(ctx)
+	result := common.UnknownState
+	switch {
+	case condition.Status == metav1.ConditionTrue:
+		result = common.HealthyState
+	case condition.Status == metav1.ConditionFalse && (condition.Reason == RestoreFailedReason || condition.Reason == UnsupportedObjectStoreReason):
+		result = common.ErrorState
+	case condition.Status == metav1.ConditionFalse:
+		result = common.PendingState
+	}
+	log.Debug(
+		"implied state", "state", result, "condition", condition.Type,
+		"reason", condition.Reason, "status", condition.Status,
+	)
+	return result
+}
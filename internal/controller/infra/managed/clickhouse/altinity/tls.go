@@ -0,0 +1,124 @@
+package altinity
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	chiv1 "github.com/wandb/operator/pkg/vendored/altinity-clickhouse/clickhouse.altinity.com/v1"
+	chtypes "github.com/wandb/operator/pkg/vendored/altinity-clickhouse/common/types"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	serverTLSVolumeName = "server-tls"
+	serverTLSMountPath  = "/etc/clickhouse-server/tls"
+)
+
+// ServerDNSNames lists the names the ClickHouse server certificate covers:
+// the CHI Service and the per-host Services replicas dial each other on. The
+// per-host names follow the replica count, so scaling reissues the
+// certificate.
+func ServerDNSNames(spec *apiv2.ManagedClickHouseSpec) []string {
+	services := []string{"clickhouse-" + spec.Name}
+	for shard := 0; shard < ShardsCount; shard++ {
+		for replica := 0; replica < int(spec.Replicas); replica++ {
+			services = append(services, fmt.Sprintf("chi-%s-%s-%d-%d", spec.Name, chiClusterName, shard, replica))
+		}
+	}
+	return common.InfraServiceDNSNames(spec.Namespace, services...)
+}
+
+// ApplyServerTLS serves the native and HTTP interfaces on their secure ports
+// with the server certificate Secret, and has replicas dial each other on the
+// secure port, verifying against the same CA. The plaintext ports stay open:
+// the Altinity operator reads schema and status over them.
+func ApplyServerTLS(chi *chiv1.ClickHouseInstallation, secret *corev1.Secret) {
+	settings := chi.Spec.Configuration.Settings
+	settings.Set("tcp_port_secure", chiv1.NewSettingScalar(strconv.Itoa(ClickHouseNativeSecurePort)))
+	settings.Set("https_port", chiv1.NewSettingScalar(strconv.Itoa(ClickHouseHTTPSPort)))
+	settings.Set("openSSL/server/certificateFile", chiv1.NewSettingScalar(path.Join(serverTLSMountPath, corev1.TLSCertKey)))
+	settings.Set("openSSL/server/privateKeyFile", chiv1.NewSettingScalar(path.Join(serverTLSMountPath, corev1.TLSPrivateKeyKey)))
+	settings.Set("openSSL/server/caConfig", chiv1.NewSettingScalar(path.Join(serverTLSMountPath, common.InfraTLSCAKey)))
+	settings.Set("openSSL/server/verificationMode", chiv1.NewSettingScalar("none"))
+	settings.Set("openSSL/server/disableProtocols", chiv1.NewSettingScalar("sslv2,sslv3,tlsv1,tlsv1_1"))
+	settings.Set("openSSL/client/caConfig", chiv1.NewSettingScalar(path.Join(serverTLSMountPath, common.InfraTLSCAKey)))
+	settings.Set("openSSL/client/verificationMode", chiv1.NewSettingScalar("strict"))
+	settings.Set("openSSL/client/disableProtocols", chiv1.NewSettingScalar("sslv2,sslv3,tlsv1,tlsv1_1"))
+
+	for _, cluster := range chi.Spec.Configuration.Clusters {
+		cluster.Secure = chtypes.NewStringBool(true)
+		cluster.Insecure = chtypes.NewStringBool(false)
+	}
+
+	for i := range chi.Spec.Templates.PodTemplates {
+		podTemplate := &chi.Spec.Templates.PodTemplates[i]
+		podTemplate.Spec.Volumes = append(podTemplate.Spec.Volumes, corev1.Volume{
+			Name: serverTLSVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: secret.Name},
+			},
+		})
+		for j := range podTemplate.Spec.Containers {
+			if podTemplate.Spec.Containers[j].Name != "clickhouse" {
+				continue
+			}
+			podTemplate.Spec.Containers[j].VolumeMounts = append(podTemplate.Spec.Containers[j].VolumeMounts, corev1.VolumeMount{
+				Name:      serverTLSVolumeName,
+				MountPath: serverTLSMountPath,
+				ReadOnly:  true,
+			})
+		}
+		if podTemplate.ObjectMeta.Annotations == nil {
+			podTemplate.ObjectMeta.Annotations = map[string]string{}
+		}
+		podTemplate.ObjectMeta.Annotations[common.InfraTLSChecksumAnnotation] = common.InfraTLSChecksum(secret)
+	}
+}
+
+// readServerTLS reports whether the live CHI serves TLS, and the server
+// certificate Secret it mounts.
+func readServerTLS(actual *chiv1.ClickHouseInstallation) (bool, string) {
+	if actual.Spec.Configuration == nil {
+		return false, ""
+	}
+	secure := false
+	for _, cluster := range actual.Spec.Configuration.Clusters {
+		if cluster != nil && cluster.Name == chiClusterName {
+			secure = cluster.Secure.Value()
+		}
+	}
+	if !secure || actual.Spec.Templates == nil {
+		return secure, ""
+	}
+	for _, podTemplate := range actual.Spec.Templates.PodTemplates {
+		for _, volume := range podTemplate.Spec.Volumes {
+			if volume.Name == serverTLSVolumeName && volume.Secret != nil {
+				return true, volume.Secret.SecretName
+			}
+		}
+	}
+	return true, ""
+}
+
+// readServerCA returns the CA of the live CHI's server certificate, or ""
+// when it serves plaintext.
+func readServerCA(ctx context.Context, c client.Client, actual *chiv1.ClickHouseInstallation) (string, error) {
+	secure, secretName := readServerTLS(actual)
+	if !secure {
+		return "", nil
+	}
+	if secretName == "" {
+		return "", fmt.Errorf("ClickHouseInstallation %s is secure but mounts no server certificate", actual.Name)
+	}
+	ca, err := common.ReadInfraTLSCA(ctx, c, types.NamespacedName{Name: secretName, Namespace: actual.Namespace})
+	if err == nil && ca == "" {
+		err = fmt.Errorf("server certificate Secret %s has no CA", secretName)
+	}
+	return ca, err
+}
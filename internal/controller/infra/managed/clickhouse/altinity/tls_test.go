@@ -0,0 +1,75 @@
+package altinity
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/pkg/utils"
+	chiv1 "github.com/wandb/operator/pkg/vendored/altinity-clickhouse/clickhouse.altinity.com/v1"
+	"github.com/wandb/operator/pkg/wandb/manifest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("ClickHouse server TLS", func() {
+	BeforeEach(func() {
+		utils.SetOpenShiftMode(false)
+	})
+
+	serverSecret := func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "wandb-clickhouse-tls", Namespace: "default"},
+			Data: map[string][]byte{
+				common.InfraTLSCAKey:    []byte("ca"),
+				corev1.TLSCertKey:       []byte("cert"),
+				corev1.TLSPrivateKeyKey: []byte("key"),
+			},
+		}
+	}
+
+	It("covers the CHI Service and every replica's host Service", func() {
+		names := ServerDNSNames(&apiv2.ManagedClickHouseSpec{Name: "wandb-clickhouse", Namespace: "default", Replicas: 2})
+		Expect(names).To(ContainElements(
+			"clickhouse-wandb-clickhouse.default.svc",
+			"chi-wandb-clickhouse-default-0-0.default.svc",
+			"chi-wandb-clickhouse-default-0-1.default.svc.cluster.local",
+		))
+	})
+
+	// Applications read ports off the connection Secret, so a CHI serving TLS
+	// must publish the secure ports, and one that doesn't must not.
+	It("serves and publishes the secure ports", func() {
+		wandb := clickHouseWandb()
+		chi, err := ToClickHouseVendorSpec(context.Background(), wandb, wandb.Spec.ClickHouse[apiv2.DefaultInstanceName].ManagedClickHouse, clickHouseScheme(), testObjectStorageConn(), testObjectStorageEndpoint, true, manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+
+		ApplyServerTLS(chi, serverSecret())
+
+		settings := chi.Spec.Configuration.Settings
+		Expect(settings.GetTCPPortSecure().Value()).To(BeEquivalentTo(ClickHouseNativeSecurePort))
+		Expect(settings.GetHTTPSPort().Value()).To(BeEquivalentTo(ClickHouseHTTPSPort))
+		Expect(settings.Get("openSSL/server/certificateFile").String()).To(Equal(serverTLSMountPath + "/tls.crt"))
+		Expect(chi.Spec.Configuration.Clusters[0].Secure.Value()).To(BeTrue())
+
+		podTemplate := chi.Spec.Templates.PodTemplates[0]
+		Expect(podTemplate.ObjectMeta.Annotations).To(HaveKey(common.InfraTLSChecksumAnnotation))
+		Expect(podTemplate.Spec.Containers[0].VolumeMounts).To(ContainElement(corev1.VolumeMount{
+			Name: serverTLSVolumeName, MountPath: serverTLSMountPath, ReadOnly: true,
+		}))
+
+		secure, secretName := readServerTLS(chi)
+		Expect(secure).To(BeTrue())
+		Expect(secretName).To(Equal("wandb-clickhouse-tls"))
+
+		chi.Status = &chiv1.Status{Endpoint: "clickhouse-wandb-clickhouse.default.svc"}
+		connInfo := readConnectionDetails(chi)
+		Expect(connInfo.Tls).To(BeTrue())
+		Expect(connInfo.TCPPort).To(Equal("9440"))
+		Expect(connInfo.HTTPPort).To(Equal("8443"))
+
+		Expect(connInfo.toURL()).To(ContainSubstring(":9440/default?tls=true"))
+	})
+})
@@ -11,11 +11,13 @@ const (
 	ConnectionName = "wandb-clickhouse-connection"
 
 	// ClickHouse configuration
-	ClickHouseNativePort = 9000
-	ClickHouseHTTPPort   = 8123
-	ClickHouseUser       = "test_user"
-	ClickHousePassword   = "test_password"
-	ClickHouseDatabase   = "default"
+	ClickHouseNativePort       = 9000
+	ClickHouseHTTPPort         = 8123
+	ClickHouseNativeSecurePort = 9440
+	ClickHouseHTTPSPort        = 8443
+	ClickHouseUser             = "test_user"
+	ClickHousePassword         = "test_password"
+	ClickHouseDatabase         = "default"
 
 	// Cluster configuration
 	ShardsCount = 1
@@ -9,6 +9,7 @@ import (
 	chkv1 "github.com/wandb/operator/pkg/vendored/altinity-clickhouse/clickhouse-keeper.altinity.com/v1"
 	chiv1 "github.com/wandb/operator/pkg/vendored/altinity-clickhouse/clickhouse.altinity.com/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -46,10 +47,32 @@ func WriteState(
 		desiredKeeper,
 	)...)
 	results = append(results, writeClickHouseInstallation(ctx, client, specNamespacedName, desired)...)
+	// Both claim names end in the pod's "<shard>-<replica>-<ordinal>".
+	results = append(results, common.ExpandVolumeClaims(ctx, client, specNamespacedName.Namespace,
+		common.VolumeClaimSize{
+			NamePrefixes: []string{createNsNameBuilder(specNamespacedName).DataPVCPrefix()},
+			Ordinals:     3,
+			Size:         claimTemplateSize(desired.Spec.Templates),
+		},
+		common.VolumeClaimSize{
+			NamePrefixes: []string{keeper.DataPVCPrefix(desiredKeeper.Name)},
+			Ordinals:     3,
+			Size:         claimTemplateSize(desiredKeeper.Spec.Templates),
+		},
+	))
 
 	return results
 }
 
+// claimTemplateSize is the storage request of the data volume template; both
+// the CHI and the CHK define exactly one.
+func claimTemplateSize(templates *chiv1.Templates) resource.Quantity {
+	if templates == nil || len(templates.VolumeClaimTemplates) == 0 {
+		return resource.Quantity{}
+	}
+	return templates.VolumeClaimTemplates[0].Spec.Resources.Requests[corev1.ResourceStorage]
+}
+
 func writeServiceAccount(
 	ctx context.Context,
 	cl client.Client,
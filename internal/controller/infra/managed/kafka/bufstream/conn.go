@@ -3,6 +3,7 @@ package bufstream
 import (
 	"context"
 	"fmt"
+	"net"
 	"strconv"
 
 	apiv2 "github.com/wandb/operator/api/v2"
@@ -24,6 +25,12 @@ func (c *kafkaConnInfo) toURL() string {
 	return fmt.Sprintf("kafka://%s:%s", c.Host, c.Port)
 }
 
+// brokers is the bootstrap list clients dial, in the "host:port" form an
+// external Kafka's BrokerEndpoint takes.
+func (c *kafkaConnInfo) brokers() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
+
 func readConnectionDetails(nsnBuilder *NsNameBuilder) *kafkaConnInfo {
 	return &kafkaConnInfo{
 		Host: nsnBuilder.BufstreamHost(),
@@ -79,9 +86,10 @@ func writeKafkaConnInfo(
 		},
 		Type: corev1.SecretTypeOpaque,
 		StringData: map[string]string{
-			urlKey: connInfo.toURL(),
-			"Host": connInfo.Host,
-			"Port": connInfo.Port,
+			urlKey:    connInfo.toURL(),
+			"Host":    connInfo.Host,
+			"Port":    connInfo.Port,
+			"Brokers": connInfo.brokers(),
 		},
 	}
 
@@ -94,6 +102,6 @@ func writeKafkaConnInfo(
 		URL:            corev1.SecretKeySelector{LocalObjectReference: localRef, Key: urlKey, Optional: ptr.To(false)},
 		Host:           corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Host", Optional: ptr.To(false)},
 		Port:           corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Port", Optional: ptr.To(false)},
-		BrokerEndpoint: corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Host", Optional: ptr.To(false)},
+		BrokerEndpoint: corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Brokers", Optional: ptr.To(false)},
 	}, nil
 }
@@ -0,0 +1,30 @@
+package bufstream
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv2 "github.com/wandb/operator/api/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestWriteKafkaConnInfoPublishesBrokers(t *testing.T) {
+	scheme := testScheme(t)
+	require.NoError(t, corev1.AddToScheme(scheme))
+	cl := fake.NewClientBuilder().WithScheme(scheme).Build()
+	nsnBuilder := createNsNameBuilder(types.NamespacedName{Namespace: "default", Name: "wandb-kafka"})
+
+	owner := &apiv2.WeightsAndBiases{ObjectMeta: metav1.ObjectMeta{Name: "wandb", Namespace: "default", UID: "uid-wandb"}}
+
+	connection, err := writeKafkaConnInfo(context.Background(), cl, owner, nsnBuilder, readConnectionDetails(nsnBuilder))
+	require.NoError(t, err)
+	require.Equal(t, "Brokers", connection.BrokerEndpoint.Key)
+
+	secret := &corev1.Secret{}
+	require.NoError(t, cl.Get(context.Background(), nsnBuilder.ConnectionNsName(), secret))
+	require.Equal(t, nsnBuilder.BufstreamHost()+":9092", secret.StringData["Brokers"])
+}
@@ -51,6 +51,24 @@ func CheckDetached(
 	return nil
 }
 
+// AdoptDetached adopts the etcd and broker Applications a previous CR of this
+// deployment detached. It returns proceed=false when one of them belongs to
+// another deployment.
+func AdoptDetached(
+	ctx context.Context,
+	cl client.Client,
+	specNamespacedName types.NamespacedName,
+	wandb *apiv2.WeightsAndBiases,
+) ([]metav1.Condition, bool) {
+	nsnBuilder := createNsNameBuilder(specNamespacedName)
+	labels := BuildWandbKafkaLabels(wandb)
+	var results []metav1.Condition
+	for _, nn := range []types.NamespacedName{nsnBuilder.EtcdNsName(), nsnBuilder.BufstreamNsName()} {
+		results = append(results, common.AdoptDetached(ctx, cl, wandb, nn, ApplicationResourceType, &apiv2.Application{}, labels)...)
+	}
+	return common.AdoptionConditions(results)
+}
+
 func DetachFinalizer(
 	ctx context.Context,
 	cl client.Client,
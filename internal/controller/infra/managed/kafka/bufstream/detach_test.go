@@ -67,3 +67,32 @@ func TestCheckDetachedSkipsWhenOwned(t *testing.T) {
 	conds := CheckDetached(context.Background(), cl, nsn, types.UID("cr-uid"), 5)
 	require.Nil(t, conds)
 }
+
+// Recreating the CR re-adopts the Applications of its own deployment, but
+// never those a different deployment left behind.
+func TestAdoptDetachedChecksDeployment(t *testing.T) {
+	wandb := &apiv2.WeightsAndBiases{ObjectMeta: metav1.ObjectMeta{
+		Name: "wandb", Namespace: "default", UID: types.UID("new-cr-uid"),
+		Annotations: map[string]string{common.DeploymentUIDAnnotation: "deployment-1"},
+	}}
+	app := detachedBufstreamApp(BufstreamReplicas)
+	app.Labels = BuildWandbKafkaLabels(wandb)
+	app.Annotations = map[string]string{common.DeploymentUIDAnnotation: "deployment-1"}
+	cl := fake.NewClientBuilder().WithScheme(testScheme(t)).WithObjects(app).Build()
+	nsn := types.NamespacedName{Namespace: "default", Name: "wandb-kafka"}
+
+	foreign := wandb.DeepCopy()
+	foreign.Annotations[common.DeploymentUIDAnnotation] = "deployment-2"
+	conds, proceed := AdoptDetached(context.Background(), cl, nsn, foreign)
+	require.False(t, proceed)
+	require.Equal(t, common.ForeignResourceReason, conds[len(conds)-1].Reason)
+
+	conds, proceed = AdoptDetached(context.Background(), cl, nsn, wandb)
+	require.True(t, proceed)
+	require.Len(t, conds, 1)
+	require.Equal(t, common.AdoptedReason, conds[0].Reason)
+
+	adopted := &apiv2.Application{}
+	require.NoError(t, cl.Get(context.Background(), nsn, adopted))
+	require.False(t, common.IsDetached(adopted, wandb.UID))
+}
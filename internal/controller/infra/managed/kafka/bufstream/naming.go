@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/wandb/operator/internal/controller/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/validation"
 )
@@ -138,6 +139,16 @@ func (n *NsNameBuilder) EtcdClientEndpoints(replicas, clientPort int) []string {
 	return endpoints
 }
 
+// PodSelector matches the broker and etcd pods. Both run as Applications,
+// whose pods carry the Application name.
+func (n *NsNameBuilder) PodSelector() metav1.LabelSelector {
+	return metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+		Key:      "app.kubernetes.io/name",
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   []string{n.BufstreamName(), n.EtcdName()},
+	}}}
+}
+
 func (n *NsNameBuilder) ConnectionName() string {
 	return fmt.Sprintf("%s-connection", n.SpecName())
 }
@@ -363,7 +363,8 @@ func ToEtcdApplication(
 				{
 					ObjectMeta: metav1.ObjectMeta{Name: EtcdDataVolumeName},
 					Spec: corev1.PersistentVolumeClaimSpec{
-						AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						AccessModes:      []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						StorageClassName: common.StorageClassName(infraSpec.StorageClassName),
 						Resources: corev1.VolumeResourceRequirements{
 							Requests: corev1.ResourceList{corev1.ResourceStorage: quantity},
 						},
@@ -37,6 +37,7 @@ func ComputeStatus(
 	)
 
 	state, events := inferInfraState(ctx, enabled, result.Conditions)
+	events = append(events, common.AdoptionEvents(oldConditions, currentConditions)...)
 	result.State = state
 	result.Ready = !lo.Contains(common.NotReadyStates, result.State)
 
@@ -85,6 +86,7 @@ func inferInfraState(
 	impliedStates := make(map[string]string, len(conditions))
 
 	for _, t := range []string{
+		common.InfraOwnedType,
 		ObjectStoreReadyType,
 		EtcdApplicationType,
 		BufstreamApplicationType,
@@ -132,6 +134,9 @@ func inferStateFromCondition(ctx context.Context, conditionType string, conditio
 	if !found {
 		return common.UnknownState
 	}
+	if conditionType == common.InfraOwnedType {
+		return common.InferInfraOwnedState(cond)
+	}
 
 	result := common.UnknownState
 	if cond.Status == metav1.ConditionTrue {
@@ -0,0 +1,463 @@
+package moco
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	mococonstants "github.com/cybozu-go/moco/pkg/constants"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/internal/controller/infra/objectstore"
+	"github.com/wandb/operator/internal/logx"
+	"github.com/wandb/operator/pkg/utils"
+	"github.com/wandb/operator/pkg/wandb/manifest"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	// MySQLBackupType reports the outcome of the most recent scheduled backup.
+	// It is informational and does not affect the state.
+	MySQLBackupType = "MySQLBackup"
+
+	BackupSucceededReason = "Succeeded"
+	BackupPendingReason   = "Pending"
+	BackupFailedReason    = "Failed"
+	// ObjectStoreUnavailableReason: the backup or restore object store is not
+	// ready or its connection could not be read.
+	ObjectStoreUnavailableReason = "ObjectStoreUnavailable"
+	// UnsupportedObjectStoreReason: the backup or restore object store is not
+	// S3-compatible, the only backend MOCO and the retention job share.
+	UnsupportedObjectStoreReason = "UnsupportedObjectStore"
+
+	// TODO: remove this hardcoded default once all supported manifest versions
+	// supply mysql.<instance>.images.backupRetention.
+	defaultBackupRetentionImage = "amazon/aws-cli:2.35.10"
+	imageKeyBackupRetention     = "backupRetention"
+
+	backupCredsAccessKeyID     = "AWS_ACCESS_KEY_ID"
+	backupCredsSecretAccessKey = "AWS_SECRET_ACCESS_KEY"
+
+	backupRetentionCreatedBy = "wandb-operator"
+	// backupRetentionForAnnotation records on the retention Job the backup
+	// directory it was started for.
+	backupRetentionForAnnotation = "weightsandbiases.apps.wandb.com/backup-directory"
+)
+
+// BackupRetentionImage resolves the image the retention CronJob runs.
+func BackupRetentionImage(img manifest.ImageRef, globalImageRegistry string) string {
+	if out := img.GetImage(globalImageRegistry); out != "" {
+		return out
+	}
+	// Fallback for older manifests that don't supply the image.
+	return defaultBackupRetentionImage
+}
+
+func BackupPolicyName(specName string) string {
+	return specName + "-backup"
+}
+
+func backupServiceAccountName(specName string) string {
+	return specName + "-backup"
+}
+
+func backupCredentialsName(specName string) string {
+	return specName + "-backup-credentials"
+}
+
+func backupRetentionName(specName string) string {
+	return specName + "-backup-retention"
+}
+
+// BackupPrefix is the object key prefix MOCO writes a cluster's dumps under,
+// one "<YYYYMMDD-hhmmss>/" directory per backup.
+func BackupPrefix(namespace, clusterName string) string {
+	return fmt.Sprintf("moco/%s/%s/", namespace, clusterName)
+}
+
+// BackupPodSelector matches the pods of MOCO's backup and restore jobs and of
+// the retention job for the cluster.
+func (n *NsNameBuilder) BackupPodSelector() metav1.LabelSelector {
+	return metav1.LabelSelector{MatchLabels: map[string]string{
+		"app.kubernetes.io/name":     "mysql-backup",
+		"app.kubernetes.io/instance": n.ClusterName(),
+	}}
+}
+
+// BackupResources is the desired state for a cluster's scheduled backups.
+// Credentials is nil when the object store relies on ambient identity and
+// Retention is nil when every backup is kept.
+type BackupResources struct {
+	ServiceAccount *corev1.ServiceAccount
+	Credentials    *corev1.Secret
+	Policy         *mocov1beta2.BackupPolicy
+	// Retention is the template of the Job pruning old backups, which
+	// WriteBackupState starts after each completed backup.
+	Retention *batchv1.Job
+}
+
+// ToMocoBackup renders the BackupPolicy MOCO runs on spec.Backup.Schedule and,
+// when a retention is set, a Job pruning all but the newest backups.
+func ToMocoBackup(
+	spec apiv2.ManagedMysqlSpec,
+	wandb *apiv2.WeightsAndBiases,
+	scheme *runtime.Scheme,
+	storage objectstore.ConnInfo,
+	mfst manifest.Manifest,
+) (*BackupResources, error) {
+	if err := requireS3(storage); err != nil {
+		return nil, err
+	}
+	labels := BuildWandbMysqlLabels(wandb)
+	out := &BackupResources{
+		ServiceAccount: jobServiceAccount(backupServiceAccountName(spec.Name), spec.Namespace, labels),
+		Credentials:    jobCredentials(backupCredentialsName(spec.Name), spec.Namespace, labels, storage),
+	}
+
+	jobConfig := s3JobConfig(out.ServiceAccount, out.Credentials, storage)
+	out.Policy = &mocov1beta2.BackupPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      BackupPolicyName(spec.Name),
+			Namespace: spec.Namespace,
+			Labels:    labels,
+		},
+		Spec: mocov1beta2.BackupPolicySpec{
+			Schedule:                   spec.Backup.Schedule,
+			JobConfig:                  jobConfig,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: ptr.To[int32](1),
+			FailedJobsHistoryLimit:     ptr.To[int32](1),
+		},
+	}
+
+	if spec.Backup.Retention > 0 {
+		out.Retention = backupRetentionJob(spec, wandb, storage, jobConfig.BucketConfig.Region, credentialsEnvFrom(out.Credentials), mfst)
+	}
+
+	if err := setJobOwners(wandb, scheme, out.ServiceAccount, out.Credentials, out.Policy, out.Retention); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// requireS3 rejects object stores MOCO's backup and restore jobs cannot use.
+func requireS3(storage objectstore.ConnInfo) error {
+	if storage.Provider != apiv2.ObjectStoreProviderS3 {
+		return fmt.Errorf("managed MySQL backups need an S3-compatible object store, got %q", storage.Provider)
+	}
+	return nil
+}
+
+func jobServiceAccount(name, namespace string, labels map[string]string) *corev1.ServiceAccount {
+	return &corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+	}
+}
+
+// jobCredentials copies the object store's static keys into a Secret for
+// MOCO's jobs, or returns nil when the store relies on ambient identity.
+func jobCredentials(name, namespace string, labels map[string]string, storage objectstore.ConnInfo) *corev1.Secret {
+	if !storage.HasStaticCredentials() {
+		return nil
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+		Type:       corev1.SecretTypeOpaque,
+		StringData: map[string]string{
+			backupCredsAccessKeyID:     storage.AccessKey,
+			backupCredsSecretAccessKey: storage.SecretKey,
+		},
+	}
+}
+
+func credentialsEnvFrom(credentials *corev1.Secret) []corev1.EnvFromSource {
+	if credentials == nil {
+		return nil
+	}
+	return []corev1.EnvFromSource{{
+		SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: credentials.Name}},
+	}}
+}
+
+// s3JobConfig renders the settings MOCO's backup and restore jobs share.
+// MOCO's CRD defaults size the jobs for large databases (4 CPU, 4Gi); pin
+// something modest so they schedule on ordinary nodes.
+func s3JobConfig(serviceAccount *corev1.ServiceAccount, credentials *corev1.Secret, storage objectstore.ConnInfo) mocov1beta2.JobConfig {
+	region := storage.Region
+	if region == "" {
+		region = objectstore.DefaultRegion
+	}
+	jobConfig := mocov1beta2.JobConfig{
+		ServiceAccountName: serviceAccount.Name,
+		BucketConfig: mocov1beta2.BucketConfig{
+			BucketName:   storage.Bucket,
+			Region:       region,
+			EndpointURL:  storage.EndpointURL(),
+			UsePathStyle: storage.ForcePathStyle,
+			BackendType:  "s3",
+		},
+		WorkVolume: mocov1beta2.VolumeSourceApplyConfiguration(
+			*corev1ac.VolumeSource().WithEmptyDir(corev1ac.EmptyDirVolumeSource()),
+		),
+		Threads: 4,
+		CPU:     ptr.To(resource.MustParse("1")),
+		Memory:  ptr.To(resource.MustParse("2Gi")),
+	}
+	if credentials != nil {
+		jobConfig.EnvFrom = []mocov1beta2.EnvFromSourceApplyConfiguration{mocov1beta2.EnvFromSourceApplyConfiguration(
+			*corev1ac.EnvFromSource().WithSecretRef(corev1ac.SecretEnvSource().WithName(credentials.Name)),
+		)}
+	}
+	return jobConfig
+}
+
+func setJobOwners(wandb *apiv2.WeightsAndBiases, scheme *runtime.Scheme, objs ...client.Object) error {
+	for _, obj := range objs {
+		if common.IsNil(obj) {
+			continue
+		}
+		if err := controllerutil.SetControllerReference(wandb, obj, scheme); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backupRetentionJob renders the retention Job without the backup it runs
+// after; writeBackupRetention adds that.
+func backupRetentionJob(
+	spec apiv2.ManagedMysqlSpec,
+	wandb *apiv2.WeightsAndBiases,
+	storage objectstore.ConnInfo,
+	region string,
+	envFrom []corev1.EnvFromSource,
+	mfst manifest.Manifest,
+) *batchv1.Job {
+	podLabels := map[string]string{
+		"app.kubernetes.io/name":       "mysql-backup",
+		"app.kubernetes.io/instance":   spec.Name,
+		"app.kubernetes.io/created-by": backupRetentionCreatedBy,
+	}
+	securityContext := &corev1.SecurityContext{
+		RunAsNonRoot:             ptr.To(true),
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{mocoMySQLCapabilityAll}},
+		SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+	if !utils.IsOpenShift() {
+		securityContext.RunAsUser = ptr.To(mocoMySQLRunAsUser)
+		securityContext.RunAsGroup = ptr.To(mocoMySQLRunAsGroup)
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      backupRetentionName(spec.Name),
+			Namespace: spec.Namespace,
+			Labels:    BuildWandbMysqlLabels(wandb),
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To[int32](2),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: podLabels},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyOnFailure,
+					ServiceAccountName: backupServiceAccountName(spec.Name),
+					ImagePullSecrets:   wandb.Spec.Global.ImagePullSecrets,
+					Containers: []corev1.Container{{
+						Name:    "prune-backups",
+						Image:   BackupRetentionImage(mfst.Mysql["default"].Images[imageKeyBackupRetention], wandb.Spec.Global.ImageRegistry),
+						Command: []string{"/bin/sh", "-c"},
+						Args: []string{
+							objectstore.PruneBackupsScript, "prune-backups",
+							storage.EndpointURL(), storage.Bucket,
+							BackupPrefix(spec.Namespace, spec.Name),
+							strconv.Itoa(int(spec.Backup.Retention)),
+						},
+						Env: []corev1.EnvVar{
+							{Name: "HOME", Value: "/tmp"},
+							{Name: "AWS_REGION", Value: region},
+						},
+						EnvFrom:         envFrom,
+						SecurityContext: securityContext,
+					}},
+				},
+			},
+		},
+	}
+}
+
+// WriteBackupState applies the desired backup resources, deleting any the
+// desired state no longer carries; a nil desired removes them all. It returns
+// the MySQLBackup condition for the cluster.
+func WriteBackupState(
+	ctx context.Context,
+	cl client.Client,
+	specNamespacedName types.NamespacedName,
+	desired *BackupResources,
+) []metav1.Condition {
+	ctx, _ = logx.WithSlog(ctx, logx.Mysql)
+	log := logx.GetSlog(ctx)
+	name := specNamespacedName.Name
+	if desired == nil {
+		desired = &BackupResources{}
+	}
+
+	ns := specNamespacedName.Namespace
+	err := common.CrudResourceByName(ctx, cl, types.NamespacedName{Namespace: ns, Name: backupServiceAccountName(name)}, "ServiceAccount", desired.ServiceAccount, &corev1.ServiceAccount{})
+	if err == nil {
+		err = common.CrudResourceByName(ctx, cl, types.NamespacedName{Namespace: ns, Name: backupCredentialsName(name)}, "Secret", desired.Credentials, &corev1.Secret{})
+	}
+	if err == nil {
+		err = common.CrudResourceByName(ctx, cl, types.NamespacedName{Namespace: ns, Name: BackupPolicyName(name)}, "BackupPolicy", desired.Policy, &mocov1beta2.BackupPolicy{})
+	}
+	if err == nil {
+		err = writeBackupRetention(ctx, cl, specNamespacedName, desired.Retention)
+	}
+	if err != nil {
+		log.Error("failed to reconcile backup resources", logx.ErrAttr(err))
+		return []metav1.Condition{
+			{Type: common.ReconciledType, Status: metav1.ConditionFalse, Reason: common.ApiErrorReason},
+			{Type: MySQLBackupType, Status: metav1.ConditionUnknown, Reason: common.ApiErrorReason},
+		}
+	}
+
+	if desired.Policy == nil {
+		return nil
+	}
+	return computeMySQLBackupCondition(ctx, cl, createNsNameBuilder(specNamespacedName).ClusterNsName())
+}
+
+// writeBackupRetention starts the retention Job once for each backup MOCO
+// completes. Running only after MOCO has recorded the backup, and keeping that
+// backup and everything newer, means a run that is still uploading or that
+// failed part way can never push the last complete backup out.
+func writeBackupRetention(
+	ctx context.Context,
+	cl client.Client,
+	specNamespacedName types.NamespacedName,
+	desired *batchv1.Job,
+) error {
+	log := logx.GetSlog(ctx)
+	if desired != nil {
+		cluster := &mocov1beta2.MySQLCluster{}
+		found, err := common.GetResource(ctx, cl, createNsNameBuilder(specNamespacedName).ClusterNsName(), ResourceTypeName, cluster)
+		if err != nil {
+			return err
+		}
+		if !found || cluster.Status.Backup.Time.IsZero() {
+			desired = nil
+		} else {
+			backupDir := BackupPrefix(specNamespacedName.Namespace, specNamespacedName.Name) +
+				cluster.Status.Backup.Time.UTC().Format(mococonstants.BackupTimeFormat) + "/"
+			desired = desired.DeepCopy()
+			desired.Annotations = map[string]string{backupRetentionForAnnotation: backupDir}
+			container := &desired.Spec.Template.Spec.Containers[0]
+			container.Args = append(container.Args, backupDir)
+		}
+	}
+
+	actual := &batchv1.Job{}
+	nsName := types.NamespacedName{Namespace: specNamespacedName.Namespace, Name: backupRetentionName(specNamespacedName.Name)}
+	found, err := common.GetResource(ctx, cl, nsName, "Job", actual)
+	if err != nil {
+		return err
+	}
+	if found && (desired == nil || actual.Annotations[backupRetentionForAnnotation] != desired.Annotations[backupRetentionForAnnotation]) {
+		if err := cl.Delete(ctx, actual, client.PropagationPolicy(metav1.DeletePropagationBackground)); client.IgnoreNotFound(err) != nil {
+			return err
+		}
+		found = false
+	}
+	if desired == nil || found {
+		return nil
+	}
+	log.Info("pruning MySQL backups", "after", desired.Annotations[backupRetentionForAnnotation])
+	// A Job still being deleted is replaced on a later reconcile.
+	return client.IgnoreAlreadyExists(cl.Create(ctx, desired))
+}
+
+// computeMySQLBackupCondition reads the outcome of the latest backup from the
+// cluster status MOCO maintains and from its backup CronJob, whose last
+// schedule running past the last success without an active job means the
+// newest run failed.
+func computeMySQLBackupCondition(ctx context.Context, cl client.Client, clusterNsName types.NamespacedName) []metav1.Condition {
+	apiError := []metav1.Condition{{Type: MySQLBackupType, Status: metav1.ConditionUnknown, Reason: common.ApiErrorReason}}
+
+	cluster := &mocov1beta2.MySQLCluster{}
+	found, err := common.GetResource(ctx, cl, clusterNsName, ResourceTypeName, cluster)
+	if err != nil {
+		return apiError
+	}
+	if !found {
+		cluster = &mocov1beta2.MySQLCluster{ObjectMeta: metav1.ObjectMeta{Name: clusterNsName.Name, Namespace: clusterNsName.Namespace}}
+	}
+
+	cronJob := &batchv1.CronJob{}
+	cronFound, err := common.GetResource(
+		ctx, cl, types.NamespacedName{Namespace: clusterNsName.Namespace, Name: cluster.BackupCronJobName()}, "CronJob", cronJob,
+	)
+	if err != nil {
+		return apiError
+	}
+	failed := cronFound && len(cronJob.Status.Active) == 0 && cronJob.Status.LastScheduleTime != nil &&
+		(cronJob.Status.LastSuccessfulTime == nil || cronJob.Status.LastSuccessfulTime.Before(cronJob.Status.LastScheduleTime))
+
+	last := cluster.Status.Backup.Time
+	switch {
+	case failed && last.IsZero():
+		return []metav1.Condition{{
+			Type: MySQLBackupType, Status: metav1.ConditionFalse, Reason: BackupFailedReason,
+			Message: fmt.Sprintf("backup job from %s failed; no backup has succeeded yet", cronJob.Name),
+		}}
+	case failed:
+		return []metav1.Condition{{
+			Type: MySQLBackupType, Status: metav1.ConditionFalse, Reason: BackupFailedReason,
+			Message: fmt.Sprintf("backup job from %s failed; last successful backup at %s", cronJob.Name, last.UTC().Format(time.RFC3339)),
+		}}
+	case last.IsZero():
+		return []metav1.Condition{{
+			Type: MySQLBackupType, Status: metav1.ConditionFalse, Reason: BackupPendingReason,
+			Message: "waiting for the first scheduled backup",
+		}}
+	}
+	msg := fmt.Sprintf("last backup at %s", last.UTC().Format(time.RFC3339))
+	if w := cluster.Status.Backup.Warnings; len(w) > 0 {
+		msg += " with warnings: " + strings.Join(w, "; ")
+	}
+	return []metav1.Condition{{Type: MySQLBackupType, Status: metav1.ConditionTrue, Reason: BackupSucceededReason, Message: msg}}
+}
+
+// RetainBackupPolicy keeps the cluster pointed at an existing BackupPolicy
+// while its object store is temporarily unavailable, so a transient outage
+// does not switch scheduled backups off.
+func RetainBackupPolicy(ctx context.Context, cl client.Client, specNamespacedName types.NamespacedName, cluster *mocov1beta2.MySQLCluster) {
+	name := BackupPolicyName(specNamespacedName.Name)
+	found, err := common.GetResource(
+		ctx, cl, types.NamespacedName{Namespace: specNamespacedName.Namespace, Name: name}, "BackupPolicy", &mocov1beta2.BackupPolicy{},
+	)
+	if err == nil && found {
+		cluster.Spec.BackupPolicyName = ptr.To(name)
+	}
+}
+
+// ReadLastBackupTime returns when MOCO last completed a backup of the
+// cluster, or nil if it has not yet.
+func ReadLastBackupTime(ctx context.Context, cl client.Client, specNamespacedName types.NamespacedName) *metav1.Time {
+	cluster := &mocov1beta2.MySQLCluster{}
+	found, err := common.GetResource(ctx, cl, createNsNameBuilder(specNamespacedName).ClusterNsName(), ResourceTypeName, cluster)
+	if err != nil || !found || cluster.Status.Backup.Time.IsZero() {
+		return nil
+	}
+	return cluster.Status.Backup.Time.DeepCopy()
+}
@@ -0,0 +1,216 @@
+package moco
+
+import (
+	"context"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/infra/objectstore"
+	"github.com/wandb/operator/pkg/utils"
+	"github.com/wandb/operator/pkg/wandb/manifest"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Moco backups", func() {
+	var spec apiv2.ManagedMysqlSpec
+	seaweed := objectstore.ConnInfo{
+		Provider:       apiv2.ObjectStoreProviderS3,
+		Bucket:         "wandb",
+		Endpoint:       "wandb-seaweedfs-s3.wandb.svc.cluster.local",
+		Port:           "8333",
+		AccessKey:      "access",
+		SecretKey:      "secret",
+		ForcePathStyle: true,
+	}
+
+	BeforeEach(func() {
+		utils.SetOpenShiftMode(false)
+		spec = apiv2.ManagedMysqlSpec{
+			Name: "mysql", Namespace: "wandb",
+			Backup: &apiv2.MySQLBackupSpec{Schedule: "0 3 * * *", Retention: 7},
+		}
+	})
+
+	backupScheme := func() *runtime.Scheme {
+		scheme := mocoScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		return scheme
+	}
+
+	It("renders a BackupPolicy, credentials and retention job for an S3-compatible store", func() {
+		backup, err := ToMocoBackup(spec, mocoWandb(), backupScheme(), seaweed, manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+
+		policy := backup.Policy
+		Expect(policy.Name).To(Equal("mysql-backup"))
+		Expect(policy.OwnerReferences).To(HaveLen(1))
+		Expect(policy.Spec.Schedule).To(Equal("0 3 * * *"))
+		Expect(policy.Spec.ConcurrencyPolicy).To(Equal(batchv1.ForbidConcurrent))
+		Expect(policy.Spec.JobConfig.ServiceAccountName).To(Equal(backup.ServiceAccount.Name))
+		Expect(policy.Spec.JobConfig.BucketConfig).To(Equal(mocov1beta2.BucketConfig{
+			BucketName:   "wandb",
+			Region:       objectstore.DefaultRegion,
+			EndpointURL:  "http://wandb-seaweedfs-s3.wandb.svc.cluster.local:8333",
+			UsePathStyle: true,
+			BackendType:  "s3",
+		}))
+		Expect(policy.Spec.JobConfig.WorkVolume.EmptyDir).NotTo(BeNil())
+		Expect(policy.Spec.JobConfig.EnvFrom).To(HaveLen(1))
+		Expect(*policy.Spec.JobConfig.EnvFrom[0].SecretRef.Name).To(Equal(backup.Credentials.Name))
+
+		Expect(backup.Credentials.StringData).To(Equal(map[string]string{
+			"AWS_ACCESS_KEY_ID":     "access",
+			"AWS_SECRET_ACCESS_KEY": "secret",
+		}))
+
+		retention := backup.Retention
+		Expect(retention).NotTo(BeNil())
+		pod := retention.Spec.Template
+		Expect(pod.Labels).To(HaveKeyWithValue("app.kubernetes.io/name", "mysql-backup"))
+		Expect(pod.Labels).To(HaveKeyWithValue("app.kubernetes.io/instance", "mysql"))
+		container := pod.Spec.Containers[0]
+		Expect(container.Image).To(Equal(defaultBackupRetentionImage))
+		Expect(container.Args[1:]).To(Equal([]string{
+			"prune-backups",
+			"http://wandb-seaweedfs-s3.wandb.svc.cluster.local:8333",
+			"wandb",
+			"moco/wandb/mysql/",
+			"7",
+		}))
+		Expect(container.EnvFrom[0].SecretRef.Name).To(Equal(backup.Credentials.Name))
+		Expect(*container.SecurityContext.RunAsNonRoot).To(BeTrue())
+	})
+
+	It("relies on ambient identity and keeps every backup when unconfigured", func() {
+		spec.Backup.Retention = 0
+		s3 := objectstore.ConnInfo{Provider: apiv2.ObjectStoreProviderS3, Bucket: "wandb", Region: "eu-west-1"}
+
+		backup, err := ToMocoBackup(spec, mocoWandb(), backupScheme(), s3, manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(backup.Credentials).To(BeNil())
+		Expect(backup.Retention).To(BeNil())
+		Expect(backup.Policy.Spec.JobConfig.EnvFrom).To(BeEmpty())
+		Expect(backup.Policy.Spec.JobConfig.BucketConfig.Region).To(Equal("eu-west-1"))
+		Expect(backup.Policy.Spec.JobConfig.BucketConfig.EndpointURL).To(BeEmpty())
+	})
+
+	It("rejects object stores MOCO cannot write to", func() {
+		_, err := ToMocoBackup(spec, mocoWandb(), backupScheme(), objectstore.ConnInfo{Provider: apiv2.ObjectStoreProviderAzure}, manifest.Manifest{})
+		Expect(err).To(MatchError(ContainSubstring("S3-compatible")))
+	})
+
+	It("creates the backup resources and waits for the first backup", func() {
+		backup, err := ToMocoBackup(spec, mocoWandb(), backupScheme(), seaweed, manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		c := fake.NewClientBuilder().WithScheme(backupScheme()).Build()
+
+		conditions := WriteBackupState(context.Background(), c, types.NamespacedName{Name: "mysql", Namespace: "wandb"}, backup)
+		Expect(conditions).To(HaveLen(1))
+		Expect(conditions[0].Reason).To(Equal(BackupPendingReason))
+		Expect(c.Get(context.Background(), client.ObjectKeyFromObject(backup.Policy), &mocov1beta2.BackupPolicy{})).To(Succeed())
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(backup.Retention), &batchv1.Job{})
+		Expect(apierrors.IsNotFound(err)).To(BeTrue(), "nothing is pruned before a backup completes")
+	})
+
+	It("prunes once after each completed backup, keeping it and anything newer", func() {
+		backup, err := ToMocoBackup(spec, mocoWandb(), backupScheme(), seaweed, manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		cluster := &mocov1beta2.MySQLCluster{ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "wandb"}}
+		cluster.Status.Backup.Time = metav1.NewTime(time.Date(2026, 10, 1, 3, 0, 5, 0, time.UTC))
+		c := fake.NewClientBuilder().WithScheme(backupScheme()).WithObjects(cluster).WithStatusSubresource(cluster).Build()
+		nn := types.NamespacedName{Name: "mysql", Namespace: "wandb"}
+		retentionJob := func() *batchv1.Job {
+			job := &batchv1.Job{}
+			Expect(c.Get(context.Background(), client.ObjectKeyFromObject(backup.Retention), job)).To(Succeed())
+			return job
+		}
+
+		WriteBackupState(context.Background(), c, nn, backup)
+		job := retentionJob()
+		Expect(job.Spec.Template.Spec.Containers[0].Args[1:]).To(Equal([]string{
+			"prune-backups",
+			"http://wandb-seaweedfs-s3.wandb.svc.cluster.local:8333",
+			"wandb",
+			"moco/wandb/mysql/",
+			"7",
+			"moco/wandb/mysql/20261001-030005/",
+		}))
+
+		// Reconciling again leaves the finished Job alone.
+		WriteBackupState(context.Background(), c, nn, backup)
+		Expect(retentionJob().UID).To(Equal(job.UID))
+
+		cluster.Status.Backup.Time = metav1.NewTime(cluster.Status.Backup.Time.Add(24 * time.Hour))
+		Expect(c.Status().Update(context.Background(), cluster)).To(Succeed())
+		WriteBackupState(context.Background(), c, nn, backup)
+		Expect(retentionJob().Spec.Template.Spec.Containers[0].Args).To(HaveLen(7))
+		Expect(retentionJob().Spec.Template.Spec.Containers[0].Args[6]).To(Equal("moco/wandb/mysql/20261002-030005/"))
+	})
+
+	It("removes the backup resources once backups are switched off", func() {
+		backup, err := ToMocoBackup(spec, mocoWandb(), backupScheme(), seaweed, manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		c := fake.NewClientBuilder().WithScheme(backupScheme()).
+			WithObjects(backup.ServiceAccount, backup.Credentials, backup.Policy, backup.Retention).Build()
+
+		Expect(WriteBackupState(context.Background(), c, types.NamespacedName{Name: "mysql", Namespace: "wandb"}, nil)).To(BeEmpty())
+		for _, obj := range []client.Object{backup.ServiceAccount, backup.Credentials, backup.Policy, backup.Retention} {
+			err := c.Get(context.Background(), client.ObjectKeyFromObject(obj), obj.DeepCopyObject().(client.Object))
+			Expect(apierrors.IsNotFound(err)).To(BeTrue(), "%T %s", obj, obj.GetName())
+		}
+	})
+
+	Describe("MySQLBackup condition", func() {
+		nn := types.NamespacedName{Name: "mysql", Namespace: "wandb"}
+		lastBackup := metav1.NewTime(time.Date(2026, 10, 1, 3, 0, 5, 0, time.UTC))
+
+		condition := func(backupTime metav1.Time, cron *batchv1.CronJob) metav1.Condition {
+			cluster := &mocov1beta2.MySQLCluster{ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "wandb"}}
+			cluster.Status.Backup.Time = backupTime
+			builder := fake.NewClientBuilder().WithScheme(backupScheme()).WithObjects(cluster)
+			if cron != nil {
+				builder = builder.WithObjects(cron)
+			}
+			conditions := computeMySQLBackupCondition(context.Background(), builder.Build(), nn)
+			Expect(conditions).To(HaveLen(1))
+			Expect(conditions[0].Type).To(Equal(MySQLBackupType))
+			return conditions[0]
+		}
+		backupCronJob := func(lastSchedule, lastSuccess *metav1.Time) *batchv1.CronJob {
+			return &batchv1.CronJob{
+				ObjectMeta: metav1.ObjectMeta{Name: "moco-backup-mysql", Namespace: "wandb"},
+				Status:     batchv1.CronJobStatus{LastScheduleTime: lastSchedule, LastSuccessfulTime: lastSuccess},
+			}
+		}
+
+		It("is pending before the first backup", func() {
+			cond := condition(metav1.Time{}, nil)
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(BackupPendingReason))
+		})
+
+		It("reports the last successful backup", func() {
+			cond := condition(lastBackup, backupCronJob(&lastBackup, &lastBackup))
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal(BackupSucceededReason))
+			Expect(cond.Message).To(ContainSubstring("2026-10-01T03:00:05Z"))
+		})
+
+		It("fails when the newest scheduled run did not succeed", func() {
+			nextDay := metav1.NewTime(lastBackup.Add(24 * time.Hour))
+			cond := condition(lastBackup, backupCronJob(&nextDay, &lastBackup))
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(BackupFailedReason))
+			Expect(cond.Message).To(ContainSubstring("last successful backup at 2026-10-01T03:00:05Z"))
+		})
+	})
+})
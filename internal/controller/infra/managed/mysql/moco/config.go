@@ -0,0 +1,195 @@
+package moco
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	mococonstants "github.com/cybozu-go/moco/pkg/constants"
+	apiv2 "github.com/wandb/operator/api/v2"
+	ctrlcommon "github.com/wandb/operator/internal/controller/common"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	configAppliedReason = "Applied"
+	configPendingReason = "Pending"
+)
+
+// MysqldSettings renders a MysqldConfig as my.cnf [mysqld] options. Option
+// names are written the way MOCO normalizes them, and typed fields override
+// Settings naming the same option. Settings naming one option twice are
+// rejected by the webhook; should any get through, the name sorting last wins.
+func MysqldSettings(cfg *apiv2.MysqldConfig) map[string]string {
+	if cfg == nil {
+		return nil
+	}
+	out := make(map[string]string, len(cfg.Settings)+5)
+	set := func(name, value string) {
+		for k := range out {
+			if apiv2.NormalizeMysqldSetting(k) == apiv2.NormalizeMysqldSetting(name) {
+				delete(out, k)
+			}
+		}
+		out[name] = value
+	}
+	for _, k := range slices.Sorted(maps.Keys(cfg.Settings)) {
+		set(mycnfKey(k), cfg.Settings[k])
+	}
+	if cfg.InnodbBufferPoolSize != nil {
+		set("innodb_buffer_pool_size", strconv.FormatInt(cfg.InnodbBufferPoolSize.Value(), 10))
+	}
+	if cfg.MaxConnections != nil {
+		set("max_connections", strconv.Itoa(int(*cfg.MaxConnections)))
+	}
+	if cfg.SlowQueryLog != nil {
+		set("slow_query_log", onOff(*cfg.SlowQueryLog))
+	}
+	if cfg.LongQueryTime != "" {
+		set("long_query_time", cfg.LongQueryTime)
+	}
+	if cfg.BinlogExpireLogsSeconds != nil {
+		set("binlog_expire_logs_seconds", strconv.FormatInt(*cfg.BinlogExpireLogsSeconds, 10))
+	}
+	return out
+}
+
+func mycnfKey(name string) string {
+	return strings.ReplaceAll(strings.TrimSpace(name), "-", "_")
+}
+
+func onOff(b bool) string {
+	if b {
+		return "ON"
+	}
+	return "OFF"
+}
+
+// computeMySQLConfigAppliedCondition reports whether the settings in the
+// operator's my.cnf ConfigMap are live: MOCO has rendered them into the
+// ConfigMap its StatefulSet mounts, and the resulting rollout has finished.
+func computeMySQLConfigAppliedCondition(
+	ctx context.Context,
+	c client.Client,
+	actual *mocov1beta2.MySQLCluster,
+) []metav1.Condition {
+	pending := func(format string, args ...any) []metav1.Condition {
+		return []metav1.Condition{{
+			Type:    MySQLConfigAppliedType,
+			Status:  metav1.ConditionFalse,
+			Reason:  configPendingReason,
+			Message: fmt.Sprintf(format, args...),
+		}}
+	}
+	apiError := []metav1.Condition{{
+		Type:   MySQLConfigAppliedType,
+		Status: metav1.ConditionUnknown,
+		Reason: ctrlcommon.ApiErrorReason,
+	}}
+
+	userConf := &corev1.ConfigMap{}
+	userConfName := ptr.Deref(actual.Spec.MySQLConfigMapName, "")
+	if userConfName == "" {
+		return nil
+	}
+	found, err := ctrlcommon.GetResource(
+		ctx, c, types.NamespacedName{Name: userConfName, Namespace: actual.Namespace}, "ConfigMap", userConf,
+	)
+	if err != nil {
+		return apiError
+	}
+	if !found {
+		return pending("waiting for ConfigMap %s", userConfName)
+	}
+
+	sts := &appsv1.StatefulSet{}
+	found, err = ctrlcommon.GetResource(ctx, c, types.NamespacedName{Name: actual.PrefixedName(), Namespace: actual.Namespace}, "StatefulSet", sts)
+	if err != nil {
+		return apiError
+	}
+	if !found {
+		return pending("waiting for StatefulSet %s", actual.PrefixedName())
+	}
+
+	renderedName := ""
+	for _, volume := range sts.Spec.Template.Spec.Volumes {
+		if volume.Name == mococonstants.MySQLConfVolumeName && volume.ConfigMap != nil {
+			renderedName = volume.ConfigMap.Name
+		}
+	}
+	if renderedName == "" {
+		return pending("StatefulSet %s mounts no my.cnf", sts.Name)
+	}
+	rendered := &corev1.ConfigMap{}
+	found, err = ctrlcommon.GetResource(ctx, c, types.NamespacedName{Name: renderedName, Namespace: actual.Namespace}, "ConfigMap", rendered)
+	if err != nil {
+		return apiError
+	}
+	if !found {
+		return pending("waiting for ConfigMap %s", renderedName)
+	}
+
+	live := parseMysqldSection(rendered.Data[mococonstants.MySQLConfName])
+	var stale []string
+	for k, v := range userConf.Data {
+		if live[mycnfKey(k)] != v {
+			stale = append(stale, mycnfKey(k))
+		}
+	}
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		return pending("my.cnf does not yet carry %s", strings.Join(stale, ", "))
+	}
+
+	if !statefulSetRolledOut(sts) {
+		return pending("rolling out my.cnf %s", renderedName)
+	}
+	return []metav1.Condition{{
+		Type:    MySQLConfigAppliedType,
+		Status:  metav1.ConditionTrue,
+		Reason:  configAppliedReason,
+		Message: "mysqld is running with my.cnf " + renderedName,
+	}}
+}
+
+// parseMysqldSection returns the options of the [mysqld] section of a my.cnf
+// rendered by MOCO, which writes one "key = value" per line.
+func parseMysqldSection(mycnf string) map[string]string {
+	out := map[string]string{}
+	section := ""
+	scanner := bufio.NewScanner(strings.NewReader(mycnf))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.Trim(line, "[]")
+			continue
+		}
+		if section != "mysqld" {
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok {
+			out[strings.TrimSpace(k)] = strings.TrimSpace(v)
+		}
+	}
+	return out
+}
+
+func statefulSetRolledOut(sts *appsv1.StatefulSet) bool {
+	replicas := ptr.Deref(sts.Spec.Replicas, 1)
+	return sts.Status.ObservedGeneration >= sts.Generation &&
+		sts.Status.UpdateRevision != "" &&
+		sts.Status.CurrentRevision == sts.Status.UpdateRevision &&
+		sts.Status.UpdatedReplicas == replicas &&
+		sts.Status.ReadyReplicas == replicas
+}
@@ -0,0 +1,154 @@
+package moco
+
+import (
+	"context"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	mococonstants "github.com/cybozu-go/moco/pkg/constants"
+	"github.com/cybozu-go/moco/pkg/mycnf"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/pkg/utils"
+	"github.com/wandb/operator/pkg/wandb/manifest"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Moco mysqld settings", func() {
+	BeforeEach(func() {
+		utils.SetOpenShiftMode(false)
+	})
+
+	It("protects every option MOCO forces into my.cnf", func() {
+		for name := range mycnf.ConstMycnf["mysqld"] {
+			Expect(apiv2.MysqldSettingProtected(name)).To(BeTrue(), name)
+		}
+	})
+
+	It("renders settings into the my.cnf ConfigMap with typed fields winning", func() {
+		spec := apiv2.ManagedMysqlSpec{Name: "mysql", Namespace: "wandb", Replicas: 1, StorageSize: "10Gi"}
+		spec.Config.Mysqld = &apiv2.MysqldConfig{
+			InnodbBufferPoolSize:    ptr.To(resource.MustParse("1Gi")),
+			MaxConnections:          ptr.To[int32](2000),
+			SlowQueryLog:            ptr.To(false),
+			LongQueryTime:           "0.5",
+			BinlogExpireLogsSeconds: ptr.To[int64](86400),
+			Settings: map[string]string{
+				"max-connections":  "500",
+				"table-open-cache": "8000",
+				"sync_binlog":      "0",
+			},
+		}
+
+		_, confMap, err := ToMocoMySQLClusterSpec(context.Background(), spec, mocoWandb(), mocoScheme(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(confMap.Data).To(Equal(map[string]string{
+			"innodb_buffer_pool_size":        "1073741824",
+			"max_connections":                "2000",
+			"slow_query_log":                 "OFF",
+			"long_query_time":                "0.5",
+			"binlog_expire_logs_seconds":     "86400",
+			"table_open_cache":               "8000",
+			"sync_binlog":                    "0",
+			"innodb_flush_log_at_trx_commit": "1",
+		}))
+	})
+
+	It("resolves settings naming the same option twice by name order", func() {
+		cfg := &apiv2.MysqldConfig{Settings: map[string]string{
+			"Max_Connections": "100",
+			"max-connections": "200",
+			"max_connections": "300",
+		}}
+		for range 10 {
+			Expect(MysqldSettings(cfg)).To(Equal(map[string]string{"max_connections": "300"}))
+		}
+	})
+
+	Describe("ConfigApplied", func() {
+		var (
+			cluster  *mocov1beta2.MySQLCluster
+			userConf *corev1.ConfigMap
+		)
+
+		BeforeEach(func() {
+			cluster = &mocov1beta2.MySQLCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "wandb"},
+				Spec:       mocov1beta2.MySQLClusterSpec{Replicas: 3, MySQLConfigMapName: ptr.To(MyCnfConfigMapName("mysql"))},
+			}
+			userConf = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: MyCnfConfigMapName("mysql"), Namespace: "wandb"},
+				Data:       map[string]string{"max_connections": "2000", "sync_binlog": "1"},
+			}
+		})
+
+		rendered := func(name string, conf map[string]string) *corev1.ConfigMap {
+			return &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "wandb"},
+				Data:       map[string]string{mococonstants.MySQLConfName: mycnf.Generate(conf, 4<<30)},
+			}
+		}
+		statefulSet := func(confName string, rolledOut bool) *appsv1.StatefulSet {
+			sts := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "moco-mysql", Namespace: "wandb"},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas: ptr.To[int32](3),
+					Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Volumes: []corev1.Volume{{
+						Name: mococonstants.MySQLConfVolumeName,
+						VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: confName},
+						}},
+					}}}},
+				},
+				Status: appsv1.StatefulSetStatus{
+					Replicas: 3, ReadyReplicas: 3, UpdatedReplicas: 3,
+					CurrentRevision: "moco-mysql-1", UpdateRevision: "moco-mysql-1",
+				},
+			}
+			if !rolledOut {
+				sts.Status.UpdatedReplicas = 1
+				sts.Status.UpdateRevision = "moco-mysql-2"
+			}
+			return sts
+		}
+		conditionFor := func(objs ...*corev1.ConfigMap) func(*appsv1.StatefulSet) metav1.Condition {
+			return func(sts *appsv1.StatefulSet) metav1.Condition {
+				scheme := mocoScheme()
+				Expect(appsv1.AddToScheme(scheme)).To(Succeed())
+				builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(userConf, sts)
+				for _, o := range objs {
+					builder = builder.WithObjects(o)
+				}
+				conditions := computeMySQLConfigAppliedCondition(context.Background(), builder.Build(), cluster)
+				Expect(conditions).To(HaveLen(1))
+				Expect(conditions[0].Type).To(Equal(MySQLConfigAppliedType))
+				return conditions[0]
+			}
+		}
+
+		It("is true once the StatefulSet runs a my.cnf carrying every setting", func() {
+			cond := conditionFor(rendered("moco-mysql.new", userConf.Data))(statefulSet("moco-mysql.new", true))
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal(configAppliedReason))
+		})
+
+		It("is pending while MOCO has not rendered a changed setting", func() {
+			stale := rendered("moco-mysql.old", map[string]string{"max_connections": "100", "sync_binlog": "1"})
+			cond := conditionFor(stale)(statefulSet("moco-mysql.old", true))
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Message).To(ContainSubstring("max_connections"))
+		})
+
+		It("is pending while the pods roll onto the new my.cnf", func() {
+			cond := conditionFor(rendered("moco-mysql.new", userConf.Data))(statefulSet("moco-mysql.new", false))
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Message).To(ContainSubstring("rolling out"))
+		})
+	})
+})
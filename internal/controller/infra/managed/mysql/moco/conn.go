@@ -21,11 +21,18 @@ type mysqlConnInfo struct {
 	User     string
 	Password string
 	Database string
+	// SslCa is the CA of a TLS-served cluster; empty when it serves plaintext.
+	SslCa string
 }
 
 func (c *mysqlConnInfo) toURL() string {
 	password := url.QueryEscape(c.Password)
-	return fmt.Sprintf("mysql://%s:%s@%s:%s/%s", c.User, password, c.Host, c.Port, c.Database)
+	dbURL := fmt.Sprintf("mysql://%s:%s@%s:%s/%s", c.User, password, c.Host, c.Port, c.Database)
+	if c.SslCa != "" {
+		values := url.Values{"tls": []string{"custom"}, "ssl-ca": []string{clientCACertPath}}
+		dbURL += "?" + values.Encode()
+	}
+	return dbURL
 }
 
 func writeMySQLConnInfo(
@@ -86,18 +93,27 @@ func writeMySQLConnInfo(
 			"Password": connInfo.Password,
 		},
 	}
+	if connInfo.SslCa != "" {
+		desired.StringData["Tls"] = "custom"
+		desired.StringData["SslCa"] = connInfo.SslCa
+	}
 
 	if _, err = common.CrudResource(ctx, client, desired, actual); err != nil {
 		return nil, err
 	}
 
 	localRef := corev1.LocalObjectReference{Name: nsName.Name}
-	return &apiv2.MysqlConnection{
+	connection := &apiv2.MysqlConnection{
 		URL:      corev1.SecretKeySelector{LocalObjectReference: localRef, Key: urlKey, Optional: ptr.To(false)},
 		Host:     corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Host", Optional: ptr.To(false)},
 		Port:     corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Port", Optional: ptr.To(false)},
 		Database: corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Database", Optional: ptr.To(false)},
 		Username: corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Username", Optional: ptr.To(false)},
 		Password: corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Password", Optional: ptr.To(false)},
-	}, nil
+	}
+	if connInfo.SslCa != "" {
+		connection.Tls = corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Tls", Optional: ptr.To(true)}
+		connection.SslCa = corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "SslCa", Optional: ptr.To(true)}
+	}
+	return connection, nil
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	apiv2 "github.com/wandb/operator/api/v2"
 	"github.com/wandb/operator/internal/controller/common"
 	"github.com/wandb/operator/internal/logx"
 	corev1 "k8s.io/api/core/v1"
@@ -44,6 +45,21 @@ func CheckDetached(
 	return nil
 }
 
+// AdoptDetached adopts the MySQLCluster a previous CR of this deployment
+// detached. It returns proceed=false when the cluster belongs to another
+// deployment.
+func AdoptDetached(
+	ctx context.Context,
+	cl client.Client,
+	specNamespacedName types.NamespacedName,
+	wandb *apiv2.WeightsAndBiases,
+) ([]metav1.Condition, bool) {
+	nsnBuilder := createNsNameBuilder(specNamespacedName)
+	return common.AdoptionConditions(common.AdoptDetached(
+		ctx, cl, wandb, nsnBuilder.ClusterNsName(), ResourceTypeName, &mocov1beta2.MySQLCluster{}, BuildWandbMysqlLabels(wandb),
+	))
+}
+
 func DetachFinalizer(
 	ctx context.Context,
 	cl client.Client,
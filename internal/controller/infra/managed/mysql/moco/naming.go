@@ -3,7 +3,9 @@ package moco
 import (
 	"fmt"
 
+	mococonstants "github.com/cybozu-go/moco/pkg/constants"
 	"github.com/wandb/operator/internal/controller/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -47,6 +49,15 @@ func (n *NsNameBuilder) ClusterNsName() types.NamespacedName {
 	}
 }
 
+// PodSelector matches the MySQLCluster's pods by the labels Moco stamps on them.
+func (n *NsNameBuilder) PodSelector() metav1.LabelSelector {
+	return metav1.LabelSelector{MatchLabels: map[string]string{
+		mococonstants.LabelAppName:      mococonstants.AppNameMySQL,
+		mococonstants.LabelAppInstance:  n.ClusterName(),
+		mococonstants.LabelAppCreatedBy: mococonstants.AppCreator,
+	}}
+}
+
 func (n *NsNameBuilder) ConnectionName() string {
 	return fmt.Sprintf("%s-connection", n.SpecName())
 }
@@ -27,13 +27,22 @@ func readConnectionDetails(ctx context.Context, c client.Client, actual *mocov1b
 	if pw == "" {
 		return nil
 	}
-	return &mysqlConnInfo{
+	connInfo := &mysqlConnInfo{
 		Host:     fmt.Sprintf("moco-%s-primary.%s.svc.cluster.local", actual.Name, actual.Namespace),
 		Port:     "3306",
 		User:     "moco-writable",
 		Database: "wandb_local",
 		Password: pw,
 	}
+	if tlsSecret := readServerTLSSecret(actual); tlsSecret != "" {
+		ca, err := ctrlcommon.ReadInfraTLSCA(ctx, c, types.NamespacedName{Name: tlsSecret, Namespace: nn.Namespace})
+		if err != nil || ca == "" {
+			log.Error("Failed to read MySQL server CA", logx.ErrAttr(err), "Secret", tlsSecret)
+			return nil
+		}
+		connInfo.SslCa = ca
+	}
+	return connInfo
 }
 
 func ReadState(
@@ -131,6 +140,8 @@ func ReadState(
 		}
 
 		conditions = append(conditions, computeMySQLReportedReadyCondition(ctx, actual)...)
+		conditions = append(conditions, computeMySQLConfigAppliedCondition(ctx, k8sClient, actual)...)
+		conditions = append(conditions, computeMySQLRestoreCondition(ctx, k8sClient, actual)...)
 	}
 
 	return conditions, connection
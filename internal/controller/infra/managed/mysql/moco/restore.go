@@ -0,0 +1,181 @@
+package moco
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/internal/controller/infra/objectstore"
+	"github.com/wandb/operator/internal/logx"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// MySQLRestoreType reports a restore requested through spec.restore. The
+	// instance stays pending until it is restored, which holds back the
+	// database init job and migrations.
+	MySQLRestoreType = "MySQLRestore"
+
+	RestoreRestoringReason = "Restoring"
+	RestoreRestoredReason  = "Restored"
+	RestoreFailedReason    = "Failed"
+	// RestoreSkippedReason: the cluster already existed, and MOCO only
+	// restores while creating one.
+	RestoreSkippedReason = "Skipped"
+)
+
+var backupPrefixPattern = regexp.MustCompile(`^moco/([a-z0-9]([-a-z0-9]*[a-z0-9])?)/([a-z0-9]([-a-z0-9]*[a-z0-9])?)/?$`)
+
+// ParseBackupPrefix splits a moco/<namespace>/<name>/ backup prefix into the
+// source cluster MOCO's restore job looks up.
+func ParseBackupPrefix(prefix string) (namespace, name string, ok bool) {
+	m := backupPrefixPattern.FindStringSubmatch(prefix)
+	if m == nil {
+		return "", "", false
+	}
+	return m[1], m[3], true
+}
+
+func restoreServiceAccountName(specName string) string {
+	return specName + "-restore"
+}
+
+func restoreCredentialsName(specName string) string {
+	return specName + "-restore-credentials"
+}
+
+// RestoreResources is the desired state for restoring a new cluster.
+// Credentials is nil when the object store relies on ambient identity.
+type RestoreResources struct {
+	ServiceAccount *corev1.ServiceAccount
+	Credentials    *corev1.Secret
+	Restore        *mocov1beta2.RestoreSpec
+}
+
+// ToMocoRestore renders the MOCO restore spec for spec.Restore. now stands in
+// for an unset point in time; MOCO fixes the spec once the cluster exists.
+func ToMocoRestore(
+	spec apiv2.ManagedMysqlSpec,
+	wandb *apiv2.WeightsAndBiases,
+	scheme *runtime.Scheme,
+	storage objectstore.ConnInfo,
+	now time.Time,
+) (*RestoreResources, error) {
+	if err := requireS3(storage); err != nil {
+		return nil, err
+	}
+	sourceNamespace, sourceName, ok := ParseBackupPrefix(spec.Restore.Prefix)
+	if !ok {
+		return nil, fmt.Errorf("restore prefix %q is not of the form moco/<namespace>/<name>/", spec.Restore.Prefix)
+	}
+	labels := BuildWandbMysqlLabels(wandb)
+	out := &RestoreResources{
+		ServiceAccount: jobServiceAccount(restoreServiceAccountName(spec.Name), spec.Namespace, labels),
+		Credentials:    jobCredentials(restoreCredentialsName(spec.Name), spec.Namespace, labels, storage),
+	}
+	restorePoint := metav1.NewTime(now.UTC().Truncate(time.Second))
+	if spec.Restore.PointInTime != nil {
+		restorePoint = *spec.Restore.PointInTime
+	}
+	out.Restore = &mocov1beta2.RestoreSpec{
+		SourceName:      sourceName,
+		SourceNamespace: sourceNamespace,
+		RestorePoint:    restorePoint,
+		JobConfig:       s3JobConfig(out.ServiceAccount, out.Credentials, storage),
+	}
+	if err := setJobOwners(wandb, scheme, out.ServiceAccount, out.Credentials); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WriteRestoreState applies the service account and credentials the restore
+// job runs with; a nil desired removes them.
+func WriteRestoreState(
+	ctx context.Context,
+	cl client.Client,
+	specNamespacedName types.NamespacedName,
+	desired *RestoreResources,
+) []metav1.Condition {
+	ctx, _ = logx.WithSlog(ctx, logx.Mysql)
+	log := logx.GetSlog(ctx)
+	if desired == nil {
+		desired = &RestoreResources{}
+	}
+	ns := specNamespacedName.Namespace
+	saName := restoreServiceAccountName(specNamespacedName.Name)
+	credsName := restoreCredentialsName(specNamespacedName.Name)
+
+	err := common.CrudResourceByName(ctx, cl, types.NamespacedName{Namespace: ns, Name: saName}, "ServiceAccount", desired.ServiceAccount, &corev1.ServiceAccount{})
+	if err == nil {
+		err = common.CrudResourceByName(ctx, cl, types.NamespacedName{Namespace: ns, Name: credsName}, "Secret", desired.Credentials, &corev1.Secret{})
+	}
+	if err != nil {
+		log.Error("failed to reconcile restore resources", logx.ErrAttr(err))
+		return []metav1.Condition{
+			{Type: common.ReconciledType, Status: metav1.ConditionFalse, Reason: common.ApiErrorReason},
+			{Type: MySQLRestoreType, Status: metav1.ConditionUnknown, Reason: common.ApiErrorReason},
+		}
+	}
+	return nil
+}
+
+// ClusterExists reports whether the instance's MySQLCluster has been created.
+func ClusterExists(ctx context.Context, cl client.Client, specNamespacedName types.NamespacedName) (bool, error) {
+	return common.GetResource(
+		ctx, cl, createNsNameBuilder(specNamespacedName).ClusterNsName(), ResourceTypeName, &mocov1beta2.MySQLCluster{},
+	)
+}
+
+// computeMySQLRestoreCondition follows the restore MOCO runs for a cluster
+// created with spec.restore: RestoredTime marks success, and MOCO's restore
+// job, which it never retries, carries any failure.
+func computeMySQLRestoreCondition(ctx context.Context, c client.Client, actual *mocov1beta2.MySQLCluster) []metav1.Condition {
+	restore := actual.Spec.Restore
+	if restore == nil {
+		return nil
+	}
+	source := BackupPrefix(restore.SourceNamespace, restore.SourceName)
+	if restored := actual.Status.RestoredTime; restored != nil {
+		return []metav1.Condition{{
+			Type:   MySQLRestoreType,
+			Status: metav1.ConditionTrue,
+			Reason: RestoreRestoredReason,
+			Message: fmt.Sprintf("restored %s to %s at %s",
+				source, restore.RestorePoint.UTC().Format(time.RFC3339), restored.UTC().Format(time.RFC3339)),
+		}}
+	}
+
+	job := &batchv1.Job{}
+	found, err := common.GetResource(ctx, c, types.NamespacedName{Namespace: actual.Namespace, Name: actual.RestoreJobName()}, "Job", job)
+	if err != nil {
+		return []metav1.Condition{{Type: MySQLRestoreType, Status: metav1.ConditionUnknown, Reason: common.ApiErrorReason}}
+	}
+	if found {
+		for _, cond := range job.Status.Conditions {
+			if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+				return []metav1.Condition{{
+					Type:    MySQLRestoreType,
+					Status:  metav1.ConditionFalse,
+					Reason:  RestoreFailedReason,
+					Message: fmt.Sprintf("restore job %s failed: %s", job.Name, cond.Message),
+				}}
+			}
+		}
+	}
+	return []metav1.Condition{{
+		Type:    MySQLRestoreType,
+		Status:  metav1.ConditionFalse,
+		Reason:  RestoreRestoringReason,
+		Message: fmt.Sprintf("restoring %s to %s", source, restore.RestorePoint.UTC().Format(time.RFC3339)),
+	}}
+}
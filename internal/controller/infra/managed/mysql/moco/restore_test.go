@@ -0,0 +1,167 @@
+package moco
+
+import (
+	"context"
+	"time"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/samber/lo"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/internal/controller/infra/objectstore"
+	"github.com/wandb/operator/pkg/wandb/manifest"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("Moco restores", func() {
+	var spec apiv2.ManagedMysqlSpec
+	seaweed := objectstore.ConnInfo{
+		Provider:       apiv2.ObjectStoreProviderS3,
+		Bucket:         "wandb",
+		Endpoint:       "wandb-seaweedfs-s3.wandb.svc.cluster.local",
+		Port:           "8333",
+		AccessKey:      "access",
+		SecretKey:      "secret",
+		ForcePathStyle: true,
+	}
+	now := time.Date(2026, 10, 2, 12, 30, 15, 500, time.UTC)
+
+	BeforeEach(func() {
+		spec = apiv2.ManagedMysqlSpec{
+			Name: "mysql", Namespace: "wandb", Replicas: 1, StorageSize: "10Gi",
+			Restore: &apiv2.MySQLRestoreSpec{Prefix: "moco/prod/wandb-mysql/"},
+		}
+	})
+
+	restoreScheme := func() *runtime.Scheme {
+		scheme := mocoScheme()
+		Expect(batchv1.AddToScheme(scheme)).To(Succeed())
+		return scheme
+	}
+
+	It("restores the source cluster named by the prefix up to now", func() {
+		restore, err := ToMocoRestore(spec, mocoWandb(), restoreScheme(), seaweed, now)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(restore.Restore.SourceNamespace).To(Equal("prod"))
+		Expect(restore.Restore.SourceName).To(Equal("wandb-mysql"))
+		Expect(restore.Restore.RestorePoint.Time).To(Equal(now.Truncate(time.Second)))
+		Expect(restore.Restore.JobConfig.ServiceAccountName).To(Equal("mysql-restore"))
+		Expect(restore.Restore.JobConfig.BucketConfig.BucketName).To(Equal("wandb"))
+		Expect(*restore.Restore.JobConfig.EnvFrom[0].SecretRef.Name).To(Equal("mysql-restore-credentials"))
+		Expect(restore.Credentials.OwnerReferences).To(HaveLen(1))
+	})
+
+	It("restores to the requested point in time", func() {
+		pit := metav1.NewTime(time.Date(2026, 10, 1, 14, 0, 0, 0, time.UTC))
+		spec.Restore.PointInTime = &pit
+
+		restore, err := ToMocoRestore(spec, mocoWandb(), restoreScheme(), seaweed, now)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restore.Restore.RestorePoint).To(Equal(pit))
+	})
+
+	It("rejects prefixes that do not name a MOCO backup location", func() {
+		spec.Restore.Prefix = "backups/wandb-mysql/"
+		_, err := ToMocoRestore(spec, mocoWandb(), restoreScheme(), seaweed, now)
+		Expect(err).To(MatchError(ContainSubstring("moco/<namespace>/<name>/")))
+	})
+
+	It("leaves an existing cluster unrestored", func() {
+		ctx := context.Background()
+		nn := types.NamespacedName{Name: "mysql", Namespace: "wandb"}
+		existing := &mocov1beta2.MySQLCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "wandb"},
+			Spec:       mocov1beta2.MySQLClusterSpec{Replicas: 1},
+		}
+		cl := fake.NewClientBuilder().WithScheme(restoreScheme()).WithObjects(existing).Build()
+
+		desired, cm, err := ToMocoMySQLClusterSpec(ctx, spec, mocoWandb(), mocoScheme(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		restore, err := ToMocoRestore(spec, mocoWandb(), restoreScheme(), seaweed, now)
+		Expect(err).NotTo(HaveOccurred())
+		desired.Spec.Restore = restore.Restore
+
+		conditions := WriteState(ctx, cl, nn, desired, cm, nil)
+
+		skipped, found := lo.Find(conditions, func(c metav1.Condition) bool { return c.Type == MySQLRestoreType })
+		Expect(found).To(BeTrue())
+		Expect(skipped.Status).To(Equal(metav1.ConditionTrue))
+		Expect(skipped.Reason).To(Equal(RestoreSkippedReason))
+
+		got := &mocov1beta2.MySQLCluster{}
+		Expect(cl.Get(ctx, nn, got)).To(Succeed())
+		Expect(got.Spec.Restore).To(BeNil())
+	})
+
+	Describe("MySQLRestore condition", func() {
+		restoring := func() *mocov1beta2.MySQLCluster {
+			return &mocov1beta2.MySQLCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "wandb"},
+				Spec: mocov1beta2.MySQLClusterSpec{Restore: &mocov1beta2.RestoreSpec{
+					SourceName:      "wandb-mysql",
+					SourceNamespace: "prod",
+					RestorePoint:    metav1.NewTime(time.Date(2026, 10, 1, 14, 0, 0, 0, time.UTC)),
+				}},
+			}
+		}
+		condition := func(cluster *mocov1beta2.MySQLCluster, objs ...*batchv1.Job) metav1.Condition {
+			builder := fake.NewClientBuilder().WithScheme(restoreScheme()).WithObjects(cluster)
+			for _, obj := range objs {
+				builder = builder.WithObjects(obj)
+			}
+			conditions := computeMySQLRestoreCondition(context.Background(), builder.Build(), cluster)
+			Expect(conditions).To(HaveLen(1))
+			Expect(conditions[0].Type).To(Equal(MySQLRestoreType))
+			return conditions[0]
+		}
+
+		It("is absent for clusters created without a restore", func() {
+			cluster := restoring()
+			cluster.Spec.Restore = nil
+			Expect(computeMySQLRestoreCondition(context.Background(), fake.NewClientBuilder().WithScheme(restoreScheme()).Build(), cluster)).To(BeEmpty())
+		})
+
+		It("is restoring until MOCO records the restore", func() {
+			cond := condition(restoring())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(RestoreRestoringReason))
+			Expect(cond.Message).To(ContainSubstring("moco/prod/wandb-mysql/ to 2026-10-01T14:00:00Z"))
+		})
+
+		It("reports the restore once MOCO records it", func() {
+			cluster := restoring()
+			restored := metav1.NewTime(time.Date(2026, 10, 2, 12, 45, 0, 0, time.UTC))
+			cluster.Status.RestoredTime = &restored
+			cond := condition(cluster)
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal(RestoreRestoredReason))
+		})
+
+		It("fails with the restore job", func() {
+			job := &batchv1.Job{
+				ObjectMeta: metav1.ObjectMeta{Name: "moco-restore-mysql", Namespace: "wandb"},
+				Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{{
+					Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Message: "BackoffLimitExceeded",
+				}}},
+			}
+			cond := condition(restoring(), job)
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal(RestoreFailedReason))
+			Expect(cond.Message).To(ContainSubstring("BackoffLimitExceeded"))
+		})
+	})
+
+	It("keeps the instance pending while restoring", func() {
+		Expect(inferState_MySQLRestoreType(context.Background(), metav1.Condition{
+			Type: MySQLRestoreType, Status: metav1.ConditionFalse, Reason: RestoreRestoringReason,
+		})).To(Equal(common.PendingState))
+	})
+})
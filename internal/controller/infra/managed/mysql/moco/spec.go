@@ -67,6 +67,9 @@ func ToMocoMySQLClusterSpec(
 			"innodb_flush_log_at_trx_commit": "1",
 		},
 	}
+	for k, v := range MysqldSettings(spec.Config.Mysqld) {
+		cm.Data[k] = v
+	}
 	if err := controllerutil.SetControllerReference(wandb, cm, scheme); err != nil {
 		return nil, nil, err
 	}
@@ -86,7 +89,7 @@ func ToMocoMySQLClusterSpec(
 			VolumeClaimTemplates: []mocov1beta2.PersistentVolumeClaim{
 				{
 					ObjectMeta: mocov1beta2.ObjectMeta{Name: dataVolumeName},
-					Spec:       buildPVCSpec(spec.StorageSize),
+					Spec:       buildPVCSpec(spec.StorageSize, spec.StorageClassName),
 				},
 			},
 		},
@@ -178,7 +181,9 @@ func mocoOverwriteContainers() []mocov1beta2.OverwriteContainer {
 	}
 }
 
-func buildPVCSpec(storageSize string) mocov1beta2.PersistentVolumeClaimSpecApplyConfiguration {
+// buildPVCSpec leaves storageClassName unset when empty so the claim falls back
+// to the cluster default class.
+func buildPVCSpec(storageSize, storageClassName string) mocov1beta2.PersistentVolumeClaimSpecApplyConfiguration {
 	quantity, _ := resource.ParseQuantity(storageSize)
 	pvcSpec := corev1ac.PersistentVolumeClaimSpec().
 		WithAccessModes(corev1.ReadWriteOnce).
@@ -188,6 +193,9 @@ func buildPVCSpec(storageSize string) mocov1beta2.PersistentVolumeClaimSpecApply
 					corev1.ResourceStorage: quantity,
 				}),
 		)
+	if storageClassName != "" {
+		pvcSpec.WithStorageClassName(storageClassName)
+	}
 	return mocov1beta2.PersistentVolumeClaimSpecApplyConfiguration(*pvcSpec)
 }
 
@@ -144,6 +144,18 @@ var _ = Describe("Moco MySQL specs", func() {
 		Entry("zero / unset", int32(0)),
 	)
 
+	It("passes storageClassName to the data volume claim only when set", func() {
+		spec := apiv2.ManagedMysqlSpec{Name: "mysql", Namespace: "wandb", Replicas: 1, StorageSize: "10Gi"}
+		cluster, _, err := ToMocoMySQLClusterSpec(context.Background(), spec, mocoWandb(), mocoScheme(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Spec.VolumeClaimTemplates[0].Spec.StorageClassName).To(BeNil())
+
+		spec.StorageClassName = "fast-ssd"
+		cluster, _, err = ToMocoMySQLClusterSpec(context.Background(), spec, mocoWandb(), mocoScheme(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cluster.Spec.VolumeClaimTemplates[0].Spec.StorageClassName).To(HaveValue(Equal("fast-ssd")))
+	})
+
 	It("stamps wandb labels onto Moco's data PVCs", func() {
 		ctx := context.Background()
 		wandbLabels := map[string]string{"app.kubernetes.io/managed-by": "wandb"}
@@ -17,6 +17,9 @@ const (
 	MySQLCustomResourceType = "MySQLCustomResource"
 	MySQLConnectionInfoType = "MySQLConnectionInfo"
 	MySQLReportedReadyType  = "MySQLReportedReady"
+	// MySQLConfigAppliedType reports whether mysqld runs with the current
+	// my.cnf settings. It is informational and does not affect the state.
+	MySQLConfigAppliedType = "ConfigApplied"
 )
 
 func ComputeStatus(
@@ -43,6 +46,7 @@ func ComputeStatus(
 	)
 
 	state, events := inferInfraState(ctx, enabled, result.Conditions)
+	events = append(events, common.AdoptionEvents(oldConditions, currentConditions)...)
 	result.State = state
 
 	result.Ready = !lo.Contains(common.NotReadyStates, result.State)
@@ -85,9 +89,11 @@ func inferInfraState(
 	var events []corev1.Event
 	impliedStates := make(map[string]string, len(conditions))
 
+	impliedStates = inferStateFromCondition(ctx, common.InfraOwnedType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, MySQLCustomResourceType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, MySQLConnectionInfoType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, MySQLReportedReadyType, impliedStates, conditions)
+	impliedStates = inferStateFromCondition(ctx, MySQLRestoreType, impliedStates, conditions)
 
 	hasImpliedState := func(target string) bool {
 		return len(lo.FilterValues(
@@ -139,12 +145,16 @@ func inferStateFromCondition(ctx context.Context, conditionType string, impliedS
 		impliedStates[conditionType] = common.UnknownState
 	} else {
 		switch conditionType {
+		case common.InfraOwnedType:
+			impliedStates[conditionType] = common.InferInfraOwnedState(cond)
 		case MySQLCustomResourceType:
 			impliedStates[conditionType] = inferState_MySQLCustomResourceType(ctx, cond)
 		case MySQLConnectionInfoType:
 			impliedStates[conditionType] = inferState_MySQLConnectionInfoType(ctx, cond)
 		case MySQLReportedReadyType:
 			impliedStates[conditionType] = inferState_MySQLReportedReadyType(ctx, cond)
+		case MySQLRestoreType:
+			impliedStates[conditionType] = inferState_MySQLRestoreType(ctx, cond)
 		default:
 			impliedStates[conditionType] = common.UnknownState
 		}
@@ -213,3 +223,23 @@ func inferState_MySQLReportedReadyType(ctx context.Context, condition metav1.Con
 	)
 	return result
 }
+
+// inferState_MySQLRestoreType keeps the instance pending until a requested
+// restore has finished, so nothing writes to the database in the meantime.
+func inferState_MySQLRestoreType(ctx context.Context, condition metav1.Condition) string {
+	log := logx.GetSlog(ctx)
+	result := common.UnknownState
+	switch {
+	case condition.Status == metav1.ConditionTrue:
+		result = common.HealthyState
+	case condition.Status == metav1.ConditionFalse && (condition.Reason == RestoreFailedReason || condition.Reason == UnsupportedObjectStoreReason):
+		result = common.ErrorState
+	case condition.Status == metav1.ConditionFalse:
+		result = common.PendingState
+	}
+	log.Debug(
+		"implied state", "state", result, "condition", condition.Type,
+		"reason", condition.Reason, "status", condition.Status,
+	)
+	return result
+}
@@ -0,0 +1,72 @@
+package moco
+
+import (
+	"path"
+
+	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
+	mococonstants "github.com/cybozu-go/moco/pkg/constants"
+	"github.com/wandb/operator/internal/controller/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	serverTLSVolumeName = "server-tls"
+	serverTLSMountPath  = "/etc/mysql-tls"
+
+	// clientCACertPath is where applications find the CA published as the
+	// connection's SslCa; the custom CA mounts put it there.
+	clientCACertPath = "/etc/ssl/certs/mysql_ca.pem"
+)
+
+// ServerDNSNames lists the names the MySQL server certificate covers: the
+// primary and replica Services and the pods behind the headless Service.
+func ServerDNSNames(nn types.NamespacedName) []string {
+	prefixed := "moco-" + nn.Name
+	return common.InfraServiceDNSNames(nn.Namespace, prefixed+"-primary", prefixed+"-replica", prefixed)
+}
+
+// ApplyServerTLS mounts the server certificate Secret into mysqld and points
+// the my.cnf ssl_* settings at it. MOCO's own agent and controller
+// connections are left as they are, so require_secure_transport stays off;
+// applications are sent over TLS by the connection URL.
+func ApplyServerTLS(cluster *mocov1beta2.MySQLCluster, confMap *corev1.ConfigMap, secret *corev1.Secret) {
+	podSpec := corev1ac.PodSpecApplyConfiguration(cluster.Spec.PodTemplate.Spec)
+	podSpec.WithVolumes(corev1ac.Volume().
+		WithName(serverTLSVolumeName).
+		WithSecret(corev1ac.SecretVolumeSource().
+			WithSecretName(secret.Name).
+			WithDefaultMode(0o440)))
+	for i := range podSpec.Containers {
+		if ptr.Deref(podSpec.Containers[i].Name, "") != mococonstants.MysqldContainerName {
+			continue
+		}
+		podSpec.Containers[i].WithVolumeMounts(corev1ac.VolumeMount().
+			WithName(serverTLSVolumeName).
+			WithMountPath(serverTLSMountPath).
+			WithReadOnly(true))
+	}
+	cluster.Spec.PodTemplate.Spec = mocov1beta2.PodSpecApplyConfiguration(podSpec)
+
+	if cluster.Spec.PodTemplate.Annotations == nil {
+		cluster.Spec.PodTemplate.Annotations = map[string]string{}
+	}
+	cluster.Spec.PodTemplate.Annotations[common.InfraTLSChecksumAnnotation] = common.InfraTLSChecksum(secret)
+
+	confMap.Data["ssl_ca"] = path.Join(serverTLSMountPath, common.InfraTLSCAKey)
+	confMap.Data["ssl_cert"] = path.Join(serverTLSMountPath, corev1.TLSCertKey)
+	confMap.Data["ssl_key"] = path.Join(serverTLSMountPath, corev1.TLSPrivateKeyKey)
+}
+
+// readServerTLSSecret returns the server certificate Secret the live cluster
+// mounts, or "" when it is not served over TLS.
+func readServerTLSSecret(actual *mocov1beta2.MySQLCluster) string {
+	for _, volume := range actual.Spec.PodTemplate.Spec.Volumes {
+		if ptr.Deref(volume.Name, "") == serverTLSVolumeName && volume.Secret != nil {
+			return ptr.Deref(volume.Secret.SecretName, "")
+		}
+	}
+	return ""
+}
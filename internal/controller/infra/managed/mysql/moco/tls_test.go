@@ -0,0 +1,63 @@
+package moco
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/pkg/utils"
+	"github.com/wandb/operator/pkg/wandb/manifest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+)
+
+var _ = Describe("Moco MySQL server TLS", func() {
+	BeforeEach(func() {
+		utils.SetOpenShiftMode(false)
+	})
+
+	It("covers the primary, replica and per-pod names", func() {
+		names := ServerDNSNames(types.NamespacedName{Name: "mysql", Namespace: "wandb"})
+		Expect(names).To(ContainElements(
+			"moco-mysql-primary.wandb.svc",
+			"moco-mysql-replica.wandb.svc.cluster.local",
+			"*.moco-mysql.wandb.svc",
+		))
+	})
+
+	It("mounts the server certificate into mysqld and points my.cnf at it", func() {
+		cluster, confMap, err := ToMocoMySQLClusterSpec(
+			context.Background(),
+			apiv2.ManagedMysqlSpec{Name: "mysql", Namespace: "wandb", Replicas: 1, StorageSize: "10Gi"},
+			mocoWandb(),
+			mocoScheme(),
+			manifest.Manifest{},
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		ApplyServerTLS(cluster, confMap, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "mysql-tls", Namespace: "wandb"},
+			Data:       map[string][]byte{common.InfraTLSCAKey: []byte("ca"), corev1.TLSCertKey: []byte("cert")},
+		})
+
+		Expect(confMap.Data).To(HaveKeyWithValue("ssl_cert", serverTLSMountPath+"/tls.crt"))
+		Expect(confMap.Data).To(HaveKeyWithValue("ssl_ca", serverTLSMountPath+"/ca.crt"))
+		Expect(cluster.Spec.PodTemplate.Annotations).To(HaveKey(common.InfraTLSChecksumAnnotation))
+		mounts := cluster.Spec.PodTemplate.Spec.Containers[0].VolumeMounts
+		Expect(mounts).To(HaveLen(1))
+		Expect(ptr.Deref(mounts[0].MountPath, "")).To(Equal(serverTLSMountPath))
+		Expect(readServerTLSSecret(cluster)).To(Equal("mysql-tls"))
+	})
+
+	It("sends applications over TLS only when the cluster serves it", func() {
+		connInfo := &mysqlConnInfo{Host: "db", Port: "3306", User: "wandb", Password: "p", Database: "wandb_local"}
+		Expect(connInfo.toURL()).To(Equal("mysql://wandb:p@db:3306/wandb_local"))
+
+		connInfo.SslCa = "ca"
+		Expect(connInfo.toURL()).To(Equal("mysql://wandb:p@db:3306/wandb_local?ssl-ca=%2Fetc%2Fssl%2Fcerts%2Fmysql_ca.pem&tls=custom"))
+	})
+})
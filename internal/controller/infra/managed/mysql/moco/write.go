@@ -69,6 +69,21 @@ func WriteState(
 		desired.Spec.ServerIDBase = actual.Spec.ServerIDBase
 	}
 
+	// MOCO only restores while creating a cluster and rejects later edits of
+	// spec.restore, so the live value wins over whatever the CR asks for now.
+	var restoreConditions []metav1.Condition
+	if actual != nil {
+		if desired.Spec.Restore != nil && actual.Spec.Restore == nil {
+			restoreConditions = append(restoreConditions, metav1.Condition{
+				Type:    MySQLRestoreType,
+				Status:  metav1.ConditionTrue,
+				Reason:  RestoreSkippedReason,
+				Message: "the cluster already exists; restore only applies when it is created",
+			})
+		}
+		desired.Spec.Restore = actual.Spec.Restore
+	}
+
 	// Sizing is resolved from the manifest at reconcile time, after the CR
 	// admission webhook runs, so a bad value reaches here unvalidated.
 	if !apiv2.ValidMysqlReplicaCount(desired.Spec.Replicas) {
@@ -107,7 +122,7 @@ func WriteState(
 		}
 	}
 
-	result := make([]metav1.Condition, 0)
+	result := restoreConditions
 
 	if confMap != nil {
 		var actualConfMap = &corev1.ConfigMap{}
@@ -179,14 +194,27 @@ func WriteState(
 		}
 	}
 
+	if actual != nil {
+		result = append(result, common.ExpandVolumeClaims(ctx, cl, specNamespacedName.Namespace, common.VolumeClaimSize{
+			NamePrefixes: []string{dataPVCPrefix(nsnBuilder.ClusterName())},
+			Size:         desired.Spec.VolumeClaimTemplates[0].StorageSize(),
+		}))
+	}
+
 	return result
 }
 
-// ensurePVCLabels stamps the wandb labels onto Moco's PVCs (missing because Moco
-// doesn't propagate them through its StatefulSet volumeClaimTemplates), so
-// purgeAssociatedResources can select them by label on teardown. Moco names PVCs
+// dataPVCPrefix is the name prefix of the cluster's data PVCs. Moco names them
 // "<dataVolumeName>-<cluster.PrefixedName()>-<ordinal>" (see Moco pvc.go); the
 // prefix is built from those same sources so it can't drift from upstream.
+func dataPVCPrefix(clusterName string) string {
+	cluster := &mocov1beta2.MySQLCluster{ObjectMeta: metav1.ObjectMeta{Name: clusterName}}
+	return fmt.Sprintf("%s-%s-", dataVolumeName, cluster.PrefixedName())
+}
+
+// ensurePVCLabels stamps the wandb labels onto Moco's PVCs (missing because Moco
+// doesn't propagate them through its StatefulSet volumeClaimTemplates), so
+// purgeAssociatedResources can select them by label on teardown.
 func ensurePVCLabels(
 	ctx context.Context,
 	cl client.Client,
@@ -195,8 +223,7 @@ func ensurePVCLabels(
 	labels map[string]string,
 ) error {
 	log := logx.GetSlog(ctx)
-	cluster := &mocov1beta2.MySQLCluster{ObjectMeta: metav1.ObjectMeta{Name: clusterName}}
-	prefix := fmt.Sprintf("%s-%s-", dataVolumeName, cluster.PrefixedName())
+	prefix := dataPVCPrefix(clusterName)
 
 	pvcList := &corev1.PersistentVolumeClaimList{}
 	if err := cl.List(ctx, pvcList, &client.ListOptions{Namespace: namespace}); err != nil {
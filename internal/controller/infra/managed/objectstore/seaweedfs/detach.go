@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	apiv2 "github.com/wandb/operator/api/v2"
 	"github.com/wandb/operator/internal/controller/common"
 	"github.com/wandb/operator/internal/logx"
 	seaweedv1 "github.com/wandb/operator/pkg/vendored/seaweedfs-operator/seaweed.seaweedfs.com/v1"
@@ -45,6 +46,21 @@ func CheckDetached(
 	return nil
 }
 
+// AdoptDetached adopts the Seaweed CR a previous CR of this deployment
+// detached. It returns proceed=false when the Seaweed CR belongs to another
+// deployment.
+func AdoptDetached(
+	ctx context.Context,
+	cl client.Client,
+	specNamespacedName types.NamespacedName,
+	wandb *apiv2.WeightsAndBiases,
+) ([]metav1.Condition, bool) {
+	nsnBuilder := createNsNameBuilder(specNamespacedName)
+	return common.AdoptionConditions(common.AdoptDetached(
+		ctx, cl, wandb, nsnBuilder.SpecNsName(), ResourceTypeName, &seaweedv1.Seaweed{}, BuildWandbObjectStoreLabels(wandb),
+	))
+}
+
 func DetachFinalizer(
 	ctx context.Context,
 	cl client.Client,
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/wandb/operator/internal/controller/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -46,6 +47,29 @@ func (n *NsNameBuilder) SpecNsName() types.NamespacedName {
 	}
 }
 
+// VolumeDataPVCPrefix is the name prefix of the volume servers' data PVCs. The
+// seaweedfs operator names them "mount<disk>-<seaweed>-volume-<ordinal>"; we
+// run one disk per server.
+func (n *NsNameBuilder) VolumeDataPVCPrefix() string {
+	return fmt.Sprintf("mount0-%s-volume-", SeaweedName(n.SpecName()))
+}
+
+// FilerDataPVCPrefix is the name prefix of the filer's metadata PVCs, which the
+// seaweedfs operator names "<seaweed>-filer-<seaweed>-filer-<ordinal>".
+func (n *NsNameBuilder) FilerDataPVCPrefix() string {
+	name := SeaweedName(n.SpecName())
+	return fmt.Sprintf("%s-filer-%s-filer-", name, name)
+}
+
+// PodSelector matches the master, volume and filer pods, which the seaweedfs
+// operator labels with the Seaweed they belong to.
+func (n *NsNameBuilder) PodSelector() metav1.LabelSelector {
+	return metav1.LabelSelector{MatchLabels: map[string]string{
+		"app.kubernetes.io/name":     "seaweedfs",
+		"app.kubernetes.io/instance": SeaweedName(n.SpecName()),
+	}}
+}
+
 func (n *NsNameBuilder) ConfigName() string {
 	return fmt.Sprintf("%s-s3-config", n.SpecName())
 }
@@ -19,7 +19,7 @@ func PurgeFinalizer(
 	onDeleteRule common.OnDeleteRule,
 ) error {
 	ctx, _ = logx.WithSlog(ctx, logx.ObjectStore)
-	if onDeleteRule.Policy != common.Purge {
+	if !onDeleteRule.Purges() {
 		return nil
 	}
 	return purgeAssociatedResources(ctx, client, specNamespacedName.Namespace, onDeleteRule.Selector)
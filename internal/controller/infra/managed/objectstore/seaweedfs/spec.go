@@ -152,8 +152,9 @@ func ToObjectStoreVendorSpec(
 			Volume: &seaweedv1.VolumeSpec{
 				Replicas: infraSpec.Replicas,
 				VolumeServerConfig: seaweedv1.VolumeServerConfig{
-					MetricsPort:     ptr.To(seaweedVolumeMetricsPort),
-					MaxVolumeCounts: ptr.To(maxVolumeCount),
+					MetricsPort:      ptr.To(seaweedVolumeMetricsPort),
+					MaxVolumeCounts:  ptr.To(maxVolumeCount),
+					StorageClassName: common.StorageClassName(infraSpec.StorageClassName),
 					ComponentSpec: seaweedv1.ComponentSpec{
 						Volumes:      seaweedWritableVolumes(),
 						VolumeMounts: seaweedWritableVolumeMounts(),
@@ -205,8 +206,9 @@ func ToObjectStoreVendorSpec(
 					ExtraArgs:    []string{"-ip.bind=0.0.0.0"},
 				},
 				Persistence: &seaweedv1.PersistenceSpec{
-					Enabled:   true,
-					MountPath: ptr.To(seaweedFilerDataMountPath),
+					Enabled:          true,
+					MountPath:        ptr.To(seaweedFilerDataMountPath),
+					StorageClassName: common.StorageClassName(filerStorageClassName(infraSpec)),
 					Resources: corev1.VolumeResourceRequirements{
 						Requests: corev1.ResourceList{
 							corev1.ResourceStorage: filerStorageQuantity,
@@ -227,6 +229,15 @@ func ToObjectStoreVendorSpec(
 	return seaweedCR, nil
 }
 
+// filerStorageClassName picks the class for the filer's metadata disk, falling
+// back to the instance-wide class used by the volume servers.
+func filerStorageClassName(infraSpec *apiv2.ManagedObjectStoreSpec) string {
+	if infraSpec.SeaweedObjectStoreSpec.FilerStorageClassName != "" {
+		return infraSpec.SeaweedObjectStoreSpec.FilerStorageClassName
+	}
+	return infraSpec.StorageClassName
+}
+
 // seaweedReplication builds the SeaweedFS replication code from the neutral copy
 // count, clamped to the data-node count so we never request more copies than servers.
 func seaweedReplication(copies, replicas int32) string {
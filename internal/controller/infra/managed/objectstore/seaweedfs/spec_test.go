@@ -193,6 +193,30 @@ var _ = Describe("SeaweedFS vendor specs", func() {
 		Expect(seaweed).NotTo(BeNil())
 		Expect(seaweed.Spec.Filer.Persistence.Resources.Requests[corev1.ResourceStorage]).To(Equal(resource.MustParse("50Gi")))
 	})
+
+	It("uses the cluster default storage class when none is configured", func() {
+		wandb := seaweedWandb()
+		seaweed, err := ToObjectStoreVendorSpec(context.Background(), wandb, wandb.Spec.ObjectStore[apiv2.DefaultInstanceName].ManagedObjectStore, seaweedScheme(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(seaweed.Spec.Volume.StorageClassName).To(BeNil())
+		Expect(seaweed.Spec.Filer.Persistence.StorageClassName).To(BeNil())
+	})
+
+	It("puts the filer on the instance storage class unless it has its own", func() {
+		wandb := seaweedWandb()
+		infraSpec := wandb.Spec.ObjectStore[apiv2.DefaultInstanceName].ManagedObjectStore
+		infraSpec.StorageClassName = "standard-hdd"
+		seaweed, err := ToObjectStoreVendorSpec(context.Background(), wandb, infraSpec, seaweedScheme(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(seaweed.Spec.Volume.StorageClassName).To(HaveValue(Equal("standard-hdd")))
+		Expect(seaweed.Spec.Filer.Persistence.StorageClassName).To(HaveValue(Equal("standard-hdd")))
+
+		infraSpec.SeaweedObjectStoreSpec.FilerStorageClassName = "fast-ssd"
+		seaweed, err = ToObjectStoreVendorSpec(context.Background(), wandb, infraSpec, seaweedScheme(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(seaweed.Spec.Volume.StorageClassName).To(HaveValue(Equal("standard-hdd")))
+		Expect(seaweed.Spec.Filer.Persistence.StorageClassName).To(HaveValue(Equal("fast-ssd")))
+	})
 })
 
 var _ = Describe("SeaweedFS translation edge cases", func() {
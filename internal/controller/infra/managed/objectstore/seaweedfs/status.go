@@ -45,6 +45,7 @@ func ComputeStatus(
 	)
 
 	state, events := inferInfraState(ctx, enabled, result.Conditions)
+	events = append(events, common.AdoptionEvents(oldConditions, currentConditions)...)
 	result.State = state
 
 	result.Ready = !lo.Contains(common.NotReadyStates, result.State)
@@ -101,6 +102,7 @@ func inferInfraState(
 	var events []corev1.Event
 	impliedStates := make(map[string]string, len(conditions))
 
+	impliedStates = inferStateFromCondition(ctx, common.InfraOwnedType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, SeaweedCustomResourceType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, SeaweedConnectionInfoType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, SeaweedReportedReadyType, impliedStates, conditions)
@@ -153,6 +155,8 @@ func inferStateFromCondition(ctx context.Context, conditionType string, impliedS
 		impliedStates[conditionType] = common.UnknownState
 	} else {
 		switch conditionType {
+		case common.InfraOwnedType:
+			impliedStates[conditionType] = common.InferInfraOwnedState(cond)
 		case SeaweedCustomResourceType:
 			impliedStates[conditionType] = inferState_SeaweedCustomResourceType(ctx, cond)
 		case SeaweedConnectionInfoType:
@@ -100,6 +100,10 @@ func WriteState(
 		})
 	}
 
+	if actual != nil {
+		result = append(result, expandVolumeClaims(ctx, kubeClient, nsnBuilder, desiredCr))
+	}
+
 	connInfo, err := writeSeaweedS3Config(
 		ctx, kubeClient, desiredCr, nsnBuilder, envConfig,
 	)
@@ -142,6 +146,30 @@ func WriteState(
 	return result, nil
 }
 
+// expandVolumeClaims grows the volume server and filer claims to the sizes
+// requested by the desired CR.
+func expandVolumeClaims(
+	ctx context.Context,
+	kubeClient client.Client,
+	nsnBuilder *NsNameBuilder,
+	desiredCr *seaweedv1.Seaweed,
+) metav1.Condition {
+	var claims []common.VolumeClaimSize
+	if volume := desiredCr.Spec.Volume; volume != nil {
+		claims = append(claims, common.VolumeClaimSize{
+			NamePrefixes: []string{nsnBuilder.VolumeDataPVCPrefix()},
+			Size:         volume.ResourceRequirements.Requests[corev1.ResourceStorage],
+		})
+	}
+	if filer := desiredCr.Spec.Filer; filer != nil && filer.Persistence != nil {
+		claims = append(claims, common.VolumeClaimSize{
+			NamePrefixes: []string{nsnBuilder.FilerDataPVCPrefix()},
+			Size:         filer.Persistence.Resources.Requests[corev1.ResourceStorage],
+		})
+	}
+	return common.ExpandVolumeClaims(ctx, kubeClient, nsnBuilder.Namespace(), claims...)
+}
+
 // writeSeaweedS3Config persists the SeaweedFS S3 identity config secret,
 // preserving the existing secret key when one is already present so credentials
 // stay stable across reconciles, and returns the resolved ConnInfo.
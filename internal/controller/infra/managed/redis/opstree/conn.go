@@ -88,15 +88,24 @@ func writeRedisConnInfo(
 			"Port": connInfo.Port,
 		},
 	}
+	if connInfo.SslCa != "" {
+		desired.StringData["Tls"] = "true"
+		desired.StringData["SslCa"] = connInfo.SslCa
+	}
 
 	if _, err = common.CrudResource(ctx, client, desired, actual); err != nil {
 		return nil, err
 	}
 
 	localRef := corev1.LocalObjectReference{Name: nsName.Name}
-	return &apiv2.RedisConnection{
+	connection := &apiv2.RedisConnection{
 		URL:  corev1.SecretKeySelector{LocalObjectReference: localRef, Key: urlKey, Optional: ptr.To(false)},
 		Host: corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Host", Optional: ptr.To(false)},
 		Port: corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Port", Optional: ptr.To(false)},
-	}, nil
+	}
+	if connInfo.SslCa != "" {
+		connection.Tls = corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "Tls", Optional: ptr.To(true)}
+		connection.SslCa = corev1.SecretKeySelector{LocalObjectReference: localRef, Key: "SslCa", Optional: ptr.To(true)}
+	}
+	return connection, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 
+	apiv2 "github.com/wandb/operator/api/v2"
 	"github.com/wandb/operator/internal/controller/common"
 	"github.com/wandb/operator/internal/logx"
 	redisv1beta2 "github.com/wandb/operator/pkg/vendored/redis-operator/redis/v1beta2"
@@ -34,6 +35,24 @@ func CheckDetached(
 	return nil
 }
 
+// AdoptDetached adopts the Redis CRs a previous CR of this deployment
+// detached, whichever of standalone, sentinel and replication exist. It
+// returns proceed=false when one of them belongs to another deployment.
+func AdoptDetached(
+	ctx context.Context,
+	cl client.Client,
+	specNamespacedName types.NamespacedName,
+	wandb *apiv2.WeightsAndBiases,
+) ([]metav1.Condition, bool) {
+	nsnBuilder := createNsNameBuilder(specNamespacedName)
+	labels := BuildWandbRedisLabels(wandb)
+	var results []metav1.Condition
+	results = append(results, common.AdoptDetached(ctx, cl, wandb, nsnBuilder.StandaloneNsName(), StandaloneType, &redisv1beta2.Redis{}, labels)...)
+	results = append(results, common.AdoptDetached(ctx, cl, wandb, nsnBuilder.SentinelNsName(), SentinelType, &redissentinelv1beta2.RedisSentinel{}, labels)...)
+	results = append(results, common.AdoptDetached(ctx, cl, wandb, nsnBuilder.ReplicationNsName(), ReplicationType, &redisreplicationv1beta2.RedisReplication{}, labels)...)
+	return common.AdoptionConditions(results)
+}
+
 func DetachFinalizer(
 	ctx context.Context,
 	cl client.Client,
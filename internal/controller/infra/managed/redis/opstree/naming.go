@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/wandb/operator/internal/controller/common"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
 
@@ -75,6 +76,17 @@ func (n *NsNameBuilder) ReplicationNsName() types.NamespacedName {
 	}
 }
 
+// PodSelector matches every pod the opstree operator runs for this Redis. It
+// labels pods "app=<statefulset>": the standalone server, the replication
+// servers and, in sentinel mode, "<sentinel>-sentinel".
+func (n *NsNameBuilder) PodSelector() metav1.LabelSelector {
+	return metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+		Key:      "app",
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   []string{n.StandaloneName(), n.ReplicationName(), n.SentinelName() + "-sentinel"},
+	}}}
+}
+
 func (n *NsNameBuilder) ConnectionName() string {
 	return fmt.Sprintf("%s-connection", n.SpecName())
 }
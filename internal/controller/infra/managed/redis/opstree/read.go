@@ -104,6 +104,16 @@ func ReadState(
 
 	if standaloneActual != nil {
 		connInfo := readStandaloneConnectionDetails(standaloneActual)
+		if connInfo.SslCa, err = readServerCA(ctx, client, standaloneActual.Namespace, standaloneActual.Spec.TLS); err != nil {
+			log.Error("Failed to read Redis server CA", logx.ErrAttr(err))
+			return []metav1.Condition{
+				{
+					Type:   RedisConnectionInfoType,
+					Status: metav1.ConditionUnknown,
+					Reason: ctrlcommon.ApiErrorReason,
+				},
+			}, nil
+		}
 
 		connection, err = writeRedisConnInfo(
 			ctx, client, wandbOwner, nsnBuilder, connInfo,
@@ -145,6 +155,16 @@ func ReadState(
 
 	} else if sentinelActual != nil && replicationActual != nil {
 		connInfo := readSentinelConnectionDetails(sentinelActual)
+		if connInfo.SslCa, err = readServerCA(ctx, client, sentinelActual.Namespace, sentinelActual.Spec.TLS); err != nil {
+			log.Error("Failed to read Redis server CA", logx.ErrAttr(err))
+			return []metav1.Condition{
+				{
+					Type:   RedisConnectionInfoType,
+					Status: metav1.ConditionUnknown,
+					Reason: ctrlcommon.ApiErrorReason,
+				},
+			}, nil
+		}
 
 		connection, err = writeRedisConnInfo(
 			ctx, client, wandbOwner, nsnBuilder, connInfo,
@@ -192,6 +192,7 @@ func ToRedisStandaloneVendorSpec(
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      nsnBuilder.StandaloneName(),
 			Namespace: nsnBuilder.StandaloneNamespace(),
+			Labels:    BuildWandbRedisLabels(wandb),
 		},
 		Spec: redisv1beta2.RedisSpec{
 			KubernetesConfig: rediscommon.KubernetesConfig{
@@ -213,6 +214,7 @@ func ToRedisStandaloneVendorSpec(
 						AccessModes: []corev1.PersistentVolumeAccessMode{
 							corev1.ReadWriteOnce,
 						},
+						StorageClassName: common.StorageClassName(spec.StorageClassName),
 						Resources: corev1.VolumeResourceRequirements{
 							Requests: corev1.ResourceList{
 								corev1.ResourceStorage: storageQuantity,
@@ -278,6 +280,7 @@ func ToRedisSentinelVendorSpec(
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      nsnBuilder.SentinelName(),
 			Namespace: nsnBuilder.Namespace(),
+			Labels:    BuildWandbRedisLabels(wandb),
 		},
 		Spec: redissentinelv1beta2.RedisSentinelSpec{
 			Size: &sentinelCount,
@@ -358,6 +361,7 @@ func ToRedisReplicationVendorSpec(
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      nsnBuilder.ReplicationName(),
 			Namespace: nsnBuilder.Namespace(),
+			Labels:    BuildWandbRedisLabels(wandb),
 		},
 		Spec: redisreplicationv1beta2.RedisReplicationSpec{
 			Size: &replicaCount,
@@ -380,6 +384,7 @@ func ToRedisReplicationVendorSpec(
 						AccessModes: []corev1.PersistentVolumeAccessMode{
 							corev1.ReadWriteOnce,
 						},
+						StorageClassName: common.StorageClassName(spec.StorageClassName),
 						Resources: corev1.VolumeResourceRequirements{
 							Requests: corev1.ResourceList{
 								corev1.ResourceStorage: storageQuantity,
@@ -45,6 +45,7 @@ func ComputeStatus(
 	)
 
 	state, events := inferInfraState(ctx, enabled, result.Conditions)
+	events = append(events, common.AdoptionEvents(oldConditions, currentConditions)...)
 	result.State = state
 
 	result.Ready = !lo.Contains(common.NotReadyStates, result.State)
@@ -87,6 +88,7 @@ func inferInfraState(
 	var events []corev1.Event
 	impliedStates := make(map[string]string, len(conditions))
 
+	impliedStates = inferStateFromCondition(ctx, common.InfraOwnedType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, RedisStandaloneCustomResourceType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, RedisSentinelCustomResourceType, impliedStates, conditions)
 	impliedStates = inferStateFromCondition(ctx, RedisReplicationCustomResourceType, impliedStates, conditions)
@@ -143,6 +145,8 @@ func inferStateFromCondition(ctx context.Context, conditionType string, impliedS
 		impliedStates[conditionType] = common.UnknownState
 	} else {
 		switch conditionType {
+		case common.InfraOwnedType:
+			impliedStates[conditionType] = common.InferInfraOwnedState(cond)
 		case RedisStandaloneCustomResourceType:
 			impliedStates[conditionType] = inferState_RedisStandaloneCustomResourceType(ctx, cond)
 		case RedisSentinelCustomResourceType:
@@ -0,0 +1,86 @@
+package opstree
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/wandb/operator/internal/controller/common"
+	rediscommon "github.com/wandb/operator/pkg/vendored/redis-operator/common/v1beta2"
+	redisv1beta2 "github.com/wandb/operator/pkg/vendored/redis-operator/redis/v1beta2"
+	redisreplicationv1beta2 "github.com/wandb/operator/pkg/vendored/redis-operator/redisreplication/v1beta2"
+	redissentinelv1beta2 "github.com/wandb/operator/pkg/vendored/redis-operator/redissentinel/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clientCACertPath is where applications find the CA published as the
+// connection's SslCa; the custom CA mounts put it there.
+const clientCACertPath = "/etc/ssl/certs/redis_ca.pem"
+
+// ServerDNSNames lists the names the Redis server certificate covers: the
+// standalone, replication and Sentinel Services and their pods.
+func ServerDNSNames(nn types.NamespacedName) []string {
+	nsnBuilder := createNsNameBuilder(nn)
+	var services []string
+	for _, name := range []string{nsnBuilder.StandaloneName(), nsnBuilder.ReplicationName(), nsnBuilder.SentinelName() + "-sentinel"} {
+		services = append(services, name, name+"-headless")
+	}
+	return common.InfraServiceDNSNames(nn.Namespace, services...)
+}
+
+// ApplyServerTLS has the redis operator serve every desired CR over TLS with
+// the server certificate Secret. The operator replaces the plaintext port
+// with the TLS one and dials Redis and Sentinel over TLS itself.
+func ApplyServerTLS(
+	standalone *redisv1beta2.Redis,
+	sentinel *redissentinelv1beta2.RedisSentinel,
+	replication *redisreplicationv1beta2.RedisReplication,
+	secret *corev1.Secret,
+) {
+	checksum := common.InfraTLSChecksum(secret)
+	if standalone != nil {
+		standalone.Spec.TLS = serverTLSConfig(secret)
+		setTLSChecksum(&standalone.ObjectMeta, checksum)
+	}
+	if sentinel != nil {
+		sentinel.Spec.TLS = serverTLSConfig(secret)
+		setTLSChecksum(&sentinel.ObjectMeta, checksum)
+	}
+	if replication != nil {
+		replication.Spec.TLS = serverTLSConfig(secret)
+		setTLSChecksum(&replication.ObjectMeta, checksum)
+	}
+}
+
+func serverTLSConfig(secret *corev1.Secret) *rediscommon.TLSConfig {
+	return &rediscommon.TLSConfig{
+		CaKeyFile:   common.InfraTLSCAKey,
+		CertKeyFile: corev1.TLSCertKey,
+		KeyFile:     corev1.TLSPrivateKeyKey,
+		Secret:      corev1.SecretVolumeSource{SecretName: secret.Name},
+	}
+}
+
+// setTLSChecksum annotates the CR; the redis operator copies CR annotations
+// onto the StatefulSet pod template, so a reissued certificate rolls the pods.
+func setTLSChecksum(meta *metav1.ObjectMeta, checksum string) {
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[common.InfraTLSChecksumAnnotation] = checksum
+}
+
+// readServerCA returns the CA of the server certificate a live CR is served
+// with, or "" when it serves plaintext.
+func readServerCA(ctx context.Context, c client.Client, namespace string, tls *rediscommon.TLSConfig) (string, error) {
+	if tls == nil || tls.Secret.SecretName == "" {
+		return "", nil
+	}
+	ca, err := common.ReadInfraTLSCA(ctx, c, types.NamespacedName{Name: tls.Secret.SecretName, Namespace: namespace})
+	if err == nil && ca == "" {
+		err = fmt.Errorf("server certificate Secret %s has no CA", tls.Secret.SecretName)
+	}
+	return ca, err
+}
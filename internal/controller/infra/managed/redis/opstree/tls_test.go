@@ -0,0 +1,48 @@
+package opstree
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/pkg/utils"
+	"github.com/wandb/operator/pkg/wandb/manifest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Redis server TLS", func() {
+	BeforeEach(func() {
+		utils.SetOpenShiftMode(false)
+	})
+
+	It("serves every Sentinel-mode CR with the server certificate", func() {
+		wandb := redisWandb(true)
+		spec := wandb.Spec.Redis[apiv2.DefaultInstanceName].ManagedRedis
+		sentinel, err := ToRedisSentinelVendorSpec(context.Background(), wandb, spec, redisScheme(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+		replication, err := ToRedisReplicationVendorSpec(context.Background(), wandb, spec, redisScheme(), manifest.Manifest{})
+		Expect(err).NotTo(HaveOccurred())
+
+		ApplyServerTLS(nil, sentinel, replication, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "redis-tls"},
+			Data:       map[string][]byte{common.InfraTLSCAKey: []byte("ca")},
+		})
+
+		for _, meta := range []metav1.ObjectMeta{sentinel.ObjectMeta, replication.ObjectMeta} {
+			Expect(meta.Annotations).To(HaveKey(common.InfraTLSChecksumAnnotation))
+		}
+		Expect(sentinel.Spec.TLS.Secret.SecretName).To(Equal("redis-tls"))
+		Expect(replication.Spec.TLS.CaKeyFile).To(Equal(common.InfraTLSCAKey))
+	})
+
+	It("adds the TLS query to standalone and Sentinel URLs", func() {
+		standalone := &redisConnInfo{Host: "redis", Port: "6379", SslCa: "ca"}
+		Expect(standalone.toURL()).To(Equal("redis://redis:6379?tls=true&caCertPath=%2Fetc%2Fssl%2Fcerts%2Fredis_ca.pem"))
+
+		sentinel := &redisConnInfo{SentinelHost: "sentinel", SentinelPort: "26379", SentinelMaster: "gorilla", SslCa: "ca"}
+		Expect(sentinel.toURL()).To(Equal("redis://sentinel:26379?master=gorilla&tls=true&caCertPath=%2Fetc%2Fssl%2Fcerts%2Fredis_ca.pem"))
+	})
+})
@@ -4,14 +4,17 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"net/url"
 	"strings"
 
 	"github.com/wandb/operator/internal/controller/common"
 	"github.com/wandb/operator/internal/logx"
+	rediscommon "github.com/wandb/operator/pkg/vendored/redis-operator/common/v1beta2"
 	redisv1beta2 "github.com/wandb/operator/pkg/vendored/redis-operator/redis/v1beta2"
 	redisreplicationv1beta2 "github.com/wandb/operator/pkg/vendored/redis-operator/redisreplication/v1beta2"
 	redissentinelv1beta2 "github.com/wandb/operator/pkg/vendored/redis-operator/redissentinel/v1beta2"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -47,17 +50,21 @@ func WriteState(
 	results = append(results, writeSentinelState(ctx, cl, nsnBuilder, sentinelDesired)...)
 	results = append(results, writeReplicationState(ctx, cl, nsnBuilder, replicationDesired)...)
 
+	var pvcPrefixes []string
+	var podPrefixes []string
+	var storageSize resource.Quantity
+	if standaloneDesired != nil {
+		pvcPrefixes = append(pvcPrefixes, fmt.Sprintf("%s-%s-", pvcTemplatePrefix, nsnBuilder.StandaloneName()))
+		podPrefixes = append(podPrefixes, fmt.Sprintf("%s-", nsnBuilder.StandaloneName()))
+		storageSize = claimTemplateSize(standaloneDesired.Spec.Storage)
+	}
+	if replicationDesired != nil {
+		pvcPrefixes = append(pvcPrefixes, fmt.Sprintf("%s-%s-", pvcTemplatePrefix, nsnBuilder.ReplicationName()))
+		podPrefixes = append(podPrefixes, fmt.Sprintf("%s-", nsnBuilder.ReplicationName()))
+		storageSize = claimTemplateSize(replicationDesired.Spec.Storage)
+	}
+
 	if len(wandbLabels) > 0 {
-		var pvcPrefixes []string
-		var podPrefixes []string
-		if standaloneDesired != nil {
-			pvcPrefixes = append(pvcPrefixes, fmt.Sprintf("%s-%s-", pvcTemplatePrefix, nsnBuilder.StandaloneName()))
-			podPrefixes = append(podPrefixes, fmt.Sprintf("%s-", nsnBuilder.StandaloneName()))
-		}
-		if replicationDesired != nil {
-			pvcPrefixes = append(pvcPrefixes, fmt.Sprintf("%s-%s-", pvcTemplatePrefix, nsnBuilder.ReplicationName()))
-			podPrefixes = append(podPrefixes, fmt.Sprintf("%s-", nsnBuilder.ReplicationName()))
-		}
 		if err := ensurePVCLabels(ctx, cl, specNamespacedName.Namespace, pvcPrefixes, wandbLabels); err != nil {
 			results = append(results, metav1.Condition{
 				Type:   common.ReconciledType,
@@ -74,9 +81,24 @@ func WriteState(
 		}
 	}
 
+	if len(pvcPrefixes) > 0 {
+		results = append(results, common.ExpandVolumeClaims(ctx, cl, specNamespacedName.Namespace, common.VolumeClaimSize{
+			NamePrefixes: pvcPrefixes,
+			Size:         storageSize,
+		}))
+	}
+
 	return results
 }
 
+// claimTemplateSize is the storage request of a desired Redis volume template.
+func claimTemplateSize(storage *rediscommon.Storage) resource.Quantity {
+	if storage == nil {
+		return resource.Quantity{}
+	}
+	return storage.VolumeClaimTemplate.Spec.Resources.Requests[corev1.ResourceStorage]
+}
+
 // ensurePVCLabels patches PVCs whose names match any of the given prefixes
 // that are missing the wandb labels. The opstree operator creates PVCs via
 // StatefulSet volumeClaimTemplates named "redis-data", so PVCs are named
@@ -376,11 +398,25 @@ type redisConnInfo struct {
 	SentinelHost   string
 	SentinelPort   string
 	SentinelMaster string
+	// SslCa is the CA of a TLS-served instance; empty when it serves plaintext.
+	SslCa string
 }
 
 func (c *redisConnInfo) toURL() string {
+	var tlsQuery string
+	if c.SslCa != "" {
+		tlsQuery = "tls=true&caCertPath=" + url.QueryEscape(clientCACertPath)
+	}
 	if c.SentinelHost != "" {
-		return fmt.Sprintf("redis://%s:%s?master=%s", c.SentinelHost, c.SentinelPort, c.SentinelMaster)
+		redisURL := fmt.Sprintf("redis://%s:%s?master=%s", c.SentinelHost, c.SentinelPort, c.SentinelMaster)
+		if tlsQuery != "" {
+			redisURL += "&" + tlsQuery
+		}
+		return redisURL
+	}
+	redisURL := fmt.Sprintf("redis://%s:%s", c.Host, c.Port)
+	if tlsQuery != "" {
+		redisURL += "?" + tlsQuery
 	}
-	return fmt.Sprintf("redis://%s:%s", c.Host, c.Port)
+	return redisURL
 }
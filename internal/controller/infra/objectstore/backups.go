@@ -0,0 +1,25 @@
+package objectstore
+
+// PruneBackupsScript is an aws-cli script that deletes every backup directory
+// under a prefix except the newest few. Its arguments are $1 the endpoint URL
+// (empty for AWS), $2 the bucket, $3 the prefix and $4 how many to keep.
+// Backups are stored one directory per run, named by a UTC timestamp, so a
+// reverse lexical sort lists them newest first.
+//
+// An optional $5 names the directory of the newest backup known to be
+// complete. Newer directories are still being written, or were left by a
+// failed run; they are kept and do not count towards $4. Nothing is pruned
+// when $5 is not listed.
+const PruneBackupsScript = `set -eu
+endpoint=""
+if [ -n "$1" ]; then endpoint="--endpoint-url $1"; fi
+aws $endpoint s3api list-objects-v2 --bucket "$2" --prefix "$3" --delimiter / \
+  --query 'CommonPrefixes[].Prefix' --output text |
+  tr '\t' '\n' | grep -v '^None$' | sort -r |
+  if [ -n "${5:-}" ]; then sed -n "\\|^$5\$|,\$p"; else cat; fi |
+  tail -n +$(($4 + 1)) |
+  while read -r dir; do
+    [ -n "$dir" ] || continue
+    echo "pruning s3://$2/$dir"
+    aws $endpoint s3 rm --recursive --only-show-errors "s3://$2/$dir"
+  done`
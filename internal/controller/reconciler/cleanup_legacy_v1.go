@@ -57,6 +57,18 @@ func buildDesiredAppNames(manifest serverManifest.Manifest) map[string]bool {
 	return out
 }
 
+// buildDaemonSetAppNames returns the desired applications the manifest runs
+// as DaemonSets.
+func buildDaemonSetAppNames(manifest serverManifest.Manifest, desiredAppNames map[string]bool) map[string]bool {
+	out := make(map[string]bool)
+	for _, app := range manifest.Applications {
+		if desiredAppNames[app.Name] && app.Kind == serverManifest.ApplicationKindDaemonSet {
+			out[app.Name] = true
+		}
+	}
+	return out
+}
+
 // deploymentsHealthy reports whether every manifest-desired application's
 // Deployment is fully rolled out, plus the sorted names still blocking. It
 // reads live Deployments rather than status.wandb.applications so the legacy
@@ -87,6 +99,32 @@ func deploymentsHealthy(
 	return len(notReady) == 0, notReady
 }
 
+// daemonSetsHealthy returns the sorted names of the DaemonSets that have not
+// yet rolled their current spec out to every scheduled node.
+func daemonSetsHealthy(
+	ctx context.Context,
+	c ctrlClient.Client,
+	namespace string,
+	daemonSetAppNames map[string]bool,
+) []string {
+	var notReady []string
+	for name := range daemonSetAppNames {
+		ds := &appsv1.DaemonSet{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, ds); err != nil {
+			notReady = append(notReady, name)
+			continue
+		}
+		if ds.Status.ObservedGeneration != ds.Generation ||
+			ds.Status.UpdatedNumberScheduled != ds.Status.DesiredNumberScheduled ||
+			ds.Status.NumberReady != ds.Status.DesiredNumberScheduled ||
+			ds.Status.DesiredNumberScheduled == 0 {
+			notReady = append(notReady, name)
+		}
+	}
+	sort.Strings(notReady)
+	return notReady
+}
+
 func cleanupLegacyV1Deployments(
 	ctx context.Context,
 	c ctrlClient.Client,
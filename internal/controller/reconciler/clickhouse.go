@@ -9,8 +9,11 @@ import (
 	externalch "github.com/wandb/operator/internal/controller/infra/external/clickhouse"
 	"github.com/wandb/operator/internal/controller/infra/managed/clickhouse/altinity"
 	"github.com/wandb/operator/internal/controller/infra/managed/clickhouse/altinity/keeper"
+	"github.com/wandb/operator/internal/controller/infra/objectstore"
 	"github.com/wandb/operator/pkg/utils"
 	"github.com/wandb/operator/pkg/wandb/manifest"
+	batchv1 "k8s.io/api/batch/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
@@ -33,7 +36,7 @@ func clickHouseWriteState(
 	for key, spec := range wandb.Spec.ClickHouse {
 		switch {
 		case spec.ManagedClickHouse != nil:
-			out[key] = managedClickHouseWriteState(ctx, client, wandb, spec.ManagedClickHouse, mfst)
+			out[key] = managedClickHouseWriteState(ctx, client, wandb, key, spec.ManagedClickHouse, mfst)
 		case spec.ExternalClickHouse != nil:
 			out[key] = externalch.WriteState(ctx, client, wandb, key, spec.ExternalClickHouse)
 		}
@@ -98,6 +101,16 @@ func runClickHouseRetentionFinalizer(ctx context.Context, c client.Client, wandb
 		return clickHousePurgeFinalizer(ctx, c, wandb, key, spec)
 	case apiv2.DetachOnDelete:
 		return clickHouseDetachFinalizer(ctx, c, wandb, key, spec)
+	case apiv2.SnapshotOnDelete:
+		managed := spec.ManagedClickHouse
+		if managed == nil {
+			return clickHousePurgeFinalizer(ctx, c, wandb, key, spec)
+		}
+		return runSnapshotFinalizer(ctx, c, wandb, managed.Namespace,
+			altinity.ToClickHouseOnDeleteRule(wandb, wandb.GetRetentionPolicy(managed.ManagedInfraSpec)),
+			func() error { return clickHousePurgeFinalizer(ctx, c, wandb, key, spec) },
+			func() error { return clickHouseDetachFinalizer(ctx, c, wandb, key, spec) },
+		)
 	}
 	return nil
 }
@@ -148,6 +161,7 @@ func managedClickHouseWriteState(
 	ctx context.Context,
 	client client.Client,
 	wandb *apiv2.WeightsAndBiases,
+	key string,
 	spec *apiv2.ManagedClickHouseSpec,
 	mfst manifest.Manifest,
 ) []metav1.Condition {
@@ -239,13 +253,149 @@ func managedClickHouseWriteState(
 	if conditions := altinity.CheckDetached(ctx, client, specNamespacedName, wandb.GetUID()); conditions != nil {
 		return conditions
 	}
+	adoptionConditions, proceed := altinity.AdoptDetached(ctx, client, specNamespacedName, wandb)
+	if !proceed {
+		return adoptionConditions
+	}
+
+	if common.InfraTLSEnabled(spec.TLS) {
+		secret, conditions := ensureManagedInfraTLS(
+			ctx, client, wandb, spec.TLS, specNamespacedName, altinity.ServerDNSNames(spec), altinity.BuildWandbClickhouseLabels(wandb),
+		)
+		if conditions != nil {
+			return conditions
+		}
+		altinity.ApplyServerTLS(desired, secret)
+	}
+
+	// The servers hold the backup and restore buckets' credentials as named
+	// S3 endpoints; keep the live ones while a store is unavailable.
+	var backupTarget, restoreTarget *altinity.BackupTarget
+	var backupConditions, restoreConditions []metav1.Condition
+	if spec.Backup != nil {
+		backupTarget, backupConditions = resolveClickHouseBackupTarget(
+			ctx, client, wandb, spec, spec.Backup.ObjectStore, altinity.ClickHouseBackupType,
+		)
+	}
+	if spec.RestoreFrom != nil {
+		restoreTarget, restoreConditions = resolveClickHouseBackupTarget(
+			ctx, client, wandb, spec, spec.RestoreFrom.ObjectStore, altinity.ClickHouseRestoreType,
+		)
+	}
+	altinity.ApplyBackupEndpoints(desired, backupTarget, restoreTarget)
+	altinity.RetainBackupEndpoints(ctx, client, specNamespacedName, desired,
+		spec.Backup != nil && backupTarget == nil, spec.RestoreFrom != nil && restoreTarget == nil)
 
 	results := make([]metav1.Condition, 0)
 	results = append(results, altinity.WriteState(ctx, client, specNamespacedName, desiredServiceAccount, desiredKeeper, desired)...)
+	results = append(results, managedClickHouseWriteBackup(ctx, client, wandb, spec, specNamespacedName, backupTarget, backupConditions, mfst)...)
+	results = append(results, managedClickHouseWriteRestore(ctx, client, wandb, key, spec, specNamespacedName, restoreTarget, restoreConditions, mfst)...)
+	results = append(results, adoptionConditions...)
 
 	return results
 }
 
+// resolveClickHouseBackupTarget resolves the object store a backup or restore
+// reads and writes. When it cannot be used yet, the target is nil and the
+// returned condition of conditionType says why.
+func resolveClickHouseBackupTarget(
+	ctx context.Context,
+	client client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	spec *apiv2.ManagedClickHouseSpec,
+	instance string,
+	conditionType string,
+) (*altinity.BackupTarget, []metav1.Condition) {
+	unavailable := func(reason, message string) (*altinity.BackupTarget, []metav1.Condition) {
+		return nil, []metav1.Condition{{
+			Type:    conditionType,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: message,
+		}}
+	}
+
+	status, ok := apiv2.ResolveInstance(wandb.Status.ObjectStoreStatus, instance)
+	if !ok || !status.Ready {
+		return unavailable(altinity.ObjectStoreUnavailableReason, "waiting for the object store to become ready")
+	}
+	storage, err := objectstore.Resolve(ctx, client, spec.Namespace, &status.Connection)
+	if err != nil {
+		return unavailable(altinity.ObjectStoreUnavailableReason, err.Error())
+	}
+	target, err := altinity.ResolveBackupTarget(storage)
+	if err != nil {
+		return unavailable(altinity.UnsupportedObjectStoreReason, err.Error())
+	}
+	return target, nil
+}
+
+// managedClickHouseWriteBackup reconciles the backup CronJob. Backup problems
+// surface on the ClickHouseBackup condition and never hold up the instance.
+func managedClickHouseWriteBackup(
+	ctx context.Context,
+	client client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	spec *apiv2.ManagedClickHouseSpec,
+	specNamespacedName types.NamespacedName,
+	target *altinity.BackupTarget,
+	unavailable []metav1.Condition,
+	mfst manifest.Manifest,
+) []metav1.Condition {
+	if spec.Backup == nil {
+		return altinity.WriteBackupState(ctx, client, specNamespacedName, nil)
+	}
+	if target == nil {
+		// Leave the CronJob as it is; its runs fail until the store is back.
+		return unavailable
+	}
+	cronJob, err := altinity.ToBackupCronJob(spec, wandb, client.Scheme(), target, mfst)
+	if err != nil {
+		ctrl.LoggerFrom(ctx).Error(err, "failed to translate ClickHouse backup CronJob")
+		return []metav1.Condition{{
+			Type:   common.ReconciledType,
+			Status: metav1.ConditionFalse,
+			Reason: common.ControllerErrorReason,
+		}}
+	}
+	return altinity.WriteBackupState(ctx, client, specNamespacedName, cronJob)
+}
+
+// managedClickHouseWriteRestore runs the restoreFrom Job once for a new
+// instance. Its pending ClickHouseRestore condition keeps the instance from
+// being ready, which holds the W&B migrations back until the data is in.
+func managedClickHouseWriteRestore(
+	ctx context.Context,
+	client client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	key string,
+	spec *apiv2.ManagedClickHouseSpec,
+	specNamespacedName types.NamespacedName,
+	target *altinity.BackupTarget,
+	unavailable []metav1.Condition,
+	mfst manifest.Manifest,
+) []metav1.Condition {
+	if spec.RestoreFrom == nil {
+		return altinity.DeleteRestoreJob(ctx, client, specNamespacedName)
+	}
+	var job *batchv1.Job
+	if target != nil {
+		var err error
+		if job, err = altinity.ToRestoreJob(spec, wandb, client.Scheme(), target, mfst); err != nil {
+			ctrl.LoggerFrom(ctx).Error(err, "failed to translate ClickHouse restore Job")
+			return []metav1.Condition{{
+				Type:   common.ReconciledType,
+				Status: metav1.ConditionFalse,
+				Reason: common.ControllerErrorReason,
+			}}
+		}
+	}
+	previous := apimeta.FindStatusCondition(wandb.Status.ClickHouseStatus[key].Conditions, altinity.ClickHouseRestoreType)
+	migrated := wandb.Status.Wandb.Migration.LastSuccessVersion != ""
+	// A restore already under way or done outranks an unavailable store.
+	return append(unavailable, altinity.WriteRestoreState(ctx, client, specNamespacedName, job, previous, migrated)...)
+}
+
 func managedClickHouseReadState(
 	ctx context.Context,
 	client client.Client,
@@ -290,6 +440,10 @@ func managedClickHouseInferStatus(
 	for _, e := range events {
 		recorder.Event(wandb, e.Type, e.Reason, e.Message)
 	}
+	updatedStatus.LastBackupTime = utils.Coalesce(
+		altinity.ReadLastBackupTime(ctx, client, managedClickHouseSpecNamespacedName(wandb.Spec.ClickHouse[key].ManagedClickHouse)),
+		oldStatus.LastBackupTime,
+	)
 	wandb.Status.ClickHouseStatus[key] = updatedStatus
 	err := updateWandbStatusIfChanged(ctx, client, wandb, statusBefore)
 
@@ -309,7 +463,12 @@ func externalClickHouseInferStatus(ctx context.Context, c client.Client, wandb *
 		WBInfraStatus: apiv2.WBInfraStatus{Ready: ready, State: state, Conditions: updatedConditions},
 		Connection:    *conn,
 	}
-	return ctrl.Result{}, updateWandbStatusIfChanged(ctx, c, wandb, statusBefore)
+	// Requeue so the connectivity probe runs again even when nothing else changes.
+	var result ctrl.Result
+	if newInfraConn != nil {
+		result.RequeueAfter = external.ProbeInterval
+	}
+	return result, updateWandbStatusIfChanged(ctx, c, wandb, statusBefore)
 }
 
 // helpers
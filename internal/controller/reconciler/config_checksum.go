@@ -0,0 +1,241 @@
+package reconciler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// configChecksumAnnotation on an application's pod template hashes the
+	// Secret and ConfigMap keys its containers and volumes consume, so a
+	// changed password or config file rolls exactly the applications using it.
+	configChecksumAnnotation = "weightsandbiases.apps.wandb.com/config-checksum"
+
+	// ApplicationConfigRefsAnnotation lists, on the Application itself, the
+	// Secrets and ConfigMaps behind configChecksumAnnotation as sorted
+	// "Kind/name" entries. The WeightsAndBiases controller uses it to map a
+	// Secret or ConfigMap event back to the owning CR.
+	ApplicationConfigRefsAnnotation = "weightsandbiases.apps.wandb.com/config-refs"
+
+	configRefKindSecret    = "Secret"
+	configRefKindConfigMap = "ConfigMap"
+)
+
+// configRefs maps "Kind/name" to the keys consumed from that object. A nil
+// key set means the whole object is consumed (envFrom, or a volume without
+// items).
+type configRefs map[string]map[string]bool
+
+func (refs configRefs) add(kind, name, key string) {
+	if name == "" {
+		return
+	}
+	ref := kind + "/" + name
+	keys, seen := refs[ref]
+	if seen && keys == nil {
+		return
+	}
+	if key == "" {
+		refs[ref] = nil
+		return
+	}
+	if keys == nil {
+		keys = map[string]bool{}
+		refs[ref] = keys
+	}
+	keys[key] = true
+}
+
+func (refs configRefs) addKeyToPaths(kind, name string, items []corev1.KeyToPath) {
+	if len(items) == 0 {
+		refs.add(kind, name, "")
+		return
+	}
+	for _, item := range items {
+		refs.add(kind, name, item.Key)
+	}
+}
+
+func (refs configRefs) sortedNames() []string {
+	names := make([]string, 0, len(refs))
+	for ref := range refs {
+		names = append(names, ref)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// collectConfigRefs walks the rendered pod spec rather than the manifest, so
+// env from infra connection secrets, generated secrets, inline files and
+// proxy or legacy overrides are all covered by the same code path.
+func collectConfigRefs(podSpec corev1.PodSpec) configRefs {
+	refs := configRefs{}
+	containers := append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...)
+	for _, container := range containers {
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if sel := env.ValueFrom.SecretKeyRef; sel != nil {
+				refs.add(configRefKindSecret, sel.Name, sel.Key)
+			}
+			if sel := env.ValueFrom.ConfigMapKeyRef; sel != nil {
+				refs.add(configRefKindConfigMap, sel.Name, sel.Key)
+			}
+		}
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.SecretRef != nil {
+				refs.add(configRefKindSecret, envFrom.SecretRef.Name, "")
+			}
+			if envFrom.ConfigMapRef != nil {
+				refs.add(configRefKindConfigMap, envFrom.ConfigMapRef.Name, "")
+			}
+		}
+	}
+	for _, volume := range podSpec.Volumes {
+		switch {
+		case volume.Secret != nil:
+			refs.addKeyToPaths(configRefKindSecret, volume.Secret.SecretName, volume.Secret.Items)
+		case volume.ConfigMap != nil:
+			refs.addKeyToPaths(configRefKindConfigMap, volume.ConfigMap.Name, volume.ConfigMap.Items)
+		case volume.Projected != nil:
+			for _, source := range volume.Projected.Sources {
+				if source.Secret != nil {
+					refs.addKeyToPaths(configRefKindSecret, source.Secret.Name, source.Secret.Items)
+				}
+				if source.ConfigMap != nil {
+					refs.addKeyToPaths(configRefKindConfigMap, source.ConfigMap.Name, source.ConfigMap.Items)
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// configChecksum hashes the consumed keys of every referenced object. Missing
+// objects and keys hash as such, so creating an optional Secret later still
+// rolls the pods. It returns "" when nothing is referenced.
+func configChecksum(ctx context.Context, c ctrlClient.Client, namespace string, refs configRefs) (string, error) {
+	if len(refs) == 0 {
+		return "", nil
+	}
+	hash := sha256.New()
+	write := hashWriteString(hash)
+	for _, ref := range refs.sortedNames() {
+		write(ref + "\n")
+		kind, name, _ := strings.Cut(ref, "/")
+		data, err := configRefData(ctx, c, namespace, kind, name)
+		if err != nil {
+			return "", err
+		}
+		if data == nil {
+			write("missing\n")
+			continue
+		}
+		keys := refs[ref]
+		if keys == nil {
+			keys = make(map[string]bool, len(data))
+			for key := range data {
+				keys[key] = true
+			}
+		}
+		sorted := make([]string, 0, len(keys))
+		for key := range keys {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+		for _, key := range sorted {
+			value, ok := data[key]
+			if !ok {
+				write(fmt.Sprintf("%s:missing\n", key))
+				continue
+			}
+			write(fmt.Sprintf("%s:%d:", key, len(value)))
+			_, _ = hash.Write(value)
+			write("\n")
+		}
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// configRefData returns the object's data by key, or nil if it does not exist.
+func configRefData(ctx context.Context, c ctrlClient.Client, namespace, kind, name string) (map[string][]byte, error) {
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+	switch kind {
+	case configRefKindSecret:
+		secret := &corev1.Secret{}
+		if err := c.Get(ctx, key, secret); err != nil {
+			if apiErrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		data := make(map[string][]byte, len(secret.Data)+len(secret.StringData))
+		for k, v := range secret.Data {
+			data[k] = v
+		}
+		for k, v := range secret.StringData {
+			data[k] = []byte(v)
+		}
+		return data, nil
+	case configRefKindConfigMap:
+		configMap := &corev1.ConfigMap{}
+		if err := c.Get(ctx, key, configMap); err != nil {
+			if apiErrors.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		data := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+		for k, v := range configMap.Data {
+			data[k] = []byte(v)
+		}
+		for k, v := range configMap.BinaryData {
+			data[k] = v
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("unsupported config reference kind %q", kind)
+}
+
+// setApplicationConfigRefs records refs on the Application so Secret and
+// ConfigMap events can be mapped back to the CR that consumes them.
+func setApplicationConfigRefs(annotations map[string]string, refs configRefs) map[string]string {
+	if len(refs) == 0 {
+		delete(annotations, ApplicationConfigRefsAnnotation)
+		if len(annotations) == 0 {
+			return nil
+		}
+		return annotations
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ApplicationConfigRefsAnnotation] = strings.Join(refs.sortedNames(), ",")
+	return annotations
+}
+
+// ApplicationConsumesConfig reports whether the Application's config-refs
+// annotation lists the given Secret or ConfigMap.
+func ApplicationConsumesConfig(annotations map[string]string, kind, name string) bool {
+	value, ok := annotations[ApplicationConfigRefsAnnotation]
+	if !ok {
+		return false
+	}
+	want := kind + "/" + name
+	for _, ref := range strings.Split(value, ",") {
+		if ref == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	apiv2 "github.com/wandb/operator/api/v2"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func configChecksumPodSpec() corev1.PodSpec {
+	return corev1.PodSpec{
+		InitContainers: []corev1.Container{{
+			Name: "init",
+			EnvFrom: []corev1.EnvFromSource{{
+				ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}},
+			}},
+		}},
+		Containers: []corev1.Container{{
+			Name: "api",
+			Env: []corev1.EnvVar{
+				{Name: "PLAIN", Value: "x"},
+				{Name: "MYSQL_PASSWORD", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "mysql"}, Key: "password",
+				}}},
+			},
+		}},
+		Volumes: []corev1.Volume{{
+			Name: "files",
+			VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "files"},
+				Items:                []corev1.KeyToPath{{Key: "app.yaml", Path: "app.yaml"}},
+			}},
+		}},
+	}
+}
+
+func TestCollectConfigRefs(t *testing.T) {
+	refs := collectConfigRefs(configChecksumPodSpec())
+
+	require.Equal(t, []string{"ConfigMap/files", "ConfigMap/settings", "Secret/mysql"}, refs.sortedNames())
+	require.Equal(t, map[string]bool{"password": true}, refs["Secret/mysql"])
+	require.Equal(t, map[string]bool{"app.yaml": true}, refs["ConfigMap/files"])
+	require.Nil(t, refs["ConfigMap/settings"], "envFrom consumes the whole object")
+	require.Empty(t, collectConfigRefs(corev1.PodSpec{Containers: []corev1.Container{{Name: "plain"}}}))
+}
+
+func TestConfigChecksumTracksOnlyConsumedKeys(t *testing.T) {
+	ctx := context.Background()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "mysql", Namespace: "default"},
+		Data:       map[string][]byte{"password": []byte("one"), "username": []byte("wandb")},
+	}
+	c := fake.NewClientBuilder().WithScheme(newCleanupFixtureScheme(t)).WithObjects(secret).Build()
+	refs := collectConfigRefs(configChecksumPodSpec())
+
+	before, err := configChecksum(ctx, c, "default", refs)
+	require.NoError(t, err)
+	require.NotEmpty(t, before)
+
+	secret.Data["username"] = []byte("other")
+	require.NoError(t, c.Update(ctx, secret))
+	unchanged, err := configChecksum(ctx, c, "default", refs)
+	require.NoError(t, err)
+	require.Equal(t, before, unchanged, "keys the pods do not read must not roll them")
+
+	secret.Data["password"] = []byte("two")
+	require.NoError(t, c.Update(ctx, secret))
+	rotated, err := configChecksum(ctx, c, "default", refs)
+	require.NoError(t, err)
+	require.NotEqual(t, before, rotated)
+
+	require.NoError(t, c.Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+		Data:       map[string]string{"LOG_LEVEL": "debug"},
+	}))
+	created, err := configChecksum(ctx, c, "default", refs)
+	require.NoError(t, err)
+	require.NotEqual(t, rotated, created, "creating a referenced object rolls the pods")
+}
+
+func TestApplicationConfigRefsAnnotation(t *testing.T) {
+	refs := collectConfigRefs(configChecksumPodSpec())
+	annotations := setApplicationConfigRefs(map[string]string{"keep": "me"}, refs)
+
+	require.Equal(t, "ConfigMap/files,ConfigMap/settings,Secret/mysql", annotations[ApplicationConfigRefsAnnotation])
+	require.True(t, ApplicationConsumesConfig(annotations, "Secret", "mysql"))
+	require.False(t, ApplicationConsumesConfig(annotations, "ConfigMap", "mysql"))
+	require.False(t, ApplicationConsumesConfig(annotations, "Secret", "mysq"))
+
+	annotations = setApplicationConfigRefs(annotations, configRefs{})
+	require.Equal(t, map[string]string{"keep": "me"}, annotations)
+}
+
+func TestConfigChecksumFollowsGeneratedSecretRotation(t *testing.T) {
+	f := newGeneratedSecretsFixture(t)
+	f.setPolicy(apiv2.GeneratedSecretPolicy{GracePeriod: &metav1.Duration{Duration: time.Hour}})
+	f.manifest.CommonEnvvars = map[string][]serverManifest.EnvVar{
+		"session": {{Name: "SESSION_KEY", Sources: []serverManifest.EnvSource{{Name: "session-key", Type: "generatedSecret"}}}},
+	}
+	f.reconcile()
+	ctx := context.Background()
+	checksum := func(commonEnvs []string) string {
+		t.Helper()
+		env, err := resolveEnvvars(ctx, f.client, f.wandb, f.manifest, commonEnvs, nil)
+		require.NoError(t, err)
+		sum, err := configChecksum(ctx, f.client, "default", collectConfigRefs(corev1.PodSpec{Containers: []corev1.Container{{Name: "api", Env: env}}}))
+		require.NoError(t, err)
+		return sum
+	}
+
+	before := checksum([]string{"session"})
+	require.NotEmpty(t, before)
+	require.Empty(t, checksum(nil), "apps that do not consume a generated secret are not annotated")
+
+	sec := f.secret()
+	sec.Annotations[apiv2.RotateGeneratedSecretAnnotation] = "true"
+	require.NoError(t, f.client.Update(ctx, sec))
+	f.reconcile()
+	require.Contains(t, f.secret().Data, "previous")
+	rotated := checksum([]string{"session"})
+	require.NotEqual(t, before, rotated, "a rotation rolls the consuming workloads")
+
+	f.now = f.now.Add(time.Hour)
+	f.reconcile()
+	require.NotContains(t, f.secret().Data, "previous")
+	require.Equal(t, rotated, checksum([]string{"session"}), "dropping the previous value after the grace period must not roll anything")
+}
@@ -42,6 +42,14 @@ const (
 	redisCACertVolumeName = "redis-ca"
 	redisCACertPath       = "/etc/ssl/certs/redis_ca.pem"
 	redisCACertFileName   = "redis_ca.pem"
+
+	clickHouseCACertVolumeName = "clickhouse-ca"
+	clickHouseCACertPath       = "/etc/ssl/certs/clickhouse_ca.pem"
+	clickHouseCACertFileName   = "clickhouse_ca.pem"
+
+	kafkaCACertVolumeName = "kafka-ca"
+	kafkaCACertPath       = "/etc/ssl/certs/kafka_ca.pem"
+	kafkaCACertFileName   = "kafka_ca.pem"
 )
 
 var customCACertsEnvVars = []corev1.EnvVar{
@@ -73,6 +81,13 @@ func defaultRedisConnection(wandb *apiv2.WeightsAndBiases) apiv2.RedisConnection
 	return status.Connection
 }
 
+// defaultClickHouseConnection returns the default ClickHouse instance's
+// connection; see defaultMySQLConnection.
+func defaultClickHouseConnection(wandb *apiv2.WeightsAndBiases) apiv2.ClickHouseConnection {
+	status, _ := apiv2.ResolveInstance(wandb.Status.ClickHouseStatus, "")
+	return status.Connection
+}
+
 func secretSelectorConfigured(sel corev1.SecretKeySelector) bool {
 	return sel.Name != "" && sel.Key != ""
 }
@@ -143,6 +158,8 @@ func applyCustomCACertsToWorkload(
 ) ([]corev1.EnvVar, []corev1.Volume, []corev1.VolumeMount, string, error) {
 	mysqlConn := defaultMySQLConnection(wandb)
 	redisConn := defaultRedisConnection(wandb)
+	clickHouseConn := defaultClickHouseConnection(wandb)
+	kafkaConn := wandb.Status.KafkaStatus.Connection
 
 	if hasGlobalCustomCACertConfig(wandb) {
 		envVars = appendMissingEnvVars(envVars, customCACertsEnvVars)
@@ -259,6 +276,40 @@ func applyCustomCACertsToWorkload(
 		})
 	}
 
+	if hasValue, err := secretSelectorHasValue(ctx, c, wandb.Namespace, clickHouseConn.SslCa); err != nil {
+		return nil, nil, nil, "", err
+	} else if hasValue {
+		volumes = upsertVolume(volumes, corev1.Volume{
+			Name: clickHouseCACertVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: secretCACertVolumeSource(clickHouseConn.SslCa, clickHouseCACertFileName),
+			},
+		})
+		volumeMounts = upsertVolumeMount(volumeMounts, corev1.VolumeMount{
+			Name:      clickHouseCACertVolumeName,
+			MountPath: clickHouseCACertPath,
+			SubPath:   clickHouseCACertFileName,
+			ReadOnly:  true,
+		})
+	}
+
+	if hasValue, err := secretSelectorHasValue(ctx, c, wandb.Namespace, kafkaConn.SslCa); err != nil {
+		return nil, nil, nil, "", err
+	} else if hasValue {
+		volumes = upsertVolume(volumes, corev1.Volume{
+			Name: kafkaCACertVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: secretCACertVolumeSource(kafkaConn.SslCa, kafkaCACertFileName),
+			},
+		})
+		volumeMounts = upsertVolumeMount(volumeMounts, corev1.VolumeMount{
+			Name:      kafkaCACertVolumeName,
+			MountPath: kafkaCACertPath,
+			SubPath:   kafkaCACertFileName,
+			ReadOnly:  true,
+		})
+	}
+
 	checksum, err := customCACertsChecksum(ctx, c, wandb)
 	if err != nil {
 		return nil, nil, nil, "", err
@@ -267,12 +318,18 @@ func applyCustomCACertsToWorkload(
 }
 
 func setCustomCACertsChecksumAnnotation(podTemplate *corev1.PodTemplateSpec, checksum string) {
+	setPodTemplateChecksumAnnotation(podTemplate, customCACertsChecksumAnnotation, checksum)
+}
+
+// setPodTemplateChecksumAnnotation sets annotation to checksum, or removes it
+// when checksum is empty, so a change to the hashed inputs rolls the pods.
+func setPodTemplateChecksumAnnotation(podTemplate *corev1.PodTemplateSpec, annotation, checksum string) {
 	annotations := podTemplate.GetAnnotations()
 	if checksum == "" {
 		if annotations == nil {
 			return
 		}
-		delete(annotations, customCACertsChecksumAnnotation)
+		delete(annotations, annotation)
 		if len(annotations) == 0 {
 			annotations = nil
 		}
@@ -282,7 +339,7 @@ func setCustomCACertsChecksumAnnotation(podTemplate *corev1.PodTemplateSpec, che
 	if annotations == nil {
 		annotations = map[string]string{}
 	}
-	annotations[customCACertsChecksumAnnotation] = checksum
+	annotations[annotation] = checksum
 	podTemplate.SetAnnotations(annotations)
 }
 
@@ -360,8 +417,18 @@ func customCACertsChecksum(ctx context.Context, c ctrlClient.Client, wandb *apiv
 	if err != nil {
 		return "", err
 	}
+	clickHouseConn := defaultClickHouseConnection(wandb)
+	hasClickHouseCA, err := secretSelectorHasValue(ctx, c, wandb.Namespace, clickHouseConn.SslCa)
+	if err != nil {
+		return "", err
+	}
+	kafkaConn := wandb.Status.KafkaStatus.Connection
+	hasKafkaCA, err := secretSelectorHasValue(ctx, c, wandb.Namespace, kafkaConn.SslCa)
+	if err != nil {
+		return "", err
+	}
 
-	if !hasGlobalCustomCACertConfig(wandb) && !hasMySQLCA && !hasMySQLCert && !hasMySQLKey && !hasRedisCA {
+	if !hasGlobalCustomCACertConfig(wandb) && !hasMySQLCA && !hasMySQLCert && !hasMySQLKey && !hasRedisCA && !hasClickHouseCA && !hasKafkaCA {
 		return "", nil
 	}
 
@@ -402,6 +469,18 @@ func customCACertsChecksum(ctx context.Context, c ctrlClient.Client, wandb *apiv
 			return "", err
 		}
 	}
+	if sel := clickHouseConn.SslCa; hasClickHouseCA {
+		_, _ = fmt.Fprintf(hash, "clickhouse:%s/%s\n", sel.Name, sel.Key)
+		if err := hashSecretKeyData(ctx, c, wandb.Namespace, sel, hashWriteString(hash)); err != nil {
+			return "", err
+		}
+	}
+	if sel := kafkaConn.SslCa; hasKafkaCA {
+		_, _ = fmt.Fprintf(hash, "kafka:%s/%s\n", sel.Name, sel.Key)
+		if err := hashSecretKeyData(ctx, c, wandb.Namespace, sel, hashWriteString(hash)); err != nil {
+			return "", err
+		}
+	}
 
 	return hex.EncodeToString(hash.Sum(nil)), nil
 }
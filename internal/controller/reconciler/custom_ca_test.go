@@ -121,6 +121,15 @@ func TestApplyCustomCACertsToWorkloadAddsGlobalAndInfraMounts(t *testing.T) {
 					},
 				},
 			},
+			KafkaStatus: apiv2.KafkaInfraStatus{
+				Connection: apiv2.KafkaConnection{
+					SslCa: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "wandb-kafka-connection"},
+						Key:                  "SslCa",
+						Optional:             ptr.To(true),
+					},
+				},
+			},
 		},
 	}
 	mysqlSecret := &corev1.Secret{
@@ -135,11 +144,15 @@ func TestApplyCustomCACertsToWorkloadAddsGlobalAndInfraMounts(t *testing.T) {
 		ObjectMeta: metav1.ObjectMeta{Name: "wandb-redis-connection", Namespace: "default"},
 		Data:       map[string][]byte{"SslCa": []byte("---redis-ca---")},
 	}
+	kafkaSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "wandb-kafka-connection", Namespace: "default"},
+		Data:       map[string][]byte{"SslCa": []byte("---kafka-ca---")},
+	}
 	userCM := &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{Name: "user-ca-certs", Namespace: "default"},
 		Data:       map[string]string{"corp.crt": "---corp---"},
 	}
-	builder := customCATestClient(t, wandb, mysqlSecret, redisSecret, userCM)
+	builder := customCATestClient(t, wandb, mysqlSecret, redisSecret, kafkaSecret, userCM)
 	client := builder.Build()
 
 	envs, volumes, mounts, checksum, err := applyCustomCACertsToWorkload(context.Background(), client, wandb, nil, nil, nil)
@@ -158,6 +171,7 @@ func TestApplyCustomCACertsToWorkloadAddsGlobalAndInfraMounts(t *testing.T) {
 	requireVolume(t, volumes, mysqlSSLCertVolumeName)
 	requireVolume(t, volumes, mysqlSSLKeyVolumeName)
 	requireVolume(t, volumes, redisCACertVolumeName)
+	requireVolume(t, volumes, kafkaCACertVolumeName)
 
 	requireMount(t, mounts, customCACertsRootVolumeName, customCACertsRootMountPath)
 	requireMount(t, mounts, customCACertsInlineVolumeName, customCACertsInlineMountPath)
@@ -166,6 +180,7 @@ func TestApplyCustomCACertsToWorkloadAddsGlobalAndInfraMounts(t *testing.T) {
 	requireMount(t, mounts, mysqlSSLCertVolumeName, mysqlSSLCertPath)
 	requireMount(t, mounts, mysqlSSLKeyVolumeName, mysqlSSLKeyPath)
 	requireMount(t, mounts, redisCACertVolumeName, redisCACertPath)
+	requireMount(t, mounts, kafkaCACertVolumeName, kafkaCACertPath)
 
 	podTemplate := &corev1.PodTemplateSpec{}
 	setCustomCACertsChecksumAnnotation(podTemplate, checksum)
@@ -223,6 +238,45 @@ func TestApplyCustomCACertsToWorkloadSkipsMissingOptionalInfraKeys(t *testing.T)
 	require.Empty(t, mounts)
 }
 
+func TestApplyCustomCACertsToWorkloadMountsClickHouseCA(t *testing.T) {
+	wandb := &apiv2.WeightsAndBiases{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "wandb",
+			Namespace: "default",
+		},
+		Status: apiv2.WeightsAndBiasesStatus{
+			ClickHouseStatus: map[string]apiv2.ClickHouseInfraStatus{
+				apiv2.DefaultInstanceName: {
+					Connection: apiv2.ClickHouseConnection{
+						SslCa: corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "wandb-clickhouse-connection"},
+							Key:                  "SslCa",
+							Optional:             ptr.To(true),
+						},
+					},
+				},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "wandb-clickhouse-connection", Namespace: "default"},
+		Data:       map[string][]byte{"SslCa": []byte("clickhouse-ca")},
+	}
+	client := customCATestClient(t, wandb, secret).Build()
+
+	_, volumes, mounts, checksum, err := applyCustomCACertsToWorkload(context.Background(), client, wandb, nil, nil, nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, checksum)
+	requireVolume(t, volumes, clickHouseCACertVolumeName)
+	requireMount(t, mounts, clickHouseCACertVolumeName, clickHouseCACertPath)
+
+	secret.Data["SslCa"] = []byte("reissued-ca")
+	require.NoError(t, client.Update(context.Background(), secret))
+	_, _, _, rotated, err := applyCustomCACertsToWorkload(context.Background(), client, wandb, nil, nil, nil)
+	require.NoError(t, err)
+	require.NotEqual(t, checksum, rotated)
+}
+
 func requireContainsEnv(t *testing.T, envs []corev1.EnvVar, name, value string) {
 	t.Helper()
 	for _, env := range envs {
@@ -84,6 +84,7 @@ func reconcileGateway(ctx context.Context, c ctrlClient.Client, wandb *apiv2.Wei
 	} else {
 		desired.Spec.Listeners = buildDefaultListeners(wandb)
 	}
+	desired.Spec.Listeners = append(desired.Spec.Listeners, buildIngressHostListeners(wandb)...)
 
 	if err := controllerutil.SetOwnerReference(wandb, desired, c.Scheme()); err != nil {
 		return err
@@ -0,0 +1,218 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/logx"
+	wmetrics "github.com/wandb/operator/internal/observability/metrics"
+	oputils "github.com/wandb/operator/pkg/utils"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	generatedSecretKey         = "key"
+	generatedSecretPreviousKey = "previous"
+
+	// generatedSecretRotatedAtAnnotation records on the Secret when "key" was
+	// generated. It is the source of truth for scheduled rotation and the
+	// grace period; status.generatedSecrets[*].lastRotated mirrors it.
+	generatedSecretRotatedAtAnnotation = "weightsandbiases.apps.wandb.com/rotated-at"
+
+	defaultGeneratedSecretLength = 32
+)
+
+// generatedSecretsNow is replaced in tests.
+var generatedSecretsNow = time.Now
+
+// generateSecrets ensures the manifest-declared generated secrets exist,
+// rotates those whose policy or rotate annotation asks for it, and records
+// their selectors in status. The returned result requeues for the next
+// scheduled rotation or grace-period expiry.
+func generateSecrets(ctx context.Context, client ctrlClient.Client, wandb *apiv2.WeightsAndBiases, manifest serverManifest.Manifest) (ctrl.Result, error) {
+	statusBefore := wandb.DeepCopy().Status
+	if wandb.Status.GeneratedSecrets == nil {
+		wandb.Status.GeneratedSecrets = map[string]apiv2.GeneratedSecretStatus{}
+	}
+	now := generatedSecretsNow().Truncate(time.Second)
+	var requeueAfter time.Duration
+	for _, gs := range manifest.GeneratedSecrets {
+		// Deterministic secret name scoped to the CR instance
+		// If UseExactName is true, use the exact name without prefixing
+		secretName := gs.Name
+		if !gs.UseExactName {
+			secretName = fmt.Sprintf("%s-%s", wandb.Name, gs.Name)
+		}
+		policy := wandb.Spec.Wandb.GeneratedSecrets[gs.Name]
+
+		sec := &corev1.Secret{}
+		err := client.Get(ctx, types.NamespacedName{Name: secretName, Namespace: wandb.Namespace}, sec)
+		switch {
+		case apiErrors.IsNotFound(err):
+			value, err := generateSecretValue(gs)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			sec = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      secretName,
+					Namespace: wandb.Namespace,
+					Labels: map[string]string{
+						"app.kubernetes.io/managed-by": "wandb-operator",
+						"app.kubernetes.io/instance":   wandb.Name,
+						"app.kubernetes.io/part-of":    "wandb",
+					},
+					Annotations: map[string]string{generatedSecretRotatedAtAnnotation: now.Format(time.RFC3339)},
+				},
+				Data: map[string][]byte{generatedSecretKey: []byte(value)},
+				Type: corev1.SecretTypeOpaque,
+			}
+			if err := controllerutil.SetOwnerReference(wandb, sec, client.Scheme()); err != nil {
+				return ctrl.Result{}, err
+			}
+			if err := client.Create(ctx, sec); err != nil {
+				return ctrl.Result{}, err
+			}
+		case err != nil:
+			return ctrl.Result{}, err
+		default:
+			changed, err := rotateGeneratedSecret(ctx, sec, gs, policy, now)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if changed {
+				if err := client.Update(ctx, sec); err != nil {
+					return ctrl.Result{}, err
+				}
+			}
+		}
+
+		rotatedAt := generatedSecretRotatedAt(sec)
+		wandb.Status.GeneratedSecrets[gs.Name] = apiv2.GeneratedSecretStatus{
+			SecretKeySelector: corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  generatedSecretKey,
+			},
+			LastRotated: &metav1.Time{Time: rotatedAt},
+		}
+		if next := nextGeneratedSecretEvent(sec, policy, now); next > 0 && (requeueAfter == 0 || next < requeueAfter) {
+			requeueAfter = next
+		}
+	}
+	wmetrics.SetGeneratedSecrets(wandb.Namespace, wandb.Name, len(manifest.GeneratedSecrets))
+	// Persist status after updating generated secret selectors
+	if err := updateWandbStatusIfChanged(ctx, client, wandb, statusBefore); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func generateSecretValue(gs serverManifest.GeneratedSecret) (string, error) {
+	length := gs.Length
+	if length <= 0 {
+		length = defaultGeneratedSecretLength
+	}
+	value, err := oputils.GenerateRandomPassword(length, gs.CharacterType)
+	if err != nil {
+		return "", fmt.Errorf("generated secret %s: %w", gs.Name, err)
+	}
+	return value, nil
+}
+
+// rotateGeneratedSecret brings an existing Secret in line with its policy: it
+// fills a missing value, rotates a due or manually requested value into
+// "previous", and drops "previous" once the grace period is over. It reports
+// whether sec was modified.
+func rotateGeneratedSecret(
+	ctx context.Context,
+	sec *corev1.Secret,
+	gs serverManifest.GeneratedSecret,
+	policy apiv2.GeneratedSecretPolicy,
+	now time.Time,
+) (bool, error) {
+	logger := logx.GetSlog(ctx)
+	_, requested := sec.Annotations[apiv2.RotateGeneratedSecretAnnotation]
+	missing := len(sec.Data[generatedSecretKey]) == 0
+	due := policy.RotationInterval != nil && policy.RotationInterval.Duration > 0 &&
+		!now.Before(generatedSecretRotatedAt(sec).Add(policy.RotationInterval.Duration))
+
+	changed := false
+	if missing || requested || due {
+		value, err := generateSecretValue(gs)
+		if err != nil {
+			return false, err
+		}
+		if sec.Data == nil {
+			sec.Data = map[string][]byte{}
+		}
+		if !missing && generatedSecretGracePeriod(policy) > 0 {
+			sec.Data[generatedSecretPreviousKey] = sec.Data[generatedSecretKey]
+		} else {
+			delete(sec.Data, generatedSecretPreviousKey)
+		}
+		sec.Data[generatedSecretKey] = []byte(value)
+		if sec.Annotations == nil {
+			sec.Annotations = map[string]string{}
+		}
+		sec.Annotations[generatedSecretRotatedAtAnnotation] = now.Format(time.RFC3339)
+		delete(sec.Annotations, apiv2.RotateGeneratedSecretAnnotation)
+		if !missing {
+			logger.Info("Rotated generated secret", "secret", sec.Name, "requested", requested, "scheduled", due)
+		}
+		return true, nil
+	}
+
+	if _, ok := sec.Data[generatedSecretPreviousKey]; ok &&
+		!now.Before(generatedSecretRotatedAt(sec).Add(generatedSecretGracePeriod(policy))) {
+		delete(sec.Data, generatedSecretPreviousKey)
+		logger.Info("Dropped previous generated secret value after grace period", "secret", sec.Name)
+		changed = true
+	}
+	return changed, nil
+}
+
+// generatedSecretRotatedAt falls back to the creation time for Secrets
+// generated before rotation was tracked.
+func generatedSecretRotatedAt(sec *corev1.Secret) time.Time {
+	if value, ok := sec.Annotations[generatedSecretRotatedAtAnnotation]; ok {
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return t
+		}
+	}
+	return sec.CreationTimestamp.Time
+}
+
+func generatedSecretGracePeriod(policy apiv2.GeneratedSecretPolicy) time.Duration {
+	if policy.GracePeriod == nil {
+		return apiv2.DefaultGeneratedSecretGracePeriod
+	}
+	return policy.GracePeriod.Duration
+}
+
+// nextGeneratedSecretEvent returns how long until sec next needs attention:
+// its scheduled rotation or the end of its grace period. Zero means never.
+func nextGeneratedSecretEvent(sec *corev1.Secret, policy apiv2.GeneratedSecretPolicy, now time.Time) time.Duration {
+	rotatedAt := generatedSecretRotatedAt(sec)
+	var next time.Duration
+	consider := func(at time.Time) {
+		if d := at.Sub(now); d > 0 && (next == 0 || d < next) {
+			next = d
+		}
+	}
+	if policy.RotationInterval != nil && policy.RotationInterval.Duration > 0 {
+		consider(rotatedAt.Add(policy.RotationInterval.Duration))
+	}
+	if _, ok := sec.Data[generatedSecretPreviousKey]; ok {
+		consider(rotatedAt.Add(generatedSecretGracePeriod(policy)))
+	}
+	return next
+}
@@ -0,0 +1,125 @@
+package reconciler
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	apiv2 "github.com/wandb/operator/api/v2"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type generatedSecretsFixture struct {
+	t        *testing.T
+	client   ctrlClient.Client
+	wandb    *apiv2.WeightsAndBiases
+	manifest serverManifest.Manifest
+	now      time.Time
+}
+
+func newGeneratedSecretsFixture(t *testing.T) *generatedSecretsFixture {
+	t.Helper()
+	f := &generatedSecretsFixture{
+		t:     t,
+		wandb: &apiv2.WeightsAndBiases{ObjectMeta: metav1.ObjectMeta{Name: "wandb", Namespace: "default", UID: "uid"}},
+		manifest: serverManifest.Manifest{GeneratedSecrets: []serverManifest.GeneratedSecret{
+			{Name: "session-key", Length: 16, CharacterType: "hex"},
+		}},
+		now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	f.client = fake.NewClientBuilder().WithScheme(newCleanupFixtureScheme(t)).
+		WithObjects(f.wandb.DeepCopy()).WithStatusSubresource(&apiv2.WeightsAndBiases{}).Build()
+	require.NoError(t, f.client.Get(context.Background(), ctrlClient.ObjectKeyFromObject(f.wandb), f.wandb))
+	generatedSecretsNow = func() time.Time { return f.now }
+	t.Cleanup(func() { generatedSecretsNow = time.Now })
+	return f
+}
+
+func (f *generatedSecretsFixture) setPolicy(policy apiv2.GeneratedSecretPolicy) {
+	f.t.Helper()
+	f.wandb.Spec.Wandb.GeneratedSecrets = map[string]apiv2.GeneratedSecretPolicy{"session-key": policy}
+	require.NoError(f.t, f.client.Update(context.Background(), f.wandb))
+}
+
+func (f *generatedSecretsFixture) reconcile() time.Duration {
+	f.t.Helper()
+	result, err := generateSecrets(context.Background(), f.client, f.wandb, f.manifest)
+	require.NoError(f.t, err)
+	return result.RequeueAfter
+}
+
+func (f *generatedSecretsFixture) secret() *corev1.Secret {
+	f.t.Helper()
+	sec := &corev1.Secret{}
+	require.NoError(f.t, f.client.Get(context.Background(), types.NamespacedName{Name: "wandb-session-key", Namespace: "default"}, sec))
+	return sec
+}
+
+func TestGenerateSecretsCreatesWithCharacterType(t *testing.T) {
+	f := newGeneratedSecretsFixture(t)
+	require.Zero(t, f.reconcile(), "no policy means nothing to wake up for")
+
+	sec := f.secret()
+	require.Regexp(t, regexp.MustCompile(`^[0-9a-f]{16}$`), string(sec.Data["key"]))
+	require.NotContains(t, sec.Data, "previous")
+	status := f.wandb.Status.GeneratedSecrets["session-key"]
+	require.Equal(t, "wandb-session-key", status.Name)
+	require.Equal(t, "key", status.Key)
+	require.True(t, status.LastRotated.Time.Equal(f.now))
+}
+
+func TestGenerateSecretsRotatesOnIntervalAndKeepsPrevious(t *testing.T) {
+	f := newGeneratedSecretsFixture(t)
+	f.setPolicy(apiv2.GeneratedSecretPolicy{
+		RotationInterval: &metav1.Duration{Duration: 30 * 24 * time.Hour},
+		GracePeriod:      &metav1.Duration{Duration: time.Hour},
+	})
+	require.Equal(t, 30*24*time.Hour, f.reconcile())
+	original := f.secret().Data["key"]
+
+	f.now = f.now.Add(30 * 24 * time.Hour)
+	require.Equal(t, time.Hour, f.reconcile(), "the grace period ends before the next rotation")
+	sec := f.secret()
+	require.NotEqual(t, original, sec.Data["key"])
+	require.Equal(t, original, sec.Data["previous"])
+	require.True(t, f.wandb.Status.GeneratedSecrets["session-key"].LastRotated.Time.Equal(f.now))
+
+	f.now = f.now.Add(time.Hour)
+	require.Equal(t, 30*24*time.Hour-time.Hour, f.reconcile())
+	require.NotContains(t, f.secret().Data, "previous")
+}
+
+func TestGenerateSecretsRotatesOnAnnotation(t *testing.T) {
+	f := newGeneratedSecretsFixture(t)
+	f.reconcile()
+	sec := f.secret()
+	original := sec.Data["key"]
+	sec.Annotations[apiv2.RotateGeneratedSecretAnnotation] = "true"
+	require.NoError(t, f.client.Update(context.Background(), sec))
+
+	f.now = f.now.Add(time.Minute)
+	require.Equal(t, apiv2.DefaultGeneratedSecretGracePeriod, f.reconcile())
+	sec = f.secret()
+	require.NotEqual(t, original, sec.Data["key"])
+	require.Equal(t, original, sec.Data["previous"])
+	require.NotContains(t, sec.Annotations, apiv2.RotateGeneratedSecretAnnotation)
+}
+
+func TestGenerateSecretsZeroGracePeriodDropsPreviousImmediately(t *testing.T) {
+	f := newGeneratedSecretsFixture(t)
+	f.setPolicy(apiv2.GeneratedSecretPolicy{GracePeriod: &metav1.Duration{}})
+	f.reconcile()
+	sec := f.secret()
+	sec.Annotations[apiv2.RotateGeneratedSecretAnnotation] = ""
+	require.NoError(t, f.client.Update(context.Background(), sec))
+
+	require.Zero(t, f.reconcile())
+	require.NotContains(t, f.secret().Data, "previous")
+}
@@ -3,6 +3,7 @@ package reconciler
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	gkeGatewayApiNetworkingv1 "github.com/GoogleCloudPlatform/gke-gateway-api/apis/networking/v1"
@@ -35,6 +36,9 @@ type infraRouteEntry struct {
 	healthCheckPath string
 	healthCheckPort int32
 	ingress         *serverManifest.AppIngressSpec
+	// routingKey selects the spec.networking.gatewayAPI.routing entry; it is
+	// the route name without the CR name prefix.
+	routingKey string
 }
 
 const infraHTTPRouteComponent = "infra-route"
@@ -61,6 +65,7 @@ func resolveInfraRoutes(ctx context.Context, c ctrlClient.Client, wandb *apiv2.W
 			}
 			entries = append(entries, infraRouteEntry{
 				name:            fmt.Sprintf("%s-bucket-%s", wandb.Name, infraRouteInstanceName(crKey, instanceName)),
+				routingKey:      fmt.Sprintf("bucket-%s", infraRouteInstanceName(crKey, instanceName)),
 				namespace:       objectStoreSpec.Namespace,
 				serviceName:     svcName,
 				servicePort:     port,
@@ -95,6 +100,7 @@ func resolveInfraRoutes(ctx context.Context, c ctrlClient.Client, wandb *apiv2.W
 			}
 			entries = append(entries, infraRouteEntry{
 				name:            fmt.Sprintf("%s-clickhouse-%s", wandb.Name, infraRouteInstanceName(crKey, instanceName)),
+				routingKey:      fmt.Sprintf("clickhouse-%s", infraRouteInstanceName(crKey, instanceName)),
 				namespace:       chSpec.Namespace,
 				serviceName:     svcName,
 				servicePort:     port,
@@ -183,7 +189,9 @@ func reconcileInfraHTTPRoutes(
 		desiredNames[infraHTTPRouteKey(entry.namespace, entry.name).String()] = true
 
 		parentRef := buildInfraGatewayParentRef(ref, gwConfig, entry.namespace)
-		route := buildInfraHTTPRoute(wandb, parentRef, hostnames, entry)
+		routeHostnames := slices.Concat(hostnames, ingressHostHostnames(wandb, entry.routingKey))
+		route := buildInfraHTTPRoute(wandb, parentRef, routeHostnames, entry)
+		route.Spec.ParentRefs = append(route.Spec.ParentRefs, ingressHostParentRefs(wandb, parentRef, entry.routingKey)...)
 
 		httpRoute := &gatewayv1.HTTPRoute{
 			ObjectMeta: metav1.ObjectMeta{
@@ -270,34 +278,15 @@ func buildInfraHTTPRoute(
 	hostnames []gatewayv1.Hostname,
 	entry infraRouteEntry,
 ) *gatewayv1.HTTPRoute {
-	paths := []string{"/"}
-	if entry.ingress != nil && len(entry.ingress.Paths) > 0 {
-		paths = entry.ingress.Paths
+	backend := common.HTTPRouteBackend{
+		ServiceName: entry.serviceName,
+		ServicePort: &entry.servicePort,
 	}
-
-	var matches []gatewayv1.HTTPRouteMatch
-	for _, p := range paths {
-		p := p
-		matchType := gatewayv1.PathMatchPathPrefix
-		if entry.ingress != nil && entry.ingress.PathType == "Exact" {
-			matchType = gatewayv1.PathMatchExact
-		}
-		matches = append(matches, gatewayv1.HTTPRouteMatch{
-			Path: &gatewayv1.HTTPPathMatch{
-				Type:  &matchType,
-				Value: &p,
-			},
-		})
-	}
-
-	backendRef := gatewayv1.HTTPBackendRef{
-		BackendRef: gatewayv1.BackendRef{
-			BackendObjectReference: gatewayv1.BackendObjectReference{
-				Name: gatewayv1.ObjectName(entry.serviceName),
-				Port: &entry.servicePort,
-			},
-		},
+	if entry.ingress != nil {
+		backend.Paths = entry.ingress.Paths
+		backend.PathType = entry.ingress.PathType
 	}
+	routing := gatewayRouting(wandb, entry.routingKey)
 
 	return &gatewayv1.HTTPRoute{
 		ObjectMeta: metav1.ObjectMeta{
@@ -310,10 +299,7 @@ func buildInfraHTTPRoute(
 				ParentRefs: []gatewayv1.ParentReference{parentRef},
 			},
 			Hostnames: hostnames,
-			Rules: []gatewayv1.HTTPRouteRule{{
-				Matches:     matches,
-				BackendRefs: []gatewayv1.HTTPBackendRef{backendRef},
-			}},
+			Rules:     common.BuildHTTPRouteRules(backend, routing),
 		},
 	}
 }
@@ -460,3 +446,17 @@ func setInfraHTTPRouteOwnership(
 	}
 	return controllerutil.SetOwnerReference(wandb, route, scheme)
 }
+
+// gatewayRouting returns a copy of the spec.networking.gatewayAPI.routing
+// entry for key, or nil when there is none.
+func gatewayRouting(wandb *apiv2.WeightsAndBiases, key string) *apiv2.GatewayRouting {
+	gwConfig := wandb.Spec.Networking.GatewayAPI
+	if gwConfig == nil {
+		return nil
+	}
+	routing, ok := gwConfig.Routing[key]
+	if !ok {
+		return nil
+	}
+	return routing.DeepCopy()
+}
@@ -0,0 +1,55 @@
+package reconciler
+
+import (
+	"context"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ensureManagedInfraTLS issues the server certificate for a managed instance
+// with tls enabled. It returns the certificate Secret once it is ready; until
+// then, or on failure, it returns the conditions to report instead of writing
+// the vendor CR, so the instance never comes up serving plaintext.
+func ensureManagedInfraTLS(
+	ctx context.Context,
+	c client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	tls *apiv2.InfraTLSSpec,
+	instance types.NamespacedName,
+	dnsNames []string,
+	labels map[string]string,
+) (*corev1.Secret, []metav1.Condition) {
+	log := ctrl.LoggerFrom(ctx)
+
+	secretName := types.NamespacedName{Name: common.InfraTLSSecretName(instance.Name), Namespace: instance.Namespace}
+	secret, err := common.EnsureInfraTLSSecret(ctx, c, wandb, tls, secretName, dnsNames, labels)
+	if err != nil {
+		log.Error(err, "failed to issue server certificate", "secret", secretName)
+		return nil, []metav1.Condition{
+			{
+				Type:    common.ReconciledType,
+				Status:  metav1.ConditionFalse,
+				Reason:  common.ApiErrorReason,
+				Message: err.Error(),
+			},
+		}
+	}
+	if secret == nil {
+		log.Info("waiting for server certificate to be issued", "secret", secretName)
+		return nil, []metav1.Condition{
+			{
+				Type:    common.ReconciledType,
+				Status:  metav1.ConditionFalse,
+				Reason:  common.PendingCreateReason,
+				Message: "waiting for server certificate " + secretName.Name,
+			},
+		}
+	}
+	return secret, nil
+}
@@ -28,7 +28,7 @@ func reconcileConsolidatedIngress(ctx context.Context, c ctrlClient.Client, wand
 	ingressName := consolidatedIngressName(wandb)
 	hostname := parseHostname(wandb.Spec.Wandb.Hostname)
 
-	var paths []networkingv1.HTTPIngressPath
+	var backends []ingressBackend
 
 	for _, app := range sortedManifestApplications(manifest) {
 		if len(app.Features) > 0 && !manifest.FeaturesEnabled(app.Features) {
@@ -55,8 +55,9 @@ func reconcileConsolidatedIngress(ctx context.Context, c ctrlClient.Client, wand
 		serviceName := app.Name
 		servicePort := resolveIngressServicePort(app)
 
+		backend := ingressBackend{key: app.Name}
 		for _, p := range appPaths {
-			paths = append(paths, networkingv1.HTTPIngressPath{
+			backend.paths = append(backend.paths, networkingv1.HTTPIngressPath{
 				Path:     p,
 				PathType: &pathType,
 				Backend: networkingv1.IngressBackend{
@@ -67,6 +68,7 @@ func reconcileConsolidatedIngress(ctx context.Context, c ctrlClient.Client, wand
 				},
 			})
 		}
+		backends = append(backends, backend)
 	}
 
 	infraRoutes, err := resolveInfraRoutes(ctx, c, wandb, manifest)
@@ -78,47 +80,86 @@ func reconcileConsolidatedIngress(ctx context.Context, c ctrlClient.Client, wand
 			continue
 		}
 		pathType := networkingv1.PathType(route.ingress.PathType)
-		paths = append(paths, networkingv1.HTTPIngressPath{
-			Path:     route.ingress.Paths[0],
-			PathType: &pathType,
-			Backend: networkingv1.IngressBackend{
-				Service: &networkingv1.IngressServiceBackend{
-					Name: route.serviceName,
-					Port: networkingv1.ServiceBackendPort{
-						Number: route.servicePort,
+		backends = append(backends, ingressBackend{
+			key: route.routingKey,
+			paths: []networkingv1.HTTPIngressPath{{
+				Path:     route.ingress.Paths[0],
+				PathType: &pathType,
+				Backend: networkingv1.IngressBackend{
+					Service: &networkingv1.IngressServiceBackend{
+						Name: route.serviceName,
+						Port: networkingv1.ServiceBackendPort{
+							Number: route.servicePort,
+						},
 					},
 				},
-			},
+			}},
 		})
 	}
 
+	var paths []networkingv1.HTTPIngressPath
+	for _, backend := range backends {
+		paths = append(paths, backend.paths...)
+	}
 	if len(paths) == 0 {
 		return nil
 	}
 
-	rules := []networkingv1.IngressRule{{
-		Host: hostname,
-		IngressRuleValue: networkingv1.IngressRuleValue{
-			HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths},
-		},
-	}}
+	allHosts := []string{hostname}
+	allHosts = append(allHosts, wandb.Spec.Wandb.AdditionalHostnames...)
 
-	for _, additionalHost := range wandb.Spec.Wandb.AdditionalHostnames {
-		rules = append(rules, networkingv1.IngressRule{
-			Host: additionalHost,
-			IngressRuleValue: networkingv1.IngressRuleValue{
-				HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths},
-			},
-		})
+	var ingressClassName *string
+	if wandb.Spec.Networking.Ingress != nil {
+		ingressClassName = wandb.Spec.Networking.Ingress.IngressClassName
+	}
+	var tlsSecretName string
+	if wandb.Spec.Networking.TLS != nil {
+		tlsSecretName = wandb.Spec.Networking.TLS.SecretName
+	}
+
+	desired := buildIngress(wandb, ingressName, allHosts, paths, ingressClassName, tlsSecretName,
+		ingressAnnotations(wandb.Spec.Networking.Annotations, wandb.Spec.Networking.TLS))
+	desired.Labels = map[string]string{
+		"app.kubernetes.io/managed-by": "wandb-operator",
+		"app.kubernetes.io/instance":   wandb.Name,
+	}
+
+	current, err := applyIngress(ctx, c, wandb, desired)
+	if err != nil {
+		return err
+	}
+	summary := summarizeIngressStatus(current)
+	for _, host := range allHosts {
+		summary.Hosts = append(summary.Hosts, ingressHostStatus(host, current, tlsSecretName, len(paths)))
 	}
 
+	hostStatuses, err := reconcileIngressHosts(ctx, c, wandb, backends)
+	if err != nil {
+		return err
+	}
+	summary.Hosts = append(summary.Hosts, hostStatuses...)
+	wandb.Status.IngressStatus = summary
+	return nil
+}
+
+// ingressBackend is one application's, or infrastructure route's, paths on the
+// consolidated Ingress. key is the name spec.networking.hosts[].applications
+// selects it by.
+type ingressBackend struct {
+	key   string
+	paths []networkingv1.HTTPIngressPath
+}
+
+// ingressAnnotations merges the configured annotations with the cert-manager
+// issuer annotations for tls.
+func ingressAnnotations(base map[string]string, tls *apiv2.TLSConfig) map[string]string {
 	annotations := map[string]string{}
-	for k, v := range wandb.Spec.Networking.Annotations {
+	for k, v := range base {
 		annotations[k] = v
 	}
 
-	if wandb.Spec.Networking.TLS != nil && wandb.Spec.Networking.TLS.CertManager != nil {
-		cm := wandb.Spec.Networking.TLS.CertManager
+	if tls != nil && tls.CertManager != nil {
+		cm := tls.CertManager
 		if cm.ClusterIssuer != "" {
 			annotations["cert-manager.io/cluster-issuer"] = cm.ClusterIssuer
 		}
@@ -126,73 +167,94 @@ func reconcileConsolidatedIngress(ctx context.Context, c ctrlClient.Client, wand
 			annotations["cert-manager.io/issuer"] = cm.Issuer
 		}
 	}
+	return annotations
+}
+
+// buildIngress renders an Ingress routing paths for every host, terminating
+// TLS with tlsSecretName when it is set.
+func buildIngress(
+	wandb *apiv2.WeightsAndBiases,
+	name string,
+	hosts []string,
+	paths []networkingv1.HTTPIngressPath,
+	ingressClassName *string,
+	tlsSecretName string,
+	annotations map[string]string,
+) *networkingv1.Ingress {
+	var rules []networkingv1.IngressRule
+	for _, host := range hosts {
+		rules = append(rules, networkingv1.IngressRule{
+			Host: host,
+			IngressRuleValue: networkingv1.IngressRuleValue{
+				HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths},
+			},
+		})
+	}
 
 	desired := &networkingv1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      ingressName,
-			Namespace: wandb.Namespace,
-			Labels: map[string]string{
-				"app.kubernetes.io/managed-by": "wandb-operator",
-				"app.kubernetes.io/instance":   wandb.Name,
-			},
+			Name:        name,
+			Namespace:   wandb.Namespace,
 			Annotations: annotations,
 		},
 		Spec: networkingv1.IngressSpec{
-			Rules: rules,
+			Rules:            rules,
+			IngressClassName: ingressClassName,
 		},
 	}
 
-	if wandb.Spec.Networking.Ingress != nil {
-		desired.Spec.IngressClassName = wandb.Spec.Networking.Ingress.IngressClassName
-		if *desired.Spec.IngressClassName == "nginx" {
-			desired.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"] = "0"
-		}
+	if ingressClassName != nil && *ingressClassName == "nginx" {
+		desired.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"] = "0"
 	}
 
-	if wandb.Spec.Networking.TLS != nil && wandb.Spec.Networking.TLS.SecretName != "" {
-		allHosts := []string{hostname}
-		allHosts = append(allHosts, wandb.Spec.Wandb.AdditionalHostnames...)
+	if tlsSecretName != "" {
 		desired.Spec.TLS = []networkingv1.IngressTLS{{
-			Hosts:      allHosts,
-			SecretName: wandb.Spec.Networking.TLS.SecretName,
+			Hosts:      hosts,
+			SecretName: tlsSecretName,
 		}}
 	}
+	return desired
+}
 
+// applyIngress creates or updates desired and returns the Ingress as last
+// observed, so its load balancer status can be reported.
+func applyIngress(ctx context.Context, c ctrlClient.Client, wandb *apiv2.WeightsAndBiases, desired *networkingv1.Ingress) (*networkingv1.Ingress, error) {
 	if err := controllerutil.SetOwnerReference(wandb, desired, c.Scheme()); err != nil {
-		return err
+		return nil, err
 	}
 
 	current := &networkingv1.Ingress{}
-	err = c.Get(ctx, types.NamespacedName{Name: ingressName, Namespace: wandb.Namespace}, current)
+	err := c.Get(ctx, types.NamespacedName{Name: desired.Name, Namespace: desired.Namespace}, current)
 	if err != nil {
 		if apiErrors.IsNotFound(err) {
 			if err := c.Create(ctx, desired); err != nil {
-				return err
+				return nil, err
 			}
-			wandb.Status.IngressStatus = summarizeIngressStatus(desired)
-			return nil
+			return desired, nil
 		}
-		return err
+		return nil, err
 	}
 
 	desired.ResourceVersion = current.ResourceVersion
 	if err := c.Update(ctx, desired); err != nil {
-		return err
+		return nil, err
 	}
-	wandb.Status.IngressStatus = summarizeIngressStatus(current)
-	return nil
+	return current, nil
 }
 
 func deleteConsolidatedIngress(ctx context.Context, c ctrlClient.Client, wandb *apiv2.WeightsAndBiases) error {
 	ingressName := consolidatedIngressName(wandb)
 	ingress := &networkingv1.Ingress{}
-	if err := c.Get(ctx, types.NamespacedName{Name: ingressName, Namespace: wandb.Namespace}, ingress); err != nil {
-		if apiErrors.IsNotFound(err) {
-			return nil
+	err := c.Get(ctx, types.NamespacedName{Name: ingressName, Namespace: wandb.Namespace}, ingress)
+	switch {
+	case err == nil:
+		if err := c.Delete(ctx, ingress); err != nil && !apiErrors.IsNotFound(err) {
+			return err
 		}
+	case !apiErrors.IsNotFound(err):
 		return err
 	}
-	return c.Delete(ctx, ingress)
+	return deleteStaleIngressHosts(ctx, c, wandb, nil)
 }
 
 func resolveIngressServicePort(app serverManifest.Application) networkingv1.ServiceBackendPort {
@@ -0,0 +1,231 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/internal/logx"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ingressHostComponent labels the per-host Ingresses rendered for
+// spec.networking.hosts so removed hosts can be cleaned up.
+const ingressHostComponent = "ingress-host"
+
+// ingressHostKey names a host's resources: spec.networking.hosts[].name, or
+// the hostname with dots replaced by dashes.
+func ingressHostKey(host apiv2.IngressHost) string {
+	if host.Name != "" {
+		return host.Name
+	}
+	return strings.ReplaceAll(host.Hostname, ".", "-")
+}
+
+func ingressHostIngressName(wandb *apiv2.WeightsAndBiases, host apiv2.IngressHost) string {
+	return fmt.Sprintf("%s-%s", consolidatedIngressName(wandb), ingressHostKey(host))
+}
+
+// ingressHostTLSSecretName returns the certificate secret a host is served
+// with. A cert-manager issuer without a secretName issues into a secret named
+// after the host's Ingress.
+func ingressHostTLSSecretName(wandb *apiv2.WeightsAndBiases, host apiv2.IngressHost) string {
+	if host.TLS == nil {
+		return ""
+	}
+	if host.TLS.SecretName != "" {
+		return host.TLS.SecretName
+	}
+	if host.TLS.CertManager != nil {
+		return ingressHostIngressName(wandb, host) + "-tls"
+	}
+	return ""
+}
+
+func ingressHostListenerName(host apiv2.IngressHost) gatewayv1.SectionName {
+	return gatewayv1.SectionName("host-" + ingressHostKey(host))
+}
+
+// ingressHostServes reports whether host routes the application or
+// infrastructure route named key.
+func ingressHostServes(host apiv2.IngressHost, key string) bool {
+	return len(host.Applications) == 0 || slices.Contains(host.Applications, key)
+}
+
+// ingressHostPaths filters the consolidated Ingress's paths down to the ones
+// host serves.
+func ingressHostPaths(host apiv2.IngressHost, backends []ingressBackend) []networkingv1.HTTPIngressPath {
+	var paths []networkingv1.HTTPIngressPath
+	for _, backend := range backends {
+		if !ingressHostServes(host, backend.key) {
+			continue
+		}
+		for _, p := range backend.paths {
+			if len(host.Paths) > 0 && !slices.ContainsFunc(host.Paths, func(prefix string) bool {
+				return strings.HasPrefix(p.Path, prefix)
+			}) {
+				continue
+			}
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+func ingressHostLabels(wandb *apiv2.WeightsAndBiases) map[string]string {
+	labels := common.BuildWandbLabels(wandb, ingressHostComponent)
+	labels["app.kubernetes.io/managed-by"] = "wandb-operator"
+	labels["app.kubernetes.io/instance"] = wandb.Name
+	return labels
+}
+
+// reconcileIngressHosts renders one Ingress per spec.networking.hosts entry,
+// each with its own class, annotations and certificate, and removes the
+// Ingresses of hosts that are gone. Hosts left with no paths after filtering
+// get no Ingress.
+func reconcileIngressHosts(
+	ctx context.Context,
+	c ctrlClient.Client,
+	wandb *apiv2.WeightsAndBiases,
+	backends []ingressBackend,
+) ([]apiv2.IngressHostStatus, error) {
+	logger := logx.GetSlog(ctx)
+
+	var statuses []apiv2.IngressHostStatus
+	desiredNames := map[string]bool{}
+	for _, host := range wandb.Spec.Networking.Hosts {
+		paths := ingressHostPaths(host, backends)
+		if len(paths) == 0 {
+			logger.Info("No paths left for ingress host, skipping", "hostname", host.Hostname)
+			continue
+		}
+
+		name := ingressHostIngressName(wandb, host)
+		desiredNames[name] = true
+
+		ingressClassName := host.IngressClassName
+		if ingressClassName == nil && wandb.Spec.Networking.Ingress != nil {
+			ingressClassName = wandb.Spec.Networking.Ingress.IngressClassName
+		}
+		annotations := ingressAnnotations(wandb.Spec.Networking.Annotations, host.TLS)
+		for k, v := range host.Annotations {
+			annotations[k] = v
+		}
+		tlsSecretName := ingressHostTLSSecretName(wandb, host)
+
+		desired := buildIngress(wandb, name, []string{host.Hostname}, paths, ingressClassName, tlsSecretName, annotations)
+		desired.Labels = ingressHostLabels(wandb)
+
+		current, err := applyIngress(ctx, c, wandb, desired)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply Ingress for host %s: %w", host.Hostname, err)
+		}
+		statuses = append(statuses, ingressHostStatus(host.Hostname, current, tlsSecretName, len(paths)))
+	}
+
+	if err := deleteStaleIngressHosts(ctx, c, wandb, desiredNames); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// deleteStaleIngressHosts deletes the per-host Ingresses not in desiredNames.
+func deleteStaleIngressHosts(ctx context.Context, c ctrlClient.Client, wandb *apiv2.WeightsAndBiases, desiredNames map[string]bool) error {
+	ingressList := &networkingv1.IngressList{}
+	if err := c.List(ctx, ingressList,
+		ctrlClient.InNamespace(wandb.Namespace),
+		ctrlClient.MatchingLabels(common.BuildWandbLabels(wandb, ingressHostComponent)),
+	); err != nil {
+		return fmt.Errorf("failed to list ingress host Ingresses: %w", err)
+	}
+	for i := range ingressList.Items {
+		ingress := &ingressList.Items[i]
+		if desiredNames[ingress.Name] || !isOwnedBy(ingress, wandb) {
+			continue
+		}
+		if err := c.Delete(ctx, ingress); err != nil && !apiErrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete stale Ingress %s: %w", ingress.Name, err)
+		}
+	}
+	return nil
+}
+
+func ingressHostStatus(hostname string, ingress *networkingv1.Ingress, tlsSecretName string, paths int) apiv2.IngressHostStatus {
+	return apiv2.IngressHostStatus{
+		Hostname:            hostname,
+		Ingress:             ingress.Name,
+		TLSSecretName:       tlsSecretName,
+		Paths:               int32(paths),
+		LoadBalancerIngress: summarizeIngressStatus(ingress).LoadBalancerIngress,
+	}
+}
+
+// buildIngressHostListeners renders a listener per spec.networking.hosts
+// entry for the managed Gateway: HTTPS on 443 with the host's certificate, or
+// HTTP on 80 for hosts without TLS.
+func buildIngressHostListeners(wandb *apiv2.WeightsAndBiases) []gatewayv1.Listener {
+	var listeners []gatewayv1.Listener
+	for _, host := range wandb.Spec.Networking.Hosts {
+		hostname := gatewayv1.Hostname(host.Hostname)
+		listener := gatewayv1.Listener{
+			Name:          ingressHostListenerName(host),
+			Hostname:      &hostname,
+			Port:          gatewayv1.PortNumber(80),
+			Protocol:      gatewayv1.HTTPProtocolType,
+			AllowedRoutes: buildAllowedRoutes(wandb),
+		}
+		if secretName := ingressHostTLSSecretName(wandb, host); secretName != "" {
+			mode := gatewayv1.TLSModeTerminate
+			listener.Port = gatewayv1.PortNumber(443)
+			listener.Protocol = gatewayv1.HTTPSProtocolType
+			listener.TLS = &gatewayv1.ListenerTLSConfig{
+				Mode: &mode,
+				CertificateRefs: []gatewayv1.SecretObjectReference{{
+					Name: gatewayv1.ObjectName(secretName),
+				}},
+			}
+		}
+		listeners = append(listeners, listener)
+	}
+	return listeners
+}
+
+// ingressHostHostnames lists the spec.networking.hosts hostnames an HTTPRoute
+// for the application or infrastructure route named key must match.
+func ingressHostHostnames(wandb *apiv2.WeightsAndBiases, key string) []gatewayv1.Hostname {
+	var hostnames []gatewayv1.Hostname
+	for _, host := range wandb.Spec.Networking.Hosts {
+		if ingressHostServes(host, key) {
+			hostnames = append(hostnames, gatewayv1.Hostname(host.Hostname))
+		}
+	}
+	return hostnames
+}
+
+// ingressHostParentRefs attaches an HTTPRoute to the host listeners of the
+// managed Gateway. Only needed when parentRef pins a listener through
+// gatewayAPI.listenerName; otherwise the route already attaches to every
+// listener whose hostname it matches.
+func ingressHostParentRefs(wandb *apiv2.WeightsAndBiases, parentRef gatewayv1.ParentReference, key string) []gatewayv1.ParentReference {
+	gwConfig := wandb.Spec.Networking.GatewayAPI
+	if parentRef.SectionName == nil || gwConfig == nil || !gwConfig.Gateway.Managed {
+		return nil
+	}
+	var refs []gatewayv1.ParentReference
+	for _, host := range wandb.Spec.Networking.Hosts {
+		if !ingressHostServes(host, key) {
+			continue
+		}
+		ref := parentRef
+		sectionName := ingressHostListenerName(host)
+		ref.SectionName = &sectionName
+		refs = append(refs, ref)
+	}
+	return refs
+}
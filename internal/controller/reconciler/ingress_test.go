@@ -11,10 +11,18 @@ You may obtain a copy of the License at
 package reconciler
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	apiv2 "github.com/wandb/operator/api/v2"
 )
@@ -49,3 +57,152 @@ func TestConsolidatedIngressName_EmptyOverrideFallsBack(t *testing.T) {
 	}
 	require.Equal(t, "wandb", consolidatedIngressName(wandb))
 }
+
+func ingressHostTestBackends() []ingressBackend {
+	path := func(p, service string) networkingv1.HTTPIngressPath {
+		return networkingv1.HTTPIngressPath{
+			Path:     p,
+			PathType: ptr.To(networkingv1.PathTypePrefix),
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: service, Port: networkingv1.ServiceBackendPort{Number: 8080}},
+			},
+		}
+	}
+	return []ingressBackend{
+		{key: "api", paths: []networkingv1.HTTPIngressPath{path("/graphql", "api"), path("/files", "api")}},
+		{key: "frontend", paths: []networkingv1.HTTPIngressPath{path("/", "frontend")}},
+		{key: "bucket-default", paths: []networkingv1.HTTPIngressPath{path("/bucket", "seaweedfs")}},
+	}
+}
+
+func TestIngressHostPaths_FiltersApplicationsAndPaths(t *testing.T) {
+	backends := ingressHostTestBackends()
+	pathsOf := func(host apiv2.IngressHost) []string {
+		var out []string
+		for _, p := range ingressHostPaths(host, backends) {
+			out = append(out, p.Path)
+		}
+		return out
+	}
+
+	require.Equal(t, []string{"/graphql", "/files", "/", "/bucket"}, pathsOf(apiv2.IngressHost{Hostname: "a.example.com"}))
+	require.Equal(t, []string{"/graphql", "/files", "/bucket"},
+		pathsOf(apiv2.IngressHost{Hostname: "a.example.com", Applications: []string{"api", "bucket-default"}}))
+	require.Equal(t, []string{"/graphql"},
+		pathsOf(apiv2.IngressHost{Hostname: "a.example.com", Applications: []string{"api"}, Paths: []string{"/graph"}}))
+	require.Empty(t, pathsOf(apiv2.IngressHost{Hostname: "a.example.com", Applications: []string{"weave"}}))
+}
+
+func TestReconcileIngressHosts_RendersPerHostIngressesAndPrunesStale(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, apiv2.AddToScheme(scheme))
+	require.NoError(t, networkingv1.AddToScheme(scheme))
+
+	wandb := newWandbForGateway()
+	wandb.Spec.Networking = apiv2.NetworkingSpec{
+		Mode:        apiv2.NetworkingModeIngress,
+		Annotations: map[string]string{"shared": "yes"},
+		Ingress:     &apiv2.IngressConfig{IngressClassName: ptr.To("nginx")},
+		Hosts: []apiv2.IngressHost{
+			{
+				Hostname:         "wandb.partner.com",
+				TLS:              &apiv2.TLSConfig{CertManager: &apiv2.CertManagerConfig{ClusterIssuer: "letsencrypt"}},
+				Annotations:      map[string]string{"shared": "overridden"},
+				IngressClassName: ptr.To("external"),
+				Applications:     []string{"api"},
+			},
+			{Hostname: "wandb.other.com", Name: "other", TLS: &apiv2.TLSConfig{SecretName: "other-cert"}},
+			{Hostname: "weave.other.com", Applications: []string{"weave"}},
+		},
+	}
+
+	stale := &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+		Name:      "wandb-removed-example-com",
+		Namespace: wandb.Namespace,
+		Labels:    ingressHostLabels(wandb),
+		OwnerReferences: []metav1.OwnerReference{{
+			APIVersion: "apps.wandb.com/v2", Kind: "WeightsAndBiases", Name: wandb.Name, UID: wandb.UID,
+		}},
+	}}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(stale).Build()
+
+	statuses, err := reconcileIngressHosts(context.Background(), c, wandb, ingressHostTestBackends())
+	require.NoError(t, err)
+	require.Len(t, statuses, 2, "a host with no matching paths gets no Ingress")
+
+	partner := &networkingv1.Ingress{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "wandb-wandb-partner-com"}, partner))
+	require.Equal(t, "external", *partner.Spec.IngressClassName)
+	require.Equal(t, "letsencrypt", partner.Annotations["cert-manager.io/cluster-issuer"])
+	require.Equal(t, "overridden", partner.Annotations["shared"])
+	require.Len(t, partner.Spec.Rules, 1)
+	require.Equal(t, "wandb.partner.com", partner.Spec.Rules[0].Host)
+	require.Len(t, partner.Spec.Rules[0].HTTP.Paths, 2)
+	require.Equal(t, []networkingv1.IngressTLS{{Hosts: []string{"wandb.partner.com"}, SecretName: "wandb-wandb-partner-com-tls"}}, partner.Spec.TLS)
+
+	other := &networkingv1.Ingress{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: "wandb-other"}, other))
+	require.Equal(t, "nginx", *other.Spec.IngressClassName)
+	require.Equal(t, "0", other.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"])
+	require.Equal(t, "other-cert", other.Spec.TLS[0].SecretName)
+
+	require.Equal(t, apiv2.IngressHostStatus{
+		Hostname:      "wandb.partner.com",
+		Ingress:       "wandb-wandb-partner-com",
+		TLSSecretName: "wandb-wandb-partner-com-tls",
+		Paths:         2,
+	}, statuses[0])
+	require.Equal(t, int32(4), statuses[1].Paths)
+
+	err = c.Get(context.Background(), types.NamespacedName{Namespace: "default", Name: stale.Name}, &networkingv1.Ingress{})
+	require.True(t, apiErrors.IsNotFound(err), "stale host Ingress should be deleted, got %v", err)
+
+	require.NoError(t, deleteConsolidatedIngress(context.Background(), c, wandb))
+	remaining := &networkingv1.IngressList{}
+	require.NoError(t, c.List(context.Background(), remaining))
+	require.Empty(t, remaining.Items)
+}
+
+func TestBuildIngressHostListeners(t *testing.T) {
+	wandb := newWandbForGateway()
+	wandb.Spec.Networking.Hosts = []apiv2.IngressHost{
+		{Hostname: "wandb.partner.com", TLS: &apiv2.TLSConfig{SecretName: "partner-cert"}},
+		{Hostname: "wandb.plain.com", Name: "plain"},
+	}
+
+	listeners := buildIngressHostListeners(wandb)
+	require.Len(t, listeners, 2)
+
+	require.Equal(t, gatewayv1.SectionName("host-wandb-partner-com"), listeners[0].Name)
+	require.Equal(t, gatewayv1.Hostname("wandb.partner.com"), *listeners[0].Hostname)
+	require.Equal(t, gatewayv1.HTTPSProtocolType, listeners[0].Protocol)
+	require.Equal(t, gatewayv1.PortNumber(443), listeners[0].Port)
+	require.Equal(t, gatewayv1.ObjectName("partner-cert"), listeners[0].TLS.CertificateRefs[0].Name)
+
+	require.Equal(t, gatewayv1.SectionName("host-plain"), listeners[1].Name)
+	require.Equal(t, gatewayv1.HTTPProtocolType, listeners[1].Protocol)
+	require.Equal(t, gatewayv1.PortNumber(80), listeners[1].Port)
+	require.Nil(t, listeners[1].TLS)
+}
+
+func TestIngressHostParentRefs_OnlyForPinnedManagedListeners(t *testing.T) {
+	wandb := newWandbForGateway()
+	wandb.Spec.Networking.GatewayAPI = &apiv2.GatewayAPIConfig{Gateway: apiv2.GatewayConfig{Managed: true}}
+	wandb.Spec.Networking.Hosts = []apiv2.IngressHost{
+		{Hostname: "wandb.partner.com", Applications: []string{"api"}},
+		{Hostname: "weave.partner.com", Applications: []string{"weave"}},
+	}
+	parentRef := gatewayv1.ParentReference{Name: "wandb-gateway"}
+
+	require.Empty(t, ingressHostParentRefs(wandb, parentRef, "api"), "unpinned routes attach by hostname")
+	require.Equal(t, []gatewayv1.Hostname{"wandb.partner.com"}, ingressHostHostnames(wandb, "api"))
+
+	parentRef.SectionName = ptr.To(gatewayv1.SectionName("https"))
+	refs := ingressHostParentRefs(wandb, parentRef, "api")
+	require.Len(t, refs, 1)
+	require.Equal(t, gatewayv1.SectionName("host-wandb-partner-com"), *refs[0].SectionName)
+	require.Equal(t, gatewayv1.SectionName("https"), *parentRef.SectionName, "the pinned ref is left untouched")
+
+	wandb.Spec.Networking.GatewayAPI.Gateway.Managed = false
+	require.Empty(t, ingressHostParentRefs(wandb, parentRef, "api"))
+}
@@ -2,16 +2,25 @@ package reconciler
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/twmb/franz-go/pkg/kadm"
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
 	apiv2 "github.com/wandb/operator/api/v2"
 	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/internal/controller/infra/external"
+	externalkafka "github.com/wandb/operator/internal/controller/infra/external/kafka"
 	"github.com/wandb/operator/internal/controller/infra/managed/kafka/bufstream"
 	"github.com/wandb/operator/internal/logx"
+	wmetrics "github.com/wandb/operator/internal/observability/metrics"
 	"github.com/wandb/operator/pkg/utils"
 	"github.com/wandb/operator/pkg/wandb/manifest"
 	corev1 "k8s.io/api/core/v1"
@@ -28,8 +37,11 @@ func kafkaWriteState(
 	wandb *apiv2.WeightsAndBiases,
 	mfst manifest.Manifest,
 ) []metav1.Condition {
-	if wandb.Spec.Kafka.ManagedKafka != nil {
+	switch {
+	case wandb.Spec.Kafka.ManagedKafka != nil:
 		return managedKafkaWriteState(ctx, client, wandb, mfst)
+	case wandb.Spec.Kafka.ExternalKafka != nil:
+		return externalkafka.WriteState(ctx, client, wandb, wandb.Spec.Kafka.ExternalKafka)
 	}
 	return nil
 }
@@ -40,8 +52,11 @@ func kafkaReadState(
 	wandb *apiv2.WeightsAndBiases,
 	newConditions []metav1.Condition,
 ) ([]metav1.Condition, *apiv2.KafkaConnection) {
-	if wandb.Spec.Kafka.ManagedKafka != nil {
+	switch {
+	case wandb.Spec.Kafka.ManagedKafka != nil:
 		return managedKafkaReadState(ctx, client, wandb, newConditions)
+	case wandb.Spec.Kafka.ExternalKafka != nil:
+		return externalkafka.ReadState(ctx, client, wandb, newConditions)
 	}
 	return newConditions, nil
 }
@@ -54,8 +69,11 @@ func kafkaInferStatus(
 	newConditions []metav1.Condition,
 	newInfraConn *apiv2.KafkaConnection,
 ) (ctrl.Result, error) {
-	if wandb.Spec.Kafka.ManagedKafka != nil {
+	switch {
+	case wandb.Spec.Kafka.ManagedKafka != nil:
 		return managedKafkaInferStatus(ctx, client, recorder, wandb, newConditions, newInfraConn)
+	case wandb.Spec.Kafka.ExternalKafka != nil:
+		return externalKafkaInferStatus(ctx, client, wandb, newConditions, newInfraConn)
 	}
 	return ctrl.Result{}, nil
 }
@@ -70,9 +88,37 @@ func kafkaPurgeFinalizer(
 		onDeleteRule := bufstream.ToKafkaOnDeleteRule(wandb, wandb.GetRetentionPolicy(spec.ManagedInfraSpec))
 		return bufstream.PurgeFinalizer(ctx, client, specNamespacedName, onDeleteRule)
 	}
+	if wandb.Spec.Kafka.ExternalKafka != nil {
+		return externalkafka.DeleteConnectionSecret(ctx, client, wandb)
+	}
 	return nil
 }
 
+// kafkaInfraSpec returns the retention settings for the configured Kafka; an
+// external Kafka has none of its own, so the global policy applies.
+func kafkaInfraSpec(spec apiv2.KafkaSpec) apiv2.ManagedInfraSpec {
+	if spec.ManagedKafka != nil {
+		return spec.ManagedKafka.ManagedInfraSpec
+	}
+	return apiv2.ManagedInfraSpec{}
+}
+
+func kafkaSnapshotFinalizer(
+	ctx context.Context,
+	client client.Client,
+	wandb *apiv2.WeightsAndBiases,
+) error {
+	spec := wandb.Spec.Kafka.ManagedKafka
+	if spec == nil {
+		return kafkaPurgeFinalizer(ctx, client, wandb)
+	}
+	return runSnapshotFinalizer(ctx, client, wandb, spec.Namespace,
+		bufstream.ToKafkaOnDeleteRule(wandb, wandb.GetRetentionPolicy(spec.ManagedInfraSpec)),
+		func() error { return kafkaPurgeFinalizer(ctx, client, wandb) },
+		func() error { return kafkaDetachFinalizer(ctx, client, wandb) },
+	)
+}
+
 func kafkaDetachFinalizer(
 	ctx context.Context,
 	client client.Client,
@@ -100,8 +146,12 @@ func managedKafkaWriteState(
 	if conditions := bufstream.CheckDetached(ctx, client, specNamespacedName, wandb.GetUID(), spec.Replicas); conditions != nil {
 		return conditions
 	}
+	adoptionConditions, proceed := bufstream.AdoptDetached(ctx, client, specNamespacedName, wandb)
+	if !proceed {
+		return adoptionConditions
+	}
 
-	return bufstream.WriteState(ctx, client, wandb, mfst)
+	return append(bufstream.WriteState(ctx, client, wandb, mfst), adoptionConditions...)
 }
 
 func managedKafkaReadState(
@@ -149,6 +199,22 @@ func managedKafkaInferStatus(
 	return ctrlResult, err
 }
 
+// external
+
+func externalKafkaInferStatus(ctx context.Context, c client.Client, wandb *apiv2.WeightsAndBiases, newConditions []metav1.Condition, newInfraConn *apiv2.KafkaConnection) (ctrl.Result, error) {
+	statusBefore := wandb.DeepCopy().Status
+	oldStatus := wandb.Status.KafkaStatus
+	oldInfraConn := oldStatus.Connection
+	state, ready, updatedConditions := external.InferExternalStatus(oldStatus.Conditions, newConditions, wandb.Generation, newInfraConn != nil)
+	conn := utils.Coalesce(newInfraConn, &oldInfraConn)
+
+	wandb.Status.KafkaStatus = apiv2.KafkaInfraStatus{
+		WBInfraStatus: apiv2.WBInfraStatus{Ready: ready, State: state, Conditions: updatedConditions},
+		Connection:    *conn,
+	}
+	return ctrl.Result{}, updateWandbStatusIfChanged(ctx, c, wandb, statusBefore)
+}
+
 // helpers
 
 func managedKafkaSpecNamespacedName(spec *apiv2.ManagedKafkaSpec) types.NamespacedName {
@@ -162,27 +228,32 @@ func managedKafkaSpecNamespacedName(spec *apiv2.ManagedKafkaSpec) types.Namespac
 // Admin API. Bufstream is Kafka-protocol compatible, so topic creation is
 // idempotent: an already-existing topic is treated as success.
 func createKafkaTopics(ctx context.Context, cl client.Client, wandb *apiv2.WeightsAndBiases, manifest manifest.Manifest) (ctrl.Result, error) {
-	if wandb.Spec.Kafka.ManagedKafka == nil {
+	if wandb.Spec.Kafka.ManagedKafka == nil && wandb.Spec.Kafka.ExternalKafka == nil {
 		return ctrl.Result{}, nil
 	}
 	log := logx.GetSlog(ctx)
 
-	bootstrap, err := resolveKafkaBootstrap(ctx, cl, wandb)
+	conn, err := resolveKafkaBootstrap(ctx, cl, wandb)
 	if err != nil {
 		log.Error("failed to resolve kafka bootstrap endpoint", logx.ErrAttr(err))
 		return ctrl.Result{RequeueAfter: defaultRequeueDuration}, nil
 	}
 
-	kafkaSpec := wandb.Spec.Kafka.ManagedKafka
-	replicationFactor := int16(1)
-	if kafkaSpec.Config.ReplicationConfig.DefaultReplicationFactor > 0 {
-		replicationFactor = int16(kafkaSpec.Config.ReplicationConfig.DefaultReplicationFactor)
+	// -1 defers to the external cluster's default.replication.factor.
+	replicationFactor := int16(-1)
+	if kafkaSpec := wandb.Spec.Kafka.ManagedKafka; kafkaSpec != nil {
+		replicationFactor = 1
+		if kafkaSpec.Config.ReplicationConfig.DefaultReplicationFactor > 0 {
+			replicationFactor = int16(kafkaSpec.Config.ReplicationConfig.DefaultReplicationFactor)
+		}
 	}
 
-	adminClient, err := kgo.NewClient(
-		kgo.SeedBrokers(bootstrap),
-		kgo.ClientID("wandb-operator"),
-	)
+	opts, err := conn.clientOpts()
+	if err != nil {
+		log.Error("failed to configure kafka client", logx.ErrAttr(err))
+		return ctrl.Result{RequeueAfter: defaultRequeueDuration}, nil
+	}
+	adminClient, err := kgo.NewClient(opts...)
 	if err != nil {
 		log.Error("failed to create kafka client", logx.ErrAttr(err))
 		return ctrl.Result{RequeueAfter: defaultRequeueDuration}, nil
@@ -214,6 +285,7 @@ func createKafkaTopics(ctx context.Context, cl client.Client, wandb *apiv2.Weigh
 
 		if err := createTopicIdempotent(dialCtx, admin, topicName, partitions, replicationFactor); err != nil {
 			log.Error("failed to create kafka topic", logx.ErrAttr(err), "topic", topicName)
+			wmetrics.RecordKafkaTopicError(wandb.Namespace, wandb.Name, topicName)
 			return ctrl.Result{RequeueAfter: defaultRequeueDuration}, nil
 		}
 		log.Debug("ensured kafka topic", "topic", topicName, "partitions", partitions)
@@ -235,33 +307,102 @@ func createTopicIdempotent(ctx context.Context, admin *kadm.Client, topicName st
 	return nil
 }
 
-// resolveKafkaBootstrap reads the managed Kafka connection secret to obtain the
-// in-cluster broker host:port used by the admin client.
-func resolveKafkaBootstrap(ctx context.Context, cl client.Client, wandb *apiv2.WeightsAndBiases) (string, error) {
+// kafkaClientConnection is what the operator itself needs to talk to the
+// brokers, read back from the Kafka connection secret.
+type kafkaClientConnection struct {
+	Brokers       []string
+	SaslMechanism string
+	Username      string
+	Password      string
+	TLS           bool
+	CACert        []byte
+}
+
+// authenticated reports whether clients need more than the broker address.
+func (c *kafkaClientConnection) authenticated() bool {
+	return c.SaslMechanism != "" || c.TLS
+}
+
+func (c *kafkaClientConnection) clientOpts() ([]kgo.Opt, error) {
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(c.Brokers...),
+		kgo.ClientID("wandb-operator"),
+	}
+
+	if c.TLS {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if len(c.CACert) > 0 {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(c.CACert) {
+				return nil, fmt.Errorf("kafka CA certificate contains no PEM certificates")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		opts = append(opts, kgo.DialTLSConfig(tlsConfig))
+	}
+
+	switch c.SaslMechanism {
+	case "":
+	case externalkafka.SaslPlain:
+		opts = append(opts, kgo.SASL(plain.Auth{User: c.Username, Pass: c.Password}.AsMechanism()))
+	case externalkafka.SaslScramSha256:
+		opts = append(opts, kgo.SASL(scram.Auth{User: c.Username, Pass: c.Password}.AsSha256Mechanism()))
+	case externalkafka.SaslScramSha512:
+		opts = append(opts, kgo.SASL(scram.Auth{User: c.Username, Pass: c.Password}.AsSha512Mechanism()))
+	default:
+		return nil, fmt.Errorf("unsupported kafka SASL mechanism %q", c.SaslMechanism)
+	}
+	return opts, nil
+}
+
+// resolveKafkaBootstrap reads the Kafka connection secret recorded in status
+// to obtain the brokers and credentials used by the operator's own clients.
+// Managed Kafka writes it next to the broker; external Kafka writes it in the
+// CR's namespace.
+func resolveKafkaBootstrap(ctx context.Context, cl client.Client, wandb *apiv2.WeightsAndBiases) (*kafkaClientConnection, error) {
 	conn := wandb.Status.KafkaStatus.Connection
 	secretName := conn.Host.Name
 	if secretName == "" {
-		return "", fmt.Errorf("kafka connection secret not set in status")
+		return nil, fmt.Errorf("kafka connection secret not set in status")
 	}
 
-	spec := wandb.Spec.Kafka.ManagedKafka
+	namespace := wandb.Namespace
+	if spec := wandb.Spec.Kafka.ManagedKafka; spec != nil {
+		namespace = spec.Namespace
+	}
 	secret := &corev1.Secret{}
 	found, err := common.GetResource(
 		ctx, cl,
-		types.NamespacedName{Namespace: spec.Namespace, Name: secretName},
+		types.NamespacedName{Namespace: namespace, Name: secretName},
 		"Secret", secret,
 	)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	if !found {
-		return "", fmt.Errorf("kafka connection secret %s not found", secretName)
+		return nil, fmt.Errorf("kafka connection secret %s not found", secretName)
 	}
 
+	result := &kafkaClientConnection{
+		SaslMechanism: string(secret.Data["SaslMechanism"]),
+		Username:      string(secret.Data["Username"]),
+		Password:      string(secret.Data["Password"]),
+		CACert:        secret.Data["SslCa"],
+	}
+	result.TLS, _ = strconv.ParseBool(string(secret.Data["Tls"]))
+	if len(result.CACert) > 0 {
+		result.TLS = true
+	}
+
+	if brokers := string(secret.Data["BrokerEndpoint"]); brokers != "" {
+		result.Brokers = strings.Split(brokers, ",")
+		return result, nil
+	}
 	host := string(secret.Data["Host"])
 	port := string(secret.Data["Port"])
 	if host == "" || port == "" {
-		return "", fmt.Errorf("kafka connection secret missing host/port")
+		return nil, fmt.Errorf("kafka connection secret missing host/port")
 	}
-	return fmt.Sprintf("%s:%s", host, port), nil
+	result.Brokers = []string{fmt.Sprintf("%s:%s", host, port)}
+	return result, nil
 }
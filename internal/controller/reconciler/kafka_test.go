@@ -0,0 +1,142 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	externalkafka "github.com/wandb/operator/internal/controller/infra/external/kafka"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func kafkaConnSel(key string) corev1.SecretKeySelector {
+	return corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: externalkafka.ConnectionSecretName},
+		Key:                  key,
+	}
+}
+
+func externalKafkaWandb() *apiv2.WeightsAndBiases {
+	return &apiv2.WeightsAndBiases{
+		ObjectMeta: metav1.ObjectMeta{Name: "wandb", Namespace: "default"},
+		Spec: apiv2.WeightsAndBiasesSpec{
+			Kafka: apiv2.KafkaSpec{ExternalKafka: &apiv2.KafkaConnection{}},
+		},
+		Status: apiv2.WeightsAndBiasesStatus{
+			KafkaStatus: apiv2.KafkaInfraStatus{Connection: apiv2.KafkaConnection{
+				URL:            kafkaConnSel("url"),
+				Host:           kafkaConnSel("Host"),
+				Port:           kafkaConnSel("Port"),
+				BrokerEndpoint: kafkaConnSel("BrokerEndpoint"),
+				SaslMechanism:  kafkaConnSel("SaslMechanism"),
+				Username:       kafkaConnSel("Username"),
+				Password:       kafkaConnSel("Password"),
+				Tls:            kafkaConnSel("Tls"),
+				SslCa:          kafkaConnSel("SslCa"),
+			}},
+		},
+	}
+}
+
+func TestResolveKafkaBootstrapExternalConnection(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed adding corev1 to scheme: %v", err)
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: externalkafka.ConnectionSecretName, Namespace: "default"},
+		Data: map[string][]byte{
+			"Host":           []byte("b-1.msk.example.com"),
+			"Port":           []byte("9096"),
+			"BrokerEndpoint": []byte("b-1.msk.example.com:9096,b-2.msk.example.com:9096"),
+			"SaslMechanism":  []byte(externalkafka.SaslScramSha512),
+			"Username":       []byte("wandb"),
+			"Password":       []byte("s3cret"),
+			"Tls":            []byte("true"),
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+
+	conn, err := resolveKafkaBootstrap(context.Background(), client, externalKafkaWandb())
+	if err != nil {
+		t.Fatalf("resolveKafkaBootstrap returned error: %v", err)
+	}
+	if len(conn.Brokers) != 2 || conn.Brokers[1] != "b-2.msk.example.com:9096" {
+		t.Fatalf("expected both bootstrap brokers, got %v", conn.Brokers)
+	}
+	if !conn.authenticated() {
+		t.Fatalf("expected SASL over TLS connection to be authenticated")
+	}
+	opts, err := conn.clientOpts()
+	if err != nil {
+		t.Fatalf("clientOpts returned error: %v", err)
+	}
+	// seed brokers, client ID, TLS dialer and SASL
+	if len(opts) != 4 {
+		t.Fatalf("expected 4 client options, got %d", len(opts))
+	}
+
+	conn.CACert = []byte("not a certificate")
+	if _, err := conn.clientOpts(); err == nil {
+		t.Fatalf("expected an error for a CA without PEM certificates")
+	}
+}
+
+func resolveKafkaEnv(t *testing.T, wandb *apiv2.WeightsAndBiases, field string) (corev1.EnvVar, bool) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed adding corev1 to scheme: %v", err)
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	envs := []serverManifest.EnvVar{
+		{Name: "KAFKA_TEST", Sources: []serverManifest.EnvSource{{Type: "kafka", Field: field}}},
+	}
+	resolved, err := resolveEnvvars(context.Background(), client, wandb, serverManifest.Manifest{}, nil, envs)
+	if err != nil {
+		t.Fatalf("resolveEnvvars returned error: %v", err)
+	}
+	for _, env := range resolved {
+		if env.Name == "KAFKA_TEST" {
+			return env, true
+		}
+	}
+	return corev1.EnvVar{}, false
+}
+
+func TestResolveEnvvarsKafkaCredentialFields(t *testing.T) {
+	wandb := externalKafkaWandb()
+	for _, tc := range []struct {
+		field string
+		key   string
+	}{
+		{"", "url"},
+		{"host", "Host"},
+		{"brokers", "BrokerEndpoint"},
+		{"sasl-mechanism", "SaslMechanism"},
+		{"username", "Username"},
+		{"password", "Password"},
+		{"tls", "Tls"},
+	} {
+		t.Run(tc.field, func(t *testing.T) {
+			env, found := resolveKafkaEnv(t, wandb, tc.field)
+			if !found || env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+				t.Fatalf("expected a secret-backed env var for field %q, got %+v", tc.field, env)
+			}
+			if got := env.ValueFrom.SecretKeyRef.Key; got != tc.key {
+				t.Errorf("expected key %q, got %q", tc.key, got)
+			}
+		})
+	}
+
+	// Managed Kafka publishes no credentials, so the env var is dropped.
+	wandb.Status.KafkaStatus.Connection.Username = corev1.SecretKeySelector{}
+	if env, found := resolveKafkaEnv(t, wandb, "username"); found {
+		t.Fatalf("expected no env var without a published username, got %+v", env)
+	}
+}
@@ -0,0 +1,95 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// resolveManifestVerifier loads the public keys referenced by
+// spec.wandb.manifestVerification, or returns nil when verification is off.
+// A missing object or key, or a key that does not parse, wraps
+// ErrManifestUntrusted: the manifest cannot be trusted without its keys, and
+// the caller reports that the same way as a bad signature.
+func resolveManifestVerifier(
+	ctx context.Context,
+	c ctrlClient.Client,
+	wandb *apiv2.WeightsAndBiases,
+) (*serverManifest.SignatureVerifier, error) {
+	spec := wandb.Spec.Wandb.ManifestVerification
+	if spec == nil {
+		return nil, nil
+	}
+
+	verifier := &serverManifest.SignatureVerifier{}
+	for _, source := range spec.PublicKeys {
+		data, ref, err := readManifestPublicKey(ctx, c, wandb.Namespace, source)
+		if err != nil {
+			return nil, err
+		}
+		keys, err := serverManifest.ParsePublicKeys(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", serverManifest.ErrManifestUntrusted, ref, err)
+		}
+		verifier.PublicKeys = append(verifier.PublicKeys, keys...)
+	}
+	if len(verifier.PublicKeys) == 0 {
+		return nil, fmt.Errorf("%w: no public keys configured", serverManifest.ErrManifestUntrusted)
+	}
+	return verifier, nil
+}
+
+// readManifestPublicKey returns the referenced key data and a description of
+// where it came from for error messages.
+func readManifestPublicKey(
+	ctx context.Context,
+	c ctrlClient.Client,
+	namespace string,
+	source apiv2.ManifestPublicKeySource,
+) ([]byte, string, error) {
+	var (
+		obj  ctrlClient.Object
+		name string
+		key  string
+		ref  string
+	)
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		obj, name, key = &corev1.ConfigMap{}, source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Key
+		ref = fmt.Sprintf("configmap %s/%s key %s", namespace, name, key)
+	case source.SecretKeyRef != nil:
+		obj, name, key = &corev1.Secret{}, source.SecretKeyRef.Name, source.SecretKeyRef.Key
+		ref = fmt.Sprintf("secret %s/%s key %s", namespace, name, key)
+	default:
+		return nil, "", fmt.Errorf("%w: public key source has no reference", serverManifest.ErrManifestUntrusted)
+	}
+
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, ref, fmt.Errorf("%w: %s not found", serverManifest.ErrManifestUntrusted, ref)
+		}
+		return nil, ref, fmt.Errorf("read %s: %w", ref, err)
+	}
+
+	var data []byte
+	switch o := obj.(type) {
+	case *corev1.ConfigMap:
+		if value, ok := o.Data[key]; ok {
+			data = []byte(value)
+		} else {
+			data = o.BinaryData[key]
+		}
+	case *corev1.Secret:
+		data = o.Data[key]
+	}
+	if len(data) == 0 {
+		return nil, ref, fmt.Errorf("%w: %s is missing or empty", serverManifest.ErrManifestUntrusted, ref)
+	}
+	return data, ref, nil
+}
@@ -0,0 +1,125 @@
+package reconciler
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
+	corev1 "k8s.io/api/core/v1"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func manifestVerificationWandb(sources ...apiv2.ManifestPublicKeySource) *apiv2.WeightsAndBiases {
+	wandb := &apiv2.WeightsAndBiases{
+		ObjectMeta: metav1.ObjectMeta{Name: "wandb", Namespace: "default"},
+	}
+	wandb.Spec.Wandb.Version = "0.80.0"
+	wandb.Spec.Wandb.ManifestVerification = &apiv2.ManifestVerificationSpec{PublicKeys: sources}
+	return wandb
+}
+
+func testPublicKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(key.Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func TestResolveManifestVerifierReadsConfigMapAndSecretKeys(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "default"},
+			Data:       map[string]string{"cosign.pub": string(testPublicKeyPEM(t))},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "keys", Namespace: "default"},
+			Data:       map[string][]byte{"cosign.pub": testPublicKeyPEM(t), "garbage": []byte("not a key")},
+		},
+	).Build()
+	configMapKey := func(key string) apiv2.ManifestPublicKeySource {
+		return apiv2.ManifestPublicKeySource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "keys"}, Key: key,
+		}}
+	}
+	secretKey := func(key string) apiv2.ManifestPublicKeySource {
+		return apiv2.ManifestPublicKeySource{SecretKeyRef: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "keys"}, Key: key,
+		}}
+	}
+
+	verifier, err := resolveManifestVerifier(context.Background(), c,
+		manifestVerificationWandb(configMapKey("cosign.pub"), secretKey("cosign.pub")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(verifier.PublicKeys) != 2 {
+		t.Fatalf("expected keys from both sources, got %d", len(verifier.PublicKeys))
+	}
+
+	for name, source := range map[string]apiv2.ManifestPublicKeySource{
+		"missing key":      configMapKey("other.pub"),
+		"unparseable key":  secretKey("garbage"),
+		"missing object":   {ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "gone"}, Key: "cosign.pub"}},
+		"empty key source": {},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := resolveManifestVerifier(context.Background(), c, manifestVerificationWandb(source))
+			if !errors.Is(err, serverManifest.ErrManifestUntrusted) {
+				t.Fatalf("expected ErrManifestUntrusted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReportManifestUntrustedBlocksReady(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apiv2.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	wandb := manifestVerificationWandb()
+	wandb.Status.Ready = true
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&apiv2.WeightsAndBiases{}).
+		WithObjects(wandb).
+		Build()
+	recorder := record.NewFakeRecorder(1)
+
+	if err := reportManifestUntrusted(context.Background(), c, recorder, wandb, serverManifest.ErrManifestUntrusted); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := &apiv2.WeightsAndBiases{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(wandb), actual); err != nil {
+		t.Fatal(err)
+	}
+	condition := apimeta.FindStatusCondition(actual.Status.Conditions, readyConditionType)
+	if actual.Status.Ready || condition == nil || condition.Reason != manifestUntrustedReason {
+		t.Fatalf("expected Ready=False/%s, got ready=%v condition=%#v", manifestUntrustedReason, actual.Status.Ready, condition)
+	}
+	if event := <-recorder.Events; event == "" {
+		t.Fatal("expected a warning event")
+	}
+}
@@ -3,6 +3,7 @@ package reconciler
 import (
 	"context"
 	"fmt"
+	"time"
 
 	mocov1beta2 "github.com/cybozu-go/moco/api/v1beta2"
 	apiv2 "github.com/wandb/operator/api/v2"
@@ -10,6 +11,7 @@ import (
 	"github.com/wandb/operator/internal/controller/infra/external"
 	externalmysql "github.com/wandb/operator/internal/controller/infra/external/mysql"
 	"github.com/wandb/operator/internal/controller/infra/managed/mysql/moco"
+	"github.com/wandb/operator/internal/controller/infra/objectstore"
 	"github.com/wandb/operator/pkg/utils"
 	"github.com/wandb/operator/pkg/wandb/manifest"
 	"k8s.io/api/batch/v1"
@@ -18,6 +20,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -100,6 +103,16 @@ func runMysqlRetentionFinalizer(ctx context.Context, c client.Client, wandb *api
 		return mysqlPurgeFinalizer(ctx, c, wandb, key, spec)
 	case apiv2.DetachOnDelete:
 		return mysqlDetachFinalizer(ctx, c, wandb, key, spec)
+	case apiv2.SnapshotOnDelete:
+		managed := spec.ManagedMysql
+		if managed == nil {
+			return mysqlPurgeFinalizer(ctx, c, wandb, key, spec)
+		}
+		return runSnapshotFinalizer(ctx, c, wandb, managed.Namespace,
+			moco.ToMysqlOnDeleteRule(wandb, wandb.GetRetentionPolicy(managed.ManagedInfraSpec)),
+			func() error { return mysqlPurgeFinalizer(ctx, c, wandb, key, spec) },
+			func() error { return mysqlDetachFinalizer(ctx, c, wandb, key, spec) },
+		)
 	}
 	return nil
 }
@@ -162,7 +175,7 @@ func managedMysqlWriteState(
 		if errors.IsNotFound(err) {
 			dbPasswordSecret.Name = fmt.Sprintf("%s-%s", specNamespacedName.Name, "db-password")
 			dbPasswordSecret.Namespace = specNamespacedName.Namespace
-			userPassword, err := utils.GenerateRandomPassword(32)
+			userPassword, err := utils.GenerateRandomPassword(32, utils.CharacterTypePassword)
 			if err != nil {
 				logger.Error(err, "failed to generate random password")
 				return []metav1.Condition{
@@ -173,7 +186,7 @@ func managedMysqlWriteState(
 					},
 				}
 			}
-			rootPassword, err := utils.GenerateRandomPassword(32)
+			rootPassword, err := utils.GenerateRandomPassword(32, utils.CharacterTypePassword)
 			if err != nil {
 				logger.Error(err, "failed to generate random password")
 				return []metav1.Condition{
@@ -217,6 +230,10 @@ func managedMysqlWriteState(
 	if conditions := moco.CheckDetached(ctx, client, specNamespacedName, wandb.GetUID(), spec.Replicas); conditions != nil {
 		return conditions
 	}
+	adoptionConditions, proceed := moco.AdoptDetached(ctx, client, specNamespacedName, wandb)
+	if !proceed {
+		return adoptionConditions
+	}
 
 	var desired *mocov1beta2.MySQLCluster
 	var confMap *corev1.ConfigMap
@@ -231,7 +248,120 @@ func managedMysqlWriteState(
 			},
 		}
 	}
-	return moco.WriteState(ctx, client, specNamespacedName, desired, confMap, moco.BuildWandbMysqlLabels(wandb))
+
+	if common.InfraTLSEnabled(spec.TLS) {
+		secret, conditions := ensureManagedInfraTLS(
+			ctx, client, wandb, spec.TLS, specNamespacedName, moco.ServerDNSNames(specNamespacedName), moco.BuildWandbMysqlLabels(wandb),
+		)
+		if conditions != nil {
+			return conditions
+		}
+		moco.ApplyServerTLS(desired, confMap, secret)
+	}
+
+	restoreConditions, proceed := managedMysqlWriteRestore(ctx, client, wandb, spec, specNamespacedName, desired)
+	if !proceed {
+		return restoreConditions
+	}
+	backupConditions := managedMysqlWriteBackup(ctx, client, wandb, spec, specNamespacedName, desired, mfst)
+	conditions := moco.WriteState(ctx, client, specNamespacedName, desired, confMap, moco.BuildWandbMysqlLabels(wandb))
+	conditions = append(conditions, restoreConditions...)
+	conditions = append(conditions, backupConditions...)
+	return append(conditions, adoptionConditions...)
+}
+
+// managedMysqlWriteRestore puts spec.restore onto desired and applies the
+// credentials MOCO's restore job runs with. MOCO only restores while creating
+// a cluster, so it returns proceed=false to hold creation back until the
+// source object store can be read, rather than create an empty cluster.
+func managedMysqlWriteRestore(
+	ctx context.Context,
+	client client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	spec *apiv2.ManagedMysqlSpec,
+	specNamespacedName types.NamespacedName,
+	desired *mocov1beta2.MySQLCluster,
+) ([]metav1.Condition, bool) {
+	if spec.Restore == nil {
+		return moco.WriteRestoreState(ctx, client, specNamespacedName, nil), true
+	}
+	unavailable := func(reason, message string) ([]metav1.Condition, bool) {
+		exists, err := moco.ClusterExists(ctx, client, specNamespacedName)
+		if err != nil {
+			return []metav1.Condition{{
+				Type:   common.ReconciledType,
+				Status: metav1.ConditionFalse,
+				Reason: common.ApiErrorReason,
+			}}, false
+		}
+		if exists {
+			// The restore, if any, is already under way or done; leave its
+			// credentials alone and let the cluster report on it.
+			return nil, true
+		}
+		return []metav1.Condition{{
+			Type:    moco.MySQLRestoreType,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: message,
+		}}, false
+	}
+
+	status, ok := apiv2.ResolveInstance(wandb.Status.ObjectStoreStatus, spec.Restore.ObjectStore)
+	if !ok || !status.Ready {
+		return unavailable(moco.ObjectStoreUnavailableReason, "waiting for the restore object store to become ready")
+	}
+	storage, err := objectstore.Resolve(ctx, client, spec.Namespace, &status.Connection)
+	if err != nil {
+		return unavailable(moco.ObjectStoreUnavailableReason, err.Error())
+	}
+	restore, err := moco.ToMocoRestore(*spec, wandb, client.Scheme(), storage, time.Now())
+	if err != nil {
+		return unavailable(moco.UnsupportedObjectStoreReason, err.Error())
+	}
+	desired.Spec.Restore = restore.Restore
+	return moco.WriteRestoreState(ctx, client, specNamespacedName, restore), true
+}
+
+// managedMysqlWriteBackup reconciles the instance's scheduled backups and
+// points desired at its BackupPolicy. Backup problems surface on the
+// MySQLBackup condition and never hold up the cluster itself.
+func managedMysqlWriteBackup(
+	ctx context.Context,
+	client client.Client,
+	wandb *apiv2.WeightsAndBiases,
+	spec *apiv2.ManagedMysqlSpec,
+	specNamespacedName types.NamespacedName,
+	desired *mocov1beta2.MySQLCluster,
+	mfst manifest.Manifest,
+) []metav1.Condition {
+	if spec.Backup == nil {
+		return moco.WriteBackupState(ctx, client, specNamespacedName, nil)
+	}
+	unavailable := func(reason, message string) []metav1.Condition {
+		moco.RetainBackupPolicy(ctx, client, specNamespacedName, desired)
+		return []metav1.Condition{{
+			Type:    moco.MySQLBackupType,
+			Status:  metav1.ConditionFalse,
+			Reason:  reason,
+			Message: message,
+		}}
+	}
+
+	status, ok := apiv2.ResolveInstance(wandb.Status.ObjectStoreStatus, spec.Backup.ObjectStore)
+	if !ok || !status.Ready {
+		return unavailable(moco.ObjectStoreUnavailableReason, "waiting for the backup object store to become ready")
+	}
+	storage, err := objectstore.Resolve(ctx, client, spec.Namespace, &status.Connection)
+	if err != nil {
+		return unavailable(moco.ObjectStoreUnavailableReason, err.Error())
+	}
+	backup, err := moco.ToMocoBackup(*spec, wandb, client.Scheme(), storage, mfst)
+	if err != nil {
+		return unavailable(moco.UnsupportedObjectStoreReason, err.Error())
+	}
+	desired.Spec.BackupPolicyName = ptr.To(backup.Policy.Name)
+	return moco.WriteBackupState(ctx, client, specNamespacedName, backup)
 }
 
 func managedMysqlReadState(
@@ -275,6 +405,10 @@ func managedMysqlInferStatus(
 	for _, e := range events {
 		recorder.Event(wandb, e.Type, e.Reason, e.Message)
 	}
+	updatedStatus.LastBackupTime = utils.Coalesce(
+		moco.ReadLastBackupTime(ctx, client, managedMysqlSpecNamespacedName(wandb.Spec.MySQL[key].ManagedMysql)),
+		oldStatus.LastBackupTime,
+	)
 	wandb.Status.MySQLStatus[key] = updatedStatus
 	err := updateWandbStatusIfChanged(ctx, client, wandb, statusBefore)
 
@@ -294,7 +428,12 @@ func externalMysqlInferStatus(ctx context.Context, c client.Client, wandb *apiv2
 		WBInfraStatus: apiv2.WBInfraStatus{Ready: ready, State: state, Conditions: updatedConditions},
 		Connection:    *conn,
 	}
-	return ctrl.Result{}, updateWandbStatusIfChanged(ctx, c, wandb, statusBefore)
+	// Requeue so the connectivity probe runs again even when nothing else changes.
+	var result ctrl.Result
+	if newInfraConn != nil {
+		result.RequeueAfter = external.ProbeInterval
+	}
+	return result, updateWandbStatusIfChanged(ctx, c, wandb, statusBefore)
 }
 
 // helpers
@@ -391,14 +530,13 @@ func runMysqlInitJobInstance(ctx context.Context, client client.Client, wandb *a
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      jobName,
 				Namespace: wandb.Namespace,
-				Labels: map[string]string{
-					"app.kubernetes.io/managed-by": "wandb-operator",
-					"app.kubernetes.io/instance":   wandb.Name,
-					"app.kubernetes.io/component":  "moco-init",
-				},
+				Labels:    jobPodLabels(wandb, mysqlInitJobComponent),
 			},
 			Spec: v1.JobSpec{
 				Template: corev1.PodTemplateSpec{
+					ObjectMeta: metav1.ObjectMeta{
+						Labels: jobPodLabels(wandb, mysqlInitJobComponent),
+					},
 					Spec: corev1.PodSpec{
 						// mysql-init runs before the W&B ServiceAccount exists, so set
 						// pull secrets on the pod directly rather than via the SA.
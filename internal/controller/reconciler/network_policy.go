@@ -0,0 +1,673 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	"github.com/wandb/operator/internal/controller/infra/managed/clickhouse/altinity"
+	"github.com/wandb/operator/internal/controller/infra/managed/clickhouse/altinity/keeper"
+	"github.com/wandb/operator/internal/controller/infra/managed/kafka/bufstream"
+	"github.com/wandb/operator/internal/controller/infra/managed/mysql/moco"
+	"github.com/wandb/operator/internal/controller/infra/managed/objectstore/seaweedfs"
+	"github.com/wandb/operator/internal/controller/infra/managed/redis/opstree"
+	"github.com/wandb/operator/internal/observability/telemetry"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	networkPolicyComponent = "network-policy"
+
+	// namespaceNameLabel is set by the apiserver on every namespace.
+	namespaceNameLabel = "kubernetes.io/metadata.name"
+
+	migrationJobComponent = "migration"
+	mysqlInitJobComponent = "moco-init"
+)
+
+// Infra kinds, as used in NetworkPolicy names.
+const (
+	infraKindMySQL       = "mysql"
+	infraKindRedis       = "redis"
+	infraKindClickHouse  = "clickhouse"
+	infraKindObjectStore = "objectstore"
+	infraKindKafka       = "kafka"
+)
+
+// infraRef names one infra instance: a kind and its spec instance key. Kafka
+// has a single instance with an empty key.
+type infraRef struct {
+	kind string
+	key  string
+}
+
+// infraPodGroup is a set of pods belonging to a managed infra instance.
+// Consumers may reach clientPorts; a group without client ports (the
+// ClickHouse Keeper) only talks to its own instance.
+type infraPodGroup struct {
+	policyName  string
+	namespace   string
+	selector    metav1.LabelSelector
+	clientPorts []networkingv1.NetworkPolicyPort
+}
+
+type managedInfraInstance struct {
+	ref    infraRef
+	groups []infraPodGroup
+	// exposed is set when the manifest routes ingress traffic to the instance.
+	exposed bool
+}
+
+// networkTopology is who talks to which managed infra instance, derived from
+// the env sources in the manifest.
+type networkTopology struct {
+	apps       []serverManifest.Application
+	appInfra   map[string]map[infraRef]bool
+	migrations map[infraRef]bool
+	instances  []managedInfraInstance
+}
+
+func networkPoliciesEnabled(wandb *apiv2.WeightsAndBiases) bool {
+	return wandb.Spec.Networking.NetworkPolicies != nil && wandb.Spec.Networking.NetworkPolicies.Enabled
+}
+
+// reconcileNetworkPolicies renders the NetworkPolicies for the CR's
+// applications and managed infra, and removes the ones no longer desired. With
+// networkPolicies disabled, every policy the operator created is removed.
+func reconcileNetworkPolicies(
+	ctx context.Context,
+	c ctrlClient.Client,
+	wandb *apiv2.WeightsAndBiases,
+	manifest serverManifest.Manifest,
+	telemetryConfig telemetry.TelemetryRuntimeConfig,
+) error {
+	var desired []*networkingv1.NetworkPolicy
+	if networkPoliciesEnabled(wandb) {
+		desired = buildNetworkPolicies(wandb, manifest, telemetryConfig)
+	}
+
+	// Managed infra may live in other namespaces, so policies are found by
+	// label rather than owner reference.
+	existing := &networkingv1.NetworkPolicyList{}
+	if err := c.List(ctx, existing, ctrlClient.MatchingLabels(common.BuildWandbLabels(wandb, networkPolicyComponent))); err != nil {
+		return err
+	}
+	stale := map[ctrlClient.ObjectKey]*networkingv1.NetworkPolicy{}
+	for i := range existing.Items {
+		stale[ctrlClient.ObjectKeyFromObject(&existing.Items[i])] = &existing.Items[i]
+	}
+
+	for _, policy := range desired {
+		if policy.Namespace == wandb.Namespace {
+			if err := controllerutil.SetOwnerReference(wandb, policy, c.Scheme()); err != nil {
+				return err
+			}
+		}
+		key := ctrlClient.ObjectKeyFromObject(policy)
+		current, found := stale[key]
+		delete(stale, key)
+		if !found {
+			if err := c.Create(ctx, policy); err != nil {
+				return err
+			}
+			continue
+		}
+		if apiequality.Semantic.DeepEqual(current.Spec, policy.Spec) &&
+			apiequality.Semantic.DeepEqual(current.Labels, policy.Labels) {
+			continue
+		}
+		policy.ResourceVersion = current.ResourceVersion
+		if err := c.Update(ctx, policy); err != nil {
+			return err
+		}
+	}
+
+	for _, policy := range stale {
+		if err := c.Delete(ctx, policy); err != nil && !apiErrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildNetworkPolicies renders a default-deny policy over the W&B
+// applications, one allow policy per application and one ingress policy per
+// managed infra pod group.
+func buildNetworkPolicies(
+	wandb *apiv2.WeightsAndBiases,
+	manifest serverManifest.Manifest,
+	telemetryConfig telemetry.TelemetryRuntimeConfig,
+) []*networkingv1.NetworkPolicy {
+	topology := buildNetworkTopology(wandb, manifest)
+	config := wandb.Spec.Networking.NetworkPolicies
+
+	var policies []*networkingv1.NetworkPolicy
+	if len(topology.apps) > 0 {
+		policies = append(policies, newNetworkPolicy(wandb, wandb.Name+"-default-deny", wandb.Namespace,
+			networkingv1.NetworkPolicySpec{
+				PodSelector: appsSelector(topology.apps),
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			}))
+	}
+
+	for _, app := range topology.apps {
+		policies = append(policies, buildAppNetworkPolicy(wandb, topology, app, telemetryConfig))
+	}
+
+	for _, instance := range topology.instances {
+		var peers []networkingv1.NetworkPolicyPeer
+		for _, group := range instance.groups {
+			peers = append(peers, podsPeer(group.namespace, group.selector))
+		}
+		for _, group := range instance.groups {
+			spec := networkingv1.NetworkPolicySpec{
+				PodSelector: group.selector,
+				PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+				Ingress: []networkingv1.NetworkPolicyIngressRule{
+					{From: peers},
+					{From: []networkingv1.NetworkPolicyPeer{namespacePeer(config.OperatorNamespace)}},
+				},
+			}
+			if telemetryConfig.Enabled {
+				spec.Ingress = append(spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+					From: []networkingv1.NetworkPolicyPeer{namespacePeer(telemetryNamespace(wandb, telemetryConfig))},
+				})
+			}
+			if len(group.clientPorts) > 0 {
+				if from := infraClientPeers(wandb, topology, instance.ref); len(from) > 0 {
+					spec.Ingress = append(spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+						From:  from,
+						Ports: group.clientPorts,
+					})
+				}
+				if instance.exposed && wandb.Spec.Networking.Mode != apiv2.NetworkingModeNone {
+					spec.Ingress = append(spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+						From:  config.IngressFrom,
+						Ports: group.clientPorts,
+					})
+				}
+			}
+			policies = append(policies, newNetworkPolicy(wandb, group.policyName, group.namespace, spec))
+		}
+	}
+	return policies
+}
+
+func buildAppNetworkPolicy(
+	wandb *apiv2.WeightsAndBiases,
+	topology networkTopology,
+	app serverManifest.Application,
+	telemetryConfig telemetry.TelemetryRuntimeConfig,
+) *networkingv1.NetworkPolicy {
+	config := wandb.Spec.Networking.NetworkPolicies
+	appsPeer := podsPeer(wandb.Namespace, appsSelector(topology.apps))
+
+	spec := networkingv1.NetworkPolicySpec{
+		PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app.kubernetes.io/name": app.Name}},
+		PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		Ingress: []networkingv1.NetworkPolicyIngressRule{
+			{From: []networkingv1.NetworkPolicyPeer{appsPeer}},
+		},
+		Egress: []networkingv1.NetworkPolicyEgressRule{
+			// Any resolver: node-local caches sit outside kube-system, and
+			// OpenShift's DNS pods listen on 5353.
+			{Ports: []networkingv1.NetworkPolicyPort{
+				policyPort(corev1.ProtocolUDP, intstr.FromInt32(53)),
+				policyPort(corev1.ProtocolTCP, intstr.FromInt32(53)),
+				policyPort(corev1.ProtocolUDP, intstr.FromInt32(5353)),
+				policyPort(corev1.ProtocolTCP, intstr.FromInt32(5353)),
+			}},
+			{To: []networkingv1.NetworkPolicyPeer{appsPeer}},
+		},
+	}
+
+	if app.Ingress != nil && app.Service != nil && wandb.Spec.Networking.Mode != apiv2.NetworkingModeNone {
+		spec.Ingress = append(spec.Ingress, networkingv1.NetworkPolicyIngressRule{
+			From:  config.IngressFrom,
+			Ports: []networkingv1.NetworkPolicyPort{policyPort(corev1.ProtocolTCP, ingressTargetPort(app))},
+		})
+	}
+
+	if telemetryConfig.Enabled {
+		telemetryPeer := []networkingv1.NetworkPolicyPeer{namespacePeer(telemetryNamespace(wandb, telemetryConfig))}
+		spec.Ingress = append(spec.Ingress, networkingv1.NetworkPolicyIngressRule{From: telemetryPeer})
+		spec.Egress = append(spec.Egress, networkingv1.NetworkPolicyEgressRule{
+			To:    telemetryPeer,
+			Ports: telemetry.EgressPorts(),
+		})
+	}
+
+	for _, instance := range topology.instances {
+		if !topology.appInfra[app.Name][instance.ref] {
+			continue
+		}
+		for _, group := range instance.groups {
+			if len(group.clientPorts) == 0 {
+				continue
+			}
+			spec.Egress = append(spec.Egress, networkingv1.NetworkPolicyEgressRule{
+				To:    []networkingv1.NetworkPolicyPeer{podsPeer(group.namespace, group.selector)},
+				Ports: group.clientPorts,
+			})
+		}
+	}
+
+	if len(config.EgressCIDRs) > 0 {
+		var to []networkingv1.NetworkPolicyPeer
+		for _, cidr := range config.EgressCIDRs {
+			to = append(to, networkingv1.NetworkPolicyPeer{IPBlock: &networkingv1.IPBlock{CIDR: cidr}})
+		}
+		spec.Egress = append(spec.Egress, networkingv1.NetworkPolicyEgressRule{To: to})
+	}
+
+	return newNetworkPolicy(wandb, fmt.Sprintf("%s-app-%s", wandb.Name, app.Name), wandb.Namespace, spec)
+}
+
+// infraClientPeers lists the pods allowed to reach an instance's client ports:
+// the applications and migrations that reference it, the database init job
+// and backup jobs for MySQL, and the managed Kafka and ClickHouse pods and
+// MySQL backup jobs for the object store, which they use for storage and
+// backups.
+func infraClientPeers(wandb *apiv2.WeightsAndBiases, topology networkTopology, ref infraRef) []networkingv1.NetworkPolicyPeer {
+	var consumers []string
+	for _, app := range topology.apps {
+		if topology.appInfra[app.Name][ref] {
+			consumers = append(consumers, app.Name)
+		}
+	}
+
+	var peers []networkingv1.NetworkPolicyPeer
+	if len(consumers) > 0 {
+		peers = append(peers, podsPeer(wandb.Namespace, metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{
+				Key:      "app.kubernetes.io/name",
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   consumers,
+			}},
+		}))
+	}
+	if topology.migrations[ref] {
+		peers = append(peers, podsPeer(wandb.Namespace, jobPodSelector(wandb, migrationJobComponent)))
+	}
+	switch ref.kind {
+	case infraKindMySQL:
+		peers = append(peers, podsPeer(wandb.Namespace, jobPodSelector(wandb, mysqlInitJobComponent)))
+		if spec := wandb.Spec.MySQL[ref.key].ManagedMysql; spec != nil && (spec.Backup != nil || spec.Restore != nil) {
+			peers = append(peers, mysqlBackupPeer(spec))
+		}
+	case infraKindClickHouse:
+		if spec := wandb.Spec.ClickHouse[ref.key].ManagedClickHouse; spec != nil && (spec.Backup != nil || spec.RestoreFrom != nil) {
+			peers = append(peers, clickHouseBackupPeer(spec))
+		}
+	case infraKindObjectStore:
+		for _, instance := range topology.instances {
+			if instance.ref.kind != infraKindKafka && instance.ref.kind != infraKindClickHouse {
+				continue
+			}
+			for _, group := range instance.groups {
+				peers = append(peers, podsPeer(group.namespace, group.selector))
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(wandb.Spec.MySQL)) {
+			spec := wandb.Spec.MySQL[key].ManagedMysql
+			if spec == nil {
+				continue
+			}
+			if mysqlBackupTargets(wandb, spec)[ref.key] {
+				peers = append(peers, mysqlBackupPeer(spec))
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(wandb.Spec.ClickHouse)) {
+			spec := wandb.Spec.ClickHouse[key].ManagedClickHouse
+			if spec == nil {
+				continue
+			}
+			if clickHouseBackupTargets(wandb, spec)[ref.key] {
+				peers = append(peers, clickHouseBackupPeer(spec))
+			}
+		}
+	}
+	return peers
+}
+
+// mysqlBackupTargets is the set of object stores the instance's backup and
+// restore jobs talk to.
+func mysqlBackupTargets(wandb *apiv2.WeightsAndBiases, spec *apiv2.ManagedMysqlSpec) map[string]bool {
+	targets := map[string]bool{}
+	if spec.Backup != nil {
+		if target, ok := resolveInstanceKey(wandb.Spec.ObjectStore, spec.Backup.ObjectStore); ok {
+			targets[target] = true
+		}
+	}
+	if spec.Restore != nil {
+		if target, ok := resolveInstanceKey(wandb.Spec.ObjectStore, spec.Restore.ObjectStore); ok {
+			targets[target] = true
+		}
+	}
+	return targets
+}
+
+// mysqlBackupPeer matches MOCO's backup and restore job pods, which share
+// their labels.
+func mysqlBackupPeer(spec *apiv2.ManagedMysqlSpec) networkingv1.NetworkPolicyPeer {
+	return podsPeer(spec.Namespace, moco.CreateNsNameBuilder(managedMysqlSpecNamespacedName(spec)).BackupPodSelector())
+}
+
+// clickHouseBackupTargets is the set of object stores the instance's backup
+// and restore jobs list and prune; the servers themselves already reach every
+// object store.
+func clickHouseBackupTargets(wandb *apiv2.WeightsAndBiases, spec *apiv2.ManagedClickHouseSpec) map[string]bool {
+	targets := map[string]bool{}
+	if spec.Backup != nil {
+		if target, ok := resolveInstanceKey(wandb.Spec.ObjectStore, spec.Backup.ObjectStore); ok {
+			targets[target] = true
+		}
+	}
+	if spec.RestoreFrom != nil {
+		if target, ok := resolveInstanceKey(wandb.Spec.ObjectStore, spec.RestoreFrom.ObjectStore); ok {
+			targets[target] = true
+		}
+	}
+	return targets
+}
+
+// clickHouseBackupPeer matches the backup and restore job pods, which connect
+// to the servers to run BACKUP and RESTORE.
+func clickHouseBackupPeer(spec *apiv2.ManagedClickHouseSpec) networkingv1.NetworkPolicyPeer {
+	return podsPeer(spec.Namespace, altinity.CreateNsNameBuilder(managedClickHouseSpecNamespacedName(spec)).BackupPodSelector())
+}
+
+// buildNetworkTopology resolves every env source in the manifest to the
+// managed infra instance it reads its connection from, the same way
+// resolveEnvvars does.
+func buildNetworkTopology(wandb *apiv2.WeightsAndBiases, manifest serverManifest.Manifest) networkTopology {
+	topology := networkTopology{
+		appInfra:   map[string]map[infraRef]bool{},
+		migrations: map[infraRef]bool{},
+		instances:  managedInfraInstances(wandb, manifest),
+	}
+
+	for _, app := range sortedManifestApplications(manifest) {
+		if len(app.Features) > 0 && !manifest.FeaturesEnabled(app.Features) {
+			continue
+		}
+		topology.apps = append(topology.apps, app)
+		topology.appInfra[app.Name] = envSourceInfraRefs(wandb, manifest, app.CommonEnvs, app.Env)
+	}
+	for _, migration := range manifest.Migrations {
+		maps.Copy(topology.migrations, envSourceInfraRefs(wandb, manifest, migration.CommonEnvs, migration.Env))
+	}
+	return topology
+}
+
+func envSourceInfraRefs(
+	wandb *apiv2.WeightsAndBiases,
+	manifest serverManifest.Manifest,
+	commonEnvs []string,
+	envs []serverManifest.EnvVar,
+) map[infraRef]bool {
+	var combined []serverManifest.EnvVar
+	for _, group := range commonEnvs {
+		combined = append(combined, manifest.CommonEnvvars[group]...)
+	}
+	combined = append(combined, envs...)
+
+	refs := map[infraRef]bool{}
+	for _, env := range combined {
+		for _, src := range env.Sources {
+			var key string
+			var ok bool
+			kind := src.Type
+			switch src.Type {
+			case "mysql":
+				key, ok = resolveInstanceKey(wandb.Spec.MySQL, src.Name)
+			case "redis":
+				key, ok = resolveInstanceKey(wandb.Spec.Redis, src.Name)
+			case "clickhouse":
+				key, ok = resolveInstanceKey(wandb.Spec.ClickHouse, src.Name)
+			case "bucket":
+				kind = infraKindObjectStore
+				key, ok = resolveInstanceKey(wandb.Spec.ObjectStore, src.Name)
+			case "kafka":
+				ok = true
+			}
+			if ok {
+				refs[infraRef{kind: kind, key: key}] = true
+			}
+		}
+	}
+	return refs
+}
+
+// resolveInstanceKey is apiv2.ResolveInstance for the key rather than the value.
+func resolveInstanceKey[T any](m map[string]T, key string) (string, bool) {
+	if key == "" {
+		key = apiv2.DefaultInstanceName
+	}
+	if _, ok := m[key]; ok {
+		return key, true
+	}
+	if _, ok := m[apiv2.DefaultInstanceName]; ok {
+		return apiv2.DefaultInstanceName, true
+	}
+	return "", false
+}
+
+// managedInfraInstances lists the pods of every managed instance. External
+// instances are reached through spec.networking.networkPolicies.egressCIDRs.
+func managedInfraInstances(wandb *apiv2.WeightsAndBiases, manifest serverManifest.Manifest) []managedInfraInstance {
+	var instances []managedInfraInstance
+	policyName := func(kind, key string) string {
+		return fmt.Sprintf("%s-%s-%s", wandb.Name, kind, key)
+	}
+	tcpPorts := func(ports ...int32) []networkingv1.NetworkPolicyPort {
+		out := make([]networkingv1.NetworkPolicyPort, 0, len(ports))
+		for _, port := range ports {
+			out = append(out, policyPort(corev1.ProtocolTCP, intstr.FromInt32(port)))
+		}
+		return out
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(wandb.Spec.MySQL)) {
+		spec := wandb.Spec.MySQL[key].ManagedMysql
+		if spec == nil {
+			continue
+		}
+		nsnBuilder := moco.CreateNsNameBuilder(managedMysqlSpecNamespacedName(spec))
+		instances = append(instances, managedInfraInstance{
+			ref: infraRef{kind: infraKindMySQL, key: key},
+			groups: []infraPodGroup{{
+				policyName:  policyName(infraKindMySQL, key),
+				namespace:   spec.Namespace,
+				selector:    nsnBuilder.PodSelector(),
+				clientPorts: tcpPorts(3306),
+			}},
+		})
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(wandb.Spec.Redis)) {
+		spec := wandb.Spec.Redis[key].ManagedRedis
+		if spec == nil {
+			continue
+		}
+		nsnBuilder := opstree.CreateNsNameBuilder(managedRedisSpecNamespacedName(spec))
+		instances = append(instances, managedInfraInstance{
+			ref: infraRef{kind: infraKindRedis, key: key},
+			groups: []infraPodGroup{{
+				policyName:  policyName(infraKindRedis, key),
+				namespace:   spec.Namespace,
+				selector:    nsnBuilder.PodSelector(),
+				clientPorts: tcpPorts(6379, 26379),
+			}},
+		})
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(wandb.Spec.ClickHouse)) {
+		spec := wandb.Spec.ClickHouse[key].ManagedClickHouse
+		if spec == nil {
+			continue
+		}
+		nsnBuilder := altinity.CreateNsNameBuilder(managedClickHouseSpecNamespacedName(spec))
+		instances = append(instances, managedInfraInstance{
+			ref: infraRef{kind: infraKindClickHouse, key: key},
+			groups: []infraPodGroup{
+				{
+					policyName:  policyName(infraKindClickHouse, key),
+					namespace:   spec.Namespace,
+					selector:    nsnBuilder.PodSelector(),
+					clientPorts: tcpPorts(altinity.ClickHouseHTTPPort, altinity.ClickHouseNativePort),
+				},
+				{
+					policyName: policyName(infraKindClickHouse, key) + "-keeper",
+					namespace:  spec.Namespace,
+					selector:   keeper.PodSelector(altinity.KeeperNsName(spec).Name),
+				},
+			},
+			exposed: manifestInfraExposed(manifest.Clickhouse),
+		})
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(wandb.Spec.ObjectStore)) {
+		spec := wandb.Spec.ObjectStore[key].ManagedObjectStore
+		if spec == nil {
+			continue
+		}
+		nsnBuilder := seaweedfs.CreateNsNameBuilder(managedObjectStoreSpecNamespacedName(spec))
+		instances = append(instances, managedInfraInstance{
+			ref: infraRef{kind: infraKindObjectStore, key: key},
+			groups: []infraPodGroup{{
+				policyName: policyName(infraKindObjectStore, key),
+				namespace:  spec.Namespace,
+				selector:   nsnBuilder.PodSelector(),
+				clientPorts: []networkingv1.NetworkPolicyPort{
+					policyPort(corev1.ProtocolTCP, intstr.Parse(seaweedfs.S3Port)),
+				},
+			}},
+			exposed: manifestInfraExposed(manifest.Bucket),
+		})
+	}
+
+	if spec := wandb.Spec.Kafka.ManagedKafka; spec != nil {
+		nsnBuilder := bufstream.CreateNsNameBuilder(managedKafkaSpecNamespacedName(spec))
+		instances = append(instances, managedInfraInstance{
+			ref: infraRef{kind: infraKindKafka},
+			groups: []infraPodGroup{{
+				policyName:  fmt.Sprintf("%s-%s", wandb.Name, infraKindKafka),
+				namespace:   spec.Namespace,
+				selector:    nsnBuilder.PodSelector(),
+				clientPorts: tcpPorts(bufstream.KafkaListenerPort),
+			}},
+		})
+	}
+
+	return instances
+}
+
+// manifestInfraExposed mirrors resolveInfraRoutes: an infra kind is routed
+// when any of its manifest configs has an ingress section.
+func manifestInfraExposed(configs map[string]serverManifest.InfraConfig) bool {
+	for _, cfg := range configs {
+		if cfg.Ingress != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// ingressTargetPort is the container port behind the Service port the
+// Ingress or HTTPRoute sends traffic to; policies match on pod ports.
+func ingressTargetPort(app serverManifest.Application) intstr.IntOrString {
+	backend := resolveIngressServicePort(app)
+	for _, port := range app.Service.Ports {
+		if (backend.Name != "" && port.Name == backend.Name) || (backend.Name == "" && port.Port == backend.Number) {
+			if port.TargetPort.Type == intstr.String && port.TargetPort.StrVal != "" ||
+				port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal != 0 {
+				return port.TargetPort
+			}
+			return intstr.FromInt32(port.Port)
+		}
+	}
+	if backend.Name != "" {
+		return intstr.FromString(backend.Name)
+	}
+	return intstr.FromInt32(backend.Number)
+}
+
+// telemetryNamespace is where the telemetry gateway runs. Without a managed
+// namespace, workloads resolve the gateway in their own namespace.
+func telemetryNamespace(wandb *apiv2.WeightsAndBiases, telemetryConfig telemetry.TelemetryRuntimeConfig) string {
+	if telemetryConfig.Namespace != "" {
+		return telemetryConfig.Namespace
+	}
+	return wandb.Namespace
+}
+
+func appsSelector(apps []serverManifest.Application) metav1.LabelSelector {
+	names := make([]string, 0, len(apps))
+	for _, app := range apps {
+		names = append(names, app.Name)
+	}
+	return metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{
+		Key:      "app.kubernetes.io/name",
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   names,
+	}}}
+}
+
+// jobPodLabels labels the pods of the CR's one-off jobs so NetworkPolicies can
+// tell them apart.
+func jobPodLabels(wandb *apiv2.WeightsAndBiases, component string) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/managed-by": "wandb-operator",
+		"app.kubernetes.io/instance":   wandb.Name,
+		"app.kubernetes.io/component":  component,
+	}
+}
+
+func jobPodSelector(wandb *apiv2.WeightsAndBiases, component string) metav1.LabelSelector {
+	return metav1.LabelSelector{MatchLabels: jobPodLabels(wandb, component)}
+}
+
+func podsPeer(namespace string, selector metav1.LabelSelector) networkingv1.NetworkPolicyPeer {
+	peer := namespacePeer(namespace)
+	peer.PodSelector = &selector
+	return peer
+}
+
+func namespacePeer(namespace string) networkingv1.NetworkPolicyPeer {
+	return networkingv1.NetworkPolicyPeer{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{namespaceNameLabel: namespace}},
+	}
+}
+
+func policyPort(protocol corev1.Protocol, port intstr.IntOrString) networkingv1.NetworkPolicyPort {
+	return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &port}
+}
+
+func newNetworkPolicy(
+	wandb *apiv2.WeightsAndBiases,
+	name, namespace string,
+	spec networkingv1.NetworkPolicySpec,
+) *networkingv1.NetworkPolicy {
+	labels := common.BuildWandbLabels(wandb, networkPolicyComponent)
+	labels["app.kubernetes.io/managed-by"] = "wandb-operator"
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: spec,
+	}
+}
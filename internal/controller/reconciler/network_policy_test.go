@@ -0,0 +1,267 @@
+package reconciler
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/observability/telemetry"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func networkPolicyTestWandb() *apiv2.WeightsAndBiases {
+	wandb := &apiv2.WeightsAndBiases{
+		TypeMeta: metav1.TypeMeta{APIVersion: apiv2.GroupVersion.String(), Kind: "WeightsAndBiases"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "wandb",
+			Namespace: "default",
+			UID:       types.UID("wandb-uid"),
+		},
+	}
+	wandb.Spec.Networking.Mode = apiv2.NetworkingModeIngress
+	wandb.Spec.Networking.NetworkPolicies = &apiv2.NetworkPoliciesConfig{
+		Enabled:           true,
+		OperatorNamespace: "wandb-operator",
+	}
+	wandb.Spec.MySQL = map[string]apiv2.MySQLSpec{
+		apiv2.DefaultInstanceName: {ManagedMysql: &apiv2.ManagedMysqlSpec{Name: "wandb-mysql", Namespace: "default"}},
+	}
+	wandb.Spec.Redis = map[string]apiv2.RedisSpec{
+		apiv2.DefaultInstanceName: {ManagedRedis: &apiv2.ManagedRedisSpec{Name: "wandb-redis", Namespace: "infra"}},
+	}
+	return wandb
+}
+
+func networkPolicyTestManifest() serverManifest.Manifest {
+	return serverManifest.Manifest{
+		CommonEnvvars: map[string][]serverManifest.EnvVar{
+			"mysql": {{Name: "MYSQL", Sources: []serverManifest.EnvSource{{Type: "mysql"}}}},
+		},
+		Migrations: map[string]serverManifest.MigrationJob{
+			"default": {CommonEnvs: []string{"mysql"}},
+		},
+		Applications: map[string]serverManifest.Application{
+			"api": {
+				Name:       "api",
+				CommonEnvs: []string{"mysql"},
+				Env: []serverManifest.EnvVar{
+					{Name: "REDIS", Sources: []serverManifest.EnvSource{{Type: "redis", Name: "missing"}}},
+				},
+				Service: &serverManifest.ServiceSpec{Ports: []corev1.ServicePort{
+					{Name: "http", Port: 80, TargetPort: intstr.FromInt32(8080)},
+				}},
+				Ingress: &serverManifest.AppIngressSpec{},
+			},
+			"worker": {Name: "worker"},
+		},
+	}
+}
+
+func findNetworkPolicy(t *testing.T, policies []*networkingv1.NetworkPolicy, name string) *networkingv1.NetworkPolicy {
+	t.Helper()
+	for _, policy := range policies {
+		if policy.Name == name {
+			return policy
+		}
+	}
+	t.Fatalf("NetworkPolicy %s not rendered", name)
+	return nil
+}
+
+func peersSelectApp(peers []networkingv1.NetworkPolicyPeer, app string) bool {
+	for _, peer := range peers {
+		if peer.PodSelector == nil {
+			continue
+		}
+		for _, expr := range peer.PodSelector.MatchExpressions {
+			if expr.Key == "app.kubernetes.io/name" && slices.Contains(expr.Values, app) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestBuildNetworkTopologyResolvesEnvSources(t *testing.T) {
+	topology := buildNetworkTopology(networkPolicyTestWandb(), networkPolicyTestManifest())
+
+	mysql := infraRef{kind: infraKindMySQL, key: apiv2.DefaultInstanceName}
+	redis := infraRef{kind: infraKindRedis, key: apiv2.DefaultInstanceName}
+	if !topology.appInfra["api"][mysql] || !topology.appInfra["api"][redis] {
+		t.Fatalf("api should consume the default mysql and redis (unknown keys fall back), got %v", topology.appInfra["api"])
+	}
+	if len(topology.appInfra["worker"]) != 0 {
+		t.Fatalf("worker has no infra sources, got %v", topology.appInfra["worker"])
+	}
+	if !topology.migrations[mysql] || topology.migrations[redis] {
+		t.Fatalf("migrations should only consume mysql, got %v", topology.migrations)
+	}
+}
+
+func TestBuildNetworkPoliciesLimitsInfraToConsumers(t *testing.T) {
+	wandb := networkPolicyTestWandb()
+	policies := buildNetworkPolicies(wandb, networkPolicyTestManifest(), telemetry.DefaultTelemetryRuntimeConfig())
+
+	deny := findNetworkPolicy(t, policies, "wandb-default-deny")
+	if len(deny.Spec.Ingress) != 0 || len(deny.Spec.Egress) != 0 || len(deny.Spec.PolicyTypes) != 2 {
+		t.Fatalf("default-deny should deny both directions, got %+v", deny.Spec)
+	}
+
+	redis := findNetworkPolicy(t, policies, "wandb-redis-default")
+	if redis.Namespace != "infra" {
+		t.Fatalf("redis policy should live with the instance, got namespace %q", redis.Namespace)
+	}
+	var clientRule *networkingv1.NetworkPolicyIngressRule
+	for i := range redis.Spec.Ingress {
+		if len(redis.Spec.Ingress[i].Ports) > 0 {
+			clientRule = &redis.Spec.Ingress[i]
+		}
+	}
+	if clientRule == nil || !peersSelectApp(clientRule.From, "api") || peersSelectApp(clientRule.From, "worker") {
+		t.Fatalf("only api should reach redis client ports, got %+v", clientRule)
+	}
+
+	api := findNetworkPolicy(t, policies, "wandb-app-api")
+	var ingressPort *intstr.IntOrString
+	for _, rule := range api.Spec.Ingress {
+		if rule.From == nil && len(rule.Ports) == 1 {
+			ingressPort = rule.Ports[0].Port
+		}
+	}
+	if ingressPort == nil || ingressPort.IntValue() != 8080 {
+		t.Fatalf("api should admit the ingress controller on its target port 8080, got %v", ingressPort)
+	}
+
+	worker := findNetworkPolicy(t, policies, "wandb-app-worker")
+	for _, rule := range worker.Spec.Egress {
+		for _, peer := range rule.To {
+			if peer.NamespaceSelector != nil && peer.NamespaceSelector.MatchLabels[namespaceNameLabel] == "infra" {
+				t.Fatalf("worker should not reach redis, got egress %+v", rule)
+			}
+		}
+	}
+}
+
+func TestReconcileNetworkPoliciesRemovesPoliciesWhenDisabled(t *testing.T) {
+	scheme := runtime.NewScheme()
+	for _, add := range []func(*runtime.Scheme) error{networkingv1.AddToScheme, apiv2.AddToScheme} {
+		if err := add(scheme); err != nil {
+			t.Fatal(err)
+		}
+	}
+	wandb := networkPolicyTestWandb()
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(wandb).Build()
+	ctx := context.Background()
+	telemetryConfig := telemetry.DefaultTelemetryRuntimeConfig()
+
+	if err := reconcileNetworkPolicies(ctx, c, wandb, networkPolicyTestManifest(), telemetryConfig); err != nil {
+		t.Fatal(err)
+	}
+	policies := &networkingv1.NetworkPolicyList{}
+	if err := c.List(ctx, policies); err != nil {
+		t.Fatal(err)
+	}
+	// default-deny, two apps, mysql, redis
+	if len(policies.Items) != 5 {
+		t.Fatalf("expected 5 policies, got %d", len(policies.Items))
+	}
+
+	wandb.Spec.Networking.NetworkPolicies.Enabled = false
+	if err := reconcileNetworkPolicies(ctx, c, wandb, networkPolicyTestManifest(), telemetryConfig); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.List(ctx, policies, ctrlClient.InNamespace("")); err != nil {
+		t.Fatal(err)
+	}
+	if len(policies.Items) != 0 {
+		t.Fatalf("expected policies to be removed, %d left", len(policies.Items))
+	}
+}
+
+func TestBuildNetworkPoliciesAdmitsMySQLBackupJobs(t *testing.T) {
+	wandb := networkPolicyTestWandb()
+	wandb.Spec.MySQL[apiv2.DefaultInstanceName].ManagedMysql.Backup = &apiv2.MySQLBackupSpec{Schedule: "0 3 * * *", ObjectStore: "backups"}
+	wandb.Spec.ObjectStore = map[string]apiv2.ObjectStoreSpec{
+		apiv2.DefaultInstanceName: {ManagedObjectStore: &apiv2.ManagedObjectStoreSpec{Name: "wandb-bucket", Namespace: "default"}},
+	}
+	policies := buildNetworkPolicies(wandb, networkPolicyTestManifest(), telemetry.DefaultTelemetryRuntimeConfig())
+
+	admitsBackups := func(policyName string) bool {
+		for _, rule := range findNetworkPolicy(t, policies, policyName).Spec.Ingress {
+			for _, peer := range rule.From {
+				if peer.PodSelector != nil &&
+					peer.PodSelector.MatchLabels["app.kubernetes.io/name"] == "mysql-backup" &&
+					peer.PodSelector.MatchLabels["app.kubernetes.io/instance"] == "wandb-mysql" {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if !admitsBackups("wandb-mysql-default") {
+		t.Fatal("backup jobs should reach mysql")
+	}
+	// An unknown object store key falls back to the default instance.
+	if !admitsBackups("wandb-objectstore-default") {
+		t.Fatal("backup jobs should reach the object store they write to")
+	}
+
+	// Restore jobs carry the same labels and need the same paths.
+	wandb.Spec.MySQL[apiv2.DefaultInstanceName].ManagedMysql.Backup = nil
+	wandb.Spec.MySQL[apiv2.DefaultInstanceName].ManagedMysql.Restore = &apiv2.MySQLRestoreSpec{Prefix: "moco/default/wandb-mysql/"}
+	policies = buildNetworkPolicies(wandb, networkPolicyTestManifest(), telemetry.DefaultTelemetryRuntimeConfig())
+	if !admitsBackups("wandb-mysql-default") || !admitsBackups("wandb-objectstore-default") {
+		t.Fatal("restore jobs should reach mysql and the object store they read from")
+	}
+}
+
+func TestBuildNetworkPoliciesAdmitsClickHouseBackupJobs(t *testing.T) {
+	wandb := networkPolicyTestWandb()
+	wandb.Spec.ClickHouse = map[string]apiv2.ClickHouseSpec{
+		apiv2.DefaultInstanceName: {ManagedClickHouse: &apiv2.ManagedClickHouseSpec{
+			Name: "wandb-clickhouse", Namespace: "default",
+			Backup: &apiv2.ClickHouseBackupSpec{Schedule: "0 3 * * *", ObjectStore: "backups"},
+		}},
+	}
+	wandb.Spec.ObjectStore = map[string]apiv2.ObjectStoreSpec{
+		apiv2.DefaultInstanceName: {ManagedObjectStore: &apiv2.ManagedObjectStoreSpec{Name: "wandb-bucket", Namespace: "default"}},
+	}
+	policies := buildNetworkPolicies(wandb, networkPolicyTestManifest(), telemetry.DefaultTelemetryRuntimeConfig())
+
+	admitsBackups := func(policyName string) bool {
+		for _, rule := range findNetworkPolicy(t, policies, policyName).Spec.Ingress {
+			for _, peer := range rule.From {
+				if peer.PodSelector != nil &&
+					peer.PodSelector.MatchLabels["app.kubernetes.io/name"] == "clickhouse-backup" &&
+					peer.PodSelector.MatchLabels["app.kubernetes.io/instance"] == "wandb-clickhouse" {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if !admitsBackups("wandb-clickhouse-default") {
+		t.Fatal("backup jobs should reach clickhouse")
+	}
+	if !admitsBackups("wandb-objectstore-default") {
+		t.Fatal("backup jobs should reach the object store they prune")
+	}
+
+	wandb.Spec.ClickHouse[apiv2.DefaultInstanceName].ManagedClickHouse.Backup = nil
+	wandb.Spec.ClickHouse[apiv2.DefaultInstanceName].ManagedClickHouse.RestoreFrom = &apiv2.ClickHouseRestoreSpec{
+		Prefix: "clickhouse-backup/default/wandb-clickhouse/",
+	}
+	policies = buildNetworkPolicies(wandb, networkPolicyTestManifest(), telemetry.DefaultTelemetryRuntimeConfig())
+	if !admitsBackups("wandb-clickhouse-default") || !admitsBackups("wandb-objectstore-default") {
+		t.Fatal("restore jobs should reach clickhouse and the object store they read from")
+	}
+}
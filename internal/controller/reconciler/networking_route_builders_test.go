@@ -92,6 +92,37 @@ var _ = Describe("Networking Route Builders", func() {
 		Expect(*route.Spec.Rules[0].BackendRefs[0].Port).To(Equal(gatewayv1.PortNumber(8333)))
 	})
 
+	It("applies the routing entry keyed by the infra route name without the CR prefix", func() {
+		entry := infraRouteEntry{
+			name:        "wandb-bucket-default",
+			namespace:   "infra-ns",
+			serviceName: "wandb-seaweedfs-filer",
+			servicePort: gatewayv1.PortNumber(8333),
+			routingKey:  "bucket-default",
+		}
+		wandb := &apiv2.WeightsAndBiases{
+			ObjectMeta: metav1.ObjectMeta{Name: "wandb", Namespace: "wandb-ns"},
+			Spec: apiv2.WeightsAndBiasesSpec{
+				Networking: apiv2.NetworkingSpec{
+					Mode: apiv2.NetworkingModeGatewayAPI,
+					GatewayAPI: &apiv2.GatewayAPIConfig{
+						Routing: map[string]apiv2.GatewayRouting{
+							"bucket-default": {GatewayRouteActions: apiv2.GatewayRouteActions{
+								Timeouts: &apiv2.GatewayRouteTimeouts{Request: "30m"},
+							}},
+						},
+					},
+				},
+			},
+		}
+
+		route := buildInfraHTTPRoute(wandb, gatewayv1.ParentReference{Name: "shared-gateway"}, nil, entry)
+
+		Expect(route.Spec.Rules).To(HaveLen(1))
+		Expect(route.Spec.Rules[0].Timeouts).NotTo(BeNil())
+		Expect(*route.Spec.Rules[0].Timeouts.Request).To(Equal(gatewayv1.Duration("30m")))
+	})
+
 	It("builds application HTTPRoute templates for external gateways", func() {
 		listenerName := "https"
 		wandb := &apiv2.WeightsAndBiases{
@@ -92,6 +92,16 @@ func runObjectStoreRetentionFinalizer(ctx context.Context, c client.Client, wand
 		return objectStorePurgeFinalizer(ctx, c, wandb, key, spec)
 	case apiv2.DetachOnDelete:
 		return objectStoreDetachFinalizer(ctx, c, wandb, key, spec)
+	case apiv2.SnapshotOnDelete:
+		managed := spec.ManagedObjectStore
+		if managed == nil {
+			return objectStorePurgeFinalizer(ctx, c, wandb, key, spec)
+		}
+		return runSnapshotFinalizer(ctx, c, wandb, managed.Namespace,
+			seaweedfs.ToObjectStoreOnDeleteRule(wandb, wandb.GetRetentionPolicy(managed.ManagedInfraSpec)),
+			func() error { return objectStorePurgeFinalizer(ctx, c, wandb, key, spec) },
+			func() error { return objectStoreDetachFinalizer(ctx, c, wandb, key, spec) },
+		)
 	}
 	return nil
 }
@@ -179,6 +189,10 @@ func managedObjectStoreWriteState(
 	if conditions := seaweedfs.CheckDetached(ctx, client, specNamespacedName, wandb.GetUID(), spec.Replicas); conditions != nil {
 		return conditions, nil
 	}
+	adoptionConditions, proceed := seaweedfs.AdoptDetached(ctx, client, specNamespacedName, wandb)
+	if !proceed {
+		return adoptionConditions, nil
+	}
 
 	desiredCr, err := seaweedfs.ToObjectStoreVendorSpec(ctx, wandb, spec, client.Scheme(), mfst)
 	if err != nil {
@@ -205,7 +219,7 @@ func managedObjectStoreWriteState(
 	}
 
 	conditions, connection := seaweedfs.WriteState(ctx, client, specNamespacedName, desiredCr, desiredConfig, wandb)
-	return conditions, connection
+	return append(conditions, adoptionConditions...), connection
 }
 
 func managedObjectStoreReadState(
@@ -272,7 +286,12 @@ func externalObjectStoreInferStatus(ctx context.Context, c client.Client, wandb
 		WBInfraStatus: apiv2.WBInfraStatus{Ready: ready, State: state, Conditions: updatedConditions},
 		Connection:    *conn,
 	}
-	return ctrl.Result{}, updateWandbStatusIfChanged(ctx, c, wandb, statusBefore)
+	// Requeue so the connectivity probe runs again even when nothing else changes.
+	var result ctrl.Result
+	if newInfraConn != nil {
+		result.RequeueAfter = external.ProbeInterval
+	}
+	return result, updateWandbStatusIfChanged(ctx, c, wandb, statusBefore)
 }
 
 // helpers
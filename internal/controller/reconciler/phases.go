@@ -0,0 +1,23 @@
+package reconciler
+
+import (
+	"context"
+	"time"
+
+	wmetrics "github.com/wandb/operator/internal/observability/metrics"
+	"github.com/wandb/operator/internal/observability/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// startPhase opens the span for one reconcile phase, e.g. "mysql.write" or
+// "migrations". Call the returned function with the phase's error, if any,
+// when the phase is done; it also records the phase duration for the CR the
+// context was tagged with.
+func startPhase(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, func(error)) {
+	started := time.Now()
+	ctx, span := tracing.Start(ctx, name, attrs...)
+	return ctx, func(err error) {
+		wmetrics.ObserveReconcilePhase(ctx, name, time.Since(started), err)
+		tracing.End(span, err)
+	}
+}
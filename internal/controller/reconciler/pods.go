@@ -202,10 +202,10 @@ func resolveEnvvars(ctx context.Context, client ctrlClient.Client, wandb *v2.Wei
 		for idx, src := range env.Sources {
 			switch src.Type {
 			case "generatedSecret":
-				if sel, ok := wandb.Status.GeneratedSecrets[src.Name]; ok {
-					singleSecretSelector = sel
+				if generated, ok := wandb.Status.GeneratedSecrets[src.Name]; ok {
+					singleSecretSelector = generated.SecretKeySelector
 					secretOnlyCount++
-					addSecretComponent(sel, idx)
+					addSecretComponent(generated.SecretKeySelector, idx)
 				}
 			case "mysql":
 				// MySQL connection URL as a secret ref. src.Name selects the
@@ -308,14 +308,31 @@ func resolveEnvvars(ctx context.Context, client ctrlClient.Client, wandb *v2.Wei
 					addSecretComponent(selector, idx)
 					break
 				}
+				kafkaConn := wandb.Status.KafkaStatus.Connection
 				selector := v1.SecretKeySelector{
-					LocalObjectReference: wandb.Status.KafkaStatus.Connection.URL.LocalObjectReference,
+					LocalObjectReference: kafkaConn.URL.LocalObjectReference,
 				}
 				switch src.Field {
 				case "host":
 					selector.Key = "Host"
 				case "port":
 					selector.Key = "Port"
+				case "brokers", "sasl-mechanism", "username", "password", "tls":
+					// brokers is published for managed and external Kafka alike;
+					// credentials and TLS settings only for an external Kafka.
+					// Skip the env var rather than mount a key that may not be
+					// there.
+					fieldSelector := map[string]v1.SecretKeySelector{
+						"brokers":        kafkaConn.BrokerEndpoint,
+						"sasl-mechanism": kafkaConn.SaslMechanism,
+						"username":       kafkaConn.Username,
+						"password":       kafkaConn.Password,
+						"tls":            kafkaConn.Tls,
+					}[src.Field]
+					if fieldSelector.Name == "" || fieldSelector.Key == "" {
+						continue
+					}
+					selector = fieldSelector
 				default:
 					selector.Key = "url"
 				}
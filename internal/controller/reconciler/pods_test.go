@@ -46,3 +46,26 @@ func TestResolveContainersPreservesResolvedProbes(t *testing.T) {
 		t.Fatalf("expected TCP readiness probe to be preserved, got %+v", containers[0].ReadinessProbe)
 	}
 }
+
+func TestResolveEnvvarsManagedKafkaBrokers(t *testing.T) {
+	// Managed Kafka publishes "host:port" brokers but no credentials.
+	wandb := externalKafkaWandb()
+	wandb.Spec.Kafka = apiv2.KafkaSpec{ManagedKafka: &apiv2.ManagedKafkaSpec{}}
+	wandb.Status.KafkaStatus.Connection = apiv2.KafkaConnection{
+		URL:            kafkaConnSel("url"),
+		Host:           kafkaConnSel("Host"),
+		Port:           kafkaConnSel("Port"),
+		BrokerEndpoint: kafkaConnSel("Brokers"),
+	}
+
+	env, found := resolveKafkaEnv(t, wandb, "brokers")
+	if !found || env.ValueFrom == nil || env.ValueFrom.SecretKeyRef == nil {
+		t.Fatalf("expected a secret-backed brokers env var, got %+v", env)
+	}
+	if got := env.ValueFrom.SecretKeyRef.Key; got != "Brokers" {
+		t.Errorf("expected key %q, got %q", "Brokers", got)
+	}
+	if env, found := resolveKafkaEnv(t, wandb, "username"); found {
+		t.Fatalf("expected no username env var for managed Kafka, got %+v", env)
+	}
+}
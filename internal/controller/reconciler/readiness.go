@@ -7,15 +7,24 @@ import (
 	"strings"
 
 	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/infra/managed/clickhouse/altinity"
+	"github.com/wandb/operator/internal/controller/infra/managed/mysql/moco"
+	"github.com/wandb/operator/internal/logx"
 	wmetrics "github.com/wandb/operator/internal/observability/metrics"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
+	corev1 "k8s.io/api/core/v1"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	readyConditionType = "Ready"
 
+	manifestIncompatibleReason = "ManifestIncompatible"
+	manifestUntrustedReason    = "ManifestUntrusted"
+
 	migrationPhaseRunning   = "Running"
 	migrationPhaseFailed    = "Failed"
 	migrationPhaseSucceeded = "Succeeded"
@@ -36,6 +45,11 @@ func setReadyStatus(wandb *apiv2.WeightsAndBiases, ready bool, reason, message s
 		Reason:             reason,
 		Message:            message,
 	})
+	// SetStatusCondition only moves LastTransitionTime when the status flips,
+	// so it is the time Ready last changed, across operator restarts too.
+	if cond := apimeta.FindStatusCondition(wandb.Status.Conditions, readyConditionType); cond != nil {
+		wmetrics.SetReadyTransition(wandb.Namespace, wandb.Name, cond.LastTransitionTime.Time)
+	}
 }
 
 func updateReadyStatus(
@@ -51,6 +65,59 @@ func updateReadyStatus(
 	return updateWandbStatusIfChanged(ctx, c, wandb, statusBefore)
 }
 
+// checkManifestCompatibility gates reconciliation on the manifest's
+// requiredOperatorVersion. When the running operator is too old (or too new)
+// for the requested server version it marks Ready=False/ManifestIncompatible,
+// emits a warning event and returns false so the caller stops before any infra
+// or migration work would half-apply the release.
+func checkManifestCompatibility(
+	ctx context.Context,
+	c ctrlclient.Client,
+	recorder record.EventRecorder,
+	wandb *apiv2.WeightsAndBiases,
+	manifest serverManifest.Manifest,
+	operatorVersion string,
+) (bool, error) {
+	err := manifest.CheckOperatorVersion(ctx, operatorVersion)
+	if err == nil {
+		return true, nil
+	}
+
+	message := fmt.Sprintf("server version %s: %v", wandb.Spec.Wandb.Version, err)
+	logx.GetSlog(ctx).Warn("manifest is incompatible with this operator", logx.ErrAttr(err),
+		"version", wandb.Spec.Wandb.Version, "operatorVersion", operatorVersion)
+	if recorder != nil {
+		recorder.Event(wandb, corev1.EventTypeWarning, manifestIncompatibleReason, message)
+	}
+
+	statusBefore := wandb.DeepCopy().Status
+	if err := updateReadyStatus(ctx, c, wandb, statusBefore, false, manifestIncompatibleReason, message); err != nil {
+		return false, err
+	}
+	return false, nil
+}
+
+// reportManifestUntrusted marks Ready=False/ManifestUntrusted and emits a
+// warning event when spec.wandb.manifestVerification rejects the manifest.
+// Nothing from the manifest is applied, so the caller stops reconciling.
+func reportManifestUntrusted(
+	ctx context.Context,
+	c ctrlclient.Client,
+	recorder record.EventRecorder,
+	wandb *apiv2.WeightsAndBiases,
+	err error,
+) error {
+	message := fmt.Sprintf("server version %s: %v", wandb.Spec.Wandb.Version, err)
+	logx.GetSlog(ctx).Warn("manifest failed signature verification", logx.ErrAttr(err),
+		"repository", wandb.Spec.Wandb.ManifestRepository, "version", wandb.Spec.Wandb.Version)
+	if recorder != nil {
+		recorder.Event(wandb, corev1.EventTypeWarning, manifestUntrustedReason, message)
+	}
+
+	statusBefore := wandb.DeepCopy().Status
+	return updateReadyStatus(ctx, c, wandb, statusBefore, false, manifestUntrustedReason, message)
+}
+
 func infrastructureBlockers(wandb *apiv2.WeightsAndBiases) []string {
 	var blockers []string
 	for key := range wandb.Spec.Redis {
@@ -59,11 +126,15 @@ func infrastructureBlockers(wandb *apiv2.WeightsAndBiases) []string {
 		}
 	}
 	for key := range wandb.Spec.MySQL {
-		if !wandb.Status.MySQLStatus[key].Ready {
-			blockers = append(blockers, "mysql/"+key)
+		status := wandb.Status.MySQLStatus[key]
+		if status.Ready {
+			continue
 		}
+		// A restore can run for a long time; name it so the Ready message
+		// explains why the init job and migrations are held back.
+		blockers = append(blockers, restoreBlocker("mysql/"+key, status.Conditions, moco.MySQLRestoreType))
 	}
-	if wandb.Spec.Kafka.ManagedKafka != nil && !wandb.Status.KafkaStatus.Ready {
+	if (wandb.Spec.Kafka.ManagedKafka != nil || wandb.Spec.Kafka.ExternalKafka != nil) && !wandb.Status.KafkaStatus.Ready {
 		blockers = append(blockers, "kafka")
 	}
 	for key := range wandb.Spec.ObjectStore {
@@ -72,14 +143,23 @@ func infrastructureBlockers(wandb *apiv2.WeightsAndBiases) []string {
 		}
 	}
 	for key := range wandb.Spec.ClickHouse {
-		if !wandb.Status.ClickHouseStatus[key].Ready {
-			blockers = append(blockers, "clickhouse/"+key)
+		if status := wandb.Status.ClickHouseStatus[key]; !status.Ready {
+			blockers = append(blockers, restoreBlocker("clickhouse/"+key, status.Conditions, altinity.ClickHouseRestoreType))
 		}
 	}
 	sort.Strings(blockers)
 	return blockers
 }
 
+// restoreBlocker names the blocker, adding the reason of an unfinished
+// restore.
+func restoreBlocker(name string, conditions []metav1.Condition, restoreType string) string {
+	if restore := apimeta.FindStatusCondition(conditions, restoreType); restore != nil && restore.Status == metav1.ConditionFalse {
+		return fmt.Sprintf("%s (restore: %s)", name, restore.Reason)
+	}
+	return name
+}
+
 func mysqlInitializationReadiness(wandb *apiv2.WeightsAndBiases) (string, string) {
 	var failed []string
 	var pending []string
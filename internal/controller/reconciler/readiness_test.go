@@ -2,15 +2,19 @@ package reconciler
 
 import (
 	"context"
+	"strings"
 	"testing"
 
 	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/infra/managed/clickhouse/altinity"
+	"github.com/wandb/operator/internal/controller/infra/managed/mysql/moco"
 	servermanifest "github.com/wandb/operator/pkg/wandb/manifest"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apimeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
@@ -64,6 +68,59 @@ func TestInferStateBlocksOnExternalInfrastructure(t *testing.T) {
 	}
 }
 
+func TestInfrastructureBlockersNameMySQLRestore(t *testing.T) {
+	wandb := &apiv2.WeightsAndBiases{
+		Spec: apiv2.WeightsAndBiasesSpec{
+			MySQL: map[string]apiv2.MySQLSpec{
+				apiv2.DefaultInstanceName: {ManagedMysql: &apiv2.ManagedMysqlSpec{}},
+				"audit":                   {ManagedMysql: &apiv2.ManagedMysqlSpec{}},
+			},
+		},
+		Status: apiv2.WeightsAndBiasesStatus{
+			MySQLStatus: map[string]apiv2.MysqlInfraStatus{
+				apiv2.DefaultInstanceName: {WBInfraStatus: apiv2.WBInfraStatus{
+					Conditions: []metav1.Condition{{
+						Type:   moco.MySQLRestoreType,
+						Status: metav1.ConditionFalse,
+						Reason: moco.RestoreRestoringReason,
+					}},
+				}},
+			},
+		},
+	}
+
+	got := strings.Join(infrastructureBlockers(wandb), ", ")
+	if want := "mysql/audit, mysql/default (restore: Restoring)"; got != want {
+		t.Fatalf("blockers = %q, want %q", got, want)
+	}
+}
+
+func TestInfrastructureBlockersNameClickHouseRestore(t *testing.T) {
+	wandb := &apiv2.WeightsAndBiases{
+		Spec: apiv2.WeightsAndBiasesSpec{
+			ClickHouse: map[string]apiv2.ClickHouseSpec{
+				apiv2.DefaultInstanceName: {ManagedClickHouse: &apiv2.ManagedClickHouseSpec{}},
+			},
+		},
+		Status: apiv2.WeightsAndBiasesStatus{
+			ClickHouseStatus: map[string]apiv2.ClickHouseInfraStatus{
+				apiv2.DefaultInstanceName: {WBInfraStatus: apiv2.WBInfraStatus{
+					Conditions: []metav1.Condition{{
+						Type:   altinity.ClickHouseRestoreType,
+						Status: metav1.ConditionFalse,
+						Reason: altinity.ObjectStoreUnavailableReason,
+					}},
+				}},
+			},
+		},
+	}
+
+	got := strings.Join(infrastructureBlockers(wandb), ", ")
+	if want := "clickhouse/default (restore: ObjectStoreUnavailable)"; got != want {
+		t.Fatalf("blockers = %q, want %q", got, want)
+	}
+}
+
 func TestSetReadyStatusKeepsBooleanAndConditionConsistent(t *testing.T) {
 	wandb := &apiv2.WeightsAndBiases{
 		ObjectMeta: metav1.ObjectMeta{Generation: 4},
@@ -153,3 +210,53 @@ func TestRunMigrationsSurfacesFailedJobPhaseAndReason(t *testing.T) {
 		t.Fatal("readiness message should identify the failed migration")
 	}
 }
+
+func TestCheckManifestCompatibilityBlocksIncompatibleOperator(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := apiv2.AddToScheme(scheme); err != nil {
+		t.Fatalf("add W&B API to scheme: %v", err)
+	}
+	wandb := &apiv2.WeightsAndBiases{
+		ObjectMeta: metav1.ObjectMeta{Name: "wandb", Namespace: "default", Generation: 3},
+		Spec: apiv2.WeightsAndBiasesSpec{
+			Wandb: apiv2.WandbAppSpec{Version: "0.90.0"},
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithStatusSubresource(&apiv2.WeightsAndBiases{}).
+		WithObjects(wandb).
+		Build()
+	recorder := record.NewFakeRecorder(1)
+	manifest := servermanifest.Manifest{RequiredOperatorVersion: "^3.0.0"}
+
+	compatible, err := checkManifestCompatibility(context.Background(), c, recorder, wandb, manifest, "2.4.0")
+	if err != nil {
+		t.Fatalf("check manifest compatibility: %v", err)
+	}
+	if compatible {
+		t.Fatal("operator 2.4.0 must not satisfy ^3.0.0")
+	}
+
+	actual := &apiv2.WeightsAndBiases{}
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(wandb), actual); err != nil {
+		t.Fatalf("get updated W&B resource: %v", err)
+	}
+	condition := apimeta.FindStatusCondition(actual.Status.Conditions, readyConditionType)
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != manifestIncompatibleReason {
+		t.Fatalf("unexpected Ready condition: %#v", condition)
+	}
+	select {
+	case event := <-recorder.Events:
+		if !strings.Contains(event, manifestIncompatibleReason) {
+			t.Fatalf("unexpected event: %q", event)
+		}
+	default:
+		t.Fatal("expected a ManifestIncompatible event")
+	}
+
+	compatible, err = checkManifestCompatibility(context.Background(), c, recorder, wandb, manifest, "3.1.0")
+	if err != nil || !compatible {
+		t.Fatalf("operator 3.1.0 must satisfy ^3.0.0: compatible=%v err=%v", compatible, err)
+	}
+}
@@ -33,9 +33,10 @@ import (
 	"github.com/wandb/operator/internal/logx"
 	wmetrics "github.com/wandb/operator/internal/observability/metrics"
 	"github.com/wandb/operator/internal/observability/telemetry"
-	oputils "github.com/wandb/operator/pkg/utils"
+	"github.com/wandb/operator/internal/version"
 	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
 	"github.com/wandb/operator/pkg/wandb/manifest/registryauth"
+	"go.opentelemetry.io/otel/attribute"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
@@ -64,12 +65,15 @@ func runRetentionFinalizer(
 	infraSpec apiv2.ManagedInfraSpec,
 	purgeFn finalizerFunc,
 	detachFn finalizerFunc,
+	snapshotFn finalizerFunc,
 ) error {
 	switch wandb.GetRetentionPolicy(infraSpec).OnDelete {
 	case apiv2.PurgeOnDelete:
 		return purgeFn(ctx, client, wandb)
 	case apiv2.DetachOnDelete:
 		return detachFn(ctx, client, wandb)
+	case apiv2.SnapshotOnDelete:
+		return snapshotFn(ctx, client, wandb)
 	}
 	return nil
 }
@@ -83,6 +87,7 @@ func Reconcile(
 	telemetryConfig telemetry.TelemetryRuntimeConfig,
 ) (ctrl.Result, error) {
 	ctx, log := logx.WithSlog(ctx, logx.ReconcileInfraV2)
+	ctx = wmetrics.WithWeightsAndBiases(ctx, wandb.Namespace, wandb.Name)
 
 	var err error
 
@@ -104,6 +109,18 @@ func Reconcile(
 		}
 	}
 
+	// record the deployment identity that managed infrastructure is adopted by;
+	// patch only the annotation rather than sending the whole spec back
+	if !isFlaggedForDeletion {
+		before := wandb.DeepCopy()
+		if common.EnsureDeploymentUID(wandb) {
+			if err := client.Patch(ctx, wandb, ctrlClient.MergeFrom(before)); err != nil {
+				log.Error("Failed to record deployment UID", logx.ErrAttr(err))
+				return ctrl.Result{}, err
+			}
+		}
+	}
+
 	// if deleting and handle cleanup or preservation of config and data
 	if isFlaggedForDeletion && !wandb.GetDeletionTimestamp().IsZero() {
 		if ctrlqueue.ContainsString(wandb.GetFinalizers(), CleanupFinalizer) {
@@ -112,28 +129,28 @@ func Reconcile(
 			// configured policy to each managed or external instance.
 			for key, spec := range wandb.Spec.ObjectStore {
 				if err = runObjectStoreRetentionFinalizer(ctx, client, wandb, key, spec); err != nil {
-					return ctrl.Result{}, err
+					return retentionFinalizerResult(err)
 				}
 			}
 			for key, spec := range wandb.Spec.MySQL {
 				if err = runMysqlRetentionFinalizer(ctx, client, wandb, key, spec); err != nil {
-					return ctrl.Result{}, err
+					return retentionFinalizerResult(err)
 				}
 			}
 			for key, spec := range wandb.Spec.Redis {
 				if err = runRedisRetentionFinalizer(ctx, client, wandb, key, spec); err != nil {
-					return ctrl.Result{}, err
+					return retentionFinalizerResult(err)
 				}
 			}
 			for key, spec := range wandb.Spec.ClickHouse {
 				if err = runClickHouseRetentionFinalizer(ctx, client, wandb, key, spec); err != nil {
-					return ctrl.Result{}, err
+					return retentionFinalizerResult(err)
 				}
 			}
 			// Kafka remains single-instance.
-			if wandb.Spec.Kafka.ManagedKafka != nil {
-				if err = runRetentionFinalizer(ctx, client, wandb, wandb.Spec.Kafka.ManagedKafka.ManagedInfraSpec, kafkaPurgeFinalizer, kafkaDetachFinalizer); err != nil {
-					return ctrl.Result{}, err
+			if wandb.Spec.Kafka.ManagedKafka != nil || wandb.Spec.Kafka.ExternalKafka != nil {
+				if err = runRetentionFinalizer(ctx, client, wandb, kafkaInfraSpec(wandb.Spec.Kafka), kafkaPurgeFinalizer, kafkaDetachFinalizer, kafkaSnapshotFinalizer); err != nil {
+					return retentionFinalizerResult(err)
 				}
 			}
 			if err = deleteInfraHTTPRoutes(ctx, client, wandb); err != nil {
@@ -180,21 +197,52 @@ func Reconcile(
 	// Fetch manifest early so infra sizing can be applied before provisioning.
 	// Local file:// manifests need no registry credentials, so a missing pull
 	// secret must not block reconcile for them.
+	manifestCtx, endManifestPhase := startPhase(ctx, "manifest.fetch",
+		attribute.String("wandb.version", wandb.Spec.Wandb.Version))
 	var registryAuth *serverManifest.RegistryAuth
 	if !serverManifest.IsFileRepository(wandb.Spec.Wandb.ManifestRepository) {
 		// Ambient cloud creds only for non-default (private) registries; the
 		// public default pulls anonymously and must not probe cloud metadata.
 		allowAmbient := wandb.Spec.Wandb.ManifestRepository != apiv2.DefaultManifestRepository
-		registryAuth, err = registryauth.Resolve(ctx, client, wandb.Namespace, wandb.Spec.Global.ImagePullSecrets, allowAmbient)
+		registryAuth, err = registryauth.Resolve(manifestCtx, client, wandb.Namespace, wandb.Spec.Global.ImagePullSecrets, allowAmbient)
 		if err != nil {
+			endManifestPhase(err)
+			return ctrl.Result{}, err
+		}
+	}
+	// Manifest signature verification applies to the cached copy too, so a
+	// tampered /tmp/server-manifest is caught as well as a tampered registry.
+	verifier, err := resolveManifestVerifier(manifestCtx, client, wandb)
+	if err == nil && verifier != nil {
+		if registryAuth == nil {
+			registryAuth = &serverManifest.RegistryAuth{}
+		}
+		registryAuth.Verifier = verifier
+	}
+	var manifest serverManifest.Manifest
+	if err == nil {
+		manifest, err = serverManifest.GetServerManifest(manifestCtx, wandb.Spec.Wandb.ManifestRepository, wandb.Spec.Wandb.Version, registryAuth)
+	}
+	endManifestPhase(err)
+	if errors.Is(err, serverManifest.ErrManifestUntrusted) {
+		if err := reportManifestUntrusted(ctx, client, recorder, wandb, err); err != nil {
 			return ctrl.Result{}, err
 		}
+		return ctrl.Result{RequeueAfter: defaultRequeueDuration}, nil
 	}
-	manifest, err := serverManifest.GetServerManifest(ctx, wandb.Spec.Wandb.ManifestRepository, wandb.Spec.Wandb.Version, registryAuth)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 
+	// Refuse releases that need a different operator before touching infra.
+	compatible, err := checkManifestCompatibility(ctx, client, recorder, wandb, manifest, version.Version)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if !compatible {
+		return ctrl.Result{RequeueAfter: defaultRequeueDuration}, nil
+	}
+
 	// Override features from CR spec if present
 	for key, enabled := range wandb.Spec.Wandb.Features {
 		manifest.Features[key] = enabled
@@ -205,46 +253,81 @@ func Reconcile(
 
 	/////////////////////////
 	// Write Infra State
-	redisConditions := redisWriteState(ctx, client, wandb, manifest)
-	mysqlConditions := mysqlWriteState(ctx, client, wandb, manifest)
-	objectStoreConditions, objectStoreConnection := objectStoreWriteState(ctx, client, wandb, manifest)
-	kafkaConditions := kafkaWriteState(ctx, client, wandb, manifest)
-	clickHouseConditions := clickHouseWriteState(ctx, client, wandb, manifest)
+	phaseCtx, endPhase := startPhase(ctx, "redis.write")
+	redisConditions := redisWriteState(phaseCtx, client, wandb, manifest)
+	endPhase(nil)
+	phaseCtx, endPhase = startPhase(ctx, "mysql.write")
+	mysqlConditions := mysqlWriteState(phaseCtx, client, wandb, manifest)
+	endPhase(nil)
+	phaseCtx, endPhase = startPhase(ctx, "objectstore.write")
+	objectStoreConditions, objectStoreConnection := objectStoreWriteState(phaseCtx, client, wandb, manifest)
+	endPhase(nil)
+	phaseCtx, endPhase = startPhase(ctx, "kafka.write")
+	kafkaConditions := kafkaWriteState(phaseCtx, client, wandb, manifest)
+	endPhase(nil)
+	phaseCtx, endPhase = startPhase(ctx, "clickhouse.write")
+	clickHouseConditions := clickHouseWriteState(phaseCtx, client, wandb, manifest)
+	endPhase(nil)
 
 	/////////////////////////
 	// Read Infra State
-	redisConditions, redisInfraConn := redisReadState(ctx, client, wandb, redisConditions)
-	mysqlConditions, mysqlInfraConn := mysqlReadState(ctx, client, wandb, mysqlConditions)
-	kafkaConditions, kafkaInfraConn := kafkaReadState(ctx, client, wandb, kafkaConditions)
-	objectStoreConditions = objectStoreReadState(ctx, client, wandb, objectStoreConditions)
-	clickHouseConditions, clickHouseInfraConn := clickHouseReadState(ctx, client, wandb, clickHouseConditions)
+	phaseCtx, endPhase = startPhase(ctx, "redis.read")
+	redisConditions, redisInfraConn := redisReadState(phaseCtx, client, wandb, redisConditions)
+	endPhase(nil)
+	phaseCtx, endPhase = startPhase(ctx, "mysql.read")
+	mysqlConditions, mysqlInfraConn := mysqlReadState(phaseCtx, client, wandb, mysqlConditions)
+	endPhase(nil)
+	phaseCtx, endPhase = startPhase(ctx, "kafka.read")
+	kafkaConditions, kafkaInfraConn := kafkaReadState(phaseCtx, client, wandb, kafkaConditions)
+	endPhase(nil)
+	phaseCtx, endPhase = startPhase(ctx, "objectstore.read")
+	objectStoreConditions = objectStoreReadState(phaseCtx, client, wandb, objectStoreConditions)
+	endPhase(nil)
+	phaseCtx, endPhase = startPhase(ctx, "clickhouse.read")
+	clickHouseConditions, clickHouseInfraConn := clickHouseReadState(phaseCtx, client, wandb, clickHouseConditions)
+	endPhase(nil)
 
 	/////////////////////////
 	// WandB Status Inference
 	var res ctrl.Result
 	var ctrlResults []ctrl.Result
 
-	if res, err = redisInferStatus(ctx, client, recorder, wandb, redisConditions, redisInfraConn); err != nil {
+	phaseCtx, endPhase = startPhase(ctx, "redis.infer")
+	res, err = redisInferStatus(phaseCtx, client, recorder, wandb, redisConditions, redisInfraConn)
+	endPhase(err)
+	if err != nil {
 		errorCount++
 	}
 	ctrlResults = append(ctrlResults, res)
 
-	if res, err = mysqlInferStatus(ctx, client, recorder, wandb, mysqlConditions, mysqlInfraConn); err != nil {
+	phaseCtx, endPhase = startPhase(ctx, "mysql.infer")
+	res, err = mysqlInferStatus(phaseCtx, client, recorder, wandb, mysqlConditions, mysqlInfraConn)
+	endPhase(err)
+	if err != nil {
 		errorCount++
 	}
 	ctrlResults = append(ctrlResults, res)
 
-	if res, err = kafkaInferStatus(ctx, client, recorder, wandb, kafkaConditions, kafkaInfraConn); err != nil {
+	phaseCtx, endPhase = startPhase(ctx, "kafka.infer")
+	res, err = kafkaInferStatus(phaseCtx, client, recorder, wandb, kafkaConditions, kafkaInfraConn)
+	endPhase(err)
+	if err != nil {
 		errorCount++
 	}
 	ctrlResults = append(ctrlResults, res)
 
-	if res, err = objectStoreInferStatus(ctx, client, recorder, wandb, objectStoreConditions, objectStoreConnection); err != nil {
+	phaseCtx, endPhase = startPhase(ctx, "objectstore.infer")
+	res, err = objectStoreInferStatus(phaseCtx, client, recorder, wandb, objectStoreConditions, objectStoreConnection)
+	endPhase(err)
+	if err != nil {
 		errorCount++
 	}
 	ctrlResults = append(ctrlResults, res)
 
-	if res, err = clickHouseInferStatus(ctx, client, recorder, wandb, clickHouseConditions, clickHouseInfraConn); err != nil {
+	phaseCtx, endPhase = startPhase(ctx, "clickhouse.infer")
+	res, err = clickHouseInferStatus(phaseCtx, client, recorder, wandb, clickHouseConditions, clickHouseInfraConn)
+	endPhase(err)
+	if err != nil {
 		errorCount++
 	}
 	ctrlResults = append(ctrlResults, res)
@@ -345,21 +428,32 @@ func ReconcileWandbManifest(
 
 	validateLegacyOverrides(ctx, wandb, manifest)
 
-	result, err = generateSecrets(ctx, client, wandb, manifest)
+	phaseCtx, endPhase := startPhase(ctx, "secrets.generate")
+	secretsResult, err := generateSecrets(phaseCtx, client, wandb, manifest)
+	endPhase(err)
 	if err != nil {
-		return result, err
+		return secretsResult, err
 	}
 
 	if err := reconcileEmailSink(ctx, client, wandb); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	result, err = createKafkaTopics(ctx, client, wandb, manifest)
+	if err := reconcileNetworkPolicies(ctx, client, wandb, manifest, telemetryConfig); err != nil {
+		logger.Error(err, "Failed to reconcile NetworkPolicies")
+		return ctrl.Result{}, err
+	}
+
+	phaseCtx, endPhase = startPhase(ctx, "kafka.topics")
+	result, err = createKafkaTopics(phaseCtx, client, wandb, manifest)
+	endPhase(err)
 	if err != nil {
 		return result, err
 	}
 
-	result, err = runMysqlInitJob(ctx, client, wandb, manifest)
+	phaseCtx, endPhase = startPhase(ctx, "mysql.init")
+	result, err = runMysqlInitJob(phaseCtx, client, wandb, manifest)
+	endPhase(err)
 	if err != nil {
 		return result, err
 	}
@@ -411,7 +505,9 @@ func ReconcileWandbManifest(
 		}
 	}
 
-	result, err = runMigrations(ctx, client, wandb, manifest)
+	phaseCtx, endPhase = startPhase(ctx, "migrations")
+	result, err = runMigrations(phaseCtx, client, wandb, manifest)
+	endPhase(err)
 	if err != nil {
 		return result, err
 	}
@@ -425,16 +521,20 @@ func ReconcileWandbManifest(
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 
-	result, err = reconcileApplications(ctx, client, wandb, manifest, telemetryConfig)
+	phaseCtx, endPhase = startPhase(ctx, "applications")
+	result, err = reconcileApplications(phaseCtx, client, wandb, manifest, telemetryConfig)
+	endPhase(err)
 	if err != nil {
 		return result, err
 	}
 
-	// Gate on live Deployment readiness, not status.wandb.applications: the
-	// copied status map can be a stale snapshot (it only refreshes when this
+	// Gate on live Deployment and Rollout readiness, not status.wandb.applications:
+	// the copied status map can be a stale snapshot (it only refreshes when this
 	// reconciler runs), and a frozen mid-rollout entry would block cleanup forever.
-	applicationsHealthy, notReady := deploymentsHealthy(ctx, client, wandb.Namespace, buildDesiredAppNames(manifest))
-	if applicationsHealthy {
+	desiredAppNames := buildDesiredAppNames(manifest)
+	healthy, notReady, rolloutAborted := applicationsHealthy(ctx, client, wandb, desiredAppNames,
+		buildDaemonSetAppNames(manifest, desiredAppNames))
+	if healthy {
 		if err := cleanupLegacyV1Deployments(ctx, client, wandb); err != nil {
 			logger.Error(err, "Failed to clean up legacy v1 deployments")
 			return ctrl.Result{}, err
@@ -451,7 +551,7 @@ func ReconcileWandbManifest(
 		}
 	}
 
-	if applicationsHealthy {
+	if healthy {
 		setReadyStatus(
 			wandb,
 			true,
@@ -463,14 +563,23 @@ func ReconcileWandbManifest(
 		if len(notReady) == 0 {
 			message = "no desired application deployments were found"
 		}
-		setReadyStatus(wandb, false, "ApplicationsNotReady", message)
+		reason := "ApplicationsNotReady"
+		if rolloutAborted {
+			reason = rolloutAbortedReason
+		}
+		setReadyStatus(wandb, false, reason, message)
 	}
 
-	if err := updateWandbStatusIfChanged(ctx, client, wandb, statusBefore); err != nil {
+	phaseCtx, endPhase = startPhase(ctx, "status.update")
+	err = updateWandbStatusIfChanged(phaseCtx, client, wandb, statusBefore)
+	endPhase(err)
+	if err != nil {
 		return ctrl.Result{}, err
 	}
 
-	return result, nil
+	// Scheduled secret rotations and grace-period expiries need a wakeup
+	// even when nothing else changes.
+	return consolidateResults([]ctrl.Result{result, secretsResult}), nil
 }
 
 func reconcileApplications(
@@ -496,6 +605,22 @@ func reconcileApplications(
 
 	desiredAppNames := buildDesiredAppNames(manifest)
 
+	// Kafka-lag autoscaling needs the broker address; without it the apps keep
+	// their HPA. Brokers that need SASL or TLS would also need a KEDA
+	// TriggerAuthentication, which is not rendered, so they keep the HPA too.
+	var kafkaBootstrap string
+	if wandb.Spec.Kafka.ManagedKafka != nil || wandb.Spec.Kafka.ExternalKafka != nil {
+		conn, err := resolveKafkaBootstrap(ctx, client, wandb)
+		switch {
+		case err != nil:
+			logger.Info("Kafka bootstrap unavailable; skipping Kafka-lag autoscaling", logx.ErrAttr(err))
+		case conn.authenticated():
+			logger.Info("Kafka requires SASL or TLS; skipping Kafka-lag autoscaling")
+		default:
+			kafkaBootstrap = strings.Join(conn.Brokers, ",")
+		}
+	}
+
 	for _, app := range sortedManifestApplications(manifest) {
 		// If the application is gated behind features, only install it when
 		// at least one of those features is enabled in the manifest.
@@ -564,6 +689,19 @@ func reconcileApplications(
 		}
 
 		application.Spec.Kind = "Deployment"
+		application.Spec.RolloutStrategy = nil
+		if app.Kind == serverManifest.ApplicationKindDaemonSet {
+			// A DaemonSet runs a pod per node, so there is no canary to roll.
+			application.Spec.Kind = "DaemonSet"
+		} else if strategy := resolveRolloutStrategy(wandb, app.Name); strategy != nil {
+			if rolloutsEnabled(client) {
+				application.Spec.Kind = "Rollout"
+				application.Spec.RolloutStrategy = strategy.DeepCopy()
+			} else {
+				logger.Info("rolloutStrategy is set but Argo Rollouts support is disabled; rendering a Deployment",
+					"application", app.Name)
+			}
+		}
 		application.Spec.PodTemplate.Spec.Containers = containers
 		// Replace volumes entirely on each reconcile to avoid accumulating duplicates
 		// across updates (e.g., duplicate "files-inline" volume names).
@@ -575,7 +713,25 @@ func reconcileApplications(
 		application.Spec.PodTemplate.Spec.ImagePullSecrets = wandb.Spec.Global.ImagePullSecrets
 		setCustomCACertsChecksumAnnotation(&application.Spec.PodTemplate, caChecksum)
 
-		application.Spec.HpaTemplate = ResolveAutoscaling(app, wandb)
+		// Hash every Secret/ConfigMap key the pods consume so rotating a
+		// password or editing a config file rolls only this application.
+		configRefs := collectConfigRefs(application.Spec.PodTemplate.Spec)
+		configSum, err := configChecksum(ctx, client, wandb.Namespace, configRefs)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		setPodTemplateChecksumAnnotation(&application.Spec.PodTemplate, configChecksumAnnotation, configSum)
+		application.SetAnnotations(setApplicationConfigRefs(application.GetAnnotations(), configRefs))
+
+		if application.Spec.Kind == "DaemonSet" {
+			// A DaemonSet is sized by its nodes; there is nothing to autoscale.
+			application.Spec.HpaTemplate = nil
+			application.Spec.ScaledObjectTemplate = nil
+		} else {
+			application.Spec.HpaTemplate = ResolveAutoscaling(app, wandb)
+			application.Spec.ScaledObjectTemplate = ResolveScaledObject(app, wandb, manifest.Kafka.Topics, kafkaBootstrap)
+		}
+		application.Spec.PdbTemplate = ResolvePodDisruptionBudget(app, wandb)
 
 		// Set shared service account for all W&B applications
 		application.Spec.PodTemplate.Spec.ServiceAccountName = serviceAccountName
@@ -731,6 +887,7 @@ func buildHTTPRouteTemplate(wandb *apiv2.WeightsAndBiases, app serverManifest.Ap
 	for _, h := range wandb.Spec.Wandb.AdditionalHostnames {
 		hostnames = append(hostnames, gatewayv1.Hostname(h))
 	}
+	hostnames = append(hostnames, ingressHostHostnames(wandb, app.Name)...)
 
 	var paths []string
 	var pathType string
@@ -740,11 +897,12 @@ func buildHTTPRouteTemplate(wandb *apiv2.WeightsAndBiases, app serverManifest.Ap
 	}
 
 	return &apiv2.HTTPRouteTemplateSpec{
-		ParentRefs:  []gatewayv1.ParentReference{parentRef},
+		ParentRefs:  append([]gatewayv1.ParentReference{parentRef}, ingressHostParentRefs(wandb, parentRef, app.Name)...),
 		Hostnames:   hostnames,
 		Paths:       paths,
 		PathType:    pathType,
 		ServicePort: resolveHTTPRouteServicePort(app),
+		Routing:     gatewayRouting(wandb, app.Name),
 	}
 }
 
@@ -1091,6 +1249,9 @@ func runMigrations(ctx context.Context, client ctrlClient.Client, wandb *apiv2.W
 			envVars = overrideEnvVars(ctx, envVars, wandb.Spec.Wandb.LegacyOverrides[apiv2.LegacyOverridesGlobalKey].Env)
 
 			podTemplate := corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: jobPodLabels(wandb, migrationJobComponent),
+				},
 				Spec: corev1.PodSpec{
 					RestartPolicy: corev1.RestartPolicyOnFailure,
 					Containers: []corev1.Container{
@@ -1114,11 +1275,7 @@ func runMigrations(ctx context.Context, client ctrlClient.Client, wandb *apiv2.W
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      jobName,
 					Namespace: wandb.Namespace,
-					Labels: map[string]string{
-						"app.kubernetes.io/managed-by": "wandb-operator",
-						"app.kubernetes.io/instance":   wandb.Name,
-						"app.kubernetes.io/component":  "migration",
-					},
+					Labels:    jobPodLabels(wandb, migrationJobComponent),
 				},
 				Spec: batchv1.JobSpec{
 					Template: podTemplate,
@@ -1186,6 +1343,7 @@ func runMigrations(ctx context.Context, client ctrlClient.Client, wandb *apiv2.W
 			}
 		}
 
+		observeMigrationJob(wandb, name, version, job, jobStatus)
 		wandb.Status.Wandb.Migration.Jobs[name] = jobStatus
 	}
 
@@ -1221,87 +1379,31 @@ func runMigrations(ctx context.Context, client ctrlClient.Client, wandb *apiv2.W
 	return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 }
 
-func generateSecrets(ctx context.Context, client ctrlClient.Client, wandb *apiv2.WeightsAndBiases, manifest serverManifest.Manifest) (ctrl.Result, error) {
-	statusBefore := wandb.DeepCopy().Status
-	// Ensure any manifest-declared generated secrets exist and capture their selectors in status
-	if wandb.Status.GeneratedSecrets == nil {
-		wandb.Status.GeneratedSecrets = map[string]corev1.SecretKeySelector{}
-	}
-	for _, gs := range manifest.GeneratedSecrets {
-		// Deterministic secret name scoped to the CR instance
-		// If UseExactName is true, use the exact name without prefixing
-		secretName := gs.Name
-		if !gs.UseExactName {
-			secretName = fmt.Sprintf("%s-%s", wandb.Name, gs.Name)
-		}
-		keyName := "key"
-		sec := &corev1.Secret{}
-		err := client.Get(ctx, types.NamespacedName{Name: secretName, Namespace: wandb.Namespace}, sec)
-		if err != nil {
-			if apiErrors.IsNotFound(err) {
-				// Create new secret with generated value
-				valueLen := gs.Length
-				if valueLen <= 0 {
-					valueLen = 32
-				}
-				pw, err := oputils.GenerateRandomPassword(valueLen)
-				if err != nil {
-					return ctrl.Result{}, err
-				}
-				sec = &corev1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      secretName,
-						Namespace: wandb.Namespace,
-						Labels: map[string]string{
-							"app.kubernetes.io/managed-by": "wandb-operator",
-							"app.kubernetes.io/instance":   wandb.Name,
-							"app.kubernetes.io/part-of":    "wandb",
-						},
-					},
-					StringData: map[string]string{keyName: pw},
-					Type:       corev1.SecretTypeOpaque,
-				}
-				if err := controllerutil.SetOwnerReference(wandb, sec, client.Scheme()); err != nil {
-					return ctrl.Result{}, err
-				}
-				if err := client.Create(ctx, sec); err != nil {
-					return ctrl.Result{}, err
-				}
-			} else {
-				return ctrl.Result{}, err
-			}
+// observeMigrationJob records a migration job's duration and outcome the first
+// time its status reaches a terminal phase, so requeues do not count it twice.
+func observeMigrationJob(wandb *apiv2.WeightsAndBiases, name, version string, job *batchv1.Job, jobStatus apiv2.MigrationJobStatus) {
+	if jobStatus.Phase != migrationPhaseSucceeded && jobStatus.Phase != migrationPhaseFailed {
+		return
+	}
+	if previous, ok := wandb.Status.Wandb.Migration.Jobs[name]; ok && previous.Phase == jobStatus.Phase {
+		return
+	}
+	var duration time.Duration
+	if job.Status.StartTime != nil {
+		finished := time.Now()
+		if job.Status.CompletionTime != nil {
+			finished = job.Status.CompletionTime.Time
 		} else {
-			// Secret exists. Ensure it has the expected key; do not overwrite existing value.
-			if sec.Data == nil || (sec.Data != nil && sec.Data[keyName] == nil && sec.StringData == nil) {
-				if sec.StringData == nil {
-					sec.StringData = map[string]string{}
-				}
-				// Generate a value only if missing
-				valueLen := gs.Length
-				if valueLen <= 0 {
-					valueLen = 32
-				}
-				pw, err := oputils.GenerateRandomPassword(valueLen)
-				if err != nil {
-					return ctrl.Result{}, err
-				}
-				sec.StringData[keyName] = pw
-				if err := client.Update(ctx, sec); err != nil {
-					return ctrl.Result{}, err
+			for _, cond := range job.Status.Conditions {
+				if cond.Type == batchv1.JobFailed && cond.Status == corev1.ConditionTrue {
+					finished = cond.LastTransitionTime.Time
+					break
 				}
 			}
 		}
-		// Record selector in status
-		wandb.Status.GeneratedSecrets[gs.Name] = corev1.SecretKeySelector{
-			LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
-			Key:                  keyName,
-		}
+		duration = finished.Sub(job.Status.StartTime.Time)
 	}
-	// Persist status after updating generated secret selectors
-	if err := updateWandbStatusIfChanged(ctx, client, wandb, statusBefore); err != nil {
-		return ctrl.Result{}, err
-	}
-	return ctrl.Result{}, nil
+	wmetrics.ObserveMigration(wandb.Namespace, wandb.Name, name, version, duration, jobStatus.Succeeded)
 }
 
 // resolveCRField traverses a dotted field path (e.g., "spec.wandb.license") in the
@@ -1420,7 +1522,7 @@ func recordInfraStateMetrics(wandb *apiv2.WeightsAndBiases) {
 	for key, s := range wandb.Status.ClickHouseStatus {
 		wmetrics.SetInfraState(ns, name, "clickhouse", key, s.State)
 	}
-	if wandb.Spec.Kafka.ManagedKafka != nil {
+	if wandb.Spec.Kafka.ManagedKafka != nil || wandb.Spec.Kafka.ExternalKafka != nil {
 		wmetrics.SetInfraState(ns, name, "kafka", "", wandb.Status.KafkaStatus.State)
 	}
 }
@@ -0,0 +1,81 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/observability/telemetry"
+	"github.com/wandb/operator/pkg/utils"
+	"github.com/wandb/operator/pkg/vendored/argo-rollouts/argoproj.io.rollouts/v1alpha1"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileApplicationsRendersManifestDaemonSets(t *testing.T) {
+	utils.AddServerResource("Rollout.argoproj.io/v1alpha1")
+	scheme := newCleanupFixtureScheme(t)
+	require.NoError(t, v1alpha1.AddToScheme(scheme))
+
+	wandb := rolloutStrategyTestWandb()
+	wandb.UID = "uid-wandb"
+	wandb.Spec.Tolerations = &[]corev1.Toleration{}
+	wandb.Spec.Networking.Mode = apiv2.NetworkingModeNone
+	wandb.Spec.Wandb.Applications = map[string]apiv2.WandbApplicationOverride{
+		"node-agent": {Autoscaling: &apiv2.ApplicationAutoscalingOverride{MinReplicas: ptr.To[int32](2)}},
+	}
+	wandb.Status.Wandb.Applications = map[string]apiv2.ApplicationStatus{}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(wandb).Build()
+
+	manifest := serverManifest.Manifest{Applications: map[string]serverManifest.Application{
+		"api":        {Name: "api", Image: serverManifest.ImageRef{Repository: "wandb/api", Tag: "1"}},
+		"node-agent": {Name: "node-agent", Kind: serverManifest.ApplicationKindDaemonSet, Image: serverManifest.ImageRef{Repository: "wandb/agent", Tag: "1"}},
+	}}
+	_, err := reconcileApplications(context.Background(), c, wandb, manifest, telemetry.TelemetryRuntimeConfig{})
+	require.NoError(t, err)
+
+	agent := &apiv2.Application{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "node-agent", Namespace: "default"}, agent))
+	require.Equal(t, "DaemonSet", agent.Spec.Kind)
+	require.Nil(t, agent.Spec.RolloutStrategy, "a DaemonSet ignores the rollout strategy")
+	require.Nil(t, agent.Spec.HpaTemplate, "a DaemonSet is not autoscaled")
+	require.Nil(t, agent.Spec.ScaledObjectTemplate)
+
+	api := &apiv2.Application{}
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "api", Namespace: "default"}, api))
+	require.Equal(t, "Rollout", api.Spec.Kind)
+}
+
+func TestApplicationsHealthyChecksDaemonSets(t *testing.T) {
+	daemonSet := func(desired, ready int32) *appsv1.DaemonSet {
+		return &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-agent", Namespace: "default", Generation: 1},
+			Status: appsv1.DaemonSetStatus{
+				ObservedGeneration:     1,
+				DesiredNumberScheduled: desired,
+				UpdatedNumberScheduled: desired,
+				NumberReady:            ready,
+			},
+		}
+	}
+	desired := map[string]bool{"api": true, "node-agent": true}
+	daemonSets := map[string]bool{"node-agent": true}
+	wandb := &apiv2.WeightsAndBiases{ObjectMeta: metav1.ObjectMeta{Name: "wandb", Namespace: "default"}}
+
+	c := fake.NewClientBuilder().WithScheme(newCleanupFixtureScheme(t)).
+		WithObjects(readyDeployment("api", "default"), daemonSet(3, 3)).Build()
+	healthy, blocked, _ := applicationsHealthy(context.Background(), c, wandb, desired, daemonSets)
+	require.True(t, healthy, "blocked: %v", blocked)
+
+	c = fake.NewClientBuilder().WithScheme(newCleanupFixtureScheme(t)).
+		WithObjects(readyDeployment("api", "default"), daemonSet(3, 2)).Build()
+	healthy, blocked, _ = applicationsHealthy(context.Background(), c, wandb, desired, daemonSets)
+	require.False(t, healthy)
+	require.Equal(t, []string{"node-agent"}, blocked)
+}
@@ -229,6 +229,52 @@ var _ = Describe("Infra Sizing", func() {
 			Expect(keeper.Replicas).To(Equal(int32(3)))  // from manifest small tier
 			Expect(keeper.StorageSize).To(Equal("20Gi")) // user override preserved
 		})
+
+		It("should default mysqld settings from the manifest, treating CR settings as overrides", func() {
+			wandb := &apiv2.WeightsAndBiases{
+				Spec: apiv2.WeightsAndBiasesSpec{
+					Size: "large",
+					MySQL: map[string]apiv2.MySQLSpec{apiv2.DefaultInstanceName: {ManagedMysql: &apiv2.ManagedMysqlSpec{
+						Config: apiv2.MySQLConfig{Mysqld: &apiv2.MysqldConfig{
+							Settings: map[string]string{"max-connections": "800"},
+						}},
+					}}},
+				},
+			}
+			manifest := serverManifest.Manifest{
+				Mysql: map[string]serverManifest.InfraConfig{
+					"default": {
+						Sizing: map[apiv2.Size]serverManifest.SizingConfig{
+							"default": {
+								Mysqld: map[string]string{"max_connections": "300", "long_query_time": "2"},
+							},
+							"large": {
+								Mysqld: map[string]string{"innodb_buffer_pool_size": "24G", "long-query-time": "1", "gtid_mode": "OFF"},
+							},
+						},
+					},
+				},
+			}
+			v2.ApplyInfraSizing(wandb, manifest)
+			Expect(wandb.Spec.MySQL[apiv2.DefaultInstanceName].ManagedMysql.Config.Mysqld.Settings).To(Equal(map[string]string{
+				"max-connections":         "800", // user override preserved
+				"innodb_buffer_pool_size": "24G", // from manifest large tier
+				"long-query-time":         "1",   // large tier replaces the default tier's long_query_time
+			}))
+		})
+
+		It("should resolve a manifest tier naming one mysqld option twice by name order", func() {
+			sizing := map[apiv2.Size]serverManifest.SizingConfig{
+				"default": {Mysqld: map[string]string{"long-query-time": "1", "long_query_time": "2"}},
+				"large":   {Mysqld: map[string]string{"max_connections": "300", "max-connections": "500"}},
+			}
+			for range 10 {
+				Expect(v2.ResolveInfraSizing(sizing, "large", false).Mysqld).To(Equal(map[string]string{
+					"long_query_time": "2",
+					"max_connections": "300",
+				}))
+			}
+		})
 	})
 
 	Context("ResolveKafkaSizing", func() {
@@ -9,6 +9,7 @@ import (
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/ptr"
 )
 
@@ -436,4 +437,115 @@ var _ = Describe("ReconcileV2 Sizing", func() {
 			Expect(hpa.MaxReplicas).To(Equal(int32(2)))
 		})
 	})
+
+	Context("resolvePodDisruptionBudget", func() {
+		It("should return nil when no sizing defines a budget", func() {
+			app := serverManifest.Application{
+				Sizing: map[apiv2.Size]serverManifest.SizingConfig{
+					"default": {Replicas: 2},
+				},
+			}
+			wandb := &apiv2.WeightsAndBiases{Spec: apiv2.WeightsAndBiasesSpec{Size: "small"}}
+
+			Expect(v2.ResolvePodDisruptionBudget(app, wandb)).To(BeNil())
+		})
+
+		It("should replace the default budget with the size-specific one", func() {
+			app := serverManifest.Application{
+				Sizing: map[apiv2.Size]serverManifest.SizingConfig{
+					"default": {
+						PodDisruptionBudget: &serverManifest.PodDisruptionBudgetConfig{
+							MinAvailable: ptr.To(intstr.FromInt32(1)),
+						},
+					},
+					"large": {
+						PodDisruptionBudget: &serverManifest.PodDisruptionBudgetConfig{
+							MaxUnavailable: ptr.To(intstr.FromString("25%")),
+						},
+					},
+				},
+			}
+
+			small := v2.ResolvePodDisruptionBudget(app, &apiv2.WeightsAndBiases{Spec: apiv2.WeightsAndBiasesSpec{Size: "small"}})
+			Expect(small).NotTo(BeNil())
+			Expect(small.MinAvailable).To(Equal(ptr.To(intstr.FromInt32(1))))
+			Expect(small.MaxUnavailable).To(BeNil())
+			Expect(small.Selector).To(BeNil())
+
+			large := v2.ResolvePodDisruptionBudget(app, &apiv2.WeightsAndBiases{Spec: apiv2.WeightsAndBiasesSpec{Size: "large"}})
+			Expect(large).NotTo(BeNil())
+			Expect(large.MinAvailable).To(BeNil())
+			Expect(large.MaxUnavailable).To(Equal(ptr.To(intstr.FromString("25%"))))
+		})
+	})
+
+	Context("resolveScaledObject", func() {
+		topics := []serverManifest.KafkaTopic{{Name: "weave-worker", Topic: "weave.call_ended"}}
+		kafkaLagApp := func() serverManifest.Application {
+			return serverManifest.Application{
+				Name: "weave-worker",
+				Sizing: map[apiv2.Size]serverManifest.SizingConfig{
+					"default": {
+						Autoscaling: &serverManifest.AutoscalingConfig{
+							Horizontal: autoscalingv2.HorizontalPodAutoscalerSpec{
+								MinReplicas: ptr.To(int32(1)),
+								MaxReplicas: 8,
+							},
+							KafkaLag: &serverManifest.KafkaLagTrigger{
+								KafkaTopicDef: serverManifest.KafkaTopicDef{
+									Topic:         "weave-worker",
+									ConsumerGroup: "weave-worker",
+								},
+								LagThreshold: 50,
+							},
+						},
+					},
+				},
+			}
+		}
+
+		It("should render a kafka trigger resolving the topic from the manifest topics", func() {
+			wandb := &apiv2.WeightsAndBiases{Spec: apiv2.WeightsAndBiasesSpec{Size: "small"}}
+
+			scaledObject := v2.ResolveScaledObject(kafkaLagApp(), wandb, topics, "kafka:9092")
+			Expect(scaledObject).NotTo(BeNil())
+			Expect(scaledObject.ScaleTargetRef).To(BeNil())
+			Expect(*scaledObject.MinReplicaCount).To(Equal(int32(1)))
+			Expect(*scaledObject.MaxReplicaCount).To(Equal(int32(8)))
+			Expect(scaledObject.Triggers).To(HaveLen(1))
+			Expect(scaledObject.Triggers[0].Type).To(Equal("kafka"))
+			Expect(scaledObject.Triggers[0].Metadata).To(Equal(map[string]string{
+				"bootstrapServers": "kafka:9092",
+				"consumerGroup":    "weave-worker",
+				"topic":            "weave.call_ended",
+				"lagThreshold":     "50",
+			}))
+		})
+
+		It("should apply spec.wandb.applications overrides to the replica bounds", func() {
+			wandb := &apiv2.WeightsAndBiases{
+				Spec: apiv2.WeightsAndBiasesSpec{
+					Size: "small",
+					Wandb: apiv2.WandbAppSpec{
+						Applications: map[string]apiv2.WandbApplicationOverride{
+							"weave-worker": {
+								Autoscaling: &apiv2.ApplicationAutoscalingOverride{MaxReplicas: ptr.To(int32(20))},
+							},
+						},
+					},
+				},
+			}
+
+			scaledObject := v2.ResolveScaledObject(kafkaLagApp(), wandb, topics, "kafka:9092")
+			Expect(scaledObject).NotTo(BeNil())
+			Expect(*scaledObject.MaxReplicaCount).To(Equal(int32(20)))
+		})
+
+		It("should return nil without a trigger or bootstrap servers", func() {
+			wandb := &apiv2.WeightsAndBiases{Spec: apiv2.WeightsAndBiasesSpec{Size: "small"}}
+
+			Expect(v2.ResolveScaledObject(kafkaLagApp(), wandb, topics, "")).To(BeNil())
+			Expect(v2.ResolveScaledObject(serverManifest.Application{}, wandb, topics, "kafka:9092")).To(BeNil())
+		})
+	})
 })
@@ -92,6 +92,16 @@ func runRedisRetentionFinalizer(ctx context.Context, c client.Client, wandb *api
 		return redisPurgeFinalizer(ctx, c, wandb, key, spec)
 	case apiv2.DetachOnDelete:
 		return redisDetachFinalizer(ctx, c, wandb, key, spec)
+	case apiv2.SnapshotOnDelete:
+		managed := spec.ManagedRedis
+		if managed == nil {
+			return redisPurgeFinalizer(ctx, c, wandb, key, spec)
+		}
+		return runSnapshotFinalizer(ctx, c, wandb, managed.Namespace,
+			opstree.ToRedisOnDeleteRule(wandb, wandb.GetRetentionPolicy(managed.ManagedInfraSpec)),
+			func() error { return redisPurgeFinalizer(ctx, c, wandb, key, spec) },
+			func() error { return redisDetachFinalizer(ctx, c, wandb, key, spec) },
+		)
 	}
 	return nil
 }
@@ -187,9 +197,23 @@ func managedRedisWriteState(
 	if conditions := opstree.CheckDetached(ctx, client, specNamespacedName, wandb.GetUID()); conditions != nil {
 		return conditions
 	}
+	adoptionConditions, proceed := opstree.AdoptDetached(ctx, client, specNamespacedName, wandb)
+	if !proceed {
+		return adoptionConditions
+	}
+
+	if common.InfraTLSEnabled(spec.TLS) {
+		secret, conditions := ensureManagedInfraTLS(
+			ctx, client, wandb, spec.TLS, specNamespacedName, opstree.ServerDNSNames(specNamespacedName), opstree.BuildWandbRedisLabels(wandb),
+		)
+		if conditions != nil {
+			return conditions
+		}
+		opstree.ApplyServerTLS(standaloneDesired, sentinelDesired, replicationDesired, secret)
+	}
 
 	results := opstree.WriteState(ctx, client, specNamespacedName, standaloneDesired, sentinelDesired, replicationDesired, opstree.BuildWandbRedisLabels(wandb))
-	return results
+	return append(results, adoptionConditions...)
 }
 
 func managedRedisReadState(
@@ -251,7 +275,12 @@ func externalRedisInferStatus(ctx context.Context, c client.Client, wandb *apiv2
 		WBInfraStatus: apiv2.WBInfraStatus{Ready: ready, State: state, Conditions: updatedConditions},
 		Connection:    *conn,
 	}
-	return ctrl.Result{}, updateWandbStatusIfChanged(ctx, c, wandb, statusBefore)
+	// Requeue so the connectivity probe runs again even when nothing else changes.
+	var result ctrl.Result
+	if newInfraConn != nil {
+		result.RequeueAfter = external.ProbeInterval
+	}
+	return result, updateWandbStatusIfChanged(ctx, c, wandb, statusBefore)
 }
 
 // helpers
@@ -0,0 +1,121 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	volumeSnapshotPollInterval = 10 * time.Second
+	volumeSnapshotsComponent   = "volume-snapshots"
+)
+
+// volumeSnapshotsConfigMapName is where the snapshot policy records the
+// snapshots it took. The ConfigMap has no owner, so it outlives the CR.
+func volumeSnapshotsConfigMapName(wandb *apiv2.WeightsAndBiases) string {
+	return wandb.Name + "-volume-snapshots"
+}
+
+// runSnapshotFinalizer snapshots the PVCs an instance's purge would delete
+// and purges once every snapshot is ready to use. Without the VolumeSnapshot
+// API it detaches instead, so the data is kept rather than lost.
+func runSnapshotFinalizer(
+	ctx context.Context,
+	client ctrlClient.Client,
+	wandb *apiv2.WeightsAndBiases,
+	namespace string,
+	onDeleteRule common.OnDeleteRule,
+	purge func() error,
+	detach func() error,
+) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	snapshots, err := common.SnapshotVolumeClaims(ctx, client, namespace, onDeleteRule.Selector)
+	if common.IsVolumeSnapshotAPIMissing(err) {
+		log.Info("VolumeSnapshot API is not available; detaching instead of purging",
+			"namespace", namespace, "selector", onDeleteRule.Selector.String())
+		return detach()
+	}
+	if err != nil {
+		return err
+	}
+	if err := recordVolumeSnapshots(ctx, client, wandb, snapshots); err != nil {
+		return err
+	}
+	for _, snapshot := range snapshots {
+		if !snapshot.ReadyToUse {
+			log.Info("Waiting for VolumeSnapshot to become ready to use",
+				"namespace", snapshot.Namespace, "name", snapshot.Name)
+			return common.ErrVolumeSnapshotsPending
+		}
+	}
+	return purge()
+}
+
+// recordVolumeSnapshots adds snapshots to the CR's volume snapshots
+// ConfigMap, keyed by "<namespace>.<snapshot>" with the source PVC as value.
+func recordVolumeSnapshots(
+	ctx context.Context,
+	client ctrlClient.Client,
+	wandb *apiv2.WeightsAndBiases,
+	snapshots []common.VolumeSnapshot,
+) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	configMap := &corev1.ConfigMap{}
+	nsName := types.NamespacedName{Namespace: wandb.Namespace, Name: volumeSnapshotsConfigMapName(wandb)}
+	err := client.Get(ctx, nsName, configMap)
+	if apiErrors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: nsName.Namespace,
+				Name:      nsName.Name,
+				Labels:    common.BuildWandbLabels(wandb, volumeSnapshotsComponent),
+			},
+			Data: map[string]string{},
+		}
+		for _, snapshot := range snapshots {
+			configMap.Data[snapshot.Namespace+"."+snapshot.Name] = snapshot.PVC
+		}
+		return client.Create(ctx, configMap)
+	}
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	if configMap.Data == nil {
+		configMap.Data = map[string]string{}
+	}
+	for _, snapshot := range snapshots {
+		key := snapshot.Namespace + "." + snapshot.Name
+		if configMap.Data[key] != snapshot.PVC {
+			configMap.Data[key] = snapshot.PVC
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return client.Update(ctx, configMap)
+}
+
+// retentionFinalizerResult polls while volume snapshots are being taken
+// instead of treating the wait as a failure.
+func retentionFinalizerResult(err error) (ctrl.Result, error) {
+	if errors.Is(err, common.ErrVolumeSnapshotsPending) {
+		return ctrl.Result{RequeueAfter: volumeSnapshotPollInterval}, nil
+	}
+	return ctrl.Result{}, err
+}
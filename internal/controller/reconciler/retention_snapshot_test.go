@@ -0,0 +1,129 @@
+package reconciler
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/controller/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func snapshotTestWandb() *apiv2.WeightsAndBiases {
+	return &apiv2.WeightsAndBiases{ObjectMeta: metav1.ObjectMeta{Name: "wandb", Namespace: "default"}}
+}
+
+func snapshotTestPVC(wandb *apiv2.WeightsAndBiases) *corev1.PersistentVolumeClaim {
+	return &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "data-mysql-0", Namespace: "infra", UID: types.UID("0f1e2d3c-aaaa"),
+			Labels: common.BuildWandbLabels(wandb, "mysql"),
+		},
+		Status: corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimBound},
+	}
+}
+
+func snapshotTestRule(wandb *apiv2.WeightsAndBiases) common.OnDeleteRule {
+	return common.ToOnDeleteRule(wandb, apiv2.RetentionPolicy{OnDelete: apiv2.SnapshotOnDelete}, "mysql")
+}
+
+func snapshotTestClient(t *testing.T, funcs *interceptor.Funcs, objects ...ctrlClient.Object) ctrlClient.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...)
+	if funcs != nil {
+		builder = builder.WithInterceptorFuncs(*funcs)
+	}
+	return builder.Build()
+}
+
+type finalizerCalls struct{ purged, detached bool }
+
+func (f *finalizerCalls) purge() error  { f.purged = true; return nil }
+func (f *finalizerCalls) detach() error { f.detached = true; return nil }
+
+func TestRunSnapshotFinalizerWaitsForSnapshotsBeforePurging(t *testing.T) {
+	wandb := snapshotTestWandb()
+	pvc := snapshotTestPVC(wandb)
+	c := snapshotTestClient(t, nil, pvc)
+	calls := &finalizerCalls{}
+
+	err := runSnapshotFinalizer(context.Background(), c, wandb, "infra", snapshotTestRule(wandb), calls.purge, calls.detach)
+	if !errors.Is(err, common.ErrVolumeSnapshotsPending) {
+		t.Fatalf("err = %v, want ErrVolumeSnapshotsPending", err)
+	}
+	if calls.purged || calls.detached {
+		t.Fatalf("nothing should run before the snapshots are ready: %+v", calls)
+	}
+	if res, err := retentionFinalizerResult(err); err != nil || res.RequeueAfter != volumeSnapshotPollInterval {
+		t.Fatalf("result = %+v, %v; want a poll without error", res, err)
+	}
+
+	snapshotName := common.VolumeSnapshotName(pvc)
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(context.Background(), ctrlClient.ObjectKey{Namespace: "default", Name: "wandb-volume-snapshots"}, configMap); err != nil {
+		t.Fatal(err)
+	}
+	if got := configMap.Data["infra."+snapshotName]; got != "data-mysql-0" {
+		t.Fatalf("recorded snapshots = %v, want infra.%s=data-mysql-0", configMap.Data, snapshotName)
+	}
+
+	snapshot := &unstructured.Unstructured{}
+	snapshot.SetGroupVersionKind(common.VolumeSnapshotGVK)
+	if err := c.Get(context.Background(), ctrlClient.ObjectKey{Namespace: "infra", Name: snapshotName}, snapshot); err != nil {
+		t.Fatal(err)
+	}
+	_ = unstructured.SetNestedField(snapshot.Object, true, "status", "readyToUse")
+	if err := c.Update(context.Background(), snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if err := runSnapshotFinalizer(context.Background(), c, wandb, "infra", snapshotTestRule(wandb), calls.purge, calls.detach); err != nil {
+		t.Fatal(err)
+	}
+	if !calls.purged || calls.detached {
+		t.Fatalf("ready snapshots should lead to a purge: %+v", calls)
+	}
+}
+
+func TestRunSnapshotFinalizerDetachesWithoutSnapshotAPI(t *testing.T) {
+	wandb := snapshotTestWandb()
+	c := snapshotTestClient(t, &interceptor.Funcs{
+		Get: func(ctx context.Context, c ctrlClient.WithWatch, key ctrlClient.ObjectKey, obj ctrlClient.Object, opts ...ctrlClient.GetOption) error {
+			if u, ok := obj.(*unstructured.Unstructured); ok && u.GroupVersionKind() == common.VolumeSnapshotGVK {
+				return &meta.NoKindMatchError{GroupKind: common.VolumeSnapshotGVK.GroupKind()}
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}, snapshotTestPVC(wandb))
+	calls := &finalizerCalls{}
+
+	if err := runSnapshotFinalizer(context.Background(), c, wandb, "infra", snapshotTestRule(wandb), calls.purge, calls.detach); err != nil {
+		t.Fatal(err)
+	}
+	if calls.purged || !calls.detached {
+		t.Fatalf("a cluster without snapshots should keep the data: %+v", calls)
+	}
+}
+
+func TestSnapshotOnDeleteRulePurgesOnlyFromFinalizer(t *testing.T) {
+	rule := snapshotTestRule(snapshotTestWandb())
+	if rule.Policy != common.Snapshot || !rule.Purges() {
+		t.Fatalf("rule = %+v, want a purging snapshot rule", rule)
+	}
+	if !rule.Selector.Matches(labels.Set(snapshotTestPVC(snapshotTestWandb()).Labels)) {
+		t.Fatal("the rule should select the instance's PVCs")
+	}
+}
@@ -0,0 +1,119 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/pkg/utils"
+	v1alpha1 "github.com/wandb/operator/pkg/vendored/argo-rollouts/argoproj.io.rollouts/v1alpha1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlClient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rolloutAbortedReason is the Ready reason while a canary was aborted, by hand
+// or by a failed analysis, and the application is back on its stable version.
+const rolloutAbortedReason = "RolloutAborted"
+
+// resolveRolloutStrategy returns the canary strategy for appName: the
+// per-application override, else spec.wandb.rolloutStrategy. Nil means the
+// application is rendered as a Deployment.
+func resolveRolloutStrategy(wandb *apiv2.WeightsAndBiases, appName string) *apiv2.RolloutStrategySpec {
+	if override, ok := wandb.Spec.Wandb.Applications[appName]; ok && override.RolloutStrategy != nil {
+		return override.RolloutStrategy
+	}
+	return wandb.Spec.Wandb.RolloutStrategy
+}
+
+// rolloutsEnabled reports whether the operator can render Argo Rollouts: it
+// runs with --enable-rollouts and the Rollout CRD is served by the cluster.
+func rolloutsEnabled(c ctrlClient.Client) bool {
+	return utils.IsRegistered(c.Scheme(), &v1alpha1.Rollout{})
+}
+
+// rolloutApplicationNames returns the desired applications rendered as
+// Rollouts.
+func rolloutApplicationNames(
+	c ctrlClient.Client,
+	wandb *apiv2.WeightsAndBiases,
+	desiredAppNames map[string]bool,
+) map[string]bool {
+	out := make(map[string]bool)
+	if !rolloutsEnabled(c) {
+		return out
+	}
+	for name := range desiredAppNames {
+		if resolveRolloutStrategy(wandb, name) != nil {
+			out[name] = true
+		}
+	}
+	return out
+}
+
+// applicationsHealthy extends deploymentsHealthy to applications rendered as
+// Rollouts, which are healthy once Argo has promoted the current spec, and to
+// daemonSetApps, the desired applications rendered as DaemonSets. Paused
+// and aborted canaries are reported with their step so the Ready message says
+// what is holding the rollout; aborted is true if any canary was aborted.
+func applicationsHealthy(
+	ctx context.Context,
+	c ctrlClient.Client,
+	wandb *apiv2.WeightsAndBiases,
+	desiredAppNames map[string]bool,
+	daemonSetApps map[string]bool,
+) (healthy bool, notReady []string, aborted bool) {
+	if len(desiredAppNames) == 0 {
+		return false, nil, false
+	}
+	rolloutApps := rolloutApplicationNames(c, wandb, desiredAppNames)
+	deploymentApps := make(map[string]bool, len(desiredAppNames))
+	for name := range desiredAppNames {
+		if daemonSetApps[name] {
+			delete(rolloutApps, name)
+		} else if !rolloutApps[name] {
+			deploymentApps[name] = true
+		}
+	}
+	if len(deploymentApps) > 0 {
+		_, notReady = deploymentsHealthy(ctx, c, wandb.Namespace, deploymentApps)
+	}
+	notReady = append(notReady, daemonSetsHealthy(ctx, c, wandb.Namespace, daemonSetApps)...)
+
+	for name := range rolloutApps {
+		rollout := &v1alpha1.Rollout{}
+		if err := c.Get(ctx, types.NamespacedName{Name: name, Namespace: wandb.Namespace}, rollout); err != nil {
+			notReady = append(notReady, name)
+			continue
+		}
+		status := rollout.Status
+		if status.Phase == v1alpha1.RolloutPhaseHealthy &&
+			status.ObservedGeneration == strconv.FormatInt(rollout.Generation, 10) {
+			continue
+		}
+		notReady = append(notReady, describeRolloutProgress(name, rollout))
+		if status.Abort {
+			aborted = true
+		}
+	}
+	sort.Strings(notReady)
+	return len(notReady) == 0, notReady, aborted
+}
+
+func describeRolloutProgress(name string, rollout *v1alpha1.Rollout) string {
+	status := rollout.Status
+	var totalSteps int
+	if rollout.Spec.Strategy.Canary != nil {
+		totalSteps = len(rollout.Spec.Strategy.Canary.Steps)
+	}
+	switch {
+	case status.Abort:
+		return fmt.Sprintf("%s (aborted)", name)
+	case (status.ControllerPause || len(status.PauseConditions) > 0) && status.CurrentStepIndex != nil:
+		return fmt.Sprintf("%s (paused at canary step %d/%d)", name, *status.CurrentStepIndex+1, totalSteps)
+	case status.CurrentStepIndex != nil && totalSteps > 0:
+		return fmt.Sprintf("%s (canary step %d/%d)", name, min(int(*status.CurrentStepIndex)+1, totalSteps), totalSteps)
+	}
+	return name
+}
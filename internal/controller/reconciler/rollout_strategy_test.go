@@ -0,0 +1,123 @@
+package reconciler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/pkg/utils"
+	"github.com/wandb/operator/pkg/vendored/argo-rollouts/argoproj.io.rollouts/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func rolloutStrategyTestWandb() *apiv2.WeightsAndBiases {
+	wandb := &apiv2.WeightsAndBiases{ObjectMeta: metav1.ObjectMeta{Name: "wandb", Namespace: "default"}}
+	wandb.Spec.Wandb.RolloutStrategy = &apiv2.RolloutStrategySpec{
+		Steps: []apiv2.CanaryStepSpec{{SetWeight: ptr.To[int32](25)}, {Pause: &apiv2.CanaryPauseSpec{}}},
+	}
+	return wandb
+}
+
+func TestResolveRolloutStrategyPrefersOverride(t *testing.T) {
+	wandb := rolloutStrategyTestWandb()
+	override := &apiv2.RolloutStrategySpec{Steps: []apiv2.CanaryStepSpec{{SetWeight: ptr.To[int32](10)}}}
+	wandb.Spec.Wandb.Applications = map[string]apiv2.WandbApplicationOverride{
+		"api":     {RolloutStrategy: override},
+		"console": {Autoscaling: &apiv2.ApplicationAutoscalingOverride{MinReplicas: ptr.To[int32](2)}},
+	}
+
+	require.Same(t, override, resolveRolloutStrategy(wandb, "api"))
+	require.Same(t, wandb.Spec.Wandb.RolloutStrategy, resolveRolloutStrategy(wandb, "console"))
+	require.Same(t, wandb.Spec.Wandb.RolloutStrategy, resolveRolloutStrategy(wandb, "parquet"))
+}
+
+func TestApplicationsHealthyChecksRollouts(t *testing.T) {
+	utils.AddServerResource("Rollout.argoproj.io/v1alpha1")
+	const namespace = "default"
+	desired := map[string]bool{"api": true, "console": true}
+	rollout := func(name string, status v1alpha1.RolloutStatus) *v1alpha1.Rollout {
+		r := &v1alpha1.Rollout{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Generation: 2},
+			Status:     status,
+		}
+		r.Spec.Strategy.Canary = &v1alpha1.CanaryStrategy{Steps: make([]v1alpha1.CanaryStep, 4)}
+		return r
+	}
+	promoted := v1alpha1.RolloutStatus{
+		Phase: v1alpha1.RolloutPhaseHealthy, ObservedGeneration: "2", CurrentStepIndex: ptr.To[int32](4),
+	}
+
+	tests := []struct {
+		name        string
+		rollouts    []*v1alpha1.Rollout
+		wantHealthy bool
+		wantBlocked []string
+		wantAborted bool
+	}{
+		{
+			name:        "promoted rollouts open the gate",
+			rollouts:    []*v1alpha1.Rollout{rollout("console", promoted), rollout("api", promoted)},
+			wantHealthy: true,
+		},
+		{
+			name:        "missing rollout blocks",
+			rollouts:    []*v1alpha1.Rollout{rollout("console", promoted)},
+			wantBlocked: []string{"api"},
+		},
+		{
+			name: "paused canary reports its step",
+			rollouts: []*v1alpha1.Rollout{rollout("console", promoted), rollout("api", v1alpha1.RolloutStatus{
+				Phase:              v1alpha1.RolloutPhasePaused,
+				ObservedGeneration: "2",
+				CurrentStepIndex:   ptr.To[int32](1),
+				PauseConditions:    []v1alpha1.PauseCondition{{Reason: v1alpha1.PauseReasonCanaryPauseStep}},
+			})},
+			wantBlocked: []string{"api (paused at canary step 2/4)"},
+		},
+		{
+			name: "aborted canary",
+			rollouts: []*v1alpha1.Rollout{rollout("console", promoted), rollout("api", v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseDegraded, ObservedGeneration: "2", Abort: true,
+			})},
+			wantBlocked: []string{"api (aborted)"},
+			wantAborted: true,
+		},
+		{
+			name: "healthy phase for an older generation blocks",
+			rollouts: []*v1alpha1.Rollout{rollout("console", promoted), rollout("api", v1alpha1.RolloutStatus{
+				Phase: v1alpha1.RolloutPhaseHealthy, ObservedGeneration: "1",
+			})},
+			wantBlocked: []string{"api"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scheme := newCleanupFixtureScheme(t)
+			require.NoError(t, v1alpha1.AddToScheme(scheme))
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			for _, r := range tc.rollouts {
+				builder = builder.WithObjects(r)
+			}
+			healthy, blocked, aborted := applicationsHealthy(context.Background(), builder.Build(), rolloutStrategyTestWandb(), desired, nil)
+			require.Equal(t, tc.wantHealthy, healthy)
+			require.Equal(t, tc.wantBlocked, blocked)
+			require.Equal(t, tc.wantAborted, aborted)
+		})
+	}
+}
+
+func TestApplicationsHealthyFallsBackToDeploymentsWithoutRollouts(t *testing.T) {
+	// Without --enable-rollouts the Rollout types are not registered and
+	// every application is a Deployment, whatever rolloutStrategy says.
+	c := fake.NewClientBuilder().WithScheme(newCleanupFixtureScheme(t)).
+		WithObjects(readyDeployment("api", "default")).Build()
+
+	healthy, blocked, aborted := applicationsHealthy(context.Background(), c, rolloutStrategyTestWandb(),
+		map[string]bool{"api": true}, nil)
+	require.True(t, healthy, "blocked: %v", blocked)
+	require.False(t, aborted)
+}
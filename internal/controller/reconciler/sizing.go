@@ -1,10 +1,16 @@
 package reconciler
 
 import (
+	"maps"
+	"slices"
+	"strconv"
+
+	kedav1alpha1 "github.com/kedacore/keda/v2/apis/keda/v1alpha1"
 	"github.com/wandb/operator/api/v2"
 	"github.com/wandb/operator/pkg/wandb/manifest"
 	v3 "k8s.io/api/autoscaling/v2"
 	"k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 )
 
 func ResolveResources(app manifest.Application, wandb *v2.WeightsAndBiases, containerResources *v1.ResourceRequirements) *v1.ResourceRequirements {
@@ -92,6 +98,105 @@ func ResolveAutoscaling(app manifest.Application, wandb *v2.WeightsAndBiases) *v
 	return hpa
 }
 
+// ResolveScaledObject returns a KEDA ScaledObject spec scaling the application
+// on Kafka consumer-group lag, or nil when its sizing declares no kafkaLag
+// trigger or the broker address is unknown. Replica bounds and behavior come
+// from the resolved HPA spec so spec.wandb.applications overrides apply to
+// both autoscalers alike. The scale target is left empty; the Application
+// controller points it at the workload.
+func ResolveScaledObject(app manifest.Application, wandb *v2.WeightsAndBiases, topics []manifest.KafkaTopic, bootstrapServers string) *kedav1alpha1.ScaledObjectSpec {
+	var trigger *manifest.KafkaLagTrigger
+
+	if defaultConfig, ok := app.Sizing["default"]; ok && defaultConfig.Autoscaling != nil && defaultConfig.Autoscaling.KafkaLag != nil {
+		trigger = defaultConfig.Autoscaling.KafkaLag
+	}
+
+	if sizeConfig, ok := app.Sizing[wandb.Spec.Size]; ok && sizeConfig.Autoscaling != nil && sizeConfig.Autoscaling.KafkaLag != nil {
+		trigger = sizeConfig.Autoscaling.KafkaLag
+	}
+
+	if trigger == nil || trigger.Topic == "" || trigger.ConsumerGroup == "" || bootstrapServers == "" {
+		return nil
+	}
+
+	topic := trigger.Topic
+	for _, t := range topics {
+		if t.Name == trigger.Topic && t.Topic != "" {
+			topic = t.Topic
+			break
+		}
+	}
+
+	metadata := map[string]string{
+		"bootstrapServers": bootstrapServers,
+		"consumerGroup":    trigger.ConsumerGroup,
+		"topic":            topic,
+	}
+	if trigger.LagThreshold > 0 {
+		metadata["lagThreshold"] = strconv.FormatInt(trigger.LagThreshold, 10)
+	}
+	if trigger.ActivationLagThreshold > 0 {
+		metadata["activationLagThreshold"] = strconv.FormatInt(trigger.ActivationLagThreshold, 10)
+	}
+
+	scaledObject := &kedav1alpha1.ScaledObjectSpec{
+		PollingInterval: trigger.PollingInterval,
+		CooldownPeriod:  trigger.CooldownPeriod,
+		Triggers: []kedav1alpha1.ScaleTriggers{{
+			Type:     "kafka",
+			Name:     "kafka-lag",
+			Metadata: metadata,
+		}},
+	}
+
+	if hpa := ResolveAutoscaling(app, wandb); hpa != nil {
+		scaledObject.MinReplicaCount = hpa.MinReplicas
+		maxReplicas := hpa.MaxReplicas
+		scaledObject.MaxReplicaCount = &maxReplicas
+		if hpa.Behavior != nil {
+			scaledObject.Advanced = &kedav1alpha1.AdvancedConfig{
+				HorizontalPodAutoscalerConfig: &kedav1alpha1.HorizontalPodAutoscalerConfig{
+					Behavior: hpa.Behavior,
+				},
+			}
+		}
+	}
+
+	return scaledObject
+}
+
+// ResolvePodDisruptionBudget returns the PodDisruptionBudget spec for an
+// application from its manifest sizing, or nil when none is configured. A
+// size-specific budget replaces the "default" one outright rather than merging,
+// since minAvailable and maxUnavailable are mutually exclusive. The selector is
+// left empty; the Application controller fills it from the pod labels.
+func ResolvePodDisruptionBudget(app manifest.Application, wandb *v2.WeightsAndBiases) *policyv1.PodDisruptionBudgetSpec {
+	var budget *manifest.PodDisruptionBudgetConfig
+
+	if defaultConfig, ok := app.Sizing["default"]; ok && defaultConfig.PodDisruptionBudget != nil {
+		budget = defaultConfig.PodDisruptionBudget
+	}
+
+	if sizeConfig, ok := app.Sizing[wandb.Spec.Size]; ok && sizeConfig.PodDisruptionBudget != nil {
+		budget = sizeConfig.PodDisruptionBudget
+	}
+
+	if budget == nil || (budget.MinAvailable == nil && budget.MaxUnavailable == nil) {
+		return nil
+	}
+
+	pdb := &policyv1.PodDisruptionBudgetSpec{}
+	if budget.MaxUnavailable != nil {
+		// maxUnavailable wins when a manifest sets both.
+		value := *budget.MaxUnavailable
+		pdb.MaxUnavailable = &value
+	} else {
+		value := *budget.MinAvailable
+		pdb.MinAvailable = &value
+	}
+	return pdb
+}
+
 // mergeResources merges an overlay ResourceRequirements into a base, with
 // overlay values taking precedence on a per-resource-name basis.
 func mergeResources(base, overlay *v1.ResourceRequirements, requireLimits bool) *v1.ResourceRequirements {
@@ -154,6 +259,7 @@ func ResolveInfraSizing(sizing map[v2.Size]manifest.SizingConfig, size v2.Size,
 		if defaultSizing.Resources != nil {
 			result.Resources = defaultSizing.Resources.DeepCopy()
 		}
+		result.Mysqld = mergeMysqldSettings(result.Mysqld, defaultSizing.Mysqld)
 	}
 
 	// Override with size-specific sizing, merging resources
@@ -174,11 +280,34 @@ func ResolveInfraSizing(sizing map[v2.Size]manifest.SizingConfig, size v2.Size,
 			result.MetadataVolumeSize = sizeSizing.MetadataVolumeSize
 		}
 		result.Resources = mergeResources(result.Resources, sizeSizing.Resources, requireLimits)
+		result.Mysqld = mergeMysqldSettings(result.Mysqld, sizeSizing.Mysqld)
 	}
 
 	return result
 }
 
+// mergeMysqldSettings merges overlay mysqld settings into base, matching
+// option names the way mysqld does, with overlay values taking precedence.
+// When one map names an option twice, the name sorting last wins.
+func mergeMysqldSettings(base, overlay map[string]string) map[string]string {
+	if len(overlay) == 0 {
+		return base
+	}
+	result := make(map[string]string, len(base)+len(overlay))
+	names := map[string]string{}
+	for _, settings := range []map[string]string{base, overlay} {
+		for _, k := range slices.Sorted(maps.Keys(settings)) {
+			name := v2.NormalizeMysqldSetting(k)
+			if existing, ok := names[name]; ok {
+				delete(result, existing)
+			}
+			names[name] = k
+			result[k] = settings[k]
+		}
+	}
+	return result
+}
+
 // ResolveKafkaSizing resolves a SizingConfig from the KafkaConfig for the given Size.
 func ResolveKafkaSizing(sizing map[v2.Size]manifest.KafkaSizingConfig, size v2.Size, requireLimits bool) *manifest.KafkaSizingConfig {
 	result := &manifest.KafkaSizingConfig{}
@@ -261,6 +390,7 @@ func ApplyInfraSizing(wandb *v2.WeightsAndBiases, manifest manifest.Manifest) {
 		if sizing.Resources != nil && len(spec.Config.Resources.Requests) == 0 && len(spec.Config.Resources.Limits) == 0 {
 			spec.Config.Resources = *sizing.Resources
 		}
+		applyMysqldSizing(&spec.Config, sizing.Mysqld)
 	}
 
 	// Redis
@@ -381,3 +511,28 @@ func ApplyInfraSizing(wandb *v2.WeightsAndBiases, manifest manifest.Manifest) {
 		}
 	}
 }
+
+// applyMysqldSizing fills in the manifest's mysqld settings for options the
+// user has not put in Settings; typed fields still win when the my.cnf is
+// rendered. Protected options are skipped, as the webhook never saw them.
+func applyMysqldSizing(config *v2.MySQLConfig, defaults map[string]string) {
+	if len(defaults) == 0 {
+		return
+	}
+	if config.Mysqld == nil {
+		config.Mysqld = &v2.MysqldConfig{}
+	}
+	set := map[string]bool{}
+	for k := range config.Mysqld.Settings {
+		set[v2.NormalizeMysqldSetting(k)] = true
+	}
+	for k, v := range defaults {
+		if set[v2.NormalizeMysqldSetting(k)] || v2.MysqldSettingProtected(k) {
+			continue
+		}
+		if config.Mysqld.Settings == nil {
+			config.Mysqld.Settings = map[string]string{}
+		}
+		config.Mysqld.Settings[k] = v
+	}
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	apiv2 "github.com/wandb/operator/api/v2"
+	v2 "github.com/wandb/operator/internal/controller/reconciler"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestMapConfigToWandb(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, apiv2.AddToScheme(scheme))
+
+	app := func(name, owner, refs string) *apiv2.Application {
+		return &apiv2.Application{ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   "default",
+			Annotations: map[string]string{v2.ApplicationConfigRefsAnnotation: refs},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: apiv2.GroupVersion.String(), Kind: "WeightsAndBiases", Name: owner, UID: types.UID(owner),
+			}},
+		}}
+	}
+	r := &WeightsAndBiasesReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		app("api", "wandb", "ConfigMap/files,Secret/mysql"),
+		app("console", "wandb", "Secret/mysql"),
+		app("parquet", "other", "Secret/oidc"),
+	).Build()}
+	ctx := context.Background()
+
+	secret := func(name string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+	require.Equal(t, []ctrl.Request{{NamespacedName: types.NamespacedName{Name: "wandb", Namespace: "default"}}},
+		r.mapConfigToWandb(ctx, secret("mysql")), "one request per CR, however many of its apps consume the Secret")
+	require.Equal(t, []ctrl.Request{{NamespacedName: types.NamespacedName{Name: "other", Namespace: "default"}}},
+		r.mapConfigToWandb(ctx, secret("oidc")))
+	require.Empty(t, r.mapConfigToWandb(ctx, secret("files")), "kind is part of the reference")
+	require.Empty(t, r.mapConfigToWandb(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "files", Namespace: "elsewhere"}}))
+	require.Len(t, r.mapConfigToWandb(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "files", Namespace: "default"}}), 1)
+}
@@ -24,8 +24,10 @@ import (
 	apiv2 "github.com/wandb/operator/api/v2"
 	v2 "github.com/wandb/operator/internal/controller/reconciler"
 	"github.com/wandb/operator/internal/observability/telemetry"
+	"github.com/wandb/operator/internal/observability/tracing"
 	"github.com/wandb/operator/pkg/utils"
 	"github.com/wandb/operator/pkg/wandb/spec/channel/deployer"
+	"go.opentelemetry.io/otel/attribute"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -65,6 +67,7 @@ type WeightsAndBiasesReconciler struct {
 //+kubebuilder:rbac:groups=apps.wandb.com,resources=weightsandbiases/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=update;delete;get;list;patch;create;watch
 //+kubebuilder:rbac:groups=batch,resources=cronjobs;jobs,verbs=get;list;watch;create;delete;update;patch
+//+kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=clickhouse.altinity.com,resources=clickhouseinstallations,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=clickhouse.altinity.com,resources=clickhouseinstallations/status,verbs=get
 //+kubebuilder:rbac:groups=clickhouse-keeper.altinity.com,resources=clickhousekeeperinstallations,verbs=get;list;watch;create;update;patch;delete
@@ -75,7 +78,7 @@ type WeightsAndBiasesReconciler struct {
 //+kubebuilder:rbac:groups=grafana.integreatly.org,resources=grafanas/status;grafanadashboards/status;grafanadatasources/status,verbs=get
 //+kubebuilder:rbac:groups=seaweed.seaweedfs.com,resources=seaweeds,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=seaweed.seaweedfs.com,resources=seaweeds/status,verbs=get
-//+kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlclusters,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=moco.cybozu.com,resources=backuppolicies;mysqlclusters,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=moco.cybozu.com,resources=mysqlclusters/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmagents;vmalerts;vmnodescrapes;vmpodscrapes;vmrules;vmservicescrapes;vmsingles;vlsingles;vtsingles,verbs=get;list;watch
 //+kubebuilder:rbac:groups=operator.victoriametrics.com,resources=vmagents/status;vmalerts/status;vmsingles/status;vlsingles/status;vtsingles/status,verbs=get
@@ -90,6 +93,8 @@ type WeightsAndBiasesReconciler struct {
 //+kubebuilder:rbac:groups=redis.redis.opstreelabs.in,resources=redis;redissentinels;redisreplications,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=redis.redis.opstreelabs.in,resources=redis/status,verbs=get
 //+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=nonroot-v2,verbs=use
+//+kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=create;get;list;watch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 //+kubebuilder:rbac:urls=/metrics,verbs=get
 
 // Deprecated/Erroneously required RBAC rules
@@ -124,7 +129,17 @@ func (r *WeightsAndBiasesReconciler) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
-	return v2.Reconcile(ctx, r.Client, r.Recorder, wandb, telemetryConfig)
+	// Tracing is best effort: an unreachable gateway must not stop reconciles.
+	if err := tracing.Configure(ctx, telemetryConfig); err != nil {
+		log.Error(err, "failed to configure operator tracing")
+	}
+	ctx, span := tracing.Start(ctx, "WeightsAndBiases.Reconcile",
+		attribute.String("k8s.namespace.name", req.Namespace),
+		attribute.String("wandb.name", req.Name),
+	)
+	result, err := v2.Reconcile(ctx, r.Client, r.Recorder, wandb, telemetryConfig)
+	tracing.End(span, err)
+	return result, err
 }
 
 // Delete was taken from original v1 reconciler
@@ -143,7 +158,14 @@ func (r *WeightsAndBiasesReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Owns(&batchv1.Job{}).
 		Owns(&corev1.Secret{}).
 		Owns(&corev1.ConfigMap{}).
-		Owns(&networkingv1.Ingress{})
+		Owns(&networkingv1.Ingress{}).
+		Owns(&networkingv1.NetworkPolicy{}).
+		// Secrets and ConfigMaps the applications consume but do not own
+		// (external DB passwords, OIDC and SMTP credentials) are mapped back
+		// through each Application's config-refs annotation, so editing one
+		// refreshes the config checksum of only the applications using it.
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.mapConfigToWandb)).
+		Watches(&corev1.ConfigMap{}, handler.EnqueueRequestsFromMapFunc(r.mapConfigToWandb))
 	if utils.IsRegistered(r.Scheme, &gatewayv1.Gateway{}) {
 		b = b.Watches(&gatewayv1.Gateway{}, handler.EnqueueRequestsFromMapFunc(r.mapGatewayToWandb))
 	}
@@ -198,6 +220,45 @@ func (r *WeightsAndBiasesReconciler) mapTelemetryConfigToWandb(ctx context.Conte
 	return requests
 }
 
+func (r *WeightsAndBiasesReconciler) mapConfigToWandb(ctx context.Context, obj client.Object) []ctrl.Request {
+	var kind string
+	switch obj.(type) {
+	case *corev1.Secret:
+		kind = "Secret"
+	case *corev1.ConfigMap:
+		kind = "ConfigMap"
+	default:
+		return nil
+	}
+
+	appList := &apiv2.ApplicationList{}
+	if err := r.List(ctx, appList, client.InNamespace(obj.GetNamespace())); err != nil {
+		return nil
+	}
+
+	seen := make(map[types.NamespacedName]bool)
+	requests := make([]ctrl.Request, 0)
+	for i := range appList.Items {
+		app := &appList.Items[i]
+		if !v2.ApplicationConsumesConfig(app.GetAnnotations(), kind, obj.GetName()) {
+			continue
+		}
+		for _, owner := range app.GetOwnerReferences() {
+			if owner.Kind != "WeightsAndBiases" || owner.APIVersion != apiv2.GroupVersion.String() {
+				continue
+			}
+			key := types.NamespacedName{Name: owner.Name, Namespace: app.Namespace}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			requests = append(requests, ctrl.Request{NamespacedName: key})
+		}
+	}
+
+	return requests
+}
+
 func (r *WeightsAndBiasesReconciler) mapGatewayToWandb(ctx context.Context, obj client.Object) []ctrl.Request {
 	gateway, ok := obj.(*gatewayv1.Gateway)
 	if !ok {
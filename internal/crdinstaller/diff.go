@@ -0,0 +1,335 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crdinstaller
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChangeKind classifies one difference between a composed CRD and the one in
+// the cluster.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "+"
+	ChangeRemoved ChangeKind = "-"
+	ChangeChanged ChangeKind = "~"
+	// ChangeTightened marks a schema change that can make objects already
+	// stored in the cluster invalid, e.g. a new required field or a narrower
+	// enum. Existing objects keep working until they are next written.
+	ChangeTightened ChangeKind = "!"
+)
+
+// Change is one difference, scoped to a CRD version and, for schema changes,
+// a field path such as ".spec.mysql.replicas".
+type Change struct {
+	Kind    ChangeKind
+	Version string
+	Path    string
+	Detail  string
+}
+
+func (c Change) String() string {
+	var b strings.Builder
+	b.WriteString(string(c.Kind))
+	if c.Version != "" {
+		b.WriteString(" ")
+		b.WriteString(c.Version)
+	}
+	if c.Path != "" {
+		b.WriteString(" ")
+		b.WriteString(c.Path)
+	}
+	if c.Detail != "" {
+		b.WriteString(": ")
+		b.WriteString(c.Detail)
+	}
+	return b.String()
+}
+
+// CRDDiff lists the changes applying one composed CRD would make.
+type CRDDiff struct {
+	Name    string
+	New     bool
+	Changes []Change
+}
+
+// Tightened reports whether any change can invalidate existing objects.
+func (d CRDDiff) Tightened() bool {
+	return slices.ContainsFunc(d.Changes, func(c Change) bool { return c.Kind == ChangeTightened })
+}
+
+// Diff compares every selected CRD against the cluster without changing
+// anything. Only versions and their schemas are compared; server-populated
+// fields and the caBundle owned by cert-manager would otherwise always show
+// up. CRDs with no changes are omitted.
+func Diff(ctx context.Context, opts Options, client apiextensionsclient.Interface) ([]CRDDiff, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	crds, err := compose(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []CRDDiff
+	for _, crd := range crds {
+		live, err := client.ApiextensionsV1().CustomResourceDefinitions().Get(ctx, crd.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			out = append(out, CRDDiff{Name: crd.Name, New: true})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting CRD %s: %w", crd.Name, err)
+		}
+		if changes := diffCRD(live, crd); len(changes) > 0 {
+			out = append(out, CRDDiff{Name: crd.Name, Changes: changes})
+		}
+	}
+	return out, nil
+}
+
+// WriteDiff prints diffs in a stable, line-oriented format.
+func WriteDiff(out io.Writer, diffs []CRDDiff) error {
+	if len(diffs) == 0 {
+		_, err := io.WriteString(out, "no changes\n")
+		return err
+	}
+	for _, d := range diffs {
+		header := d.Name
+		if d.New {
+			header += " (new)"
+		}
+		if _, err := fmt.Fprintln(out, header); err != nil {
+			return err
+		}
+		for _, c := range d.Changes {
+			if _, err := fmt.Fprintf(out, "  %s\n", c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func diffCRD(live, desired *apiextensionsv1.CustomResourceDefinition) []Change {
+	var changes []Change
+	liveVersions := versionsByName(live)
+	desiredVersions := versionsByName(desired)
+
+	for _, v := range desired.Spec.Versions {
+		old, ok := liveVersions[v.Name]
+		if !ok {
+			changes = append(changes, Change{Kind: ChangeAdded, Version: v.Name, Detail: "version"})
+			continue
+		}
+		if old.Served != v.Served {
+			changes = append(changes, Change{Kind: ChangeChanged, Version: v.Name, Detail: fmt.Sprintf("served %t -> %t", old.Served, v.Served)})
+		}
+		if old.Storage != v.Storage {
+			changes = append(changes, Change{Kind: ChangeChanged, Version: v.Name, Detail: fmt.Sprintf("storage %t -> %t", old.Storage, v.Storage)})
+		}
+		var oldSchema, newSchema *apiextensionsv1.JSONSchemaProps
+		if old.Schema != nil {
+			oldSchema = old.Schema.OpenAPIV3Schema
+		}
+		if v.Schema != nil {
+			newSchema = v.Schema.OpenAPIV3Schema
+		}
+		for _, c := range diffSchema("", oldSchema, newSchema) {
+			c.Version = v.Name
+			changes = append(changes, c)
+		}
+	}
+	for _, v := range live.Spec.Versions {
+		if _, ok := desiredVersions[v.Name]; ok {
+			continue
+		}
+		// The API server refuses to drop a version that is still listed in
+		// status.storedVersions.
+		if slices.Contains(live.Status.StoredVersions, v.Name) {
+			changes = append(changes, Change{Kind: ChangeTightened, Version: v.Name, Detail: "version removed but still in status.storedVersions; run migrate-storage first"})
+			continue
+		}
+		changes = append(changes, Change{Kind: ChangeRemoved, Version: v.Name, Detail: "version"})
+	}
+	return changes
+}
+
+func versionsByName(crd *apiextensionsv1.CustomResourceDefinition) map[string]apiextensionsv1.CustomResourceDefinitionVersion {
+	out := make(map[string]apiextensionsv1.CustomResourceDefinitionVersion, len(crd.Spec.Versions))
+	for _, v := range crd.Spec.Versions {
+		out[v.Name] = v
+	}
+	return out
+}
+
+// diffSchema walks two schemas in parallel and reports added and removed
+// fields plus any constraint that got stricter at the same path.
+func diffSchema(path string, old, desired *apiextensionsv1.JSONSchemaProps) []Change {
+	if old == nil && desired == nil {
+		return nil
+	}
+	display := path
+	if display == "" {
+		display = "."
+	}
+	if old == nil {
+		return []Change{{Kind: ChangeAdded, Path: display}}
+	}
+	if desired == nil {
+		return []Change{{Kind: ChangeRemoved, Path: display}}
+	}
+
+	var changes []Change
+	tightened := func(format string, args ...any) {
+		changes = append(changes, Change{Kind: ChangeTightened, Path: display, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	if old.Type != desired.Type && old.Type != "" {
+		tightened("type %s -> %s", old.Type, desired.Type)
+	}
+	if desired.Format != old.Format && desired.Format != "" {
+		tightened("format %q -> %q", old.Format, desired.Format)
+	}
+	if desired.Pattern != old.Pattern && desired.Pattern != "" {
+		tightened("pattern %q -> %q", old.Pattern, desired.Pattern)
+	}
+	if old.Nullable && !desired.Nullable {
+		tightened("no longer nullable")
+	}
+	if preserves(old) && !preserves(desired) {
+		tightened("unknown fields are now pruned")
+	}
+	for _, name := range desired.Required {
+		if !slices.Contains(old.Required, name) {
+			tightened("%q is now required", name)
+		}
+	}
+	diffEnum(old.Enum, desired.Enum, tightened)
+	diffLowerBound("minimum", old.Minimum, desired.Minimum, tightened)
+	diffUpperBound("maximum", old.Maximum, desired.Maximum, tightened)
+	diffLowerBound("minLength", intToFloat(old.MinLength), intToFloat(desired.MinLength), tightened)
+	diffUpperBound("maxLength", intToFloat(old.MaxLength), intToFloat(desired.MaxLength), tightened)
+	diffLowerBound("minItems", intToFloat(old.MinItems), intToFloat(desired.MinItems), tightened)
+	diffUpperBound("maxItems", intToFloat(old.MaxItems), intToFloat(desired.MaxItems), tightened)
+	diffLowerBound("minProperties", intToFloat(old.MinProperties), intToFloat(desired.MinProperties), tightened)
+	diffUpperBound("maxProperties", intToFloat(old.MaxProperties), intToFloat(desired.MaxProperties), tightened)
+	for _, rule := range desired.XValidations {
+		if !slices.ContainsFunc(old.XValidations, func(r apiextensionsv1.ValidationRule) bool { return r.Rule == rule.Rule }) {
+			tightened("new validation rule %q", rule.Rule)
+		}
+	}
+
+	names := make(map[string]bool, len(old.Properties)+len(desired.Properties))
+	for name := range old.Properties {
+		names[name] = true
+	}
+	for name := range desired.Properties {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		changes = append(changes, diffSchema(path+"."+name, propertyPtr(old.Properties, name), propertyPtr(desired.Properties, name))...)
+	}
+
+	changes = append(changes, diffSchema(path+"[*]", itemsSchema(old), itemsSchema(desired))...)
+	changes = append(changes, diffSchema(path+".*", additionalSchema(old), additionalSchema(desired))...)
+	return changes
+}
+
+func diffEnum(old, desired []apiextensionsv1.JSON, tightened func(string, ...any)) {
+	if len(desired) == 0 {
+		return
+	}
+	if len(old) == 0 {
+		tightened("values restricted to an enum")
+		return
+	}
+	for _, value := range old {
+		if !slices.ContainsFunc(desired, func(v apiextensionsv1.JSON) bool { return string(v.Raw) == string(value.Raw) }) {
+			tightened("enum value %s removed", value.Raw)
+		}
+	}
+}
+
+func diffLowerBound(name string, old, desired *float64, tightened func(string, ...any)) {
+	if desired != nil && (old == nil || *desired > *old) {
+		tightened("%s %s -> %g", name, boundString(old), *desired)
+	}
+}
+
+func diffUpperBound(name string, old, desired *float64, tightened func(string, ...any)) {
+	if desired != nil && (old == nil || *desired < *old) {
+		tightened("%s %s -> %g", name, boundString(old), *desired)
+	}
+}
+
+func boundString(v *float64) string {
+	if v == nil {
+		return "unset"
+	}
+	return fmt.Sprintf("%g", *v)
+}
+
+func intToFloat(v *int64) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := float64(*v)
+	return &f
+}
+
+func preserves(s *apiextensionsv1.JSONSchemaProps) bool {
+	return s.XPreserveUnknownFields != nil && *s.XPreserveUnknownFields
+}
+
+func propertyPtr(props map[string]apiextensionsv1.JSONSchemaProps, name string) *apiextensionsv1.JSONSchemaProps {
+	p, ok := props[name]
+	if !ok {
+		return nil
+	}
+	return &p
+}
+
+func itemsSchema(s *apiextensionsv1.JSONSchemaProps) *apiextensionsv1.JSONSchemaProps {
+	if s.Items == nil {
+		return nil
+	}
+	return s.Items.Schema
+}
+
+func additionalSchema(s *apiextensionsv1.JSONSchemaProps) *apiextensionsv1.JSONSchemaProps {
+	if s.AdditionalProperties == nil {
+		return nil
+	}
+	return s.AdditionalProperties.Schema
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+*/
+
+package crdinstaller
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func schemaCRD(storedVersions []string, versions ...apiextensionsv1.CustomResourceDefinitionVersion) *apiextensionsv1.CustomResourceDefinition {
+	crd := &apiextensionsv1.CustomResourceDefinition{}
+	crd.Name = "widgets.apps.wandb.com"
+	crd.Spec.Versions = versions
+	crd.Status.StoredVersions = storedVersions
+	return crd
+}
+
+func schemaVersion(name string, storage bool, props map[string]apiextensionsv1.JSONSchemaProps, required ...string) apiextensionsv1.CustomResourceDefinitionVersion {
+	return apiextensionsv1.CustomResourceDefinitionVersion{
+		Name:    name,
+		Served:  true,
+		Storage: storage,
+		Schema: &apiextensionsv1.CustomResourceValidation{
+			OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]apiextensionsv1.JSONSchemaProps{
+					"spec": {Type: "object", Properties: props, Required: required},
+				},
+			},
+		},
+	}
+}
+
+func int64Ptr(v int64) *int64 { return &v }
+
+func TestDiffCRDReportsFieldsVersionsAndTightening(t *testing.T) {
+	live := schemaCRD([]string{"v1", "v2"},
+		schemaVersion("v1", false, nil),
+		schemaVersion("v2", true, map[string]apiextensionsv1.JSONSchemaProps{
+			"size":    {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"small"`)}, {Raw: []byte(`"large"`)}}},
+			"legacy":  {Type: "string"},
+			"workers": {Type: "integer"},
+		}),
+	)
+	desired := schemaCRD(nil,
+		schemaVersion("v2", true, map[string]apiextensionsv1.JSONSchemaProps{
+			"size":    {Type: "string", Enum: []apiextensionsv1.JSON{{Raw: []byte(`"small"`)}}},
+			"workers": {Type: "integer", MaxItems: int64Ptr(3)},
+			"region":  {Type: "string"},
+		}, "region"),
+		schemaVersion("v3", false, nil),
+	)
+
+	var got []string
+	for _, c := range diffCRD(live, desired) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`! v2 .spec: "region" is now required`,
+		`- v2 .spec.legacy`,
+		`+ v2 .spec.region`,
+		`! v2 .spec.size: enum value "large" removed`,
+		`! v2 .spec.workers: maxItems unset -> 3`,
+		`+ v3: version`,
+		`! v1: version removed but still in status.storedVersions; run migrate-storage first`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("diff =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestDiffCRDIgnoresLooserSchema(t *testing.T) {
+	live := schemaCRD([]string{"v2"}, schemaVersion("v2", true, map[string]apiextensionsv1.JSONSchemaProps{
+		"replicas": {Type: "integer", MaxLength: int64Ptr(3)},
+	}, "replicas"))
+	desired := schemaCRD(nil, schemaVersion("v2", true, map[string]apiextensionsv1.JSONSchemaProps{
+		"replicas": {Type: "integer", MaxLength: int64Ptr(10)},
+	}))
+	if changes := diffCRD(live, desired); len(changes) != 0 {
+		t.Errorf("expected no changes for a looser schema, got %v", changes)
+	}
+}
+
+func TestDiffAgainstCluster(t *testing.T) {
+	crds, err := compose(validOpts)
+	if err != nil {
+		t.Fatalf("compose failed: %v", err)
+	}
+	// Only the first CRD is installed, exactly as composed.
+	client := fake.NewSimpleClientset([]runtime.Object{crds[0]}...)
+
+	diffs, err := Diff(context.Background(), validOpts, client)
+	if err != nil {
+		t.Fatalf("diff failed: %v", err)
+	}
+	if len(diffs) != 1 || diffs[0].Name != crds[1].Name || !diffs[0].New {
+		t.Fatalf("expected only %s to be reported as new, got %+v", crds[1].Name, diffs)
+	}
+
+	var out bytes.Buffer
+	if err := WriteDiff(&out, diffs); err != nil {
+		t.Fatalf("write diff: %v", err)
+	}
+	if got, want := out.String(), crds[1].Name+" (new)\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crdinstaller
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/retry"
+)
+
+// migrateListPageSize bounds how many objects one list call returns while
+// rewriting a CRD's objects.
+const migrateListPageSize = 500
+
+// MigrateStorage rewrites every WeightsAndBiases and Application object in the
+// storage version of its CRD, then prunes status.storedVersions down to that
+// version so older versions can stop being served and eventually be removed.
+//
+// Each object is re-written unchanged: the API server decodes it from etcd,
+// converts it and stores it again in the current storage version. CRDs whose
+// storedVersions already only list the storage version are left alone, so the
+// command is safe to re-run.
+func MigrateStorage(ctx context.Context, client apiextensionsclient.Interface, dyn dynamic.Interface, logger *slog.Logger) error {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	// The conversion webhook settings don't matter here, only the names.
+	crds, err := compose(Options{})
+	if err != nil {
+		return err
+	}
+	for _, crd := range crds {
+		if err := migrateOne(ctx, client, dyn, crd.Name, logger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func migrateOne(ctx context.Context, client apiextensionsclient.Interface, dyn dynamic.Interface, name string, logger *slog.Logger) error {
+	crds := client.ApiextensionsV1().CustomResourceDefinitions()
+	crd, err := crds.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		logger.Info("CRD not installed, skipping", "name", name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("getting CRD %s: %w", name, err)
+	}
+
+	storage := storageVersion(crd)
+	if storage == "" {
+		return fmt.Errorf("CRD %s has no storage version", name)
+	}
+	if slices.Equal(crd.Status.StoredVersions, []string{storage}) {
+		logger.Info("stored versions already current", "name", name, "storageVersion", storage)
+		return nil
+	}
+
+	gvr := schema.GroupVersionResource{Group: crd.Spec.Group, Version: storage, Resource: crd.Spec.Names.Plural}
+	migrated, err := rewriteObjects(ctx, dyn.Resource(gvr))
+	if err != nil {
+		return fmt.Errorf("migrating %s objects to %s: %w", name, storage, err)
+	}
+	logger.Info("rewrote objects in storage version", "name", name, "storageVersion", storage, "count", migrated)
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		crd, err := crds.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		previous := crd.Status.StoredVersions
+		crd.Status.StoredVersions = []string{storage}
+		if _, err := crds.UpdateStatus(ctx, crd, metav1.UpdateOptions{FieldManager: FieldManager}); err != nil {
+			return err
+		}
+		logger.Info("pruned stored versions", "name", name, "previous", previous, "storedVersions", crd.Status.StoredVersions)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("updating storedVersions of CRD %s: %w", name, err)
+	}
+	return nil
+}
+
+// rewriteObjects issues a no-op update for every object of the resource,
+// across all namespaces, and returns how many were rewritten.
+func rewriteObjects(ctx context.Context, resource dynamic.NamespaceableResourceInterface) (int, error) {
+	count := 0
+	opts := metav1.ListOptions{Limit: migrateListPageSize}
+	for {
+		list, err := resource.List(ctx, opts)
+		if err != nil {
+			return count, err
+		}
+		for i := range list.Items {
+			obj := &list.Items[i]
+			objects := resource.Namespace(obj.GetNamespace())
+			err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				_, err := objects.Update(ctx, obj, metav1.UpdateOptions{FieldManager: FieldManager})
+				if apierrors.IsConflict(err) {
+					latest, getErr := objects.Get(ctx, obj.GetName(), metav1.GetOptions{})
+					if getErr != nil {
+						return getErr
+					}
+					obj = latest
+				}
+				return err
+			})
+			if apierrors.IsNotFound(err) {
+				continue // deleted while migrating; nothing left to rewrite
+			}
+			if err != nil {
+				return count, fmt.Errorf("rewriting %s/%s: %w", obj.GetNamespace(), obj.GetName(), err)
+			}
+			count++
+		}
+		if list.GetContinue() == "" {
+			return count, nil
+		}
+		opts.Continue = list.GetContinue()
+	}
+}
+
+func storageVersion(crd *apiextensionsv1.CustomResourceDefinition) string {
+	for _, v := range crd.Spec.Versions {
+		if v.Storage {
+			return v.Name
+		}
+	}
+	return ""
+}
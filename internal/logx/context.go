@@ -3,10 +3,23 @@ package logx
 import (
 	"context"
 	"log/slog"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TraceIDKey = "trace_id"
+	SpanIDKey  = "span_id"
 )
 
 type logKey struct{}
 
+// untracedLogKey and untracedLogrKey hold the loggers before trace attributes
+// were added, so a child span replaces its parent's IDs instead of appending.
+type untracedLogKey struct{}
+type untracedLogrKey struct{}
+
 func WithSlog(ctx context.Context, logName string) (context.Context, *slog.Logger) {
 	if log, ok := ctx.Value(logKey{}).(*slog.Logger); ok {
 		if log.Handler().(*Handler).loggerName == logName {
@@ -14,6 +27,8 @@ func WithSlog(ctx context.Context, logName string) (context.Context, *slog.Logge
 		}
 	}
 	log := NewSlogLogger(logName)
+	ctx = context.WithValue(ctx, untracedLogKey{}, log)
+	log = withTraceAttrs(ctx, log)
 	return context.WithValue(ctx, logKey{}, log), log
 }
 
@@ -23,3 +38,40 @@ func GetSlog(ctx context.Context) *slog.Logger {
 	}
 	return NewSlogLogger("unknown")
 }
+
+// WithTraceContext attaches the trace and span IDs of the span in ctx to the
+// slog and logr loggers carried by ctx, so every record logged during the
+// span can be found from the trace and vice versa.
+func WithTraceContext(ctx context.Context) context.Context {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return ctx
+	}
+
+	if _, ok := ctx.Value(logKey{}).(*slog.Logger); ok {
+		base, ok := ctx.Value(untracedLogKey{}).(*slog.Logger)
+		if !ok {
+			base = GetSlog(ctx)
+			ctx = context.WithValue(ctx, untracedLogKey{}, base)
+		}
+		ctx = context.WithValue(ctx, logKey{}, withTraceAttrs(ctx, base))
+	}
+
+	if current, err := logr.FromContext(ctx); err == nil {
+		base, ok := ctx.Value(untracedLogrKey{}).(logr.Logger)
+		if !ok {
+			base = current
+			ctx = context.WithValue(ctx, untracedLogrKey{}, base)
+		}
+		ctx = logr.NewContext(ctx, base.WithValues(TraceIDKey, sc.TraceID().String(), SpanIDKey, sc.SpanID().String()))
+	}
+	return ctx
+}
+
+func withTraceAttrs(ctx context.Context, log *slog.Logger) *slog.Logger {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return log
+	}
+	return log.With(slog.String(TraceIDKey, sc.TraceID().String()), slog.String(SpanIDKey, sc.SpanID().String()))
+}
@@ -70,9 +70,12 @@ func SetInfraState(namespace, name, component, instanceName, state string) {
 	}).Set(1)
 }
 
-// DeleteWeightsAndBiasesMetrics clears the readiness and infra-state series for
-// a CR being torn down, so a deleted resource doesn't linger as Ready forever.
+// DeleteWeightsAndBiasesMetrics clears every per-CR series (readiness, infra
+// state, reconcile phases, manifests, migrations, Kafka topics and generated
+// secrets) for a CR being torn down, so a deleted resource doesn't linger as
+// Ready forever.
 func DeleteWeightsAndBiasesMetrics(namespace, name string) {
+	deleteReconcileMetrics(namespace, name)
 	WeightsAndBiasesReady.DeletePartialMatch(prometheus.Labels{
 		"namespace": namespace,
 		"name":      name,
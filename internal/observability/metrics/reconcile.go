@@ -0,0 +1,246 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// ReconcilePhaseDuration times each reconcile phase (manifest.fetch,
+// mysql.write, migrations, applications, ...) so SLOs can tell a slow
+// manifest pull from a slow Kafka admin call.
+var ReconcilePhaseDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "wandb_reconcile_phase_duration_seconds",
+		Help:    "Time spent in each phase of a WeightsAndBiases reconcile.",
+		Buckets: prometheus.ExponentialBuckets(0.01, 2, 15),
+	},
+	[]string{"namespace", "name", "phase", "outcome"},
+)
+
+// ManifestDownloads counts server manifest pulls from the remote registry.
+var ManifestDownloads = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "wandb_manifest_downloads_total",
+		Help: "Server manifest downloads from the remote registry, by outcome.",
+	},
+	[]string{"namespace", "name", "outcome"},
+)
+
+// ManifestCacheHits counts server manifests served from the on-disk cache.
+var ManifestCacheHits = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "wandb_manifest_cache_hits_total",
+		Help: "Server manifests resolved from the local cache without a registry pull.",
+	},
+	[]string{"namespace", "name"},
+)
+
+// MigrationDuration records each finished migration job once, from job start
+// to completion or failure.
+var MigrationDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "wandb_migration_duration_seconds",
+		Help:    "Duration of finished migration jobs, by migration, server version and outcome.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 14),
+	},
+	[]string{"namespace", "name", "migration", "version", "outcome"},
+)
+
+// KafkaTopicErrors counts failed Kafka topic creations.
+var KafkaTopicErrors = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "wandb_kafka_topic_errors_total",
+		Help: "Kafka topic creation errors, by topic.",
+	},
+	[]string{"namespace", "name", "topic"},
+)
+
+// GeneratedSecrets is the number of operator-generated secrets a CR owns.
+var GeneratedSecrets = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "wandb_generated_secrets",
+		Help: "Number of generated secrets managed for a WeightsAndBiases custom resource.",
+	},
+	[]string{"namespace", "name"},
+)
+
+// ReadyTransitionAge reports how long ago each CR's Ready condition last
+// flipped. The age is computed at scrape time from the recorded transition.
+var ReadyTransitionAge = newReadyTransitionCollector()
+
+func init() {
+	metrics.Registry.MustRegister(
+		ReconcilePhaseDuration,
+		ManifestDownloads,
+		ManifestCacheHits,
+		MigrationDuration,
+		KafkaTopicErrors,
+		GeneratedSecrets,
+		ReadyTransitionAge,
+	)
+}
+
+type crKey struct{}
+
+type crLabels struct {
+	namespace string
+	name      string
+}
+
+// WithWeightsAndBiases tags ctx with the CR being reconciled, so code that
+// does not otherwise know about the CR (such as the manifest downloader) can
+// label its metrics with it.
+func WithWeightsAndBiases(ctx context.Context, namespace, name string) context.Context {
+	return context.WithValue(ctx, crKey{}, crLabels{namespace: namespace, name: name})
+}
+
+func crFromContext(ctx context.Context) (crLabels, bool) {
+	labels, ok := ctx.Value(crKey{}).(crLabels)
+	return labels, ok
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeFailure
+	}
+	return OutcomeSuccess
+}
+
+// ObserveReconcilePhase records a phase duration for the CR in ctx. Phases
+// run outside a CR reconcile are not recorded.
+func ObserveReconcilePhase(ctx context.Context, phase string, duration time.Duration, err error) {
+	cr, ok := crFromContext(ctx)
+	if !ok {
+		return
+	}
+	ReconcilePhaseDuration.With(prometheus.Labels{
+		"namespace": cr.namespace,
+		"name":      cr.name,
+		"phase":     phase,
+		"outcome":   outcome(err),
+	}).Observe(duration.Seconds())
+}
+
+// RecordManifestDownload counts a remote manifest pull for the CR in ctx.
+// Pulls outside a CR reconcile, e.g. from the admission webhook, are not
+// counted.
+func RecordManifestDownload(ctx context.Context, err error) {
+	cr, ok := crFromContext(ctx)
+	if !ok {
+		return
+	}
+	ManifestDownloads.With(prometheus.Labels{
+		"namespace": cr.namespace,
+		"name":      cr.name,
+		"outcome":   outcome(err),
+	}).Inc()
+}
+
+// RecordManifestCacheHit counts a cached manifest resolution for the CR in ctx.
+func RecordManifestCacheHit(ctx context.Context) {
+	cr, ok := crFromContext(ctx)
+	if !ok {
+		return
+	}
+	ManifestCacheHits.With(prometheus.Labels{"namespace": cr.namespace, "name": cr.name}).Inc()
+}
+
+func ObserveMigration(namespace, name, migration, version string, duration time.Duration, succeeded bool) {
+	result := OutcomeSuccess
+	if !succeeded {
+		result = OutcomeFailure
+	}
+	MigrationDuration.With(prometheus.Labels{
+		"namespace": namespace,
+		"name":      name,
+		"migration": migration,
+		"version":   version,
+		"outcome":   result,
+	}).Observe(duration.Seconds())
+}
+
+func RecordKafkaTopicError(namespace, name, topic string) {
+	KafkaTopicErrors.With(prometheus.Labels{"namespace": namespace, "name": name, "topic": topic}).Inc()
+}
+
+func SetGeneratedSecrets(namespace, name string, count int) {
+	GeneratedSecrets.With(prometheus.Labels{"namespace": namespace, "name": name}).Set(float64(count))
+}
+
+// SetReadyTransition records when the CR's Ready condition last changed.
+func SetReadyTransition(namespace, name string, at time.Time) {
+	ReadyTransitionAge.set(crLabels{namespace: namespace, name: name}, at)
+}
+
+// deleteReconcileMetrics clears every series of the metrics above for a CR.
+func deleteReconcileMetrics(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	ReconcilePhaseDuration.DeletePartialMatch(labels)
+	ManifestDownloads.DeletePartialMatch(labels)
+	ManifestCacheHits.DeletePartialMatch(labels)
+	MigrationDuration.DeletePartialMatch(labels)
+	KafkaTopicErrors.DeletePartialMatch(labels)
+	GeneratedSecrets.DeletePartialMatch(labels)
+	ReadyTransitionAge.delete(crLabels{namespace: namespace, name: name})
+}
+
+type readyTransitionCollector struct {
+	desc *prometheus.Desc
+	now  func() time.Time
+
+	mu          sync.Mutex
+	transitions map[crLabels]time.Time
+}
+
+func newReadyTransitionCollector() *readyTransitionCollector {
+	return &readyTransitionCollector{
+		desc: prometheus.NewDesc(
+			"wandb_weightsandbiases_ready_transition_age_seconds",
+			"Seconds since the Ready condition of a WeightsAndBiases custom resource last changed.",
+			[]string{"namespace", "name"},
+			nil,
+		),
+		now:         time.Now,
+		transitions: map[crLabels]time.Time{},
+	}
+}
+
+func (c *readyTransitionCollector) set(cr crLabels, at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.transitions[cr] = at
+}
+
+func (c *readyTransitionCollector) delete(cr crLabels) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.transitions, cr)
+}
+
+func (c *readyTransitionCollector) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.transitions = map[crLabels]time.Time{}
+}
+
+func (c *readyTransitionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *readyTransitionCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for cr, at := range c.transitions {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(at).Seconds(), cr.namespace, cr.name)
+	}
+}
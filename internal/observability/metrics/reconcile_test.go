@@ -0,0 +1,120 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resetReconcileMetrics() {
+	ReconcilePhaseDuration.Reset()
+	ManifestDownloads.Reset()
+	ManifestCacheHits.Reset()
+	MigrationDuration.Reset()
+	KafkaTopicErrors.Reset()
+	GeneratedSecrets.Reset()
+	ReadyTransitionAge.reset()
+}
+
+func TestObserveReconcilePhase_LabelsFromContext(t *testing.T) {
+	t.Cleanup(resetReconcileMetrics)
+	resetReconcileMetrics()
+
+	ObserveReconcilePhase(context.Background(), "mysql.write", time.Second, nil)
+	assert.Empty(t, gather(t, ReconcilePhaseDuration), "phases outside a CR reconcile are not recorded")
+
+	ctx := WithWeightsAndBiases(context.Background(), "wandb", "prod")
+	ObserveReconcilePhase(ctx, "mysql.write", 2*time.Second, nil)
+	ObserveReconcilePhase(ctx, "mysql.write", time.Second, errors.New("boom"))
+
+	got := gather(t, ReconcilePhaseDuration)
+	assert.Len(t, got, 2)
+	outcomes := map[string]uint64{}
+	for _, m := range got {
+		lm := labelMap(m)
+		assert.Equal(t, "prod", lm["name"])
+		assert.Equal(t, "mysql.write", lm["phase"])
+		outcomes[lm["outcome"]] = m.Histogram.GetSampleCount()
+	}
+	assert.Equal(t, map[string]uint64{OutcomeSuccess: 1, OutcomeFailure: 1}, outcomes)
+}
+
+func TestRecordManifestDownload_CountsOutcomesAndCacheHits(t *testing.T) {
+	t.Cleanup(resetReconcileMetrics)
+	resetReconcileMetrics()
+
+	RecordManifestDownload(context.Background(), nil)
+	RecordManifestCacheHit(context.Background())
+	assert.Empty(t, gather(t, ManifestDownloads), "webhook pulls carry no CR and are not counted")
+	assert.Empty(t, gather(t, ManifestCacheHits))
+
+	ctx := WithWeightsAndBiases(context.Background(), "wandb", "prod")
+	RecordManifestDownload(ctx, nil)
+	RecordManifestDownload(ctx, errors.New("unauthorized"))
+	RecordManifestDownload(ctx, errors.New("unauthorized"))
+	RecordManifestCacheHit(ctx)
+
+	downloads := map[string]float64{}
+	for _, m := range gather(t, ManifestDownloads) {
+		downloads[labelMap(m)["outcome"]] = m.Counter.GetValue()
+	}
+	assert.Equal(t, map[string]float64{OutcomeSuccess: 1, OutcomeFailure: 2}, downloads)
+
+	hits := gather(t, ManifestCacheHits)
+	assert.Len(t, hits, 1)
+	assert.Equal(t, 1.0, hits[0].Counter.GetValue())
+}
+
+func TestReadyTransitionAge_ComputedAtScrape(t *testing.T) {
+	t.Cleanup(resetReconcileMetrics)
+	t.Cleanup(func() { ReadyTransitionAge.now = time.Now })
+	resetReconcileMetrics()
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	ReadyTransitionAge.now = func() time.Time { return now }
+
+	SetReadyTransition("wandb", "prod", now.Add(-90*time.Second))
+	got := gather(t, ReadyTransitionAge)
+	assert.Len(t, got, 1)
+	assert.Equal(t, 90.0, got[0].Gauge.GetValue())
+
+	now = now.Add(30 * time.Second)
+	got = gather(t, ReadyTransitionAge)
+	assert.Equal(t, 120.0, got[0].Gauge.GetValue())
+}
+
+func TestDeleteWeightsAndBiasesMetrics_ClearsReconcileMetrics(t *testing.T) {
+	t.Cleanup(resetReconcileMetrics)
+	resetReconcileMetrics()
+
+	for _, name := range []string{"gone", "stays"} {
+		ctx := WithWeightsAndBiases(context.Background(), "wandb", name)
+		ObserveReconcilePhase(ctx, "migrations", time.Second, nil)
+		RecordManifestDownload(ctx, nil)
+		RecordManifestCacheHit(ctx)
+		ObserveMigration("wandb", name, "mysql", "0.70.0", time.Minute, true)
+		RecordKafkaTopicError("wandb", name, "flat-runs")
+		SetGeneratedSecrets("wandb", name, 3)
+		SetReadyTransition("wandb", name, time.Now())
+	}
+
+	DeleteWeightsAndBiasesMetrics("wandb", "gone")
+
+	for metric, got := range map[string]int{
+		"phase":     len(gather(t, ReconcilePhaseDuration)),
+		"downloads": len(gather(t, ManifestDownloads)),
+		"cacheHits": len(gather(t, ManifestCacheHits)),
+		"migration": len(gather(t, MigrationDuration)),
+		"kafka":     len(gather(t, KafkaTopicErrors)),
+		"secrets":   len(gather(t, GeneratedSecrets)),
+		"readyAge":  len(gather(t, ReadyTransitionAge)),
+	} {
+		assert.Equal(t, 1, got, metric)
+	}
+	for _, m := range gather(t, MigrationDuration) {
+		assert.Equal(t, "stays", labelMap(m)["name"])
+	}
+}
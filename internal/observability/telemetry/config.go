@@ -3,6 +3,10 @@ package telemetry
 import (
 	"fmt"
 	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 const (
@@ -116,6 +120,19 @@ func (cfg TelemetryRuntimeConfig) ResolveEndpoints() TelemetryEndpoints {
 	}
 }
 
+// EgressPorts lists the gateway ports ResolveEndpoints sends workloads to.
+func EgressPorts() []networkingv1.NetworkPolicyPort {
+	port := func(protocol corev1.Protocol, number int32) networkingv1.NetworkPolicyPort {
+		target := intstr.FromInt32(number)
+		return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &target}
+	}
+	return []networkingv1.NetworkPolicyPort{
+		port(corev1.ProtocolTCP, telemetryOTLPGatewayHTTPPort),
+		port(corev1.ProtocolUDP, telemetryStatsdPort),
+		port(corev1.ProtocolTCP, telemetryDatadogTracePort),
+	}
+}
+
 func resolveServiceHost(name, namespace string) string {
 	if strings.TrimSpace(namespace) == "" {
 		return name
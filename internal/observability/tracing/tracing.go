@@ -0,0 +1,153 @@
+// Package tracing emits OpenTelemetry spans for the operator's own reconcile
+// loops. Spans are exported to the same OTLP gateway the telemetry ConfigMap
+// points W&B workloads at; while telemetry is off, spans are no-ops.
+package tracing
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/wandb/operator/internal/logx"
+	"github.com/wandb/operator/internal/observability/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	instrumentationName = "github.com/wandb/operator"
+
+	// ServiceName identifies the operator's spans; the telemetry ConfigMap's
+	// service name belongs to the W&B workloads.
+	ServiceName = "wandb-operator"
+)
+
+var (
+	mu          sync.RWMutex
+	provider    trace.TracerProvider = noop.NewTracerProvider()
+	sdkProvider *sdktrace.TracerProvider
+	configured  exporterConfig
+)
+
+// exporterConfig is the part of the telemetry configuration the exporter is
+// built from; the provider is only rebuilt when it changes.
+type exporterConfig struct {
+	endpoint           string
+	resourceAttributes string
+}
+
+func exporterConfigFor(cfg telemetry.TelemetryRuntimeConfig) exporterConfig {
+	return exporterConfig{
+		endpoint:           cfg.ResolveEndpoints().TracesEndpoint,
+		resourceAttributes: cfg.OTel.ResourceAttributes,
+	}
+}
+
+// Configure points operator spans at the traces endpoint of the telemetry
+// OTLP gateway. It is called on every reconcile with the current telemetry
+// configuration and only rebuilds the exporter when the endpoint or resource
+// attributes change. Spans are always sent as OTLP/HTTP, the only protocol the
+// gateway serves, whatever TELEMETRY_OTEL_PROTOCOL says for workloads.
+func Configure(ctx context.Context, cfg telemetry.TelemetryRuntimeConfig) error {
+	next := exporterConfigFor(cfg)
+
+	mu.RLock()
+	unchanged := next == configured
+	mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	var replacement *sdktrace.TracerProvider
+	if next.endpoint != "" {
+		exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(next.endpoint))
+		if err != nil {
+			return err
+		}
+		replacement = sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithResource(resource.NewSchemaless(resourceAttributes(next.resourceAttributes)...)),
+		)
+	}
+	setProvider(ctx, replacement, next)
+	logx.GetSlog(ctx).Info("Configured operator tracing", "endpoint", next.endpoint)
+	return nil
+}
+
+// setProvider swaps in p, or the no-op provider when p is nil, and flushes
+// the provider it replaces.
+func setProvider(ctx context.Context, p *sdktrace.TracerProvider, cfg exporterConfig) {
+	mu.Lock()
+	previous := sdkProvider
+	sdkProvider = p
+	configured = cfg
+	if p != nil {
+		provider = p
+	} else {
+		provider = noop.NewTracerProvider()
+	}
+	mu.Unlock()
+
+	if previous != nil {
+		if err := previous.Shutdown(ctx); err != nil {
+			logx.GetSlog(ctx).Error("Failed to flush operator traces", logx.ErrAttr(err))
+		}
+	}
+}
+
+// Shutdown flushes pending spans. Call it once the manager has stopped.
+func Shutdown(ctx context.Context) error {
+	mu.Lock()
+	previous := sdkProvider
+	sdkProvider = nil
+	configured = exporterConfig{}
+	provider = noop.NewTracerProvider()
+	mu.Unlock()
+
+	if previous == nil {
+		return nil
+	}
+	return previous.Shutdown(ctx)
+}
+
+// Start starts a span named name as a child of any span in ctx. The returned
+// context also carries loggers annotated with the new span's trace and span
+// IDs.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	mu.RLock()
+	tracer := provider.Tracer(instrumentationName)
+	mu.RUnlock()
+
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	return logx.WithTraceContext(ctx), span
+}
+
+// End records err on span, if any, and ends it.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// resourceAttributes parses the OTEL_RESOURCE_ATTRIBUTES style
+// "key=value,key=value" list from the telemetry ConfigMap. service.name is
+// always the operator's.
+func resourceAttributes(raw string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{attribute.String("service.name", ServiceName)}
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || key == "service.name" {
+			continue
+		}
+		attrs = append(attrs, attribute.String(key, strings.TrimSpace(value)))
+	}
+	return attrs
+}
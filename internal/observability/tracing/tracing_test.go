@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wandb/operator/internal/logx"
+	"github.com/wandb/operator/internal/observability/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func useRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	setProvider(context.Background(), sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), exporterConfig{})
+	t.Cleanup(func() { require.NoError(t, Shutdown(context.Background())) })
+	return recorder
+}
+
+func TestConfigureFollowsTelemetryConfig(t *testing.T) {
+	ctx := context.Background()
+	t.Cleanup(func() { require.NoError(t, Shutdown(ctx)) })
+
+	require.NoError(t, Configure(ctx, telemetry.DefaultTelemetryRuntimeConfig()))
+	_, span := Start(ctx, "disabled")
+	require.False(t, span.SpanContext().IsValid(), "spans are no-ops while telemetry is off")
+	span.End()
+
+	enabled := telemetry.DefaultTelemetryRuntimeConfig()
+	enabled.Enabled = true
+	enabled.Namespace = "telemetry"
+	enabled.OTel.SecretName = "otel"
+	require.NoError(t, Configure(ctx, enabled))
+	require.Equal(t, "http://victoria-otlp-gateway.telemetry.svc:4318/v1/traces", configured.endpoint)
+	first := sdkProvider
+	require.NotNil(t, first)
+
+	require.NoError(t, Configure(ctx, enabled))
+	require.Same(t, first, sdkProvider, "an unchanged config keeps the exporter")
+
+	_, span = Start(ctx, "enabled")
+	require.True(t, span.SpanContext().IsValid())
+	span.End()
+
+	require.NoError(t, Configure(ctx, telemetry.DefaultTelemetryRuntimeConfig()))
+	require.Nil(t, sdkProvider)
+}
+
+func TestStartNestsSpansAndRecordsErrors(t *testing.T) {
+	recorder := useRecorder(t)
+
+	ctx, parent := Start(context.Background(), "WeightsAndBiases.Reconcile", attribute.String("wandb.name", "wandb"))
+	_, child := Start(ctx, "mysql.init")
+	End(child, errors.New("init job failed"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, "mysql.init", spans[0].Name())
+	require.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	require.Equal(t, codes.Error, spans[0].Status().Code)
+	require.Equal(t, "init job failed", spans[0].Status().Description)
+	require.Equal(t, codes.Unset, spans[1].Status().Code)
+}
+
+func TestStartAddsTraceIDsToLogs(t *testing.T) {
+	useRecorder(t)
+	var out bytes.Buffer
+	logx.SetOptions(&logx.Options{Output: &out})
+	t.Cleanup(func() { logx.SetOptions(nil) })
+
+	ctx, _ := logx.WithSlog(context.Background(), "test")
+	ctx, parent := Start(ctx, "parent")
+	ctx, child := Start(ctx, "child")
+	logx.GetSlog(ctx).Info("in child")
+
+	logged := out.String()
+	require.Contains(t, logged, "trace_id="+child.SpanContext().TraceID().String())
+	require.Contains(t, logged, "span_id="+child.SpanContext().SpanID().String())
+	require.NotContains(t, logged, parent.SpanContext().SpanID().String(), "the child's IDs replace the parent's")
+}
+
+func TestResourceAttributes(t *testing.T) {
+	require.Equal(t, []attribute.KeyValue{
+		attribute.String("service.name", ServiceName),
+		attribute.String("deployment.environment", "prod"),
+		attribute.String("team", "ml"),
+	}, resourceAttributes("deployment.environment=prod, team = ml,service.name=ignored,broken"))
+}
@@ -0,0 +1,10 @@
+// Package version exposes the build version of the operator binary.
+package version
+
+// Version is the operator release this binary was built from. It is stamped at
+// link time (see the Dockerfile) with
+//
+//	-ldflags "-X github.com/wandb/operator/internal/version.Version=<version>"
+//
+// and stays "dev" for local builds, which skip manifest compatibility checks.
+var Version = "dev"
@@ -0,0 +1,76 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+)
+
+func validateClickHouseBackupCase(t *testing.T, managed *appsv2.ManagedClickHouseSpec, wantErr string) {
+	t.Helper()
+	wandb := &appsv2.WeightsAndBiases{}
+	wandb.Spec.ClickHouse = map[string]appsv2.ClickHouseSpec{
+		appsv2.DefaultInstanceName: {ManagedClickHouse: managed},
+	}
+	wandb.Spec.ObjectStore = map[string]appsv2.ObjectStoreSpec{
+		appsv2.DefaultInstanceName: {ManagedObjectStore: &appsv2.ManagedObjectStoreSpec{}},
+		"backups":                  {ManagedObjectStore: &appsv2.ManagedObjectStoreSpec{}},
+	}
+	agg := validateClickHouseSpec(wandb).ToAggregate()
+	if wantErr == "" {
+		if agg != nil {
+			t.Fatalf("expected no errors, got %v", agg)
+		}
+		return
+	}
+	if agg == nil || !strings.Contains(agg.Error(), wantErr) {
+		t.Fatalf("expected error containing %q, got %v", wantErr, agg)
+	}
+}
+
+func TestValidateClickHouseBackup(t *testing.T) {
+	cases := []struct {
+		name    string
+		backup  *appsv2.ClickHouseBackupSpec
+		wantErr string // substring; "" = accept
+	}{
+		{"unset", nil, ""},
+		{"nightly to default store", &appsv2.ClickHouseBackupSpec{Schedule: "0 3 * * *", Retention: 7}, ""},
+		{"named store", &appsv2.ClickHouseBackupSpec{Schedule: "@daily", ObjectStore: "backups"}, ""},
+		{"bad schedule", &appsv2.ClickHouseBackupSpec{Schedule: "nightly"}, "backup.schedule"},
+		{"negative retention", &appsv2.ClickHouseBackupSpec{Schedule: "0 3 * * *", Retention: -1}, "backup.retention"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			validateClickHouseBackupCase(t, &appsv2.ManagedClickHouseSpec{Backup: tc.backup}, tc.wantErr)
+		})
+	}
+}
+
+func TestValidateClickHouseRestore(t *testing.T) {
+	cases := []struct {
+		name    string
+		restore *appsv2.ClickHouseRestoreSpec
+		wantErr string // substring; "" = accept
+	}{
+		{"unset", nil, ""},
+		{"latest", &appsv2.ClickHouseRestoreSpec{Prefix: "clickhouse-backup/wandb/wandb-clickhouse/"}, ""},
+		{"named backup", &appsv2.ClickHouseRestoreSpec{
+			Prefix: "clickhouse-backup/wandb/wandb-clickhouse", Backup: "20261001T030000Z", ObjectStore: "backups",
+		}, ""},
+		{"empty prefix", &appsv2.ClickHouseRestoreSpec{}, "restoreFrom.prefix"},
+		{"not a clickhouse prefix", &appsv2.ClickHouseRestoreSpec{Prefix: "moco/wandb/wandb-mysql/"}, "restoreFrom.prefix"},
+		{"backup in prefix", &appsv2.ClickHouseRestoreSpec{
+			Prefix: "clickhouse-backup/wandb/wandb-clickhouse/20261001T030000Z/",
+		}, "restoreFrom.prefix"},
+		{"nested backup", &appsv2.ClickHouseRestoreSpec{
+			Prefix: "clickhouse-backup/wandb/wandb-clickhouse/", Backup: "../other/20261001T030000Z",
+		}, "restoreFrom.backup"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			validateClickHouseBackupCase(t, &appsv2.ManagedClickHouseSpec{RestoreFrom: tc.restore}, tc.wantErr)
+		})
+	}
+}
@@ -58,6 +58,20 @@ var _ = Describe("WeightsAndBiasesCustomDefaulter - Kafka", func() {
 		g.Expect(wandb.Spec.Kafka.ManagedKafka.Replicas).To(g.Equal(int32(5)))
 	})
 
+	It("does not add managed Kafka when external Kafka is configured", func() {
+		wandb := &apiv2.WeightsAndBiases{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-wandb", Namespace: "test-namespace"},
+			Spec: apiv2.WeightsAndBiasesSpec{
+				Kafka: apiv2.KafkaSpec{ExternalKafka: &apiv2.KafkaConnection{}},
+			},
+		}
+
+		err := defaulter.Default(ctx, wandb)
+		g.Expect(err).ToNot(g.HaveOccurred())
+		g.Expect(wandb.Spec.Kafka.ManagedKafka).To(g.BeNil())
+		g.Expect(wandb.Spec.Kafka.ExternalKafka).ToNot(g.BeNil())
+	})
+
 	It("creates managed Kafka defaults when Kafka is unset", func() {
 		wandb := &apiv2.WeightsAndBiases{
 			ObjectMeta: metav1.ObjectMeta{Name: "test-wandb", Namespace: "test-namespace"},
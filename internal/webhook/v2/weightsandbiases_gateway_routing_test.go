@@ -0,0 +1,87 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateGatewayRouting(t *testing.T) {
+	canary := []appsv2.GatewayBackend{{Name: "wandb-api-canary", Port: 8080, Weight: ptr.To[int32](10)}}
+	cases := []struct {
+		name    string
+		routing appsv2.GatewayRouting
+		wantErr string // substring; "" = accept
+	}{
+		{"unset", appsv2.GatewayRouting{}, ""},
+		{"canary split, mirror and upload timeouts", appsv2.GatewayRouting{
+			GatewayRouteActions: appsv2.GatewayRouteActions{
+				Mirror:   &appsv2.GatewayMirror{Name: "wandb-api-shadow", Port: 8080, Percent: ptr.To[int32](5)},
+				Timeouts: &appsv2.GatewayRouteTimeouts{Request: "10m", BackendRequest: "5m"},
+				Retry:    &appsv2.GatewayRouteRetry{Attempts: ptr.To[int32](2), Backoff: "100ms", Codes: []int32{502, 503}},
+			},
+			Rules: []appsv2.GatewayRouteRule{{
+				Paths:               []string{"/graphql"},
+				Headers:             []appsv2.GatewayHeaderMatch{{Name: "X-Canary", Type: "RegularExpression", Value: "^(1|true)$"}},
+				GatewayRouteActions: appsv2.GatewayRouteActions{ServiceWeight: ptr.To[int32](90), Backends: canary},
+			}},
+		}, ""},
+		{"rule matching everything", appsv2.GatewayRouting{
+			Rules: []appsv2.GatewayRouteRule{{GatewayRouteActions: appsv2.GatewayRouteActions{Backends: canary}}},
+		}, "must match on paths, headers or both"},
+		{"relative path", appsv2.GatewayRouting{
+			Rules: []appsv2.GatewayRouteRule{{Paths: []string{"graphql"}}},
+		}, `routing[api].rules[0].paths[0]`},
+		{"bad header regex", appsv2.GatewayRouting{
+			Rules: []appsv2.GatewayRouteRule{{Headers: []appsv2.GatewayHeaderMatch{{Name: "X-Canary", Type: "RegularExpression", Value: "("}}}},
+		}, "rules[0].headers[0].value"},
+		{"all weights zero", appsv2.GatewayRouting{
+			GatewayRouteActions: appsv2.GatewayRouteActions{
+				ServiceWeight: ptr.To[int32](0),
+				Backends:      []appsv2.GatewayBackend{{Name: "canary", Port: 8080, Weight: ptr.To[int32](0)}},
+			},
+		}, "must not all be zero"},
+		{"backend without port", appsv2.GatewayRouting{
+			GatewayRouteActions: appsv2.GatewayRouteActions{Backends: []appsv2.GatewayBackend{{Name: "canary"}}},
+		}, "backends[0].port"},
+		{"backend name not a service name", appsv2.GatewayRouting{
+			GatewayRouteActions: appsv2.GatewayRouteActions{Backends: []appsv2.GatewayBackend{{Name: "Canary.svc", Port: 80}}},
+		}, "backends[0].name"},
+		{"mirror percent over 100", appsv2.GatewayRouting{
+			GatewayRouteActions: appsv2.GatewayRouteActions{Mirror: &appsv2.GatewayMirror{Name: "shadow", Port: 80, Percent: ptr.To[int32](150)}},
+		}, "must be between 0 and 100"},
+		{"bad timeout", appsv2.GatewayRouting{
+			GatewayRouteActions: appsv2.GatewayRouteActions{Timeouts: &appsv2.GatewayRouteTimeouts{Request: "1d"}},
+		}, "timeouts.request"},
+		{"backend timeout over request timeout", appsv2.GatewayRouting{
+			GatewayRouteActions: appsv2.GatewayRouteActions{Timeouts: &appsv2.GatewayRouteTimeouts{Request: "30s", BackendRequest: "1m"}},
+		}, "must not exceed timeouts.request"},
+		{"retry code out of range", appsv2.GatewayRouting{
+			GatewayRouteActions: appsv2.GatewayRouteActions{Retry: &appsv2.GatewayRouteRetry{Codes: []int32{200}}},
+		}, "retry.codes[0]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wandb := &appsv2.WeightsAndBiases{}
+			wandb.Spec.Networking = appsv2.NetworkingSpec{
+				Mode: appsv2.NetworkingModeGatewayAPI,
+				GatewayAPI: &appsv2.GatewayAPIConfig{
+					Gateway: appsv2.GatewayConfig{GatewayRef: &appsv2.GatewayReference{Name: "shared"}},
+					Routing: map[string]appsv2.GatewayRouting{"api": tc.routing},
+				},
+			}
+			errs, _ := validateNetworkingSpec(wandb)
+			if tc.wantErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(errs.ToAggregate().Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateInfraTLS(t *testing.T) {
+	cases := []struct {
+		name    string
+		tls     *appsv2.InfraTLSSpec
+		wantErr string // substring; "" = accept
+	}{
+		{"unset", nil, ""},
+		{"operator CA", &appsv2.InfraTLSSpec{Enabled: true}, ""},
+		{"cluster issuer", &appsv2.InfraTLSSpec{Enabled: true, CertManager: &appsv2.CertManagerConfig{ClusterIssuer: "internal-ca"}}, ""},
+		{"namespaced issuer", &appsv2.InfraTLSSpec{Enabled: true, CertManager: &appsv2.CertManagerConfig{Issuer: "internal-ca"}}, ""},
+		{"no issuer", &appsv2.InfraTLSSpec{Enabled: true, CertManager: &appsv2.CertManagerConfig{}}, "exactly one of issuer and clusterIssuer"},
+		{"both issuers", &appsv2.InfraTLSSpec{Enabled: true, CertManager: &appsv2.CertManagerConfig{Issuer: "a", ClusterIssuer: "b"}}, "exactly one of issuer and clusterIssuer"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wandb := &appsv2.WeightsAndBiases{}
+			wandb.Spec.MySQL = map[string]appsv2.MySQLSpec{
+				appsv2.DefaultInstanceName: {ManagedMysql: &appsv2.ManagedMysqlSpec{TLS: tc.tls}},
+			}
+			wandb.Spec.Redis = map[string]appsv2.RedisSpec{
+				appsv2.DefaultInstanceName: {ManagedRedis: &appsv2.ManagedRedisSpec{TLS: tc.tls}},
+			}
+			for _, errs := range []field.ErrorList{
+				validateMySQLSpec(wandb),
+				validateRedisSpec(wandb),
+			} {
+				agg := errs.ToAggregate()
+				if tc.wantErr == "" {
+					if agg != nil {
+						t.Fatalf("expected no errors, got %v", agg)
+					}
+					continue
+				}
+				if agg == nil || !strings.Contains(agg.Error(), tc.wantErr) || !strings.Contains(agg.Error(), ".tls.certManager") {
+					t.Fatalf("expected error containing %q, got %v", tc.wantErr, agg)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateIngressHosts(t *testing.T) {
+	partner := appsv2.IngressHost{
+		Hostname:         "wandb.partner.com",
+		TLS:              &appsv2.TLSConfig{CertManager: &appsv2.CertManagerConfig{ClusterIssuer: "letsencrypt"}},
+		Annotations:      map[string]string{"nginx.ingress.kubernetes.io/whitelist-source-range": "10.0.0.0/8"},
+		IngressClassName: ptr.To("nginx-external"),
+		Applications:     []string{"api", "frontend"},
+		Paths:            []string{"/graphql", "/"},
+	}
+	cases := []struct {
+		name    string
+		hosts   []appsv2.IngressHost
+		wantErr string // substring; "" = accept
+	}{
+		{"unset", nil, ""},
+		{"partner host with its own issuer and class", []appsv2.IngressHost{partner}, ""},
+		{"two hosts", []appsv2.IngressHost{partner, {Hostname: "wandb.other.com", TLS: &appsv2.TLSConfig{SecretName: "other-tls"}}}, ""},
+		{"missing hostname", []appsv2.IngressHost{{Name: "partner"}}, "hosts[0].hostname: Required"},
+		{"invalid hostname", []appsv2.IngressHost{{Hostname: "Wandb_Partner"}}, "hosts[0].hostname: Invalid"},
+		{"same as spec.wandb.hostname", []appsv2.IngressHost{{Hostname: "wandb.corp"}}, "hosts[0].hostname: Duplicate"},
+		{"same as an additional hostname", []appsv2.IngressHost{{Hostname: "wandb-alias.corp"}}, "hosts[0].hostname: Duplicate"},
+		{"repeated hostname", []appsv2.IngressHost{partner, partner}, "hosts[1].hostname: Duplicate"},
+		{"derived names collide", []appsv2.IngressHost{{Hostname: "a.b.com"}, {Hostname: "a-b.com", Name: "a-b-com"}}, "hosts[1].name: Duplicate"},
+		{"name not a label", []appsv2.IngressHost{{Hostname: "wandb.partner.com", Name: "partner.com"}}, "hosts[0].name"},
+		{"both issuers", []appsv2.IngressHost{{
+			Hostname: "wandb.partner.com",
+			TLS:      &appsv2.TLSConfig{CertManager: &appsv2.CertManagerConfig{Issuer: "a", ClusterIssuer: "b"}},
+		}}, "only one of issuer and clusterIssuer"},
+		{"relative path", []appsv2.IngressHost{{Hostname: "wandb.partner.com", Paths: []string{"graphql"}}}, "hosts[0].paths[0]"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wandb := &appsv2.WeightsAndBiases{}
+			wandb.Spec.Wandb.Hostname = "https://wandb.corp"
+			wandb.Spec.Wandb.AdditionalHostnames = []string{"wandb-alias.corp"}
+			wandb.Spec.Networking = appsv2.NetworkingSpec{
+				Mode:  appsv2.NetworkingModeIngress,
+				Hosts: tc.hosts,
+			}
+			errs, _ := validateNetworkingSpec(wandb)
+			if tc.wantErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(errs.ToAggregate().Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, errs)
+			}
+		})
+	}
+}
+
+func TestValidateIngressHosts_GatewayWarnings(t *testing.T) {
+	wandb := &appsv2.WeightsAndBiases{}
+	wandb.Spec.Wandb.Hostname = "https://wandb.corp"
+	wandb.Spec.Networking = appsv2.NetworkingSpec{
+		Mode: appsv2.NetworkingModeGatewayAPI,
+		GatewayAPI: &appsv2.GatewayAPIConfig{
+			Gateway: appsv2.GatewayConfig{GatewayRef: &appsv2.GatewayReference{Name: "shared"}},
+		},
+		Hosts: []appsv2.IngressHost{{
+			Hostname:         "wandb.partner.com",
+			TLS:              &appsv2.TLSConfig{CertManager: &appsv2.CertManagerConfig{Issuer: "partner"}},
+			IngressClassName: ptr.To("nginx"),
+		}},
+	}
+	errs, warnings := validateNetworkingSpec(wandb)
+	if len(errs) != 0 {
+		t.Fatalf("expected no errors, got %v", errs)
+	}
+	joined := strings.Join(warnings, "\n")
+	for _, want := range []string{"only apply in Ingress mode", "not applied per listener", "only created on a managed Gateway"} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("expected a warning containing %q, got %v", want, warnings)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func kafkaSelector(key string) corev1.SecretKeySelector {
+	return corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "kafka"},
+		Key:                  key,
+	}
+}
+
+func TestValidateKafkaSpec(t *testing.T) {
+	cases := []struct {
+		name    string
+		spec    appsv2.KafkaSpec
+		wantErr string
+	}{
+		{"managed", appsv2.KafkaSpec{ManagedKafka: &appsv2.ManagedKafkaSpec{}}, ""},
+		{"managed and external", appsv2.KafkaSpec{
+			ManagedKafka:  &appsv2.ManagedKafkaSpec{},
+			ExternalKafka: &appsv2.KafkaConnection{BrokerEndpoint: kafkaSelector("brokers")},
+		}, "mutually exclusive"},
+		{"external with brokers", appsv2.KafkaSpec{
+			ExternalKafka: &appsv2.KafkaConnection{BrokerEndpoint: kafkaSelector("brokers")},
+		}, ""},
+		{"external with host and port", appsv2.KafkaSpec{
+			ExternalKafka: &appsv2.KafkaConnection{Host: kafkaSelector("host"), Port: kafkaSelector("port")},
+		}, ""},
+		{"external without brokers", appsv2.KafkaSpec{
+			ExternalKafka: &appsv2.KafkaConnection{Host: kafkaSelector("host")},
+		}, "externalKafka.port.name"},
+		{"external SCRAM", appsv2.KafkaSpec{
+			ExternalKafka: &appsv2.KafkaConnection{
+				BrokerEndpoint: kafkaSelector("brokers"),
+				SaslMechanism:  kafkaSelector("mechanism"),
+				Username:       kafkaSelector("username"),
+				Password:       kafkaSelector("password"),
+				SslCa:          kafkaSelector("ca.crt"),
+			},
+		}, ""},
+		{"external username without password", appsv2.KafkaSpec{
+			ExternalKafka: &appsv2.KafkaConnection{
+				BrokerEndpoint: kafkaSelector("brokers"),
+				Username:       kafkaSelector("username"),
+			},
+		}, "externalKafka.password.name"},
+		{"external mechanism without credentials", appsv2.KafkaSpec{
+			ExternalKafka: &appsv2.KafkaConnection{
+				BrokerEndpoint: kafkaSelector("brokers"),
+				SaslMechanism:  kafkaSelector("mechanism"),
+			},
+		}, "externalKafka.username.name"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wandb := &appsv2.WeightsAndBiases{}
+			wandb.Spec.Kafka = tc.spec
+			errs := validateKafkaSpec(wandb)
+			if tc.wantErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(errs.ToAggregate().Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package v2
+
+import (
+	"context"
+	"time"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/internal/logx"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
+	"github.com/wandb/operator/pkg/wandb/manifest/registryauth"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// manifestFetchTimeout bounds the admission-time credential lookup and
+// manifest fetch. It stays well below the API server's default 10s webhook
+// timeout so an unreachable registry admits the CR instead of failing the
+// call under failurePolicy=Fail.
+const manifestFetchTimeout = 3 * time.Second
+
+// validateManifestCompatibility rejects a create, or an update that changes the
+// server version or manifest repository, when the target manifest requires a
+// different operator version. It is best-effort: a manifest that cannot be
+// fetched is admitted and left to the reconciler, which applies the same gate
+// and reports it on the Ready condition.
+func (v *WeightsAndBiasesCustomValidator) validateManifestCompatibility(ctx context.Context, newWandb, oldWandb *appsv2.WeightsAndBiases) error {
+	if v.GetManifest == nil {
+		return nil
+	}
+	if oldWandb != nil &&
+		oldWandb.Spec.Wandb.Version == newWandb.Spec.Wandb.Version &&
+		oldWandb.Spec.Wandb.ManifestRepository == newWandb.Spec.Wandb.ManifestRepository {
+		return nil
+	}
+	log := logx.GetSlog(ctx)
+	repository := newWandb.Spec.Wandb.ManifestRepository
+
+	ctx, cancel := context.WithTimeout(ctx, manifestFetchTimeout)
+	defer cancel()
+
+	var auth *serverManifest.RegistryAuth
+	if v.Reader != nil && !serverManifest.IsFileRepository(repository) {
+		allowAmbient := repository != appsv2.DefaultManifestRepository
+		resolved, err := registryauth.Resolve(ctx, v.Reader, newWandb.Namespace, newWandb.Spec.Global.ImagePullSecrets, allowAmbient)
+		if err != nil {
+			log.Info("failed to resolve registry credentials for manifest compatibility check; attempting anonymous", logx.ErrAttr(err))
+		} else {
+			auth = resolved
+		}
+	}
+
+	manifest, err := v.GetManifest(ctx, repository, newWandb.Spec.Wandb.Version, auth)
+	if err != nil {
+		log.Info("skipping manifest compatibility check; manifest not fetchable",
+			"repository", repository, "version", newWandb.Spec.Wandb.Version, logx.ErrAttr(err))
+		return nil
+	}
+
+	if err := manifest.CheckOperatorVersion(ctx, v.OperatorVersion); err != nil {
+		return apierrors.NewInvalid(
+			schema.GroupKind{Group: "apps.wandb.com", Kind: "WeightsAndBiases"},
+			newWandb.Name,
+			field.ErrorList{field.Invalid(
+				field.NewPath("spec").Child("wandb").Child("version"),
+				newWandb.Spec.Wandb.Version,
+				err.Error(),
+			)},
+		)
+	}
+	return nil
+}
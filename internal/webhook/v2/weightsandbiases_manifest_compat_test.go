@@ -0,0 +1,98 @@
+package v2
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
+)
+
+func TestValidateManifestCompatibility(t *testing.T) {
+	manifests := map[string]serverManifest.Manifest{
+		"0.80.0": {RequiredOperatorVersion: "^2.0.0"},
+		"0.90.0": {RequiredOperatorVersion: "^3.0.0"},
+	}
+	fetches := 0
+	validator := &WeightsAndBiasesCustomValidator{
+		OperatorVersion: "2.1.0",
+		GetManifest: func(_ context.Context, _, version string, _ *serverManifest.RegistryAuth) (serverManifest.Manifest, error) {
+			fetches++
+			m, ok := manifests[version]
+			if !ok {
+				return serverManifest.Manifest{}, errors.New("not found")
+			}
+			return m, nil
+		},
+	}
+	withVersion := func(version string) *appsv2.WeightsAndBiases {
+		wandb := &appsv2.WeightsAndBiases{}
+		wandb.Name = "wandb"
+		wandb.Spec.Wandb.Version = version
+		wandb.Spec.Wandb.ManifestRepository = appsv2.DefaultManifestRepository
+		return wandb
+	}
+
+	cases := []struct {
+		name    string
+		newObj  *appsv2.WeightsAndBiases
+		oldObj  *appsv2.WeightsAndBiases
+		wantErr string
+	}{
+		{"compatible create", withVersion("0.80.0"), nil, ""},
+		{"incompatible create", withVersion("0.90.0"), nil, "requires operator ^3.0.0"},
+		{"incompatible bump", withVersion("0.90.0"), withVersion("0.80.0"), "spec.wandb.version"},
+		{"unfetchable manifest is admitted", withVersion("0.99.0"), withVersion("0.80.0"), ""},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validator.validateManifestCompatibility(context.Background(), tc.newObj, tc.oldObj)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+
+	// An update that leaves the version alone must not refetch the manifest,
+	// so an already-running incompatible CR can still be edited.
+	fetches = 0
+	if err := validator.validateManifestCompatibility(context.Background(), withVersion("0.90.0"), withVersion("0.90.0")); err != nil {
+		t.Fatalf("unchanged version must be admitted: %v", err)
+	}
+	if fetches != 0 {
+		t.Fatalf("unchanged version fetched the manifest %d times", fetches)
+	}
+}
+
+func TestValidateManifestCompatibilityAdmitsOnFetchTimeout(t *testing.T) {
+	validator := &WeightsAndBiasesCustomValidator{
+		OperatorVersion: "2.1.0",
+		GetManifest: func(ctx context.Context, _, _ string, _ *serverManifest.RegistryAuth) (serverManifest.Manifest, error) {
+			<-ctx.Done()
+			return serverManifest.Manifest{}, ctx.Err()
+		},
+	}
+	wandb := &appsv2.WeightsAndBiases{}
+	wandb.Name = "wandb"
+	wandb.Spec.Wandb.Version = "0.90.0"
+	wandb.Spec.Wandb.ManifestRepository = appsv2.DefaultManifestRepository
+
+	start := time.Now()
+	if err := validator.validateManifestCompatibility(context.Background(), wandb, nil); err != nil {
+		t.Fatalf("a manifest fetch that times out must admit the CR, got %v", err)
+	}
+	// The API server gives up on the webhook after 10s by default and then
+	// rejects the CR, so the check has to give up well before that.
+	if elapsed := time.Since(start); elapsed >= 5*time.Second {
+		t.Fatalf("manifest fetch took %s, want it cut off well before the webhook deadline", elapsed)
+	}
+}
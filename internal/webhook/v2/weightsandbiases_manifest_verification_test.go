@@ -0,0 +1,64 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidateManifestVerification(t *testing.T) {
+	configMapKey := &corev1.ConfigMapKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "manifest-keys"}, Key: "cosign.pub",
+	}
+	secretKey := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "manifest-keys"}, Key: "cosign.pub",
+	}
+	cases := []struct {
+		name         string
+		repository   string
+		verification *appsv2.ManifestVerificationSpec
+		wantErr      string // substring; "" = accept
+	}{
+		{"unset", "", nil, ""},
+		{"configmap key", "", &appsv2.ManifestVerificationSpec{
+			PublicKeys: []appsv2.ManifestPublicKeySource{{ConfigMapKeyRef: configMapKey}},
+		}, ""},
+		{"secret key", "oci://registry.example.com/wandb/server-manifest", &appsv2.ManifestVerificationSpec{
+			PublicKeys: []appsv2.ManifestPublicKeySource{{SecretKeyRef: secretKey}},
+		}, ""},
+		{"no keys", "", &appsv2.ManifestVerificationSpec{}, "at least one public key"},
+		{"empty source", "", &appsv2.ManifestVerificationSpec{
+			PublicKeys: []appsv2.ManifestPublicKeySource{{}},
+		}, "one of configMapKeyRef or secretKeyRef is required"},
+		{"both references", "", &appsv2.ManifestVerificationSpec{
+			PublicKeys: []appsv2.ManifestPublicKeySource{{ConfigMapKeyRef: configMapKey, SecretKeyRef: secretKey}},
+		}, "only one of"},
+		{"missing configmap key", "", &appsv2.ManifestVerificationSpec{
+			PublicKeys: []appsv2.ManifestPublicKeySource{{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "manifest-keys"},
+			}}},
+		}, "configmap key is required"},
+		{"file repository", "file:///manifests", &appsv2.ManifestVerificationSpec{
+			PublicKeys: []appsv2.ManifestPublicKeySource{{SecretKeyRef: secretKey}},
+		}, "cannot be verified"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wandb := &appsv2.WeightsAndBiases{}
+			wandb.Spec.Wandb.ManifestRepository = tc.repository
+			wandb.Spec.Wandb.ManifestVerification = tc.verification
+			errs := validateManifestVerification(wandb)
+			if tc.wantErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(errs.ToAggregate().Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+)
+
+func TestValidateMySQLBackup(t *testing.T) {
+	cases := []struct {
+		name    string
+		backup  *appsv2.MySQLBackupSpec
+		wantErr string // substring; "" = accept
+	}{
+		{"unset", nil, ""},
+		{"nightly to default store", &appsv2.MySQLBackupSpec{Schedule: "0 3 * * *", Retention: 7}, ""},
+		{"descriptor schedule", &appsv2.MySQLBackupSpec{Schedule: "@daily"}, ""},
+		{"named store", &appsv2.MySQLBackupSpec{Schedule: "0 3 * * *", ObjectStore: "backups"}, ""},
+		{"unknown store falls back to default", &appsv2.MySQLBackupSpec{Schedule: "0 3 * * *", ObjectStore: "missing"}, ""},
+		{"bad schedule", &appsv2.MySQLBackupSpec{Schedule: "nightly"}, "backup.schedule"},
+		{"six-field schedule", &appsv2.MySQLBackupSpec{Schedule: "0 0 3 * * *"}, "backup.schedule"},
+		{"negative retention", &appsv2.MySQLBackupSpec{Schedule: "0 3 * * *", Retention: -1}, "backup.retention"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wandb := &appsv2.WeightsAndBiases{}
+			wandb.Spec.MySQL = map[string]appsv2.MySQLSpec{
+				appsv2.DefaultInstanceName: {ManagedMysql: &appsv2.ManagedMysqlSpec{Backup: tc.backup}},
+			}
+			wandb.Spec.ObjectStore = map[string]appsv2.ObjectStoreSpec{
+				appsv2.DefaultInstanceName: {ManagedObjectStore: &appsv2.ManagedObjectStoreSpec{}},
+				"backups":                  {ManagedObjectStore: &appsv2.ManagedObjectStoreSpec{}},
+			}
+			agg := validateMySQLSpec(wandb).ToAggregate()
+			if tc.wantErr == "" {
+				if agg != nil {
+					t.Fatalf("expected no errors, got %v", agg)
+				}
+				return
+			}
+			if agg == nil || !strings.Contains(agg.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, agg)
+			}
+		})
+	}
+
+	t.Run("no object store", func(t *testing.T) {
+		wandb := &appsv2.WeightsAndBiases{}
+		wandb.Spec.MySQL = map[string]appsv2.MySQLSpec{
+			appsv2.DefaultInstanceName: {ManagedMysql: &appsv2.ManagedMysqlSpec{
+				Backup: &appsv2.MySQLBackupSpec{Schedule: "0 3 * * *"},
+			}},
+		}
+		agg := validateMySQLSpec(wandb).ToAggregate()
+		if agg == nil || !strings.Contains(agg.Error(), "backup.objectStore: Not found") {
+			t.Fatalf("expected objectStore not found, got %v", agg)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+)
+
+func TestValidateMySQLRestore(t *testing.T) {
+	cases := []struct {
+		name    string
+		restore *appsv2.MySQLRestoreSpec
+		wantErr string // substring; "" = accept
+	}{
+		{"unset", nil, ""},
+		{"latest from default store", &appsv2.MySQLRestoreSpec{Prefix: "moco/wandb/wandb-mysql/"}, ""},
+		{"no trailing slash", &appsv2.MySQLRestoreSpec{Prefix: "moco/wandb/wandb-mysql"}, ""},
+		{"named store", &appsv2.MySQLRestoreSpec{Prefix: "moco/wandb/wandb-mysql/", ObjectStore: "backups"}, ""},
+		{"empty prefix", &appsv2.MySQLRestoreSpec{}, "restore.prefix"},
+		{"not a moco prefix", &appsv2.MySQLRestoreSpec{Prefix: "backups/wandb-mysql/"}, "restore.prefix"},
+		{"a single backup", &appsv2.MySQLRestoreSpec{Prefix: "moco/wandb/wandb-mysql/20261001-030005/"}, "restore.prefix"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wandb := &appsv2.WeightsAndBiases{}
+			wandb.Spec.MySQL = map[string]appsv2.MySQLSpec{
+				appsv2.DefaultInstanceName: {ManagedMysql: &appsv2.ManagedMysqlSpec{Restore: tc.restore}},
+			}
+			wandb.Spec.ObjectStore = map[string]appsv2.ObjectStoreSpec{
+				appsv2.DefaultInstanceName: {ManagedObjectStore: &appsv2.ManagedObjectStoreSpec{}},
+				"backups":                  {ManagedObjectStore: &appsv2.ManagedObjectStoreSpec{}},
+			}
+			agg := validateMySQLSpec(wandb).ToAggregate()
+			if tc.wantErr == "" {
+				if agg != nil {
+					t.Fatalf("expected no errors, got %v", agg)
+				}
+				return
+			}
+			if agg == nil || !strings.Contains(agg.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, agg)
+			}
+		})
+	}
+
+	t.Run("no object store", func(t *testing.T) {
+		wandb := &appsv2.WeightsAndBiases{}
+		wandb.Spec.MySQL = map[string]appsv2.MySQLSpec{
+			appsv2.DefaultInstanceName: {ManagedMysql: &appsv2.ManagedMysqlSpec{
+				Restore: &appsv2.MySQLRestoreSpec{Prefix: "moco/wandb/wandb-mysql/"},
+			}},
+		}
+		agg := validateMySQLSpec(wandb).ToAggregate()
+		if agg == nil || !strings.Contains(agg.Error(), "restore.objectStore: Not found") {
+			t.Fatalf("expected objectStore not found, got %v", agg)
+		}
+	})
+}
@@ -0,0 +1,57 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateMysqld(t *testing.T) {
+	cases := []struct {
+		name    string
+		mysqld  *appsv2.MysqldConfig
+		wantErr string // substring; "" = accept
+	}{
+		{"unset", nil, ""},
+		{"typed tuning", &appsv2.MysqldConfig{
+			InnodbBufferPoolSize:    ptr.To(resource.MustParse("12Gi")),
+			MaxConnections:          ptr.To[int32](2000),
+			SlowQueryLog:            ptr.To(true),
+			LongQueryTime:           "0.5",
+			BinlogExpireLogsSeconds: ptr.To[int64](259200),
+		}, ""},
+		{"freeform setting", &appsv2.MysqldConfig{Settings: map[string]string{"table_open_cache": "8000"}}, ""},
+		{"gtid mode", &appsv2.MysqldConfig{Settings: map[string]string{"gtid_mode": "OFF"}}, "settings[gtid_mode]: Forbidden"},
+		{"dashed replication option", &appsv2.MysqldConfig{Settings: map[string]string{"log-replica-updates": "OFF"}}, "settings[log-replica-updates]: Forbidden"},
+		{"loose prefix", &appsv2.MysqldConfig{Settings: map[string]string{"loose_rpl_semi_sync_source_timeout": "1"}}, "Forbidden"},
+		{"tls paths", &appsv2.MysqldConfig{Settings: map[string]string{"ssl_cert": "/tmp/cert"}}, "Forbidden"},
+		{"multi-line value", &appsv2.MysqldConfig{Settings: map[string]string{"sql_mode": "\n[client]"}}, "must be a single line"},
+		{"same option twice", &appsv2.MysqldConfig{Settings: map[string]string{"max_connections": "300", "max-connections": "500"}}, `settings[max_connections]: Invalid value: "max_connections": names the same mysqld option as "max-connections"`},
+		{"bad option name", &appsv2.MysqldConfig{Settings: map[string]string{"a = b": "1"}}, "must be a mysqld option name"},
+		{"long query time not a number", &appsv2.MysqldConfig{LongQueryTime: "2s"}, "longQueryTime"},
+		{"zero max connections", &appsv2.MysqldConfig{MaxConnections: ptr.To[int32](0)}, "maxConnections"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wandb := &appsv2.WeightsAndBiases{}
+			wandb.Spec.MySQL = map[string]appsv2.MySQLSpec{
+				appsv2.DefaultInstanceName: {ManagedMysql: &appsv2.ManagedMysqlSpec{
+					Config: appsv2.MySQLConfig{Mysqld: tc.mysqld},
+				}},
+			}
+			agg := validateMySQLSpec(wandb).ToAggregate()
+			if tc.wantErr == "" {
+				if agg != nil {
+					t.Fatalf("expected no errors, got %v", agg)
+				}
+				return
+			}
+			if agg == nil || !strings.Contains(agg.Error(), tc.wantErr) || !strings.Contains(agg.Error(), ".config.mysqld") {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, agg)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func wandbWithNetworkPolicies(config *appsv2.NetworkPoliciesConfig) *appsv2.WeightsAndBiases {
+	wandb := &appsv2.WeightsAndBiases{}
+	wandb.Spec.Networking.NetworkPolicies = config
+	return wandb
+}
+
+func TestValidateNetworkPolicies(t *testing.T) {
+	cases := []struct {
+		name    string
+		config  *appsv2.NetworkPoliciesConfig
+		wantErr string // substring; "" = accept
+	}{
+		{"unset", nil, ""},
+		{"disabled without operator namespace", &appsv2.NetworkPoliciesConfig{}, ""},
+		{"enabled", &appsv2.NetworkPoliciesConfig{Enabled: true, OperatorNamespace: "wandb-operator"}, ""},
+		{"missing operator namespace", &appsv2.NetworkPoliciesConfig{Enabled: true}, "operatorNamespace is required"},
+		{"invalid operator namespace", &appsv2.NetworkPoliciesConfig{Enabled: true, OperatorNamespace: "Not_A_Namespace"}, "operatorNamespace"},
+		{"egress cidrs", &appsv2.NetworkPoliciesConfig{
+			Enabled: true, OperatorNamespace: "wandb-operator", EgressCIDRs: []string{"10.0.0.0/8", "2001:db8::/32"},
+		}, ""},
+		{"bad egress cidr", &appsv2.NetworkPoliciesConfig{
+			Enabled: true, OperatorNamespace: "wandb-operator", EgressCIDRs: []string{"10.0.0.1"},
+		}, "must be a valid CIDR"},
+		{"ingress from namespace", &appsv2.NetworkPoliciesConfig{
+			Enabled: true, OperatorNamespace: "wandb-operator",
+			IngressFrom: []networkingv1.NetworkPolicyPeer{{
+				NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"kubernetes.io/metadata.name": "ingress-nginx"}},
+			}},
+		}, ""},
+		{"ingress from ipBlock with selector", &appsv2.NetworkPoliciesConfig{
+			Enabled: true, OperatorNamespace: "wandb-operator",
+			IngressFrom: []networkingv1.NetworkPolicyPeer{{
+				IPBlock:     &networkingv1.IPBlock{CIDR: "10.0.0.0/8"},
+				PodSelector: &metav1.LabelSelector{},
+			}},
+		}, "cannot be combined"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validateNetworkPolicies(wandbWithNetworkPolicies(tc.config))
+			if tc.wantErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(errs.ToAggregate().Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+	"github.com/wandb/operator/pkg/vendored/argo-rollouts/argoproj.io.rollouts/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+func TestValidateRolloutStrategies(t *testing.T) {
+	templates := []v1alpha1.AnalysisTemplateRef{{TemplateName: "success-rate"}}
+	zero := intstr.FromInt32(0)
+	zeroPercent := intstr.FromString("0%")
+	cases := []struct {
+		name     string
+		global   *appsv2.RolloutStrategySpec
+		override *appsv2.RolloutStrategySpec
+		wantErr  string // substring; "" = accept
+	}{
+		{"unset", nil, nil, ""},
+		{"canary steps", &appsv2.RolloutStrategySpec{
+			Steps: []appsv2.CanaryStepSpec{
+				{SetWeight: ptr.To[int32](20)},
+				{Pause: &appsv2.CanaryPauseSpec{Duration: "5m"}},
+				{Analysis: &appsv2.RolloutAnalysisSpec{Templates: templates}},
+				{Pause: &appsv2.CanaryPauseSpec{}},
+			},
+			Analysis: &appsv2.BackgroundAnalysisSpec{
+				RolloutAnalysisSpec: appsv2.RolloutAnalysisSpec{Templates: templates},
+				StartingStep:        ptr.To[int32](1),
+			},
+		}, nil, ""},
+		{"empty step", &appsv2.RolloutStrategySpec{
+			Steps: []appsv2.CanaryStepSpec{{}},
+		}, nil, "exactly one of setWeight, pause or analysis"},
+		{"two fields in one step", nil, &appsv2.RolloutStrategySpec{
+			Steps: []appsv2.CanaryStepSpec{{SetWeight: ptr.To[int32](50), Pause: &appsv2.CanaryPauseSpec{}}},
+		}, "spec.wandb.applications[api].rolloutStrategy.steps[0]"},
+		{"weight over 100", &appsv2.RolloutStrategySpec{
+			Steps: []appsv2.CanaryStepSpec{{SetWeight: ptr.To[int32](120)}},
+		}, nil, "must be between 0 and 100"},
+		{"bad duration", &appsv2.RolloutStrategySpec{
+			Steps: []appsv2.CanaryStepSpec{{Pause: &appsv2.CanaryPauseSpec{Duration: "1d"}}},
+		}, nil, "steps[0].pause.duration"},
+		{"analysis without templates", &appsv2.RolloutStrategySpec{
+			Steps: []appsv2.CanaryStepSpec{{Analysis: &appsv2.RolloutAnalysisSpec{}}},
+		}, nil, "at least one analysis template"},
+		{"template without name", &appsv2.RolloutStrategySpec{
+			Analysis: &appsv2.BackgroundAnalysisSpec{RolloutAnalysisSpec: appsv2.RolloutAnalysisSpec{
+				Templates: []v1alpha1.AnalysisTemplateRef{{ClusterScope: true}},
+			}},
+		}, nil, "template name is required"},
+		{"starting step past the end", &appsv2.RolloutStrategySpec{
+			Steps: []appsv2.CanaryStepSpec{{SetWeight: ptr.To[int32](50)}},
+			Analysis: &appsv2.BackgroundAnalysisSpec{
+				RolloutAnalysisSpec: appsv2.RolloutAnalysisSpec{Templates: templates},
+				StartingStep:        ptr.To[int32](1),
+			},
+		}, nil, "must be the index of one of the 1 steps"},
+		{"zero surge and unavailable", &appsv2.RolloutStrategySpec{
+			MaxSurge: &zero, MaxUnavailable: &zeroPercent,
+		}, nil, "cannot both be zero"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wandb := &appsv2.WeightsAndBiases{}
+			wandb.Spec.Wandb.RolloutStrategy = tc.global
+			if tc.override != nil {
+				wandb.Spec.Wandb.Applications = map[string]appsv2.WandbApplicationOverride{
+					"api": {RolloutStrategy: tc.override},
+				}
+			}
+			errs := validateRolloutStrategies(wandb)
+			if tc.wantErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("expected no errors, got %v", errs)
+				}
+				return
+			}
+			if len(errs) == 0 || !strings.Contains(errs.ToAggregate().Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package v2
+
+import (
+	"strings"
+	"testing"
+
+	appsv2 "github.com/wandb/operator/api/v2"
+)
+
+func storageClassWandb(class string) *appsv2.WeightsAndBiases {
+	wandb := &appsv2.WeightsAndBiases{}
+	wandb.Spec.MySQL = map[string]appsv2.MySQLSpec{
+		appsv2.DefaultInstanceName: {ManagedMysql: &appsv2.ManagedMysqlSpec{StorageClassName: class}},
+	}
+	wandb.Spec.Redis = map[string]appsv2.RedisSpec{
+		appsv2.DefaultInstanceName: {ManagedRedis: &appsv2.ManagedRedisSpec{StorageClassName: class}},
+	}
+	wandb.Spec.Kafka = appsv2.KafkaSpec{ManagedKafka: &appsv2.ManagedKafkaSpec{StorageClassName: class}}
+	wandb.Spec.ObjectStore = map[string]appsv2.ObjectStoreSpec{
+		appsv2.DefaultInstanceName: {ManagedObjectStore: &appsv2.ManagedObjectStoreSpec{StorageClassName: class}},
+	}
+	wandb.Spec.ClickHouse = map[string]appsv2.ClickHouseSpec{
+		appsv2.DefaultInstanceName: {ManagedClickHouse: &appsv2.ManagedClickHouseSpec{StorageClassName: class}},
+	}
+	return wandb
+}
+
+func TestValidateStorageClassChanges(t *testing.T) {
+	cases := []struct {
+		name     string
+		mutate   func(*appsv2.WeightsAndBiases)
+		wantErrs []string
+	}{
+		{"unchanged", func(*appsv2.WeightsAndBiases) {}, nil},
+		{"mysql class changed", func(w *appsv2.WeightsAndBiases) {
+			w.Spec.MySQL[appsv2.DefaultInstanceName].ManagedMysql.StorageClassName = "fast-ssd"
+		}, []string{"spec.mysql[default].managedMysql.storageClassName"}},
+		{"kafka class cleared", func(w *appsv2.WeightsAndBiases) {
+			w.Spec.Kafka.ManagedKafka.StorageClassName = ""
+		}, []string{"spec.kafka.managedKafka.storageClassName"}},
+		{"filer pinned to the class it already uses", func(w *appsv2.WeightsAndBiases) {
+			w.Spec.ObjectStore[appsv2.DefaultInstanceName].ManagedObjectStore.SeaweedObjectStoreSpec.FilerStorageClassName = "standard"
+		}, nil},
+		{"filer moved to another class", func(w *appsv2.WeightsAndBiases) {
+			w.Spec.ObjectStore[appsv2.DefaultInstanceName].ManagedObjectStore.SeaweedObjectStoreSpec.FilerStorageClassName = "fast-ssd"
+		}, []string{"spec.objectStore[default].managedObjectStore.SeaweedObjectStoreSpec.filerStorageClassName"}},
+		{"server class changed moves keeper too", func(w *appsv2.WeightsAndBiases) {
+			w.Spec.ClickHouse[appsv2.DefaultInstanceName].ManagedClickHouse.StorageClassName = "fast-ssd"
+		}, []string{
+			"spec.clickhouse[default].managedClickhouse.storageClassName",
+			"spec.clickhouse[default].managedClickhouse.keeper.storageClassName",
+		}},
+		{"new instance may pick any class", func(w *appsv2.WeightsAndBiases) {
+			w.Spec.Redis["cache"] = appsv2.RedisSpec{ManagedRedis: &appsv2.ManagedRedisSpec{StorageClassName: "fast-ssd"}}
+		}, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldWandb := storageClassWandb("standard")
+			newWandb := storageClassWandb("standard")
+			tc.mutate(newWandb)
+
+			errs := validateStorageClassChanges(newWandb, oldWandb)
+			if len(errs) != len(tc.wantErrs) {
+				t.Fatalf("expected %d errors, got %v", len(tc.wantErrs), errs)
+			}
+			for _, want := range tc.wantErrs {
+				if !strings.Contains(errs.ToAggregate().Error(), want) {
+					t.Errorf("expected an error for %q, got %v", want, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateStorageSizeChanges(t *testing.T) {
+	sized := func(size string) *appsv2.WeightsAndBiases {
+		wandb := &appsv2.WeightsAndBiases{}
+		wandb.Spec.MySQL = map[string]appsv2.MySQLSpec{
+			appsv2.DefaultInstanceName: {ManagedMysql: &appsv2.ManagedMysqlSpec{StorageSize: size}},
+		}
+		wandb.Spec.ObjectStore = map[string]appsv2.ObjectStoreSpec{
+			appsv2.DefaultInstanceName: {ManagedObjectStore: &appsv2.ManagedObjectStoreSpec{StorageSize: size}},
+		}
+		wandb.Spec.ClickHouse = map[string]appsv2.ClickHouseSpec{
+			appsv2.DefaultInstanceName: {ManagedClickHouse: &appsv2.ManagedClickHouseSpec{StorageSize: size}},
+		}
+		return wandb
+	}
+
+	cases := []struct {
+		name     string
+		mutate   func(*appsv2.WeightsAndBiases)
+		wantErrs []string
+	}{
+		{"grow", func(w *appsv2.WeightsAndBiases) {
+			w.Spec.MySQL[appsv2.DefaultInstanceName].ManagedMysql.StorageSize = "200Gi"
+		}, nil},
+		{"same size in other units", func(w *appsv2.WeightsAndBiases) {
+			w.Spec.MySQL[appsv2.DefaultInstanceName].ManagedMysql.StorageSize = "102400Mi"
+		}, nil},
+		{"shrink", func(w *appsv2.WeightsAndBiases) {
+			w.Spec.MySQL[appsv2.DefaultInstanceName].ManagedMysql.StorageSize = "50Gi"
+		}, []string{"spec.mysql[default].managedMysql.storageSize"}},
+		{"cleared back to manifest sizing", func(w *appsv2.WeightsAndBiases) {
+			w.Spec.ObjectStore[appsv2.DefaultInstanceName].ManagedObjectStore.StorageSize = ""
+		}, nil},
+		{"keeper shrink", func(w *appsv2.WeightsAndBiases) {
+			w.Spec.ClickHouse[appsv2.DefaultInstanceName].ManagedClickHouse.Keeper.StorageSize = "1Gi"
+		}, []string{"spec.clickhouse[default].managedClickhouse.keeper.storageSize"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			oldWandb := sized("100Gi")
+			oldWandb.Spec.ClickHouse[appsv2.DefaultInstanceName].ManagedClickHouse.Keeper.StorageSize = "5Gi"
+			newWandb := sized("100Gi")
+			newWandb.Spec.ClickHouse[appsv2.DefaultInstanceName].ManagedClickHouse.Keeper.StorageSize = "5Gi"
+			tc.mutate(newWandb)
+
+			errs := validateStorageSizeChanges(newWandb, oldWandb)
+			if len(errs) != len(tc.wantErrs) {
+				t.Fatalf("expected %d errors, got %v", len(tc.wantErrs), errs)
+			}
+			for _, want := range tc.wantErrs {
+				if !strings.Contains(errs.ToAggregate().Error(), want) {
+					t.Errorf("expected an error for %q, got %v", want, errs)
+				}
+			}
+		})
+	}
+}
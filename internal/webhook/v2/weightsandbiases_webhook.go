@@ -19,9 +19,15 @@ package v2
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/robfig/cron/v3"
 	v1 "github.com/wandb/operator/api/v1"
 	"github.com/wandb/operator/internal/controller/infra/managed/clickhouse/altinity"
 	"github.com/wandb/operator/internal/controller/infra/managed/kafka/bufstream"
@@ -29,15 +35,20 @@ import (
 	"github.com/wandb/operator/internal/controller/infra/managed/objectstore/seaweedfs"
 	"github.com/wandb/operator/internal/controller/infra/managed/redis/opstree"
 	"github.com/wandb/operator/internal/logx"
+	"github.com/wandb/operator/internal/version"
+	"github.com/wandb/operator/pkg/utils"
+	serverManifest "github.com/wandb/operator/pkg/wandb/manifest"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
@@ -47,7 +58,11 @@ import (
 // SetupWeightsAndBiasesWebhookWithManager registers the webhook for WeightsAndBiases in the manager.
 func SetupWeightsAndBiasesWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&appsv2.WeightsAndBiases{}).
-		WithValidator(&WeightsAndBiasesCustomValidator{}).
+		WithValidator(&WeightsAndBiasesCustomValidator{
+			Reader:          mgr.GetAPIReader(),
+			GetManifest:     serverManifest.GetServerManifest,
+			OperatorVersion: version.Version,
+		}).
 		WithDefaulter(&WeightsAndBiasesCustomDefaulter{}).
 		Complete()
 }
@@ -152,7 +167,13 @@ func (d *WeightsAndBiasesCustomDefaulter) Default(ctx context.Context, obj runti
 // NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
 // as this struct is used only for temporary operations and does not need to be deeply copied.
 type WeightsAndBiasesCustomValidator struct {
-	// TODO(user): Add more fields as needed for validation
+	// Reader resolves image pull secrets for server-manifest fetches.
+	Reader ctrlclient.Reader
+	// GetManifest fetches the server manifest so a version bump can be checked
+	// against its requiredOperatorVersion. Nil disables the check.
+	GetManifest func(ctx context.Context, repository, version string, auth *serverManifest.RegistryAuth) (serverManifest.Manifest, error)
+	// OperatorVersion is the build version of the running operator.
+	OperatorVersion string
 }
 
 var _ webhook.CustomValidator = &WeightsAndBiasesCustomValidator{}
@@ -166,7 +187,11 @@ func (v *WeightsAndBiasesCustomValidator) ValidateCreate(ctx context.Context, ob
 	}
 	log.Info("Validation for WeightsAndBiases upon creation", "name", wandb.GetName())
 
-	return validateSpec(ctx, wandb, nil)
+	warnings, err := validateSpec(ctx, wandb, nil)
+	if err != nil {
+		return warnings, err
+	}
+	return warnings, v.validateManifestCompatibility(ctx, wandb, nil)
 }
 
 // ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type WeightsAndBiases.
@@ -190,8 +215,10 @@ func (v *WeightsAndBiasesCustomValidator) ValidateUpdate(ctx context.Context, ol
 	if specWarnings, err = validateSpec(ctx, newWandb, oldWandb); err != nil {
 		return specWarnings, err
 	}
-	changeWarnings, err = validateChanges(ctx, newWandb, oldWandb)
-	return append(specWarnings, changeWarnings...), err
+	if changeWarnings, err = validateChanges(ctx, newWandb, oldWandb); err != nil {
+		return append(specWarnings, changeWarnings...), err
+	}
+	return append(specWarnings, changeWarnings...), v.validateManifestCompatibility(ctx, newWandb, oldWandb)
 }
 
 // ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type WeightsAndBiases.
@@ -262,6 +289,9 @@ func applyRedisDefaults(wandb *appsv2.WeightsAndBiases) {
 }
 
 func applyKafkaDefaults(wandb *appsv2.WeightsAndBiases) {
+	if wandb.Spec.Kafka.ExternalKafka != nil {
+		return
+	}
 	if wandb.Spec.Kafka.ManagedKafka == nil {
 		wandb.Spec.Kafka.ManagedKafka = &appsv2.ManagedKafkaSpec{}
 	}
@@ -355,6 +385,7 @@ func validateSpec(_ context.Context, newWandb, oldWandb *appsv2.WeightsAndBiases
 	allErrors = append(allErrors, validateWandbSpec(newWandb)...)
 	allErrors = append(allErrors, validateMySQLSpec(newWandb)...)
 	allErrors = append(allErrors, validateRedisSpec(newWandb)...)
+	allErrors = append(allErrors, validateKafkaSpec(newWandb)...)
 	allErrors = append(allErrors, validateObjectStoreSpec(newWandb)...)
 	allErrors = append(allErrors, validateClickHouseSpec(newWandb)...)
 	allErrors = append(allErrors, validateNotificationSpec(newWandb)...)
@@ -363,6 +394,7 @@ func validateSpec(_ context.Context, newWandb, oldWandb *appsv2.WeightsAndBiases
 	allErrors = append(allErrors, networkingErrors...)
 	warnings = append(warnings, networkingWarnings...)
 	allErrors = append(allErrors, validateProxySpec(newWandb)...)
+	allErrors = append(allErrors, validateNetworkPolicies(newWandb)...)
 
 	if len(allErrors) == 0 {
 		return warnings, nil
@@ -421,6 +453,8 @@ func validateChanges(_ context.Context, newWandb *appsv2.WeightsAndBiases, oldWa
 
 	allErrors = append(allErrors, validateRedisChanges(newWandb, oldWandb)...)
 	allErrors = append(allErrors, validateMySQLChanges(newWandb, oldWandb)...)
+	allErrors = append(allErrors, validateStorageSizeChanges(newWandb, oldWandb)...)
+	allErrors = append(allErrors, validateStorageClassChanges(newWandb, oldWandb)...)
 
 	if len(allErrors) == 0 {
 		return warnings, nil
@@ -517,6 +551,281 @@ func validateWandbSpec(wandb *appsv2.WeightsAndBiases) field.ErrorList {
 		))
 	}
 
+	errors = append(errors, validateManifestVerification(wandb)...)
+	errors = append(errors, validateRolloutStrategies(wandb)...)
+	errors = append(errors, validateGeneratedSecretPolicies(wandb)...)
+
+	return errors
+}
+
+// validateGeneratedSecretPolicies rejects negative rotation intervals and
+// grace periods. Keys are not checked against the manifest, which is only
+// known once the operator has fetched it; unknown keys are ignored.
+func validateGeneratedSecretPolicies(wandb *appsv2.WeightsAndBiases) field.ErrorList {
+	var errors field.ErrorList
+	policiesPath := field.NewPath("spec").Child("wandb").Child("generatedSecrets")
+	names := make([]string, 0, len(wandb.Spec.Wandb.GeneratedSecrets))
+	for name := range wandb.Spec.Wandb.GeneratedSecrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		policy := wandb.Spec.Wandb.GeneratedSecrets[name]
+		if policy.RotationInterval != nil && policy.RotationInterval.Duration < 0 {
+			errors = append(errors, field.Invalid(
+				policiesPath.Key(name).Child("rotationInterval"),
+				policy.RotationInterval.Duration.String(),
+				"must not be negative",
+			))
+		}
+		if policy.GracePeriod != nil && policy.GracePeriod.Duration < 0 {
+			errors = append(errors, field.Invalid(
+				policiesPath.Key(name).Child("gracePeriod"),
+				policy.GracePeriod.Duration.String(),
+				"must not be negative",
+			))
+		}
+	}
+	return errors
+}
+
+// validateRolloutStrategies checks spec.wandb.rolloutStrategy and the
+// per-application overrides. Whether the referenced AnalysisTemplates exist
+// is left to the Argo controller, which reports it on the Rollout.
+func validateRolloutStrategies(wandb *appsv2.WeightsAndBiases) field.ErrorList {
+	wandbPath := field.NewPath("spec").Child("wandb")
+	errors := validateRolloutStrategy(wandb.Spec.Wandb.RolloutStrategy, wandbPath.Child("rolloutStrategy"))
+
+	appNames := make([]string, 0, len(wandb.Spec.Wandb.Applications))
+	for name := range wandb.Spec.Wandb.Applications {
+		appNames = append(appNames, name)
+	}
+	sort.Strings(appNames)
+	for _, name := range appNames {
+		errors = append(errors, validateRolloutStrategy(
+			wandb.Spec.Wandb.Applications[name].RolloutStrategy,
+			wandbPath.Child("applications").Key(name).Child("rolloutStrategy"),
+		)...)
+	}
+	return errors
+}
+
+var rolloutPauseDurationPattern = regexp.MustCompile(`^[0-9]+(s|m|h)?$`)
+
+func validateRolloutStrategy(strategy *appsv2.RolloutStrategySpec, strategyPath *field.Path) field.ErrorList {
+	var errors field.ErrorList
+	if strategy == nil {
+		return errors
+	}
+
+	for i, step := range strategy.Steps {
+		stepPath := strategyPath.Child("steps").Index(i)
+		set := 0
+		if step.SetWeight != nil {
+			set++
+			if *step.SetWeight < 0 || *step.SetWeight > 100 {
+				errors = append(errors, field.Invalid(stepPath.Child("setWeight"), *step.SetWeight, "must be between 0 and 100"))
+			}
+		}
+		if step.Pause != nil {
+			set++
+			if step.Pause.Duration != "" && !rolloutPauseDurationPattern.MatchString(step.Pause.Duration) {
+				errors = append(errors, field.Invalid(stepPath.Child("pause").Child("duration"), step.Pause.Duration,
+					`must be a number of seconds or end in "s", "m" or "h"`))
+			}
+		}
+		if step.Analysis != nil {
+			set++
+			errors = append(errors, validateRolloutAnalysis(*step.Analysis, stepPath.Child("analysis"))...)
+		}
+		if set != 1 {
+			errors = append(errors, field.Invalid(stepPath, "", "exactly one of setWeight, pause or analysis must be set"))
+		}
+	}
+
+	if analysis := strategy.Analysis; analysis != nil {
+		analysisPath := strategyPath.Child("analysis")
+		errors = append(errors, validateRolloutAnalysis(analysis.RolloutAnalysisSpec, analysisPath)...)
+		if analysis.StartingStep != nil && (*analysis.StartingStep < 0 || int(*analysis.StartingStep) >= len(strategy.Steps)) {
+			errors = append(errors, field.Invalid(analysisPath.Child("startingStep"), *analysis.StartingStep,
+				fmt.Sprintf("must be the index of one of the %d steps", len(strategy.Steps))))
+		}
+	}
+
+	if isZeroIntOrString(strategy.MaxSurge) && isZeroIntOrString(strategy.MaxUnavailable) {
+		errors = append(errors, field.Invalid(strategyPath.Child("maxUnavailable"), strategy.MaxUnavailable.String(),
+			"maxSurge and maxUnavailable cannot both be zero"))
+	}
+	return errors
+}
+
+func validateRolloutAnalysis(analysis appsv2.RolloutAnalysisSpec, analysisPath *field.Path) field.ErrorList {
+	var errors field.ErrorList
+	if len(analysis.Templates) == 0 {
+		errors = append(errors, field.Required(analysisPath.Child("templates"), "at least one analysis template is required"))
+	}
+	for i, template := range analysis.Templates {
+		if template.TemplateName == "" {
+			errors = append(errors, field.Required(analysisPath.Child("templates").Index(i).Child("templateName"),
+				"template name is required"))
+		}
+	}
+	for i, arg := range analysis.Args {
+		if arg.Name == "" {
+			errors = append(errors, field.Required(analysisPath.Child("args").Index(i).Child("name"), "argument name is required"))
+		}
+	}
+	return errors
+}
+
+func isZeroIntOrString(value *intstr.IntOrString) bool {
+	if value == nil {
+		return false
+	}
+	if value.Type == intstr.Int {
+		return value.IntVal == 0
+	}
+	return value.StrVal == "0" || value.StrVal == "0%"
+}
+
+// validateManifestVerification checks the key references up front; whether the
+// keys exist and parse is only known at reconcile, where it surfaces as
+// ManifestUntrusted.
+func validateManifestVerification(wandb *appsv2.WeightsAndBiases) field.ErrorList {
+	var errors field.ErrorList
+	verification := wandb.Spec.Wandb.ManifestVerification
+	if verification == nil {
+		return errors
+	}
+	verificationPath := field.NewPath("spec").Child("wandb").Child("manifestVerification")
+
+	if serverManifest.IsFileRepository(wandb.Spec.Wandb.ManifestRepository) {
+		errors = append(errors, field.Forbidden(
+			verificationPath,
+			"file:// manifest repositories carry no signatures and cannot be verified",
+		))
+	}
+	if len(verification.PublicKeys) == 0 {
+		errors = append(errors, field.Required(verificationPath.Child("publicKeys"), "at least one public key is required"))
+	}
+	for i, source := range verification.PublicKeys {
+		keyPath := verificationPath.Child("publicKeys").Index(i)
+		switch {
+		case source.ConfigMapKeyRef != nil && source.SecretKeyRef != nil:
+			errors = append(errors, field.Invalid(keyPath, "", "only one of configMapKeyRef or secretKeyRef may be set"))
+		case source.ConfigMapKeyRef != nil:
+			if source.ConfigMapKeyRef.Name == "" {
+				errors = append(errors, field.Required(keyPath.Child("configMapKeyRef").Child("name"), "configmap name is required"))
+			}
+			if source.ConfigMapKeyRef.Key == "" {
+				errors = append(errors, field.Required(keyPath.Child("configMapKeyRef").Child("key"), "configmap key is required"))
+			}
+		case source.SecretKeyRef != nil:
+			errors = append(errors, validateRequiredSecretSelector(*source.SecretKeyRef, keyPath.Child("secretKeyRef"))...)
+		default:
+			errors = append(errors, field.Required(keyPath, "one of configMapKeyRef or secretKeyRef is required"))
+		}
+	}
+	return errors
+}
+
+// validateInfraTLS checks a managed instance's tls block. A cert-manager
+// Certificate takes a single issuerRef, so exactly one issuer kind is named.
+func validateInfraTLS(tls *appsv2.InfraTLSSpec, tlsPath *field.Path) field.ErrorList {
+	if tls == nil || tls.CertManager == nil {
+		return nil
+	}
+	if (tls.CertManager.Issuer == "") == (tls.CertManager.ClusterIssuer == "") {
+		return field.ErrorList{field.Invalid(
+			tlsPath.Child("certManager"),
+			tls.CertManager,
+			"exactly one of issuer and clusterIssuer must be set",
+		)}
+	}
+	return nil
+}
+
+// validateMySQLBackup checks the backup schedule parses as a CronJob schedule
+// and that the target object store instance exists.
+func validateMySQLBackup(wandb *appsv2.WeightsAndBiases, backup *appsv2.MySQLBackupSpec, backupPath *field.Path) field.ErrorList {
+	if backup == nil {
+		return nil
+	}
+	var errors field.ErrorList
+	if _, err := cron.ParseStandard(backup.Schedule); err != nil {
+		errors = append(errors, field.Invalid(backupPath.Child("schedule"), backup.Schedule, err.Error()))
+	}
+	if backup.Retention < 0 {
+		errors = append(errors, field.Invalid(backupPath.Child("retention"), backup.Retention, "retention must not be negative"))
+	}
+	if _, ok := appsv2.ResolveInstance(wandb.Spec.ObjectStore, backup.ObjectStore); !ok {
+		errors = append(errors, field.NotFound(backupPath.Child("objectStore"), backup.ObjectStore))
+	}
+	return errors
+}
+
+// validateMySQLRestore checks the prefix names a MOCO backup location and the
+// source object store exists.
+func validateMySQLRestore(wandb *appsv2.WeightsAndBiases, restore *appsv2.MySQLRestoreSpec, restorePath *field.Path) field.ErrorList {
+	if restore == nil {
+		return nil
+	}
+	var errors field.ErrorList
+	if _, _, ok := moco.ParseBackupPrefix(restore.Prefix); !ok {
+		errors = append(errors, field.Invalid(restorePath.Child("prefix"), restore.Prefix, "must be of the form moco/<namespace>/<name>/"))
+	}
+	if _, ok := appsv2.ResolveInstance(wandb.Spec.ObjectStore, restore.ObjectStore); !ok {
+		errors = append(errors, field.NotFound(restorePath.Child("objectStore"), restore.ObjectStore))
+	}
+	return errors
+}
+
+// validateMysqld checks mysqld settings before they reach the my.cnf
+// ConfigMap. Options MOCO manages for replication are rejected rather than
+// silently overridden, and values must fit on a single my.cnf line.
+func validateMysqld(cfg *appsv2.MysqldConfig, mysqldPath *field.Path) field.ErrorList {
+	if cfg == nil {
+		return nil
+	}
+	var errors field.ErrorList
+	if cfg.InnodbBufferPoolSize != nil && cfg.InnodbBufferPoolSize.Sign() <= 0 {
+		errors = append(errors, field.Invalid(mysqldPath.Child("innodbBufferPoolSize"), cfg.InnodbBufferPoolSize.String(), "must be positive"))
+	}
+	if cfg.MaxConnections != nil && *cfg.MaxConnections <= 0 {
+		errors = append(errors, field.Invalid(mysqldPath.Child("maxConnections"), *cfg.MaxConnections, "must be positive"))
+	}
+	if cfg.LongQueryTime != "" {
+		if seconds, err := strconv.ParseFloat(cfg.LongQueryTime, 64); err != nil || seconds < 0 {
+			errors = append(errors, field.Invalid(mysqldPath.Child("longQueryTime"), cfg.LongQueryTime, "must be a non-negative number of seconds"))
+		}
+	}
+	if cfg.BinlogExpireLogsSeconds != nil && *cfg.BinlogExpireLogsSeconds < 0 {
+		errors = append(errors, field.Invalid(mysqldPath.Child("binlogExpireLogsSeconds"), *cfg.BinlogExpireLogsSeconds, "must not be negative"))
+	}
+
+	names := make([]string, 0, len(cfg.Settings))
+	for name := range cfg.Settings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	seen := map[string]string{}
+	for _, name := range names {
+		settingPath := mysqldPath.Child("settings").Key(name)
+		first, duplicate := seen[appsv2.NormalizeMysqldSetting(name)]
+		switch {
+		case strings.TrimSpace(name) == "" || strings.ContainsAny(name, "=[]# \t\n"):
+			errors = append(errors, field.Invalid(settingPath, name, "must be a mysqld option name"))
+		case appsv2.MysqldSettingProtected(name):
+			errors = append(errors, field.Forbidden(settingPath, "managed by MOCO or the operator and cannot be overridden"))
+		case duplicate:
+			errors = append(errors, field.Invalid(settingPath, name, fmt.Sprintf("names the same mysqld option as %q", first)))
+		case strings.ContainsAny(cfg.Settings[name], "\n\r"):
+			errors = append(errors, field.Invalid(settingPath, cfg.Settings[name], "must be a single line"))
+		}
+		if !duplicate {
+			seen[appsv2.NormalizeMysqldSetting(name)] = name
+		}
+	}
 	return errors
 }
 
@@ -543,6 +852,10 @@ func validateMySQLSpec(wandb *appsv2.WeightsAndBiases) field.ErrorList {
 					"replicas must be an odd number (Moco enforces quorum-based replication)",
 				))
 			}
+			errors = append(errors, validateInfraTLS(managed.TLS, instancePath.Child("managedMysql").Child("tls"))...)
+			errors = append(errors, validateMysqld(managed.Config.Mysqld, instancePath.Child("managedMysql").Child("config").Child("mysqld"))...)
+			errors = append(errors, validateMySQLBackup(wandb, managed.Backup, instancePath.Child("managedMysql").Child("backup"))...)
+			errors = append(errors, validateMySQLRestore(wandb, managed.Restore, instancePath.Child("managedMysql").Child("restore"))...)
 		}
 	}
 
@@ -576,6 +889,8 @@ func validateRedisSpec(wandb *appsv2.WeightsAndBiases) field.ErrorList {
 			continue
 		}
 
+		errors = append(errors, validateInfraTLS(spec.ManagedRedis.TLS, instancePath.Child("managedRedis").Child("tls"))...)
+
 		if spec.ManagedRedis.StorageSize != "" {
 			if _, err := resource.ParseQuantity(spec.ManagedRedis.StorageSize); err != nil {
 				errors = append(errors, field.Invalid(
@@ -590,6 +905,47 @@ func validateRedisSpec(wandb *appsv2.WeightsAndBiases) field.ErrorList {
 	return errors
 }
 
+func validateKafkaSpec(wandb *appsv2.WeightsAndBiases) field.ErrorList {
+	var errors field.ErrorList
+	kafkaPath := field.NewPath("spec").Child("kafka")
+
+	if wandb.Spec.Kafka.ManagedKafka != nil && wandb.Spec.Kafka.ExternalKafka != nil {
+		errors = append(errors, field.Invalid(
+			kafkaPath,
+			"",
+			"managedKafka and externalKafka are mutually exclusive",
+		))
+	}
+
+	externalKafka := wandb.Spec.Kafka.ExternalKafka
+	if externalKafka == nil {
+		return errors
+	}
+	externalPath := kafkaPath.Child("externalKafka")
+
+	// The bootstrap list may be given as brokerEndpoint or as a single host and
+	// port; the values themselves are checked when the secrets are resolved.
+	if externalKafka.BrokerEndpoint.Name == "" && externalKafka.BrokerEndpoint.Key == "" {
+		errors = append(errors, validateRequiredSecretSelector(externalKafka.Host, externalPath.Child("host"))...)
+		errors = append(errors, validateRequiredSecretSelector(externalKafka.Port, externalPath.Child("port"))...)
+	} else {
+		errors = append(errors, validateRequiredSecretSelector(externalKafka.BrokerEndpoint, externalPath.Child("brokerEndpoint"))...)
+	}
+
+	hasUsername := externalKafka.Username != (corev1.SecretKeySelector{})
+	hasPassword := externalKafka.Password != (corev1.SecretKeySelector{})
+	hasMechanism := externalKafka.SaslMechanism != (corev1.SecretKeySelector{})
+	if hasUsername || hasPassword || hasMechanism {
+		errors = append(errors, validateRequiredSecretSelector(externalKafka.Username, externalPath.Child("username"))...)
+		errors = append(errors, validateRequiredSecretSelector(externalKafka.Password, externalPath.Child("password"))...)
+	}
+	if hasMechanism {
+		errors = append(errors, validateRequiredSecretSelector(externalKafka.SaslMechanism, externalPath.Child("saslMechanism"))...)
+	}
+
+	return errors
+}
+
 func validateRequiredSecretSelector(selector corev1.SecretKeySelector, path *field.Path) field.ErrorList {
 	var errors field.ErrorList
 	if selector.Name == "" {
@@ -694,6 +1050,10 @@ func validateClickHouseSpec(wandb *appsv2.WeightsAndBiases) field.ErrorList {
 			))
 		}
 
+		errors = append(errors, validateInfraTLS(managed.TLS, instancePath.Child("managedClickhouse").Child("tls"))...)
+		errors = append(errors, validateClickHouseBackup(wandb, managed.Backup, instancePath.Child("managedClickhouse").Child("backup"))...)
+		errors = append(errors, validateClickHouseRestore(wandb, managed.RestoreFrom, instancePath.Child("managedClickhouse").Child("restoreFrom"))...)
+
 		// Keeper requires an odd number of replicas to form a quorum.
 		if managed.Keeper.Replicas != 0 && managed.Keeper.Replicas%2 == 0 {
 			errors = append(errors, field.Invalid(
@@ -722,6 +1082,45 @@ func validateClickHouseSpec(wandb *appsv2.WeightsAndBiases) field.ErrorList {
 	return errors
 }
 
+// validateClickHouseBackup checks the backup schedule parses as a CronJob
+// schedule and that the target object store instance exists.
+func validateClickHouseBackup(wandb *appsv2.WeightsAndBiases, backup *appsv2.ClickHouseBackupSpec, backupPath *field.Path) field.ErrorList {
+	if backup == nil {
+		return nil
+	}
+	var errors field.ErrorList
+	if _, err := cron.ParseStandard(backup.Schedule); err != nil {
+		errors = append(errors, field.Invalid(backupPath.Child("schedule"), backup.Schedule, err.Error()))
+	}
+	if backup.Retention < 0 {
+		errors = append(errors, field.Invalid(backupPath.Child("retention"), backup.Retention, "retention must not be negative"))
+	}
+	if _, ok := appsv2.ResolveInstance(wandb.Spec.ObjectStore, backup.ObjectStore); !ok {
+		errors = append(errors, field.NotFound(backupPath.Child("objectStore"), backup.ObjectStore))
+	}
+	return errors
+}
+
+// validateClickHouseRestore checks the prefix names a ClickHouse backup
+// location, the backup names a single directory under it, and the source
+// object store exists.
+func validateClickHouseRestore(wandb *appsv2.WeightsAndBiases, restore *appsv2.ClickHouseRestoreSpec, restorePath *field.Path) field.ErrorList {
+	if restore == nil {
+		return nil
+	}
+	var errors field.ErrorList
+	if !altinity.ValidBackupPrefix(restore.Prefix) {
+		errors = append(errors, field.Invalid(restorePath.Child("prefix"), restore.Prefix, "must be of the form clickhouse-backup/<namespace>/<name>/"))
+	}
+	if restore.Backup != "" && !altinity.ValidBackupName(restore.Backup) {
+		errors = append(errors, field.Invalid(restorePath.Child("backup"), restore.Backup, "must name a single backup directory under prefix"))
+	}
+	if _, ok := appsv2.ResolveInstance(wandb.Spec.ObjectStore, restore.ObjectStore); !ok {
+		errors = append(errors, field.NotFound(restorePath.Child("objectStore"), restore.ObjectStore))
+	}
+	return errors
+}
+
 // validateInfraNames rejects managed infra names whose derived object names
 // cannot be deployed (vendor operators wedge silently past DNS-1123 limits).
 // Empty names are the defaulter's to fill; on update only changed names are
@@ -866,15 +1265,6 @@ func validateRedisChanges(newWandb, oldWandb *appsv2.WeightsAndBiases) field.Err
 		oldSpec := oldInstance.ManagedRedis
 		instancePath := redisPath.Key(key).Child("managedRedis")
 
-		if oldSpec.StorageSize != "" &&
-			oldSpec.StorageSize != newSpec.StorageSize {
-			errors = append(errors, field.Invalid(
-				instancePath.Child("storageSize"),
-				newSpec.StorageSize,
-				"storageSize may not be changed",
-			))
-		}
-
 		if oldSpec.Namespace != newSpec.Namespace {
 			errors = append(errors, field.Invalid(
 				instancePath.Child("namespace"),
@@ -895,6 +1285,181 @@ func validateRedisChanges(newWandb, oldWandb *appsv2.WeightsAndBiases) field.Err
 	return errors
 }
 
+const storageSizeShrinkMessage = "storageSize cannot be decreased; PersistentVolumeClaims can only grow"
+
+// validateStorageSizeChanges rejects lowering an explicitly-set storageSize on
+// a managed instance. The reconciler grows existing PVCs in place, but no
+// StorageClass can shrink a bound volume. Sizes left empty are resolved from
+// the manifest at reconcile time and are not checked here.
+func validateStorageSizeChanges(newWandb, oldWandb *appsv2.WeightsAndBiases) field.ErrorList {
+	var errors field.ErrorList
+	check := func(path *field.Path, oldSize, newSize string) {
+		if oldSize == "" || newSize == "" {
+			return
+		}
+		oldQuantity, oldErr := resource.ParseQuantity(oldSize)
+		newQuantity, newErr := resource.ParseQuantity(newSize)
+		if oldErr != nil || newErr != nil {
+			return
+		}
+		if newQuantity.Cmp(oldQuantity) < 0 {
+			errors = append(errors, field.Invalid(path, newSize, storageSizeShrinkMessage))
+		}
+	}
+	specPath := field.NewPath("spec")
+
+	for key, newInstance := range newWandb.Spec.MySQL {
+		oldInstance, ok := oldWandb.Spec.MySQL[key]
+		if !ok || newInstance.ManagedMysql == nil || oldInstance.ManagedMysql == nil {
+			continue
+		}
+		check(specPath.Child("mysql").Key(key).Child("managedMysql", "storageSize"),
+			oldInstance.ManagedMysql.StorageSize, newInstance.ManagedMysql.StorageSize)
+	}
+
+	for key, newInstance := range newWandb.Spec.Redis {
+		oldInstance, ok := oldWandb.Spec.Redis[key]
+		if !ok || newInstance.ManagedRedis == nil || oldInstance.ManagedRedis == nil {
+			continue
+		}
+		check(specPath.Child("redis").Key(key).Child("managedRedis", "storageSize"),
+			oldInstance.ManagedRedis.StorageSize, newInstance.ManagedRedis.StorageSize)
+	}
+
+	for key, newInstance := range newWandb.Spec.ObjectStore {
+		oldInstance, ok := oldWandb.Spec.ObjectStore[key]
+		if !ok || newInstance.ManagedObjectStore == nil || oldInstance.ManagedObjectStore == nil {
+			continue
+		}
+		newSpec, oldSpec := newInstance.ManagedObjectStore, oldInstance.ManagedObjectStore
+		instancePath := specPath.Child("objectStore").Key(key).Child("managedObjectStore")
+		check(instancePath.Child("storageSize"), oldSpec.StorageSize, newSpec.StorageSize)
+		check(instancePath.Child("SeaweedObjectStoreSpec", "filerStorageSize"),
+			oldSpec.SeaweedObjectStoreSpec.FilerStorageSize, newSpec.SeaweedObjectStoreSpec.FilerStorageSize)
+	}
+
+	for key, newInstance := range newWandb.Spec.ClickHouse {
+		oldInstance, ok := oldWandb.Spec.ClickHouse[key]
+		if !ok || newInstance.ManagedClickHouse == nil || oldInstance.ManagedClickHouse == nil {
+			continue
+		}
+		newSpec, oldSpec := newInstance.ManagedClickHouse, oldInstance.ManagedClickHouse
+		instancePath := specPath.Child("clickhouse").Key(key).Child("managedClickhouse")
+		check(instancePath.Child("storageSize"), oldSpec.StorageSize, newSpec.StorageSize)
+		check(instancePath.Child("keeper", "storageSize"), oldSpec.Keeper.StorageSize, newSpec.Keeper.StorageSize)
+	}
+
+	return errors
+}
+
+const storageClassImmutableMessage = "storageClassName cannot be changed once volumes are provisioned; " +
+	"the existing PersistentVolumeClaims keep their class and cannot be migrated in place"
+
+// validateStorageClassChanges rejects storageClassName edits on managed
+// instances that already exist. A PVC's class is immutable and none of the
+// vendor operators move data between classes, so the change would either be
+// ignored or wedge the StatefulSet rollout. The filer and Keeper classes are
+// compared after falling back to the instance class, so spelling out the
+// class they already use is allowed.
+func validateStorageClassChanges(newWandb, oldWandb *appsv2.WeightsAndBiases) field.ErrorList {
+	var errors field.ErrorList
+	check := func(path *field.Path, oldClass, newClass string) {
+		if oldClass != newClass {
+			errors = append(errors, field.Invalid(path, newClass, storageClassImmutableMessage))
+		}
+	}
+	specPath := field.NewPath("spec")
+
+	for key, newInstance := range newWandb.Spec.MySQL {
+		oldInstance, ok := oldWandb.Spec.MySQL[key]
+		if !ok || newInstance.ManagedMysql == nil || oldInstance.ManagedMysql == nil {
+			continue
+		}
+		check(specPath.Child("mysql").Key(key).Child("managedMysql", "storageClassName"),
+			oldInstance.ManagedMysql.StorageClassName, newInstance.ManagedMysql.StorageClassName)
+	}
+
+	for key, newInstance := range newWandb.Spec.Redis {
+		oldInstance, ok := oldWandb.Spec.Redis[key]
+		if !ok || newInstance.ManagedRedis == nil || oldInstance.ManagedRedis == nil {
+			continue
+		}
+		check(specPath.Child("redis").Key(key).Child("managedRedis", "storageClassName"),
+			oldInstance.ManagedRedis.StorageClassName, newInstance.ManagedRedis.StorageClassName)
+	}
+
+	if newKafka, oldKafka := newWandb.Spec.Kafka.ManagedKafka, oldWandb.Spec.Kafka.ManagedKafka; newKafka != nil && oldKafka != nil {
+		check(specPath.Child("kafka", "managedKafka", "storageClassName"), oldKafka.StorageClassName, newKafka.StorageClassName)
+	}
+
+	for key, newInstance := range newWandb.Spec.ObjectStore {
+		oldInstance, ok := oldWandb.Spec.ObjectStore[key]
+		if !ok || newInstance.ManagedObjectStore == nil || oldInstance.ManagedObjectStore == nil {
+			continue
+		}
+		newSpec, oldSpec := newInstance.ManagedObjectStore, oldInstance.ManagedObjectStore
+		instancePath := specPath.Child("objectStore").Key(key).Child("managedObjectStore")
+		check(instancePath.Child("storageClassName"), oldSpec.StorageClassName, newSpec.StorageClassName)
+		check(instancePath.Child("SeaweedObjectStoreSpec", "filerStorageClassName"),
+			utils.Coalesce(oldSpec.SeaweedObjectStoreSpec.FilerStorageClassName, oldSpec.StorageClassName),
+			utils.Coalesce(newSpec.SeaweedObjectStoreSpec.FilerStorageClassName, newSpec.StorageClassName))
+	}
+
+	for key, newInstance := range newWandb.Spec.ClickHouse {
+		oldInstance, ok := oldWandb.Spec.ClickHouse[key]
+		if !ok || newInstance.ManagedClickHouse == nil || oldInstance.ManagedClickHouse == nil {
+			continue
+		}
+		newSpec, oldSpec := newInstance.ManagedClickHouse, oldInstance.ManagedClickHouse
+		instancePath := specPath.Child("clickhouse").Key(key).Child("managedClickhouse")
+		check(instancePath.Child("storageClassName"), oldSpec.StorageClassName, newSpec.StorageClassName)
+		check(instancePath.Child("keeper", "storageClassName"),
+			utils.Coalesce(oldSpec.Keeper.StorageClassName, oldSpec.StorageClassName),
+			utils.Coalesce(newSpec.Keeper.StorageClassName, newSpec.StorageClassName))
+	}
+
+	return errors
+}
+
+// validateNetworkPolicies validates spec.networking.networkPolicies. Managed
+// infrastructure only admits the operators from operatorNamespace, so it is
+// required once policies are on.
+func validateNetworkPolicies(wandb *appsv2.WeightsAndBiases) field.ErrorList {
+	var errors field.ErrorList
+	config := wandb.Spec.Networking.NetworkPolicies
+	if config == nil || !config.Enabled {
+		return errors
+	}
+	base := field.NewPath("spec").Child("networking").Child("networkPolicies")
+
+	if config.OperatorNamespace == "" {
+		errors = append(errors, field.Required(base.Child("operatorNamespace"),
+			"operatorNamespace is required when networkPolicies are enabled"))
+	} else {
+		for _, msg := range validation.IsDNS1123Label(config.OperatorNamespace) {
+			errors = append(errors, field.Invalid(base.Child("operatorNamespace"), config.OperatorNamespace, msg))
+		}
+	}
+	for i, cidr := range config.EgressCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errors = append(errors, field.Invalid(base.Child("egressCIDRs").Index(i), cidr, "must be a valid CIDR"))
+		}
+	}
+	for i, peer := range config.IngressFrom {
+		if peer.IPBlock != nil {
+			if _, _, err := net.ParseCIDR(peer.IPBlock.CIDR); err != nil {
+				errors = append(errors, field.Invalid(base.Child("ingressFrom").Index(i).Child("ipBlock", "cidr"),
+					peer.IPBlock.CIDR, "must be a valid CIDR"))
+			}
+			if peer.NamespaceSelector != nil || peer.PodSelector != nil {
+				errors = append(errors, field.Invalid(base.Child("ingressFrom").Index(i), peer,
+					"ipBlock cannot be combined with namespaceSelector or podSelector"))
+			}
+		}
+	}
+	return errors
+}
+
 // validateProxySpec validates spec.global.proxy: each proxy value sets exactly
 // one of value|valueFrom, a literal value parses as an http(s) URL with no
 // userinfo (credentials must use valueFrom so they never land in the CR), and
@@ -956,6 +1521,220 @@ func validateProxySpec(wandb *appsv2.WeightsAndBiases) field.ErrorList {
 	return errors
 }
 
+// gatewayDurationPattern is the GEP-2257 duration format HTTPRoute timeouts and
+// retry backoffs accept.
+var gatewayDurationPattern = regexp.MustCompile(`^([0-9]{1,5}(h|m|s|ms)){1,4}$`)
+
+// validateGatewayRouting checks the HTTPRoute overrides. Whether a key names an
+// application or infrastructure route in the manifest, and whether the
+// referenced Services exist, is only known at reconcile; the Gateway reports
+// missing backends on the HTTPRoute.
+func validateGatewayRouting(routing map[string]appsv2.GatewayRouting, routingPath *field.Path) field.ErrorList {
+	var errors field.ErrorList
+	keys := make([]string, 0, len(routing))
+	for key := range routing {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		keyPath := routingPath.Key(key)
+		entry := routing[key]
+		errors = append(errors, validateGatewayRouteActions(entry.GatewayRouteActions, keyPath)...)
+		for i, rule := range entry.Rules {
+			rulePath := keyPath.Child("rules").Index(i)
+			if len(rule.Paths) == 0 && len(rule.Headers) == 0 {
+				errors = append(errors, field.Required(rulePath, "a rule must match on paths, headers or both"))
+			}
+			for j, p := range rule.Paths {
+				if !strings.HasPrefix(p, "/") {
+					errors = append(errors, field.Invalid(rulePath.Child("paths").Index(j), p, `must start with "/"`))
+				}
+			}
+			for j, header := range rule.Headers {
+				headerPath := rulePath.Child("headers").Index(j)
+				if header.Name == "" {
+					errors = append(errors, field.Required(headerPath.Child("name"), "header name is required"))
+				}
+				if header.Type == "RegularExpression" {
+					if _, err := regexp.Compile(header.Value); err != nil {
+						errors = append(errors, field.Invalid(headerPath.Child("value"), header.Value, err.Error()))
+					}
+				}
+			}
+			errors = append(errors, validateGatewayRouteActions(rule.GatewayRouteActions, rulePath)...)
+		}
+	}
+	return errors
+}
+
+func validateGatewayRouteActions(actions appsv2.GatewayRouteActions, actionsPath *field.Path) field.ErrorList {
+	var errors field.ErrorList
+
+	totalWeight := int64(1)
+	if actions.ServiceWeight != nil {
+		totalWeight = int64(*actions.ServiceWeight)
+		if *actions.ServiceWeight < 0 {
+			errors = append(errors, field.Invalid(actionsPath.Child("serviceWeight"), *actions.ServiceWeight, "must not be negative"))
+		}
+	}
+	for i, backend := range actions.Backends {
+		backendPath := actionsPath.Child("backends").Index(i)
+		errors = append(errors, validateGatewayBackendRef(backend.Name, backend.Port, backendPath)...)
+		weight := int64(1)
+		if backend.Weight != nil {
+			weight = int64(*backend.Weight)
+			if *backend.Weight < 0 {
+				errors = append(errors, field.Invalid(backendPath.Child("weight"), *backend.Weight, "must not be negative"))
+			}
+		}
+		totalWeight += weight
+	}
+	if totalWeight <= 0 {
+		errors = append(errors, field.Invalid(actionsPath.Child("serviceWeight"), 0,
+			"serviceWeight and backend weights must not all be zero"))
+	}
+
+	if mirror := actions.Mirror; mirror != nil {
+		mirrorPath := actionsPath.Child("mirror")
+		errors = append(errors, validateGatewayBackendRef(mirror.Name, mirror.Port, mirrorPath)...)
+		if mirror.Percent != nil && (*mirror.Percent < 0 || *mirror.Percent > 100) {
+			errors = append(errors, field.Invalid(mirrorPath.Child("percent"), *mirror.Percent, "must be between 0 and 100"))
+		}
+	}
+
+	if timeouts := actions.Timeouts; timeouts != nil {
+		timeoutsPath := actionsPath.Child("timeouts")
+		request, requestOK := parseGatewayDuration(timeouts.Request, timeoutsPath.Child("request"), &errors)
+		backendRequest, backendOK := parseGatewayDuration(timeouts.BackendRequest, timeoutsPath.Child("backendRequest"), &errors)
+		if requestOK && backendOK && request > 0 && backendRequest > request {
+			errors = append(errors, field.Invalid(timeoutsPath.Child("backendRequest"), timeouts.BackendRequest,
+				"must not exceed timeouts.request"))
+		}
+	}
+
+	if retry := actions.Retry; retry != nil {
+		retryPath := actionsPath.Child("retry")
+		if retry.Attempts != nil && *retry.Attempts < 0 {
+			errors = append(errors, field.Invalid(retryPath.Child("attempts"), *retry.Attempts, "must not be negative"))
+		}
+		parseGatewayDuration(retry.Backoff, retryPath.Child("backoff"), &errors)
+		for i, code := range retry.Codes {
+			if code < 400 || code > 599 {
+				errors = append(errors, field.Invalid(retryPath.Child("codes").Index(i), code, "must be between 400 and 599"))
+			}
+		}
+	}
+	return errors
+}
+
+// validateIngressHosts checks spec.networking.hosts. Hostnames must be unique
+// across the hosts, spec.wandb.hostname and spec.wandb.additionalHostnames, and
+// so must the names the hosts' Ingresses and listeners are derived from.
+func validateIngressHosts(wandb *appsv2.WeightsAndBiases, hostsPath *field.Path) (field.ErrorList, admission.Warnings) {
+	var errors field.ErrorList
+	var warnings admission.Warnings
+	spec := wandb.Spec.Networking
+
+	seenHostnames := map[string]bool{}
+	if parsed, err := url.Parse(wandb.Spec.Wandb.Hostname); err == nil && parsed.Hostname() != "" {
+		seenHostnames[parsed.Hostname()] = true
+	}
+	for _, h := range wandb.Spec.Wandb.AdditionalHostnames {
+		seenHostnames[h] = true
+	}
+	seenNames := map[string]bool{}
+
+	for i, host := range spec.Hosts {
+		hostPath := hostsPath.Index(i)
+		if host.Hostname == "" {
+			errors = append(errors, field.Required(hostPath.Child("hostname"), "hostname is required"))
+		} else {
+			for _, msg := range validation.IsDNS1123Subdomain(host.Hostname) {
+				errors = append(errors, field.Invalid(hostPath.Child("hostname"), host.Hostname, msg))
+			}
+			if seenHostnames[host.Hostname] {
+				errors = append(errors, field.Duplicate(hostPath.Child("hostname"), host.Hostname))
+			}
+			seenHostnames[host.Hostname] = true
+		}
+
+		name := host.Name
+		if name == "" {
+			name = strings.ReplaceAll(host.Hostname, ".", "-")
+		} else {
+			for _, msg := range validation.IsDNS1123Label(host.Name) {
+				errors = append(errors, field.Invalid(hostPath.Child("name"), host.Name, msg))
+			}
+		}
+		if name != "" {
+			if seenNames[name] {
+				errors = append(errors, field.Duplicate(hostPath.Child("name"), name))
+			}
+			seenNames[name] = true
+		}
+
+		if host.TLS != nil && host.TLS.CertManager != nil &&
+			host.TLS.CertManager.Issuer != "" && host.TLS.CertManager.ClusterIssuer != "" {
+			errors = append(errors, field.Invalid(
+				hostPath.Child("tls").Child("certManager"),
+				host.TLS.CertManager,
+				"only one of issuer and clusterIssuer may be set",
+			))
+		}
+		for j, p := range host.Paths {
+			if !strings.HasPrefix(p, "/") {
+				errors = append(errors, field.Invalid(hostPath.Child("paths").Index(j), p, "must start with /"))
+			}
+		}
+
+		if spec.Mode == appsv2.NetworkingModeGatewayAPI {
+			if len(host.Annotations) > 0 || host.IngressClassName != nil || len(host.Paths) > 0 {
+				warnings = append(warnings, fmt.Sprintf("networking.hosts[%d]: annotations, ingressClassName and paths only apply in Ingress mode", i))
+			}
+			if host.TLS != nil && host.TLS.CertManager != nil {
+				warnings = append(warnings, fmt.Sprintf("networking.hosts[%d]: certManager issuers are not applied per listener; annotate the Gateway through networking.tls.certManager", i))
+			}
+			if spec.GatewayAPI != nil && !spec.GatewayAPI.Gateway.Managed {
+				warnings = append(warnings, fmt.Sprintf("networking.hosts[%d]: listeners are only created on a managed Gateway; the referenced Gateway must already serve %s", i, host.Hostname))
+			}
+		}
+	}
+	return errors, warnings
+}
+
+func validateGatewayBackendRef(name string, port int32, refPath *field.Path) field.ErrorList {
+	var errors field.ErrorList
+	if name == "" {
+		errors = append(errors, field.Required(refPath.Child("name"), "service name is required"))
+	} else {
+		for _, msg := range validation.IsDNS1035Label(name) {
+			errors = append(errors, field.Invalid(refPath.Child("name"), name, msg))
+		}
+	}
+	if port < 1 || port > 65535 {
+		errors = append(errors, field.Invalid(refPath.Child("port"), port, "must be between 1 and 65535"))
+	}
+	return errors
+}
+
+// parseGatewayDuration reports whether value is empty or a valid Gateway API
+// duration, appending an error otherwise.
+func parseGatewayDuration(value string, valuePath *field.Path, errors *field.ErrorList) (time.Duration, bool) {
+	if value == "" {
+		return 0, true
+	}
+	if !gatewayDurationPattern.MatchString(value) {
+		*errors = append(*errors, field.Invalid(valuePath, value, `must be a Gateway API duration such as "30s" or "1h30m"`))
+		return 0, false
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		*errors = append(*errors, field.Invalid(valuePath, value, err.Error()))
+		return 0, false
+	}
+	return d, true
+}
+
 func validateNetworkingSpec(wandb *appsv2.WeightsAndBiases) (field.ErrorList, admission.Warnings) {
 	var errors field.ErrorList
 	var warnings admission.Warnings
@@ -1019,9 +1798,14 @@ func validateNetworkingSpec(wandb *appsv2.WeightsAndBiases) (field.ErrorList, ad
 					))
 				}
 			}
+			errors = append(errors, validateGatewayRouting(spec.GatewayAPI.Routing, netPath.Child("gatewayAPI").Child("routing"))...)
 		}
 	}
 
+	hostErrs, hostWarnings := validateIngressHosts(wandb, netPath.Child("hosts"))
+	errors = append(errors, hostErrs...)
+	warnings = append(warnings, hostWarnings...)
+
 	if spec.TLS != nil && spec.TLS.CertManager != nil && spec.Mode == "" {
 		warnings = append(warnings, "networking.tls.certManager annotations are only applied when using Ingress or GatewayAPI")
 	}
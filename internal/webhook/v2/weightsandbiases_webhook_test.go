@@ -268,13 +268,21 @@ var _ = Describe("WeightsAndBiases Webhook", func() {
 			Expect(err.Error()).To(ContainSubstring("namespace"))
 		})
 
-		It("rejects redis storage size changes when already set", func() {
+		It("rejects redis storage size decreases", func() {
+			oldObj.Spec.Redis = map[string]appsv2.RedisSpec{appsv2.DefaultInstanceName: {ManagedRedis: &appsv2.ManagedRedisSpec{Namespace: "redis", StorageSize: "20Gi"}}}
+			obj.Spec.Redis = map[string]appsv2.RedisSpec{appsv2.DefaultInstanceName: {ManagedRedis: &appsv2.ManagedRedisSpec{Namespace: "redis", StorageSize: "10Gi"}}}
+
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("storageSize cannot be decreased"))
+		})
+
+		It("allows redis storage size increases", func() {
 			oldObj.Spec.Redis = map[string]appsv2.RedisSpec{appsv2.DefaultInstanceName: {ManagedRedis: &appsv2.ManagedRedisSpec{Namespace: "redis", StorageSize: "10Gi"}}}
 			obj.Spec.Redis = map[string]appsv2.RedisSpec{appsv2.DefaultInstanceName: {ManagedRedis: &appsv2.ManagedRedisSpec{Namespace: "redis", StorageSize: "20Gi"}}}
 
 			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
-			Expect(err).To(HaveOccurred())
-			Expect(err.Error()).To(ContainSubstring("storageSize may not be changed"))
+			Expect(err).NotTo(HaveOccurred())
 		})
 
 		It("allows redis storage size to be initially set on update", func() {
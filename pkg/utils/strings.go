@@ -2,6 +2,7 @@ package utils
 
 import (
 	"crypto/rand"
+	"fmt"
 	"math/big"
 
 	"golang.org/x/text/cases"
@@ -27,8 +28,40 @@ func RemoveString(strings []string, target string) []string {
 	return result
 }
 
-func GenerateRandomPassword(length int) (string, error) {
-	allowedCharacters := "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!#%^*()-_=+{}|,.<>?"
+// Character types accepted by GenerateRandomPassword, as used by the
+// server manifest's generatedSecrets[].type.
+const (
+	CharacterTypePassword     = "password"
+	CharacterTypeAlphanumeric = "alphanumeric"
+	CharacterTypeAlpha        = "alpha"
+	CharacterTypeNumeric      = "numeric"
+	CharacterTypeHex          = "hex"
+)
+
+const (
+	lowercaseCharacters = "abcdefghijklmnopqrstuvwxyz"
+	uppercaseCharacters = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitCharacters     = "0123456789"
+)
+
+var characterSets = map[string]string{
+	CharacterTypePassword:     lowercaseCharacters + uppercaseCharacters + digitCharacters + "!#%^*()-_=+{}|,.<>?",
+	CharacterTypeAlphanumeric: lowercaseCharacters + uppercaseCharacters + digitCharacters,
+	CharacterTypeAlpha:        lowercaseCharacters + uppercaseCharacters,
+	CharacterTypeNumeric:      digitCharacters,
+	CharacterTypeHex:          digitCharacters + "abcdef",
+}
+
+// GenerateRandomPassword returns a random string of length characters drawn
+// from the set named by characterType. An empty type means "password".
+func GenerateRandomPassword(length int, characterType string) (string, error) {
+	if characterType == "" {
+		characterType = CharacterTypePassword
+	}
+	allowedCharacters, ok := characterSets[characterType]
+	if !ok {
+		return "", fmt.Errorf("unsupported character type %q", characterType)
+	}
 	characterSetLength := len(allowedCharacters)
 
 	result := make([]byte, length)
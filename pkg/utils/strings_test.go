@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateRandomPassword(t *testing.T) {
+	for characterType, allowed := range map[string]string{
+		"":                        characterSets[CharacterTypePassword],
+		CharacterTypePassword:     characterSets[CharacterTypePassword],
+		CharacterTypeAlphanumeric: lowercaseCharacters + uppercaseCharacters + digitCharacters,
+		CharacterTypeAlpha:        lowercaseCharacters + uppercaseCharacters,
+		CharacterTypeNumeric:      digitCharacters,
+		CharacterTypeHex:          "0123456789abcdef",
+	} {
+		t.Run(characterType, func(t *testing.T) {
+			value, err := GenerateRandomPassword(64, characterType)
+			require.NoError(t, err)
+			assert.Len(t, value, 64)
+			for _, c := range value {
+				assert.True(t, strings.ContainsRune(allowed, c), "%q is not a %q character", c, characterType)
+			}
+		})
+	}
+
+	_, err := GenerateRandomPassword(32, "emoji")
+	assert.ErrorContains(t, err, `unsupported character type "emoji"`)
+}
@@ -0,0 +1,50 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/wandb/operator/internal/logx"
+)
+
+// ErrOperatorIncompatible is returned (wrapped) by CheckOperatorVersion when
+// the running operator does not satisfy the manifest's requiredOperatorVersion.
+var ErrOperatorIncompatible = errors.New("operator version does not satisfy manifest requirement")
+
+// CheckOperatorVersion reports whether operatorVersion satisfies the manifest's
+// requiredOperatorVersion constraint (e.g. "^2.0.0").
+//
+// Prerelease and build metadata are dropped from the operator version before
+// matching so a 2.1.0-beta.1 build counts as 2.1.0; semver constraints would
+// otherwise reject every prerelease. Manifests without a requirement, and
+// operator builds without a semver version (local "dev" builds, dev image
+// tags), are not gated. A constraint that does not parse is treated as
+// incompatible: we cannot tell what the release needs.
+func (m *Manifest) CheckOperatorVersion(ctx context.Context, operatorVersion string) error {
+	required := strings.TrimSpace(m.RequiredOperatorVersion)
+	if required == "" {
+		return nil
+	}
+
+	constraint, err := semver.NewConstraint(required)
+	if err != nil {
+		return fmt.Errorf("%w: invalid requiredOperatorVersion %q: %v", ErrOperatorIncompatible, required, err)
+	}
+
+	running, err := semver.NewVersion(operatorVersion)
+	if err != nil {
+		logx.GetSlog(ctx).Info("skipping manifest operator version check for non-release build",
+			"operatorVersion", operatorVersion, "requiredOperatorVersion", required)
+		return nil
+	}
+	release, _ := running.SetPrerelease("")
+	release, _ = release.SetMetadata("")
+
+	if !constraint.Check(&release) {
+		return fmt.Errorf("%w: manifest requires operator %s, running %s", ErrOperatorIncompatible, required, operatorVersion)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package manifest
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCheckOperatorVersion(t *testing.T) {
+	cases := []struct {
+		name     string
+		required string
+		running  string
+		wantErr  bool
+	}{
+		{name: "no requirement", required: "", running: "1.0.0"},
+		{name: "satisfied", required: "^2.0.0", running: "2.3.1"},
+		{name: "prerelease counts as its release", required: "^2.0.0", running: "2.0.0-beta.3"},
+		{name: "v prefix and metadata", required: ">=2.1.0", running: "v2.1.0+abc123"},
+		{name: "too old", required: "^2.1.0", running: "2.0.5", wantErr: true},
+		{name: "next major", required: "^2.0.0", running: "3.0.0", wantErr: true},
+		{name: "dev build is not gated", required: "^9.0.0", running: "dev"},
+		{name: "dev image tag is not gated", required: "^9.0.0", running: "dev-main-0123abc"},
+		{name: "invalid constraint", required: "not-a-constraint", running: "2.0.0", wantErr: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := Manifest{RequiredOperatorVersion: tc.required}
+			err := m.CheckOperatorVersion(context.Background(), tc.running)
+			if tc.wantErr {
+				if !errors.Is(err, ErrOperatorIncompatible) {
+					t.Fatalf("expected ErrOperatorIncompatible, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	v2 "github.com/wandb/operator/api/v2"
 	"github.com/wandb/operator/internal/logx"
+	wmetrics "github.com/wandb/operator/internal/observability/metrics"
 	autoscalingv2 "k8s.io/api/autoscaling/v2"
 
 	"oras.land/oras-go/v2"
@@ -35,6 +36,7 @@ import (
 	"oras.land/oras-go/v2/registry/remote/retry"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/yaml"
 )
 
@@ -148,9 +150,17 @@ type AppKafkaSection struct {
 	Topics map[string]KafkaTopicDef `yaml:"topics"`
 }
 
+// ApplicationKindDaemonSet is the Application.Kind that runs one pod of the
+// application on every node.
+const ApplicationKindDaemonSet = "DaemonSet"
+
 // Application describes one entry in the applications list.
 type Application struct {
 	Name string `yaml:"name"`
+	// Kind is the workload the application runs as. ApplicationKindDaemonSet
+	// runs it on every node; empty renders a Deployment, or an Argo Rollout
+	// when a rollout strategy is set.
+	Kind string `yaml:"kind,omitempty"`
 	// LegacyKey is the v1 operator-wandb helm values key for this application
 	// when it differs from the name (e.g. nginx-proxy was `nginx`).
 	LegacyKey string   `yaml:"legacyKey,omitempty"`
@@ -196,6 +206,19 @@ type SizingConfig struct {
 	Autoscaling *AutoscalingConfig           `yaml:"autoscaling,omitempty"`
 	// MetadataVolumeSize sizes the disk backing an object store's index/metadata
 	MetadataVolumeSize string `yaml:"metadataVolumeSize,omitempty"`
+	// PodDisruptionBudget sets the disruption budget for an application at this
+	// size. The selector is always derived from the application's pods.
+	PodDisruptionBudget *PodDisruptionBudgetConfig `yaml:"podDisruptionBudget,omitempty"`
+	// Mysqld holds default mysqld settings for a MySQL instance at this size,
+	// keyed by option name. Ignored for other infra.
+	Mysqld map[string]string `yaml:"mysqld,omitempty"`
+}
+
+// PodDisruptionBudgetConfig carries the budget knobs of a PodDisruptionBudget.
+// Set at most one of MinAvailable and MaxUnavailable, as Kubernetes requires.
+type PodDisruptionBudgetConfig struct {
+	MinAvailable   *intstr.IntOrString `yaml:"minAvailable,omitempty"`
+	MaxUnavailable *intstr.IntOrString `yaml:"maxUnavailable,omitempty"`
 }
 
 type KafkaSizingConfig struct {
@@ -209,6 +232,23 @@ type KafkaSizingConfig struct {
 
 type AutoscalingConfig struct {
 	Horizontal autoscalingv2.HorizontalPodAutoscalerSpec
+	// KafkaLag scales the application on consumer-group lag through a KEDA
+	// ScaledObject. Replica bounds and behavior still come from Horizontal.
+	KafkaLag *KafkaLagTrigger `yaml:"kafkaLag,omitempty"`
+}
+
+// KafkaLagTrigger declares a KEDA kafka trigger for an application. Topic may
+// name an entry in the top-level kafka topics list (resolved to its topic) or
+// be a literal topic name.
+type KafkaLagTrigger struct {
+	KafkaTopicDef `yaml:",inline"`
+	// LagThreshold is the average lag per replica KEDA scales towards.
+	LagThreshold int64 `yaml:"lagThreshold,omitempty"`
+	// ActivationLagThreshold is the lag above which the application is
+	// scaled up from zero.
+	ActivationLagThreshold int64  `yaml:"activationLagThreshold,omitempty"`
+	PollingInterval        *int32 `yaml:"pollingInterval,omitempty"`
+	CooldownPeriod         *int32 `yaml:"cooldownPeriod,omitempty"`
 }
 
 // ContainerSpec represents a minimal container definition used by
@@ -306,8 +346,21 @@ type RegistryAuth struct {
 	Credential orasauth.CredentialFunc
 	// PlainHTTP allows pulling over HTTP for insecure/in-cluster registries.
 	PlainHTTP bool
+	// Verifier, when set, requires the manifest to carry a signature by one of
+	// its keys, whether it is pulled or served from the local cache.
+	Verifier *SignatureVerifier
 }
 
+func (a *RegistryAuth) verifier() *SignatureVerifier {
+	if a == nil {
+		return nil
+	}
+	return a.Verifier
+}
+
+// serverManifestCacheDir is where pulled manifests are kept as OCI layouts.
+var serverManifestCacheDir = "/tmp/server-manifest"
+
 // IsFileRepository reports whether repository is a local file:// manifest, which
 // needs no registry credentials.
 func IsFileRepository(repository string) bool {
@@ -325,6 +378,11 @@ func GetServerManifest(ctx context.Context, repository string, version string, a
 	case "http", "https":
 		return Manifest{}, errors.New("http manifest not implemented")
 	case "file":
+		// Local manifests carry no signatures; refuse them rather than
+		// silently skipping verification that was asked for.
+		if auth.verifier() != nil {
+			return Manifest{}, fmt.Errorf("%w: %s is not an OCI repository", ErrManifestUntrusted, repository)
+		}
 		return LoadManifestFromFile(ctx, repository, version)
 	default:
 		return DownloadServerManifest(ctx, repository, version, auth)
@@ -565,6 +623,11 @@ func mergeApplications(dst, src map[string]Application) {
 			// Merge existing application
 			mergedApp := dstApp
 
+			// Take the kind from src when it sets one
+			if srcApp.Kind != "" {
+				mergedApp.Kind = srcApp.Kind
+			}
+
 			// Preserve ingress from dst if src doesn't have it
 			if srcApp.Ingress == nil && dstApp.Ingress != nil {
 				mergedApp.Ingress = dstApp.Ingress
@@ -612,45 +675,93 @@ func DownloadServerManifest(ctx context.Context, repository string, version stri
 	// Namespace the on-disk cache by repository so two CRs referencing different
 	// (possibly private) registries under the same version tag can never resolve
 	// each other's cached artifact.
-	ociDir := filepath.Join("/tmp/server-manifest", repositoryCacheKey(repository))
+	ociDir := filepath.Join(serverManifestCacheDir, repositoryCacheKey(repository))
 	localRepo, err := oci.New(ociDir)
 	if err != nil {
 		return manifest, err
 	}
 
+	verifier := auth.verifier()
 	var descriptor ocispec.Descriptor
 	descriptor, err = localRepo.Resolve(ctx, version)
-	if err != nil {
+	cached := err == nil
+	if !cached {
 		logger.Info("image not found in local", "repository", repository, "version", version, "error", err)
-		remoteRepo, err := remote.NewRepository(repository)
+		remoteRepo, err := newRemoteRepository(repository, auth)
 		if err != nil {
 			logger.Error("failed to create repository", "repository", repository, "version", version, "error", err)
+			wmetrics.RecordManifestDownload(ctx, err)
 			return manifest, err
 		}
-		if auth != nil {
-			remoteRepo.PlainHTTP = auth.PlainHTTP
-			if auth.Credential != nil {
-				remoteRepo.Client = &orasauth.Client{
-					Client:     retry.DefaultClient,
-					Header:     http.Header{"User-Agent": []string{"wandb-operator"}},
-					Cache:      orasauth.NewCache(),
-					Credential: auth.Credential,
-				}
-			}
-		}
 		descriptor, err = oras.Copy(ctx, remoteRepo, version, localRepo, version, oras.DefaultCopyOptions)
+		wmetrics.RecordManifestDownload(ctx, err)
 		if err != nil {
 			logger.Error("failed to fetch image from remote", "repository", repository, "version", version, "error", err)
 			return manifest, err
 		}
 		logger.Info("successfully fetched image from remote", "repository", repository, "version", version, "desc", descriptor)
+		if verifier != nil {
+			if err := copySignatures(ctx, remoteRepo, localRepo, descriptor); err != nil {
+				return manifest, fmt.Errorf("%w: %v", ErrManifestUntrusted, err)
+			}
+		}
 	} else {
+		wmetrics.RecordManifestCacheHit(ctx)
 		logger.Debug("successfully fetched image from local", "repository", repository, "version", version)
 	}
 
+	if verifier != nil {
+		err = verifier.Verify(ctx, localRepo, descriptor)
+		if err != nil && cached {
+			// The cache may predate verification being turned on (the webhook
+			// pulls without it), so refresh the signatures for the cached
+			// digest once before giving up.
+			logger.Info("cached manifest is not verified, fetching signatures", "repository", repository, "version", version, "error", err)
+			err = refreshSignatures(ctx, repository, auth, localRepo, descriptor)
+			if err == nil {
+				err = verifier.Verify(ctx, localRepo, descriptor)
+			}
+		}
+		if err != nil {
+			logger.Error("manifest signature verification failed", "repository", repository, "version", version, "error", err)
+			return manifest, err
+		}
+		logger.Debug("verified manifest signature", "repository", repository, "version", version, "digest", descriptor.Digest)
+	}
+
 	return processManifest(ctx, localRepo, descriptor, logger)
 }
 
+func newRemoteRepository(repository string, auth *RegistryAuth) (*remote.Repository, error) {
+	remoteRepo, err := remote.NewRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	if auth != nil {
+		remoteRepo.PlainHTTP = auth.PlainHTTP
+		if auth.Credential != nil {
+			remoteRepo.Client = &orasauth.Client{
+				Client:     retry.DefaultClient,
+				Header:     http.Header{"User-Agent": []string{"wandb-operator"}},
+				Cache:      orasauth.NewCache(),
+				Credential: auth.Credential,
+			}
+		}
+	}
+	return remoteRepo, nil
+}
+
+func refreshSignatures(ctx context.Context, repository string, auth *RegistryAuth, localRepo *oci.Store, descriptor ocispec.Descriptor) error {
+	remoteRepo, err := newRemoteRepository(repository, auth)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrManifestUntrusted, err)
+	}
+	if err := copySignatures(ctx, remoteRepo, localRepo, descriptor); err != nil {
+		return fmt.Errorf("%w: %v", ErrManifestUntrusted, err)
+	}
+	return nil
+}
+
 // repositoryCacheKey derives a filesystem-safe, collision-resistant directory
 // name from a repository reference so each repository gets its own cache.
 func repositoryCacheKey(repository string) string {
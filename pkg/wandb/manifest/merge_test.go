@@ -0,0 +1,24 @@
+package manifest
+
+import "testing"
+
+func TestMergeApplicationsCarriesOverlayKind(t *testing.T) {
+	dst := map[string]Application{
+		"node-agent": {Name: "node-agent", CommonEnvs: []string{"base"}},
+		"api":        {Name: "api", Kind: ApplicationKindDaemonSet},
+	}
+	mergeApplications(dst, map[string]Application{
+		"node-agent": {Name: "node-agent", Kind: ApplicationKindDaemonSet},
+		"api":        {Name: "api"},
+	})
+
+	if got := dst["node-agent"].Kind; got != ApplicationKindDaemonSet {
+		t.Fatalf("expected the overlay kind %q, got %q", ApplicationKindDaemonSet, got)
+	}
+	if got := dst["node-agent"].CommonEnvs; len(got) != 1 || got[0] != "base" {
+		t.Fatalf("expected the base common envs to survive the merge, got %v", got)
+	}
+	if got := dst["api"].Kind; got != ApplicationKindDaemonSet {
+		t.Fatalf("expected an overlay without a kind to keep %q, got %q", ApplicationKindDaemonSet, got)
+	}
+}
@@ -0,0 +1,201 @@
+package manifest
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry"
+)
+
+const (
+	// CosignSignatureArtifactType is the artifactType cosign gives signature
+	// manifests it attaches as OCI 1.1 referrers of the signed artifact.
+	CosignSignatureArtifactType = "application/vnd.dev.cosign.artifact.sig.v1+json"
+	// CosignSimpleSigningMediaType is the media type of the signed payload layer.
+	CosignSimpleSigningMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+	// CosignSignatureAnnotation holds the base64 signature over the payload layer.
+	CosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+)
+
+// ErrManifestUntrusted is returned when signature verification is requested
+// and no signature attached to the manifest verifies against the trusted keys.
+var ErrManifestUntrusted = errors.New("manifest signature could not be verified")
+
+// SignatureVerifier holds the public keys a server manifest must be signed
+// with. Signatures are cosign key-based signatures attached to the manifest as
+// OCI referrers; keyless (Fulcio/Rekor) and notation envelopes are not
+// supported, so a manifest signed only that way is reported as untrusted.
+type SignatureVerifier struct {
+	PublicKeys []crypto.PublicKey
+}
+
+// ParsePublicKeys decodes every PEM "PUBLIC KEY" block in data. ECDSA, RSA and
+// Ed25519 keys are accepted, matching what cosign generates.
+func ParsePublicKeys(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "PUBLIC KEY" {
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		switch key.(type) {
+		case *ecdsa.PublicKey, *rsa.PublicKey, ed25519.PublicKey:
+			keys = append(keys, key)
+		default:
+			return nil, fmt.Errorf("unsupported public key type %T", key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no PEM encoded public key found")
+	}
+	return keys, nil
+}
+
+// simpleSigningPayload is the part of cosign's payload that binds the
+// signature to a manifest digest.
+type simpleSigningPayload struct {
+	Critical struct {
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// copySignatures copies the signature referrers of subject from src into dst
+// so a cached manifest can be verified again without reaching the registry.
+func copySignatures(ctx context.Context, src content.ReadOnlyGraphStorage, dst content.Storage, subject ocispec.Descriptor) error {
+	signatures, err := registry.Referrers(ctx, src, subject, CosignSignatureArtifactType)
+	if err != nil {
+		return fmt.Errorf("failed to list manifest signatures: %w", err)
+	}
+	for _, signature := range signatures {
+		if err := oras.CopyGraph(ctx, src, dst, signature, oras.DefaultCopyGraphOptions); err != nil {
+			return fmt.Errorf("failed to copy manifest signature %s: %w", signature.Digest, err)
+		}
+	}
+	return nil
+}
+
+// Verify checks that at least one signature referrer of subject in store is a
+// valid signature over subject's digest by one of the trusted keys. Any
+// failure wraps ErrManifestUntrusted.
+func (v *SignatureVerifier) Verify(ctx context.Context, store content.ReadOnlyGraphStorage, subject ocispec.Descriptor) error {
+	signatures, err := registry.Referrers(ctx, store, subject, CosignSignatureArtifactType)
+	if err != nil {
+		return fmt.Errorf("%w: failed to list signatures: %v", ErrManifestUntrusted, err)
+	}
+	if len(signatures) == 0 {
+		return fmt.Errorf("%w: %s has no signatures", ErrManifestUntrusted, subject.Digest)
+	}
+
+	var lastErr error
+	for _, signature := range signatures {
+		if lastErr = v.verifySignatureManifest(ctx, store, signature, subject); lastErr == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %v", ErrManifestUntrusted, lastErr)
+}
+
+func (v *SignatureVerifier) verifySignatureManifest(
+	ctx context.Context,
+	store content.ReadOnlyStorage,
+	signature ocispec.Descriptor,
+	subject ocispec.Descriptor,
+) error {
+	data, err := content.FetchAll(ctx, store, signature)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signature %s: %w", signature.Digest, err)
+	}
+	var signatureManifest ocispec.Manifest
+	if err := json.Unmarshal(data, &signatureManifest); err != nil {
+		return fmt.Errorf("failed to unmarshal signature %s: %w", signature.Digest, err)
+	}
+
+	err = fmt.Errorf("signature %s has no simple signing layer", signature.Digest)
+	for _, layer := range signatureManifest.Layers {
+		encoded, ok := layer.Annotations[CosignSignatureAnnotation]
+		if layer.MediaType != CosignSimpleSigningMediaType || !ok {
+			continue
+		}
+		if err = v.verifyLayer(ctx, store, layer, encoded, subject); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (v *SignatureVerifier) verifyLayer(
+	ctx context.Context,
+	store content.ReadOnlyStorage,
+	layer ocispec.Descriptor,
+	encodedSignature string,
+	subject ocispec.Descriptor,
+) error {
+	signature, err := base64.StdEncoding.DecodeString(encodedSignature)
+	if err != nil {
+		return fmt.Errorf("failed to decode signature: %w", err)
+	}
+	payload, err := content.FetchAll(ctx, store, layer)
+	if err != nil {
+		return fmt.Errorf("failed to fetch signed payload: %w", err)
+	}
+	if !v.verifyBlob(payload, signature) {
+		return errors.New("signature does not match any trusted key")
+	}
+
+	// The signature is only meaningful if the payload names the manifest we
+	// are about to use; otherwise a valid signature of another release could
+	// be attached to a tampered one.
+	var signed simpleSigningPayload
+	if err := json.Unmarshal(payload, &signed); err != nil {
+		return fmt.Errorf("failed to unmarshal signed payload: %w", err)
+	}
+	if signed.Critical.Image.DockerManifestDigest != subject.Digest.String() {
+		return fmt.Errorf("signed payload is for %s, not %s", signed.Critical.Image.DockerManifestDigest, subject.Digest)
+	}
+	return nil
+}
+
+func (v *SignatureVerifier) verifyBlob(payload, signature []byte) bool {
+	digest := sha256.Sum256(payload)
+	for _, key := range v.PublicKeys {
+		switch k := key.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(k, digest[:], signature) {
+				return true
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], signature) == nil ||
+				rsa.VerifyPSS(k, crypto.SHA256, digest[:], signature, nil) == nil {
+				return true
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(k, payload, signature) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+package manifest
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+// signatureTestRegistry is an in-process OCI registry holding one server
+// manifest artifact tagged "1.0.0".
+type signatureTestRegistry struct {
+	server     *httptest.Server
+	repository string
+	remote     *remote.Repository
+	subject    ocispec.Descriptor
+}
+
+func newSignatureTestRegistry(t *testing.T) *signatureTestRegistry {
+	t.Helper()
+	serverManifestCacheDir = t.TempDir()
+
+	server := httptest.NewServer(registry.New(registry.WithReferrersSupport(true), registry.Logger(log.New(io.Discard, "", 0))))
+	t.Cleanup(server.Close)
+	repository := strings.TrimPrefix(server.URL, "http://") + "/wandb/server-manifest"
+	repo, err := remote.NewRepository(repository)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.PlainHTTP = true
+
+	ctx := context.Background()
+	var layer bytes.Buffer
+	tw := tar.NewWriter(&layer)
+	body := []byte("requiredOperatorVersion: \">=0.0.0\"\n")
+	if err := tw.WriteHeader(&tar.Header{Name: "manifest.yaml", Mode: 0o644, Size: int64(len(body))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write(body); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	layerDesc := pushBlob(t, repo, ocispec.MediaTypeImageLayer, layer.Bytes(), nil)
+	subject, err := oras.PackManifest(ctx, repo, oras.PackManifestVersion1_1, "application/vnd.wandb.server-manifest",
+		oras.PackManifestOptions{Layers: []ocispec.Descriptor{layerDesc}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Tag(ctx, subject, "1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	return &signatureTestRegistry{server: server, repository: repository, remote: repo, subject: subject}
+}
+
+// sign attaches a cosign-style signature referrer over digest.
+func (r *signatureTestRegistry) sign(t *testing.T, key *ecdsa.PrivateKey, digest string) {
+	t.Helper()
+	payload := []byte(fmt.Sprintf(
+		`{"critical":{"identity":{"docker-reference":%q},"image":{"docker-manifest-digest":%q},"type":"cosign container image signature"},"optional":null}`,
+		r.repository, digest))
+	hash := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer := pushBlob(t, r.remote, CosignSimpleSigningMediaType, payload, map[string]string{
+		CosignSignatureAnnotation: base64.StdEncoding.EncodeToString(signature),
+	})
+	// The config carries the artifact type as well, since the test registry
+	// reports referrers by config media type.
+	config := pushBlob(t, r.remote, CosignSignatureArtifactType, []byte("{}"), nil)
+	if _, err := oras.PackManifest(context.Background(), r.remote, oras.PackManifestVersion1_1, CosignSignatureArtifactType,
+		oras.PackManifestOptions{Subject: &r.subject, ConfigDescriptor: &config, Layers: []ocispec.Descriptor{layer}}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (r *signatureTestRegistry) auth(keys ...*ecdsa.PrivateKey) *RegistryAuth {
+	auth := &RegistryAuth{PlainHTTP: true}
+	if len(keys) > 0 {
+		auth.Verifier = &SignatureVerifier{}
+		for _, key := range keys {
+			auth.Verifier.PublicKeys = append(auth.Verifier.PublicKeys, key.Public())
+		}
+	}
+	return auth
+}
+
+func pushBlob(t *testing.T, repo *remote.Repository, mediaType string, data []byte, annotations map[string]string) ocispec.Descriptor {
+	t.Helper()
+	desc := content.NewDescriptorFromBytes(mediaType, data)
+	if err := repo.Push(context.Background(), desc, bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	desc.Annotations = annotations
+	return desc
+}
+
+func newSigningKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestDownloadServerManifestVerifiesSignature(t *testing.T) {
+	ctx := context.Background()
+	reg := newSignatureTestRegistry(t)
+	key := newSigningKey(t)
+	reg.sign(t, key, reg.subject.Digest.String())
+
+	m, err := DownloadServerManifest(ctx, reg.repository, "1.0.0", reg.auth(key))
+	if err != nil {
+		t.Fatalf("signed manifest should verify: %v", err)
+	}
+	if m.RequiredOperatorVersion != ">=0.0.0" {
+		t.Fatalf("unexpected manifest %+v", m)
+	}
+
+	// The signatures travel with the cache, so verification still passes
+	// once the registry is gone.
+	reg.server.Close()
+	if _, err := DownloadServerManifest(ctx, reg.repository, "1.0.0", reg.auth(key)); err != nil {
+		t.Fatalf("cached manifest should verify offline: %v", err)
+	}
+}
+
+func TestDownloadServerManifestRejectsUntrustedManifests(t *testing.T) {
+	cases := []struct {
+		name string
+		sign func(t *testing.T, reg *signatureTestRegistry, trusted *ecdsa.PrivateKey)
+	}{
+		{name: "unsigned", sign: func(*testing.T, *signatureTestRegistry, *ecdsa.PrivateKey) {}},
+		{name: "signed by another key", sign: func(t *testing.T, reg *signatureTestRegistry, _ *ecdsa.PrivateKey) {
+			reg.sign(t, newSigningKey(t), reg.subject.Digest.String())
+		}},
+		{name: "signature for another digest", sign: func(t *testing.T, reg *signatureTestRegistry, trusted *ecdsa.PrivateKey) {
+			reg.sign(t, trusted, "sha256:"+strings.Repeat("0", 64))
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			reg := newSignatureTestRegistry(t)
+			trusted := newSigningKey(t)
+			tc.sign(t, reg, trusted)
+
+			_, err := DownloadServerManifest(context.Background(), reg.repository, "1.0.0", reg.auth(trusted))
+			if !errors.Is(err, ErrManifestUntrusted) {
+				t.Fatalf("expected ErrManifestUntrusted, got %v", err)
+			}
+		})
+	}
+}
+
+func TestDownloadServerManifestVerifiesCacheFilledWithoutVerification(t *testing.T) {
+	ctx := context.Background()
+	reg := newSignatureTestRegistry(t)
+	key := newSigningKey(t)
+
+	// A pull without a verifier (as the webhook does) caches the manifest
+	// but none of its signatures.
+	if _, err := DownloadServerManifest(ctx, reg.repository, "1.0.0", reg.auth()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DownloadServerManifest(ctx, reg.repository, "1.0.0", reg.auth(key)); !errors.Is(err, ErrManifestUntrusted) {
+		t.Fatalf("cached unsigned manifest should be untrusted, got %v", err)
+	}
+
+	reg.sign(t, key, reg.subject.Digest.String())
+	if _, err := DownloadServerManifest(ctx, reg.repository, "1.0.0", reg.auth(key)); err != nil {
+		t.Fatalf("signatures should be fetched for the cached digest: %v", err)
+	}
+}
+
+func TestParsePublicKeys(t *testing.T) {
+	der, err := x509.MarshalPKIXPublicKey(newSigningKey(t).Public())
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+
+	keys, err := ParsePublicKeys(append(append([]byte{}, block...), block...))
+	if err != nil || len(keys) != 2 {
+		t.Fatalf("expected two keys, got %d (%v)", len(keys), err)
+	}
+	if _, err := ParsePublicKeys([]byte("not a key")); err == nil {
+		t.Fatal("expected an error for input without a PEM public key")
+	}
+}